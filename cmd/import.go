@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"film-library/internal/config"
+	"film-library/internal/model"
+	"film-library/internal/repository"
+	"film-library/internal/service"
+	"film-library/internal/utils/records"
+	"film-library/internal/utils/sl"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// runImport выполняет подкоманду import: загружает файл в базу напрямую,
+// минуя HTTP, и печатает отчёт в stdout. Возвращает код завершения процесса
+func runImport(args []string, cfg *config.Config, log *slog.Logger) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	kind := fs.String("kind", "", "what to import: films, actors or cast")
+	file := fs.String("file", "", "path to CSV or NDJSON file, - for stdin")
+	format := fs.String("format", "", "csv or ndjson, by default taken from file extension")
+	dryRun := fs.Bool("dry-run", false, "validate and count without saving")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *file == "" {
+		fmt.Fprintln(os.Stderr, "import: -file is required")
+		fs.Usage()
+		return 2
+	}
+
+	if *format == "" && *file != "-" {
+		*format = records.FormatOf(*file)
+	}
+
+	var in io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Error("failed to open import file", sl.Err(err))
+			return 1
+		}
+		defer f.Close()
+		in = f
+	}
+
+	storage, err := repository.Connect(cfg.Database, log)
+	if err != nil {
+		log.Error("failed to init storage", sl.Err(err))
+		return 1
+	}
+	defer storage.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	imports := service.NewImportService(repository.NewImportRepository(storage.DB()))
+	report, err := imports.Import(ctx, model.ImportRequest{Kind: *kind, Format: *format, DryRun: *dryRun}, in)
+	if err != nil {
+		log.Error("import failed", sl.Err(err))
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(report)
+
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
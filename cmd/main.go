@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"film-library/internal/config"
 	"film-library/internal/handler"
+	"film-library/internal/job"
+	"film-library/internal/middleware"
 	"film-library/internal/repository"
 	"film-library/internal/service"
 	slogpretty "film-library/internal/utils/handlers"
-	"log"
+	"film-library/internal/utils/sl"
 	"log/slog"
 	"net/http"
 	"os"
@@ -35,41 +39,139 @@ const (
 )
 
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using environment variables")
-	}
+	envErr := godotenv.Load()
 
 	secret := os.Getenv("SECRET_KEY")
 	cfg := config.MustLoad()
 	log := setupLogger(cfg.Env)
+	slog.SetDefault(log)
+
+	if envErr != nil {
+		log.Info("no .env file found, using environment variables")
+	}
+
+	// film-library import -kind films -file films.csv [-dry-run]
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(runImport(os.Args[2:], cfg, log))
+	}
 
 	log.Info("starting film-library",
 		slog.String("env", cfg.Env),
 		slog.String("version", version),
 	)
 
-	storage, err := repository.Connect(cfg.Database)
+	storage, err := repository.Connect(cfg.Database, log)
 	if err != nil {
-		log.Error("failed to init storage", "error", err)
+		log.Error("failed to init storage", sl.Err(err))
 		os.Exit(1)
 	}
 
 	repositories := repository.NewRepository(storage.DB())
-	services := service.NewService(repositories, secret)
-	router := handler.InitRoute(services)
+	services := service.NewService(repositories, secret, cfg.Auth)
+	router := handler.InitRoute(services, log, cfg.I18n.DefaultLocale, cfg.Import)
+
+	// Фоновые задачи останавливаются вместе с сервером
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	startJobs(jobsCtx, cfg, storage, services, log)
 
-	log.Info("Server is running on port :8080")
+	inFlight := middleware.NewInFlight()
+	srv := newServer(cfg.HTTPServer, inFlight.Middleware(router))
+
+	serverErr := make(chan error, 1)
 	go func() {
-		if err := http.ListenAndServe(":8080", router); err != nil {
-			log.Error("server exited with error", "error", err)
+		log.Info("server is running", slog.String("address", srv.Addr))
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
 		}
 	}()
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
-	<-quit
 
-	log.Info("Shutting down gracefully...")
+	select {
+	case sig := <-quit:
+		log.Info("shutting down gracefully",
+			slog.String("signal", sig.String()),
+			slog.Int64("in_flight", inFlight.Active()),
+		)
+	case err := <-serverErr:
+		log.Error("server exited with error", sl.Err(err))
+	}
+
+	stopJobs()
+
+	// Новые соединения больше не принимаются, активные запросы дорабатывают до дедлайна
+	inFlight.Drain()
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.HTTPServer.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Error("failed to drain requests, closing connections",
+			sl.Err(err),
+			slog.Int64("in_flight", inFlight.Active()),
+		)
+		srv.Close()
+	}
+
+	if err := storage.Close(); err != nil {
+		log.Error("failed to close storage", sl.Err(err))
+	}
+
+	log.Info("server stopped")
+}
+
+// startJobs запускает периодические задачи обслуживания базы
+func startJobs(ctx context.Context, cfg *config.Config, storage *repository.Storage, services *service.Service, log *slog.Logger) {
+	if cfg.Trash.RetentionDays > 0 {
+		go job.Every(ctx, log, "purge_trash", cfg.Trash.PurgeInterval, func(ctx context.Context) error {
+			result, err := services.Trash.PurgeTrash(ctx, cfg.Trash.Retention())
+			if err != nil {
+				return err
+			}
+			if result.Films > 0 || result.Actors > 0 {
+				log.Info("trash purged", slog.Int64("films", result.Films), slog.Int64("actors", result.Actors))
+			}
+			return nil
+		})
+	}
+
+	go job.Every(ctx, log, "purge_tokens", cfg.Auth.CleanupInterval, func(ctx context.Context) error {
+		result, err := services.Authorization.PurgeExpiredTokens(ctx)
+		if err != nil {
+			return err
+		}
+		if result.RefreshTokens > 0 || result.RevokedTokens > 0 {
+			log.Info("expired tokens purged",
+				slog.Int64("refresh_tokens", result.RefreshTokens),
+				slog.Int64("revoked_tokens", result.RevokedTokens),
+			)
+		}
+		return nil
+	})
+
+	// Статистика пересчитывается по уведомлениям об изменении каталога;
+	// без слушателя остаётся только периодический пересчёт
+	changes, err := storage.WatchCatalog(ctx, log)
+	if err != nil {
+		log.Error("failed to watch catalog changes, stats refresh is periodic only", sl.Err(err))
+	}
+	go job.Triggered(ctx, log, "refresh_stats", changes, cfg.Stats.RefreshDelay, cfg.Stats.RefreshInterval, services.Stats.RefreshStats)
+
+	// Рекомендации зависят ещё и от оценок и просмотров, об изменении которых
+	// уведомлений нет, поэтому они пересчитываются по расписанию
+	go job.Every(ctx, log, "refresh_recommendations", cfg.Recommendations.RefreshInterval, services.Recommendation.RefreshRecommendations)
+}
+
+func newServer(cfg config.HTTPServer, h http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.Address,
+		Handler:           h,
+		ReadTimeout:       cfg.Timeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Timeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
 }
 
 func setupLogger(env string) *slog.Logger {
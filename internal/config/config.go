@@ -10,18 +10,71 @@ import (
 )
 
 type Config struct {
-	Env         string     `yaml:"env"`
-	StoragePath string     `yaml:"storage_path"`
-	HTTPServer  HTTPServer `yaml:"http_server"`
-	Database    Database   `yaml:"database"`
+	Env             string          `yaml:"env"`
+	StoragePath     string          `yaml:"storage_path"`
+	HTTPServer      HTTPServer      `yaml:"http_server"`
+	Database        Database        `yaml:"database"`
+	Auth            Auth            `yaml:"auth"`
+	I18n            I18n            `yaml:"i18n"`
+	Trash           Trash           `yaml:"trash"`
+	Stats           Stats           `yaml:"stats"`
+	Recommendations Recommendations `yaml:"recommendations"`
+	Import          Import          `yaml:"import"`
 }
 
 type HTTPServer struct {
-	Address     string        `yaml:"address"`
-	Timeout     time.Duration `yaml:"timeout"`
-	IdleTimeout time.Duration `yaml:"idle_timeout"`
-	User        string        `yaml:"user"`
-	Password    string        `yaml:"password"`
+	Address           string        `yaml:"address" env-default:":8080"`
+	Timeout           time.Duration `yaml:"timeout" env-default:"10s"`            // чтение запроса и запись ответа
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env-default:"60s"`       // простой keep-alive соединения
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env-default:"5s"` // чтение заголовков
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" env-default:"15s"`   // ожидание активных запросов при остановке
+	User              string        `yaml:"user"`
+	Password          string        `yaml:"password"`
+}
+
+// Auth - время жизни токенов: access короткий, refresh ротируется при каждом обновлении.
+// Истёкшие записи о токенах удаляются раз в CleanupInterval
+type Auth struct {
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl" env-default:"15m"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" env-default:"720h"`
+	CleanupInterval time.Duration `yaml:"cleanup_interval" env-default:"1h"`
+}
+
+// I18n - язык ответов, если клиент не прислал подходящий Accept-Language
+type I18n struct {
+	DefaultLocale string `yaml:"default_locale" env-default:"en"`
+}
+
+// Trash - очистка корзины: мягко удалённые записи старше RetentionDays удаляются навсегда.
+// RetentionDays = 0 отключает очистку
+type Trash struct {
+	RetentionDays int           `yaml:"retention_days" env-default:"30"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"`
+}
+
+// Retention - срок хранения в корзине
+func (t Trash) Retention() time.Duration {
+	return time.Duration(t.RetentionDays) * 24 * time.Hour
+}
+
+// Stats - пересчёт статистики: через RefreshDelay после изменения каталога
+// (изменения за это время дают один пересчёт) и не реже раза в RefreshInterval
+type Stats struct {
+	RefreshDelay    time.Duration `yaml:"refresh_delay" env-default:"5s"`
+	RefreshInterval time.Duration `yaml:"refresh_interval" env-default:"1h"`
+}
+
+// Recommendations - пересчёт похожих фильмов и рекомендаций. Оценки и просмотры
+// не уведомляют об изменениях, поэтому пересчёт только периодический
+type Recommendations struct {
+	RefreshInterval time.Duration `yaml:"refresh_interval" env-default:"1h"`
+}
+
+// Import - загрузка файла импорта через API. Timeout заменяет для неё общий
+// http_server.timeout, файл больше MaxBodyBytes отклоняется
+type Import struct {
+	Timeout      time.Duration `yaml:"timeout" env-default:"10m"`
+	MaxBodyBytes int64         `yaml:"max_body_bytes" env-default:"104857600"` // 100 МБ
 }
 
 type Database struct {
@@ -45,7 +98,5 @@ func MustLoad() *Config {
 		log.Fatalf("error reading config file: %s", err)
 	}
 
-	log.Printf("Loaded config: %+v", cfg)
-
 	return &cfg
 }
@@ -0,0 +1,90 @@
+// Package domain описывает ошибки предметной области, общие для всех слоёв.
+// Репозиторий переводит в них ошибки базы, сервисы уточняют сообщение,
+// а response.WriteError превращает их в HTTP-статус и машиночитаемый код.
+// Клиент получает текст из каталога i18n по коду, Message остаётся для логов
+// и на случай, если перевода нет.
+package domain
+
+import "errors"
+
+// Виды ошибок. Проверяются через errors.Is
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrPrecondition = errors.New("precondition failed") // версия из If-Match устарела
+)
+
+// Error - ошибка одного из видов выше с кодом и сообщением для клиента
+type Error struct {
+	Kind    error
+	Code    string // например "film_not_found", он же ключ в каталоге сообщений
+	Message string
+	Args    []any // подстановки в сообщение из каталога
+	cause   error
+}
+
+// New создаёт доменную ошибку без первопричины
+func New(kind error, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Wrap создаёт доменную ошибку поверх первопричины: она остаётся в цепочке
+// для логов и errors.Is, а клиент видит только Message
+func Wrap(cause, kind error, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message, cause: cause}
+}
+
+// WithArgs задаёт подстановки для сообщения из каталога
+func (e *Error) WithArgs(args ...any) *Error {
+	e.Args = args
+	return e
+}
+
+func (e *Error) Error() string {
+	if e.cause == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.cause.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	if e.cause == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.cause}
+}
+
+// BadRequest, NotFound, ... - сокращения для частых случаев
+
+func BadRequest(code, message string) *Error {
+	return New(ErrBadRequest, code, message)
+}
+
+func NotFound(code, message string) *Error {
+	return New(ErrNotFound, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return New(ErrConflict, code, message)
+}
+
+func Validation(code, message string) *Error {
+	return New(ErrValidation, code, message)
+}
+
+func Unauthorized(code, message string) *Error {
+	return New(ErrUnauthorized, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return New(ErrForbidden, code, message)
+}
+
+// VersionMismatch - запись изменили после того, как клиент получил её версию
+func VersionMismatch() *Error {
+	return New(ErrPrecondition, "version_mismatch", "запись изменена другим запросом, получите её заново")
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrap(t *testing.T) {
+	err := fmt.Errorf("storage.postgres.GetFilmByID: %w",
+		Wrap(sql.ErrNoRows, ErrNotFound, "film_not_found", "Фильм не найден"))
+
+	require.True(t, errors.Is(err, ErrNotFound))
+	require.True(t, errors.Is(err, sql.ErrNoRows))
+	require.False(t, errors.Is(err, ErrConflict))
+
+	var de *Error
+	require.True(t, errors.As(err, &de))
+	require.Equal(t, "film_not_found", de.Code)
+	require.Equal(t, "Фильм не найден", de.Message)
+	require.Equal(t, "storage.postgres.GetFilmByID: Фильм не найден: sql: no rows in result set", err.Error())
+}
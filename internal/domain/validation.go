@@ -0,0 +1,38 @@
+package domain
+
+import "strings"
+
+// FieldError - нарушение правила проверки в одном поле запроса
+type FieldError struct {
+	Field   string   `json:"field"` // путь в JSON, например "list_actors[0].name"
+	Rule    string   `json:"rule"`  // нарушенное правило: required, min, oneof, ...
+	Params  []string `json:"params,omitempty"`
+	Message string   `json:"message"`
+
+	Key  string `json:"-"` // ключ сообщения в каталоге i18n
+	Args []any  `json:"-"` // подстановки в сообщение из каталога
+}
+
+// FieldErrors - все нарушения, найденные при проверке запроса.
+// Относится к виду ErrValidation, поэтому errors.Is(err, ErrValidation) для неё верно
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, fe := range e {
+		parts = append(parts, fe.Field+": "+fe.Message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (e FieldErrors) Unwrap() error {
+	return ErrValidation
+}
+
+// Err возвращает nil для пустого списка, чтобы его можно было вернуть как error
+func (e FieldErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
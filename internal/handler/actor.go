@@ -3,11 +3,7 @@ package handler
 import (
 	"encoding/json"
 	"film-library/internal/model"
-	authmid "film-library/internal/utils/auth_mid"
 	"film-library/internal/utils/response"
-	"fmt"
-	"strconv"
-	"strings"
 
 	"film-library/internal/service"
 	"net/http"
@@ -21,35 +17,6 @@ func NewActorHandler(service service.Actor) ActorHandler {
 	return ActorHandler{service: service}
 }
 
-//
-
-func (h *ActorHandler) HandleActorPost(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodPost:
-		h.CreateActor(w, r)
-	default:
-		response.WriteJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
-}
-
-func (h *ActorHandler) HandleActorPut(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodPut:
-		h.UpdateActor(w, r)
-	default:
-		response.WriteJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
-}
-
-func (h *ActorHandler) HandleActorDelete(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodDelete:
-		h.DeleteActor(w, r)
-	default:
-		response.WriteJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
-}
-
 // @Summary Create Actor
 // @Security ApiKeyAuth
 // @Tags actor
@@ -59,30 +26,26 @@ func (h *ActorHandler) HandleActorDelete(w http.ResponseWriter, r *http.Request)
 // @Produce  json
 // @Param actor body model.Actor true "Create Actor"
 // @Success 201 {object} model.Actor
-// @Failure 400,403 {object} response.ErrorResponse
-// @Failure 500 {object} response.ErrorResponse
-// @Failure default {object} response.ErrorResponse
-// @Router /actor_create [post]
+// @Failure 400,403,409 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/actors [post]
 func (h *ActorHandler) CreateActor(w http.ResponseWriter, r *http.Request) {
-	if authmid.IsAdmin(r) {
-		response.WriteJSONError(w, "Forbidden: admin access required", http.StatusForbidden)
-		return
-	}
-
 	var actor model.Actor
 	if err := json.NewDecoder(r.Body).Decode(&actor); err != nil {
-		response.WriteJSONError(w, "Invalid request body", http.StatusBadRequest)
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
 		return
 	}
 
 	if err := actor.Validate(); err != nil {
-		response.WriteJSONError(w, fmt.Sprintf("%v", err.Error()), http.StatusBadRequest)
+		response.WriteError(w, r, err, "invalid_request_body")
 		return
 	}
 
 	err := h.service.AddActor(r.Context(), actor)
 	if err != nil {
-		response.WriteJSONError(w, "Failed to create actor", http.StatusInternalServerError)
+		response.WriteError(w, r, err, "create_actor_failed")
 		return
 	}
 
@@ -91,6 +54,37 @@ func (h *ActorHandler) CreateActor(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(actor)
 }
 
+// @Summary Get Actor
+// @Security ApiKeyAuth
+// @Tags actor
+// @Description Get actor with filmography. Supports conditional requests via If-None-Match
+// @ID get-actor
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Actor ID"
+// @Param If-None-Match header string false "ETag from previous response"
+// @Success 200 {object} model.ActorWithFilms
+// @Success 304
+// @Failure 400,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/actors/{id} [get]
+func (h *ActorHandler) GetActor(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_actor_id", http.StatusBadRequest)
+		return
+	}
+
+	actor, err := h.service.GetActorByID(r.Context(), id)
+	if err != nil {
+		response.WriteError(w, r, err, "get_actor_failed")
+		return
+	}
+
+	response.WriteJSONVersioned(w, r, actor, actor.Actor.Version)
+}
+
 // @Summary Update Actor
 // @Security ApiKeyAuth
 // @Tags actor
@@ -98,38 +92,93 @@ func (h *ActorHandler) CreateActor(w http.ResponseWriter, r *http.Request) {
 // @ID update-actor
 // @Accept  json
 // @Produce  json
+// @Param id path int true "Actor ID"
+// @Param If-Match header string true "ETag from GET; * skips the version check"
 // @Param actor body model.Actor true "Update Actor"
 // @Success 201 {object} model.Actor
-// @Failure 400,403 {object} response.ErrorResponse
-// @Failure 500 {object} response.ErrorResponse
-// @Failure default {object} response.ErrorResponse
-// @Router /actor_update [put]
+// @Failure 400,403,404,409,412,428 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/actors/{id} [put]
 func (h *ActorHandler) UpdateActor(w http.ResponseWriter, r *http.Request) {
-	if authmid.IsAdmin(r) {
-		response.WriteJSONError(w, "Forbidden: admin access required", http.StatusForbidden)
+	var actor model.Actor
+	if err := json.NewDecoder(r.Body).Decode(&actor); err != nil {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
 		return
 	}
 
-	var actor model.Actor
-	if err := json.NewDecoder(r.Body).Decode(&actor); err != nil {
-		response.WriteJSONError(w, "Invalid request body", http.StatusBadRequest)
+	// Новый маршрут передаёт id в пути, старый /actor_update — в теле запроса
+	if r.PathValue("id") != "" {
+		id, err := pathID(r, "id")
+		if err != nil {
+			response.WriteJSONError(w, r, "invalid_actor_id", http.StatusBadRequest)
+			return
+		}
+		actor.Id = id
+	}
+
+	version, ok := ifMatch(w, r)
+	if !ok {
 		return
 	}
+	actor.Version = version
 
 	if err := actor.Validate(); err != nil {
-		response.WriteJSONError(w, fmt.Sprintf("%v", err.Error()), http.StatusBadRequest)
+		response.WriteError(w, r, err, "invalid_request_body")
 		return
 	}
 
-	err := h.service.UpdateActor(r.Context(), actor)
+	updated, err := h.service.UpdateActor(r.Context(), actor)
 	if err != nil {
-		response.WriteJSONError(w, "Failed to update actor", http.StatusInternalServerError)
+		response.WriteError(w, r, err, "update_actor_failed")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(actor)
+	response.WriteJSONVersion(w, r, http.StatusCreated, updated, updated.Version)
+}
+
+// @Summary Patch Actor
+// @Security ApiKeyAuth
+// @Tags actor
+// @Description Partially update actor with JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902).
+// @Description The patched actor is validated as a whole; only changed columns are written
+// @ID patch-actor
+// @Accept  application/merge-patch+json,application/json-patch+json,json
+// @Produce  json
+// @Param id path int true "Actor ID"
+// @Param If-Match header string true "ETag from GET; * skips the version check"
+// @Param patch body object true "Merge patch object or array of JSON Patch operations"
+// @Success 200 {object} model.Actor
+// @Failure 400,403,404,409,412,415,428 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/actors/{id} [patch]
+func (h *ActorHandler) PatchActor(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_actor_id", http.StatusBadRequest)
+		return
+	}
+
+	version, ok := ifMatch(w, r)
+	if !ok {
+		return
+	}
+
+	patch, ok := readPatch(w, r)
+	if !ok {
+		return
+	}
+
+	actor, err := h.service.PatchActor(r.Context(), id, version, patch)
+	if err != nil {
+		response.WriteError(w, r, err, "update_actor_failed")
+		return
+	}
+
+	response.WriteJSONVersion(w, r, http.StatusOK, actor, actor.Version)
 }
 
 // @Summary Delete Actor
@@ -139,34 +188,28 @@ func (h *ActorHandler) UpdateActor(w http.ResponseWriter, r *http.Request) {
 // @ID delete-actor
 // @Accept  json
 // @Produce  json
-// @Param id query int true "Actor ID"
+// @Param id path int true "Actor ID"
+// @Param If-Match header string true "ETag from GET; * skips the version check"
 // @Success 200 {object} map[string]string
-// @Failure 400,403 {object} response.ErrorResponse
-// @Failure 500 {object} response.ErrorResponse
-// @Failure default {object} response.ErrorResponse
-// @Router /actor_delete/{id} [delete]
+// @Failure 400,403,404,412,428 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/actors/{id} [delete]
 func (h *ActorHandler) DeleteActor(w http.ResponseWriter, r *http.Request) {
-	if authmid.IsAdmin(r) {
-		response.WriteJSONError(w, "Forbidden: admin access required", http.StatusForbidden)
-		return
-	}
-
-	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
-	if len(parts) < 2 {
-		response.WriteJSONError(w, "Missing actor ID", http.StatusBadRequest)
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_actor_id", http.StatusBadRequest)
 		return
 	}
 
-	idStr := parts[len(parts)-1]
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		response.WriteJSONError(w, "Invalid actor ID", http.StatusBadRequest)
+	version, ok := ifMatch(w, r)
+	if !ok {
 		return
 	}
 
-	err = h.service.DeleteActor(r.Context(), id)
+	err = h.service.DeleteActor(r.Context(), id, version)
 	if err != nil {
-		response.WriteJSONError(w, "Failed to delete actor", http.StatusInternalServerError)
+		response.WriteError(w, r, err, "delete_actor_failed")
 		return
 	}
 
@@ -2,11 +2,13 @@ package handler
 
 import (
 	"encoding/json"
+	"film-library/internal/domain"
 	"film-library/internal/model"
 	"film-library/internal/service"
 	"film-library/internal/utils/response"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 type ActorMovieHandler struct {
@@ -17,47 +19,122 @@ func NewActorMovieHandler(service service.ActorMovie) ActorMovieHandler {
 	return ActorMovieHandler{service: service}
 }
 
-func (h *ActorMovieHandler) HandleActorMovieGet(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		h.GetActorMovies(w, r)
+// @Summary Get Actors
+// @Security ApiKeyAuth
+// @Tags actor_movie
+// @Description Get a page of actors with film counts, including actors without films
+// @ID get-actors
+// @Accept  json
+// @Produce  json
+// @Param sort_by query string false "Sort field: name (default), date_of_birth, film_count"
+// @Param order query string false "Sort order: asc or desc (film_count defaults to desc)"
+// @Param gender query string false "male or female"
+// @Param min_age query int false "Minimum age in full years"
+// @Param max_age query int false "Maximum age in full years"
+// @Param name query string false "Actor name prefix"
+// @Param include query string false "films - embed each actor's films"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Param cursor query string false "Cursor from next_cursor or prev_cursor"
+// @Success 200 {object} model.ActorPage
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/actors [get]
+func (h *ActorMovieHandler) GetActors(w http.ResponseWriter, r *http.Request) {
+	params, err := parseActorListParams(r)
+	if err != nil {
+		response.WriteError(w, r, err, "get_actors_failed")
+		return
+	}
+
+	if err := params.Validate(); err != nil {
+		response.WriteError(w, r, err, "get_actors_failed")
+		return
+	}
+
+	page, err := h.service.GetActors(r.Context(), params)
+	if err != nil {
+		response.WriteError(w, r, err, "get_actors_failed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(page)
+}
+
+// parseActorListParams разбирает query-параметры списка актёров
+func parseActorListParams(r *http.Request) (model.ActorListParams, error) {
+	q := r.URL.Query()
+
+	params := model.ActorListParams{
+		SortBy:     q.Get("sort_by"),
+		Order:      q.Get("order"),
+		Gender:     q.Get("gender"),
+		NamePrefix: strings.TrimSpace(q.Get("name")),
+	}
+
+	switch include := q.Get("include"); include {
+	case "":
+	case "films":
+		params.IncludeFilms = true
 	default:
-		response.WriteJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return params, domain.BadRequest("invalid_include", fmt.Sprintf("неподдерживаемое значение include %q", include)).WithArgs(include)
+	}
+
+	var err error
+	if params.Limit, err = queryInt(q, "limit", model.DefaultPageLimit); err != nil {
+		return params, err
+	}
+	if params.Offset, err = queryInt(q, "offset", 0); err != nil {
+		return params, err
+	}
+	if params.MinAge, err = queryOptionalInt(q, "min_age"); err != nil {
+		return params, err
 	}
+	if params.MaxAge, err = queryOptionalInt(q, "max_age"); err != nil {
+		return params, err
+	}
+
+	if c := q.Get("cursor"); c != "" {
+		cursor, err := model.DecodeCursor(c)
+		if err != nil {
+			return params, err
+		}
+		params.Cursor = &cursor
+	}
+
+	return params, nil
 }
 
-// @Summary Get Actors with Their Films
+// @Summary Get Actor Films
 // @Security ApiKeyAuth
 // @Tags actor_movie
-// @Description Get list of all actors with their films
-// @ID get-actors-with-films
+// @Description Get list of films the actor played in
+// @ID get-actor-films
 // @Accept  json
 // @Produce  json
-// @Success 200 {array} model.ActorWithFilms
-// @Failure 400 {object} response.ErrorResponse
-// @Failure 500 {object} response.ErrorResponse
-// @Failure default {object} response.ErrorResponse
-// @Router /get_list_actors_films [get]
-func (h *ActorMovieHandler) GetActorMovies(w http.ResponseWriter, r *http.Request) {
-	if err := model.ValidateGetActors(); err != nil {
-		response.WriteJSONError(w, fmt.Sprintf("%v", err.Error()), http.StatusBadRequest)
+// @Param id path int true "Actor ID"
+// @Success 200 {array} model.Film
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/actors/{id}/films [get]
+func (h *ActorMovieHandler) GetActorFilms(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_actor_id", http.StatusBadRequest)
 		return
 	}
 
-	actorsMap, err := h.service.GetAllActorWithFilms(r.Context())
+	films, err := h.service.GetActorFilms(r.Context(), id)
 	if err != nil {
-		response.WriteJSONError(w, fmt.Sprintf("%v", err.Error()), http.StatusInternalServerError)
+		response.WriteError(w, r, err, "get_actor_films_failed")
 		return
 	}
 
-	// Преобразование map[int]model.ActorWithFilms → []model.ActorWithFilms
-	actors := make([]model.ActorWithFilms, 0, len(actorsMap))
-	for _, v := range actorsMap {
-		actors = append(actors, v)
-	}
-
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(actors)
-
+	json.NewEncoder(w).Encode(films)
 }
@@ -8,16 +8,21 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
 
-func TestHandler_GetActorMovies(t *testing.T) {
+func TestHandler_GetActors(t *testing.T) {
 	type mockBehavior func(r *mock_service.MockActorMovie)
 
+	minAge, maxAge := 30, 60
+	born := time.Date(1964, 9, 2, 0, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name                 string
+		query                string
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
 		expectedResponseBody string
@@ -25,23 +30,70 @@ func TestHandler_GetActorMovies(t *testing.T) {
 		{
 			name: "OK",
 			mockBehavior: func(r *mock_service.MockActorMovie) {
-				// Возвращаем мапу, а не слайс!
-				r.EXPECT().GetAllActorWithFilms(gomock.Any()).Return(
-					map[int]model.ActorWithFilms{}, nil)
+				r.EXPECT().GetActors(gomock.Any(), model.ActorListParams{Limit: model.DefaultPageLimit}).Return(
+					model.ActorPage{Items: []model.ActorListItem{}, Limit: model.DefaultPageLimit}, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `[]`, // Хэндлер преобразует мапу в массив
+			expectedResponseBody: `{"items": [], "total": 0, "limit": 20, "offset": 0, "next_cursor": "", "prev_cursor": ""}`,
+		},
+		{
+			name:  "Filters And Films",
+			query: "?sort_by=film_count&gender=male&min_age=30&max_age=60&name=Kea&include=films&limit=2",
+			mockBehavior: func(r *mock_service.MockActorMovie) {
+				r.EXPECT().GetActors(gomock.Any(), model.ActorListParams{
+					SortBy: "film_count", Gender: "male", MinAge: &minAge, MaxAge: &maxAge, NamePrefix: "Kea",
+					IncludeFilms: true, Limit: 2,
+				}).Return(model.ActorPage{
+					Items: []model.ActorListItem{
+						{
+							Actor:     model.Actor{Id: 7, Name: "Keanu Reeves", Gender: "male", DateOfBirth: born},
+							FilmCount: 1,
+							Films:     []model.Film{{Id: 1, Name: "Matrix", Rating: 8.7, Releasedate: time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC)}},
+						},
+						{
+							Actor: model.Actor{Id: 9, Name: "Keanu Newcomer", Gender: "male", DateOfBirth: born},
+							Films: []model.Film{},
+						},
+					},
+					Total: 2,
+					Limit: 2,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"items": [
+				{"id": 7, "name": "Keanu Reeves", "gender": "male", "date_of_birth": "1964-09-02T00:00:00Z", "film_count": 1,
+				 "films": [{"id": 1, "name": "Matrix", "description": "", "release_date": "1999-03-31T00:00:00Z", "rating": 8.7, "list_actors": null}]},
+				{"id": 9, "name": "Keanu Newcomer", "gender": "male", "date_of_birth": "1964-09-02T00:00:00Z", "film_count": 0, "films": []}
+			], "total": 2, "limit": 2, "offset": 0, "next_cursor": "", "prev_cursor": ""}`,
+		},
+		{
+			name:                 "Invalid Sort",
+			query:                "?sort_by=rating",
+			mockBehavior:         func(r *mock_service.MockActorMovie) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Invalid sort field", "code": "invalid_sort"}`,
+		},
+		{
+			name:                 "Inverted Age Range",
+			query:                "?min_age=60&max_age=30",
+			mockBehavior:         func(r *mock_service.MockActorMovie) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Minimum age is greater than maximum age", "code": "age_range_inverted"}`,
+		},
+		{
+			name:                 "Invalid Include",
+			query:                "?include=awards",
+			mockBehavior:         func(r *mock_service.MockActorMovie) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Unsupported include \"awards\", only films is available", "code": "invalid_include"}`,
 		},
 		{
 			name: "Service Error",
 			mockBehavior: func(r *mock_service.MockActorMovie) {
-				r.EXPECT().GetAllActorWithFilms(gomock.Any()).Return(
-					nil,
-					errors.New("error"),
-				)
+				r.EXPECT().GetActors(gomock.Any(), gomock.Any()).Return(model.ActorPage{}, errors.New("error"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"message":"error"}`,
+			expectedResponseBody: `{"status": 500, "message": "Failed to get actors", "code": "internal_error"}`,
 		},
 	}
 
@@ -56,11 +108,72 @@ func TestHandler_GetActorMovies(t *testing.T) {
 			services := &service.Service{ActorMovie: auth}
 			handler := NewActorMovieHandler(services)
 
-			req := httptest.NewRequest(http.MethodGet, "/actors_films", nil)
-			req.Header.Set("Content-Type", "application/json")
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/actors"+tc.query, nil)
+
+			rr := httptest.NewRecorder()
+			handler.GetActors(rr, req)
+
+			require.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetActorFilms(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockActorMovie, id int)
+
+	tests := []struct {
+		name                 string
+		pathParam            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			pathParam: "1",
+			mockBehavior: func(r *mock_service.MockActorMovie, id int) {
+				r.EXPECT().GetActorFilms(gomock.Any(), id).Return([]model.Film{
+					{Id: 2, Name: "name", Description: "description", Releasedate: parseTime("2004-04-10T21:12:05+03:00"), Rating: 9.3},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `[{"id": 2, "name": "name", "description": "description", "release_date": "2004-04-10T21:12:05+03:00", "rating": 9.3, "list_actors": null}]`,
+		},
+		{
+			name:                 "Wrong input ID",
+			pathParam:            "first",
+			mockBehavior:         func(r *mock_service.MockActorMovie, id int) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Invalid actor ID", "code": "invalid_actor_id"}`,
+		},
+		{
+			name:      "Service Error",
+			pathParam: "1",
+			mockBehavior: func(r *mock_service.MockActorMovie, id int) {
+				r.EXPECT().GetActorFilms(gomock.Any(), id).Return(nil, errors.New("error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"status": 500, "message": "Failed to get actor films", "code": "internal_error"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockActorMovie(c)
+			tc.mockBehavior(auth, 1)
+
+			services := &service.Service{ActorMovie: auth}
+			handler := NewActorMovieHandler(services)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/actors/"+tc.pathParam+"/films", nil)
+			req.SetPathValue("id", tc.pathParam)
 
 			rr := httptest.NewRecorder()
-			handler.GetActorMovies(rr, req)
+			handler.GetActorFilms(rr, req)
 
 			require.Equal(t, tc.expectedStatusCode, rr.Code)
 			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
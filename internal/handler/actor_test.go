@@ -3,6 +3,7 @@ package handler
 import (
 	"bytes"
 	"errors"
+	"film-library/internal/domain"
 	"film-library/internal/model"
 	"film-library/internal/service"
 	mock_service "film-library/internal/service/mocks"
@@ -50,8 +51,8 @@ func TestHandler_ActorCreate(t *testing.T) {
 				DateOfBirth: parseTime("2004-04-10T21:12:05+03:00"),
 			},
 			mockBehavior:         func(r *mock_service.MockActor, actor model.Actor) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"message": "имя актера не может быть пустым и не должно превышать 100 символов"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/actors", "code": "validation_failed", "errors": [{"field": "name", "rule": "required", "message": "is required"}]}`,
 		},
 
 		{
@@ -63,8 +64,8 @@ func TestHandler_ActorCreate(t *testing.T) {
 				DateOfBirth: parseTime("2004-04-10T21:12:05+03:00"),
 			},
 			mockBehavior:         func(r *mock_service.MockActor, actor model.Actor) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"message": "не указан пол"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/actors", "code": "validation_failed", "errors": [{"field": "gender", "rule": "required", "message": "is required"}]}`,
 		},
 
 		{
@@ -76,8 +77,8 @@ func TestHandler_ActorCreate(t *testing.T) {
 				DateOfBirth: parseTime("2025-04-10T21:12:05+03:00"),
 			},
 			mockBehavior:         func(r *mock_service.MockActor, actor model.Actor) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"message": "актёр должен быть старше 5 лет"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/actors", "code": "validation_failed", "errors": [{"field": "date_of_birth", "rule": "min_age", "params": ["5"], "message": "at least 5 years must have passed since this date"}]}`,
 		},
 
 		{
@@ -92,7 +93,7 @@ func TestHandler_ActorCreate(t *testing.T) {
 				r.EXPECT().AddActor(gomock.Any(), actor).Return(errors.New("Failed to create actor"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"message": "Failed to create actor"}`,
+			expectedResponseBody: `{"status": 500, "message": "Failed to create actor", "code": "internal_error"}`,
 		},
 	}
 
@@ -139,7 +140,7 @@ func TestHandler_UpdateActor(t *testing.T) {
 				DateOfBirth: parseTime("2004-04-10T21:12:05+03:00"),
 			},
 			mockBehavior: func(r *mock_service.MockActor, actor model.Actor) {
-				r.EXPECT().UpdateActor(gomock.Any(), actor).Return(nil)
+				r.EXPECT().UpdateActor(gomock.Any(), actor).Return(actor, nil)
 			},
 			expectedStatusCode:   http.StatusCreated,
 			expectedResponseBody: `{"id": 0, "name": "name", "gender": "male", "date_of_birth": "2004-04-10T21:12:05+03:00"}`,
@@ -154,8 +155,8 @@ func TestHandler_UpdateActor(t *testing.T) {
 				DateOfBirth: parseTime("2004-04-10T21:12:05+03:00"),
 			},
 			mockBehavior:         func(r *mock_service.MockActor, actor model.Actor) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"message": "имя актера не может быть пустым и не должно превышать 100 символов"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/actor/", "code": "validation_failed", "errors": [{"field": "name", "rule": "required", "message": "is required"}]}`,
 		},
 
 		{
@@ -167,8 +168,8 @@ func TestHandler_UpdateActor(t *testing.T) {
 				DateOfBirth: parseTime("2004-04-10T21:12:05+03:00"),
 			},
 			mockBehavior:         func(r *mock_service.MockActor, actor model.Actor) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"message": "не указан пол"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/actor/", "code": "validation_failed", "errors": [{"field": "gender", "rule": "required", "message": "is required"}]}`,
 		},
 
 		{
@@ -180,8 +181,8 @@ func TestHandler_UpdateActor(t *testing.T) {
 				DateOfBirth: parseTime("2025-04-10T21:12:05+03:00"),
 			},
 			mockBehavior:         func(r *mock_service.MockActor, actor model.Actor) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"message": "актёр должен быть старше 5 лет"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/actor/", "code": "validation_failed", "errors": [{"field": "date_of_birth", "rule": "min_age", "params": ["5"], "message": "at least 5 years must have passed since this date"}]}`,
 		},
 
 		{
@@ -193,10 +194,10 @@ func TestHandler_UpdateActor(t *testing.T) {
 				DateOfBirth: parseTime("2004-04-10T21:12:05+03:00"),
 			},
 			mockBehavior: func(r *mock_service.MockActor, actor model.Actor) {
-				r.EXPECT().UpdateActor(gomock.Any(), actor).Return(errors.New("Failed to update actor"))
+				r.EXPECT().UpdateActor(gomock.Any(), actor).Return(model.Actor{}, errors.New("Failed to update actor"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"message": "Failed to update actor"}`,
+			expectedResponseBody: `{"status": 500, "message": "Failed to update actor", "code": "internal_error"}`,
 		},
 	}
 
@@ -206,6 +207,7 @@ func TestHandler_UpdateActor(t *testing.T) {
 			defer c.Finish()
 
 			auth := mock_service.NewMockActor(c)
+			tc.inputUser.Version = 3 // из If-Match
 			tc.mockBehavior(auth, tc.inputUser)
 
 			services := &service.Service{Actor: auth}
@@ -213,6 +215,7 @@ func TestHandler_UpdateActor(t *testing.T) {
 
 			req := httptest.NewRequest(http.MethodPut, "/actor/", bytes.NewBufferString(tc.inputBody))
 			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-Match", `"3-0123456789abcdef"`)
 
 			rr := httptest.NewRecorder()
 			handler.UpdateActor(rr, req)
@@ -223,6 +226,129 @@ func TestHandler_UpdateActor(t *testing.T) {
 	}
 }
 
+func TestHandler_PatchActor(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockActor, patch model.Patch)
+
+	tests := []struct {
+		name                 string
+		pathParam            string
+		contentType          string
+		inputBody            string
+		inputPatch           model.Patch
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Merge Patch",
+			pathParam:   "1",
+			contentType: "application/merge-patch+json",
+			inputBody:   `{"name": "new name"}`,
+			inputPatch:  model.Patch{ContentType: "application/merge-patch+json", Body: []byte(`{"name": "new name"}`)},
+			mockBehavior: func(r *mock_service.MockActor, patch model.Patch) {
+				r.EXPECT().PatchActor(gomock.Any(), 1, 1, patch).
+					Return(model.Actor{Id: 1, Name: "new name", Gender: "male", DateOfBirth: parseTime("2004-04-10T21:12:05+03:00")}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"id": 1, "name": "new name", "gender": "male", "date_of_birth": "2004-04-10T21:12:05+03:00"}`,
+		},
+		{
+			name:        "Plain JSON Is Merge Patch",
+			pathParam:   "1",
+			contentType: "application/json; charset=utf-8",
+			inputBody:   `{"gender": "female"}`,
+			inputPatch:  model.Patch{ContentType: "application/merge-patch+json", Body: []byte(`{"gender": "female"}`)},
+			mockBehavior: func(r *mock_service.MockActor, patch model.Patch) {
+				r.EXPECT().PatchActor(gomock.Any(), 1, 1, patch).Return(model.Actor{Id: 1, Name: "name", Gender: "female"}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"id": 1, "name": "name", "gender": "female", "date_of_birth": "0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:        "JSON Patch Test Failed",
+			pathParam:   "1",
+			contentType: "application/json-patch+json",
+			inputBody:   `[{"op": "test", "path": "/name", "value": "old"}]`,
+			inputPatch:  model.Patch{ContentType: "application/json-patch+json", Body: []byte(`[{"op": "test", "path": "/name", "value": "old"}]`)},
+			mockBehavior: func(r *mock_service.MockActor, patch model.Patch) {
+				r.EXPECT().PatchActor(gomock.Any(), 1, 1, patch).
+					Return(model.Actor{}, domain.Conflict("patch_conflict", "патч не применим к текущему состоянию"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"status": 409, "message": "Patch cannot be applied to the current state of the resource", "code": "patch_conflict"}`,
+		},
+		{
+			name:        "Read Only Field",
+			pathParam:   "1",
+			contentType: "application/merge-patch+json",
+			inputBody:   `{"id": 2}`,
+			inputPatch:  model.Patch{ContentType: "application/merge-patch+json", Body: []byte(`{"id": 2}`)},
+			mockBehavior: func(r *mock_service.MockActor, patch model.Patch) {
+				r.EXPECT().PatchActor(gomock.Any(), 1, 1, patch).Return(model.Actor{}, model.ReadOnlyField("id"))
+			},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"status": 422, "message": "Field id cannot be changed", "code": "read_only_field"}`,
+		},
+		{
+			name:                 "Unsupported Media Type",
+			pathParam:            "1",
+			contentType:          "text/plain",
+			inputBody:            `name=new`,
+			mockBehavior:         func(r *mock_service.MockActor, patch model.Patch) {},
+			expectedStatusCode:   http.StatusUnsupportedMediaType,
+			expectedResponseBody: `{"status": 415, "message": "Unsupported Content-Type, use application/merge-patch+json or application/json-patch+json", "code": "unsupported_media_type"}`,
+		},
+		{
+			name:                 "Wrong ID",
+			pathParam:            "abc",
+			contentType:          "application/merge-patch+json",
+			inputBody:            `{"name": "new name"}`,
+			mockBehavior:         func(r *mock_service.MockActor, patch model.Patch) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Invalid actor ID", "code": "invalid_actor_id"}`,
+		},
+		{
+			name:        "Not Found",
+			pathParam:   "1",
+			contentType: "application/merge-patch+json",
+			inputBody:   `{"name": "new name"}`,
+			inputPatch:  model.Patch{ContentType: "application/merge-patch+json", Body: []byte(`{"name": "new name"}`)},
+			mockBehavior: func(r *mock_service.MockActor, patch model.Patch) {
+				r.EXPECT().PatchActor(gomock.Any(), 1, 1, patch).Return(model.Actor{}, domain.NotFound("actor_not_found", "актёр не найден"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"status": 404, "message": "Actor not found", "code": "actor_not_found"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockActor(c)
+			tc.mockBehavior(auth, tc.inputPatch)
+
+			services := &service.Service{Actor: auth}
+			handler := NewActorHandler(services)
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/actors/"+tc.pathParam, bytes.NewBufferString(tc.inputBody))
+			req.SetPathValue("id", tc.pathParam)
+			req.Header.Set("Content-Type", tc.contentType)
+			req.Header.Set("If-Match", `"1"`)
+
+			rr := httptest.NewRecorder()
+			handler.PatchActor(rr, req)
+
+			require.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			if tc.expectedStatusCode == http.StatusUnsupportedMediaType {
+				require.Equal(t, "application/merge-patch+json, application/json-patch+json", rr.Header().Get("Accept-Patch"))
+			}
+		})
+	}
+}
+
 func TestHandler_DeleteActor(t *testing.T) {
 	type mockBehavior func(r *mock_service.MockActor, id int)
 
@@ -237,7 +363,7 @@ func TestHandler_DeleteActor(t *testing.T) {
 			name:       "Ok",
 			queryParam: `0`,
 			mockBehavior: func(r *mock_service.MockActor, id int) {
-				r.EXPECT().DeleteActor(gomock.Any(), id).Return(nil)
+				r.EXPECT().DeleteActor(gomock.Any(), id, 2).Return(nil)
 			},
 			expectedStatusCode:   http.StatusOK,
 			expectedResponseBody: `{"message": "actor deleted successfully"}`,
@@ -247,16 +373,16 @@ func TestHandler_DeleteActor(t *testing.T) {
 			queryParam:           `first`,
 			mockBehavior:         func(r *mock_service.MockActor, id int) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"message": "Invalid actor ID"}`,
+			expectedResponseBody: `{"status": 400, "message": "Invalid actor ID", "code": "invalid_actor_id"}`,
 		},
 		{
 			name:       "Error",
 			queryParam: `0`,
 			mockBehavior: func(r *mock_service.MockActor, id int) {
-				r.EXPECT().DeleteActor(gomock.Any(), id).Return(errors.New("Failed to delete actor"))
+				r.EXPECT().DeleteActor(gomock.Any(), id, 2).Return(errors.New("Failed to delete actor"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"message": "Failed to delete actor"}`,
+			expectedResponseBody: `{"status": 500, "message": "Failed to delete actor", "code": "internal_error"}`,
 		},
 	}
 
@@ -271,7 +397,9 @@ func TestHandler_DeleteActor(t *testing.T) {
 			services := &service.Service{Actor: auth}
 			handler := NewActorHandler(services)
 
-			req := httptest.NewRequest(http.MethodDelete, "/actor_del/"+tc.queryParam, nil)
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/actors/"+tc.queryParam, nil)
+			req.Header.Set("If-Match", `"2"`)
+			req.SetPathValue("id", tc.queryParam)
 			req.Header.Set("Content-Type", "application/json")
 
 			rr := httptest.NewRecorder()
@@ -283,6 +411,82 @@ func TestHandler_DeleteActor(t *testing.T) {
 	}
 }
 
+func TestHandler_GetActor(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockActor, id int)
+
+	tests := []struct {
+		name                 string
+		pathParam            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			pathParam: "1",
+			mockBehavior: func(r *mock_service.MockActor, id int) {
+				r.EXPECT().GetActorByID(gomock.Any(), id).Return(model.ActorWithFilms{
+					Actor: model.Actor{Id: 1, Name: "name", Gender: "male", DateOfBirth: parseTime("1980-01-01T00:00:00Z")},
+					Films: []model.Film{{Id: 2, Name: "film", Releasedate: parseTime("2004-04-10T21:12:05+03:00"), Rating: 9.3}},
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"actor": {"id": 1, "name": "name", "gender": "male", "date_of_birth": "1980-01-01T00:00:00Z"},
+				"films": [{"id": 2, "name": "film", "description": "", "release_date": "2004-04-10T21:12:05+03:00", "rating": 9.3, "list_actors": null}]}`,
+		},
+		{
+			name:                 "Wrong input ID",
+			pathParam:            "first",
+			mockBehavior:         func(r *mock_service.MockActor, id int) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Invalid actor ID", "code": "invalid_actor_id"}`,
+		},
+		{
+			name:      "Not Found",
+			pathParam: "1",
+			mockBehavior: func(r *mock_service.MockActor, id int) {
+				r.EXPECT().GetActorByID(gomock.Any(), id).Return(model.ActorWithFilms{}, domain.NotFound("actor_not_found", "актёр не найден"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"status": 404, "message": "Actor not found", "code": "actor_not_found"}`,
+		},
+		{
+			name:      "Service Error",
+			pathParam: "1",
+			mockBehavior: func(r *mock_service.MockActor, id int) {
+				r.EXPECT().GetActorByID(gomock.Any(), id).Return(model.ActorWithFilms{}, errors.New("error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"status": 500, "message": "Failed to get actor", "code": "internal_error"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockActor(c)
+			tc.mockBehavior(auth, 1)
+
+			services := &service.Service{Actor: auth}
+			handler := NewActorHandler(services)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/actors/"+tc.pathParam, nil)
+			req.SetPathValue("id", tc.pathParam)
+
+			rr := httptest.NewRecorder()
+			handler.GetActor(rr, req)
+
+			require.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+			if tc.expectedStatusCode == http.StatusOK {
+				require.NotEmpty(t, rr.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
 func parseTime(timeStr string) time.Time {
 	t, err := time.Parse(time.RFC3339, timeStr)
 	if err != nil {
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"film-library/internal/model"
+	"film-library/internal/service"
+	authmid "film-library/internal/utils/auth_mid"
+	"film-library/internal/utils/response"
+	"film-library/internal/utils/validate"
+	"net/http"
+)
+
+type AdminHandler struct {
+	service service.Access
+}
+
+func NewAdminHandler(service service.Access) AdminHandler {
+	return AdminHandler{service: service}
+}
+
+// @Summary List Roles
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Get roles with their permission matrix
+// @ID list-roles
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} model.Role
+// @Failure 401,403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/admin/roles [get]
+func (h *AdminHandler) GetRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.service.GetRoles(r.Context())
+	if err != nil {
+		response.WriteError(w, r, err, "get_roles_failed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(roles)
+}
+
+// @Summary Assign Role
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Assign role to user. New role applies to tokens issued after the change
+// @ID assign-role
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User ID"
+// @Param role body model.AssignRoleRequest true "Role name: user, editor or admin"
+// @Success 200 {object} model.UserResponse
+// @Failure 400,401,403,404,422 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/admin/users/{id}/role [put]
+func (h *AdminHandler) AssignRole(w http.ResponseWriter, r *http.Request) {
+	userID, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_user_id", http.StatusBadRequest)
+		return
+	}
+
+	// Администратор не может снять права сам с себя и остаться без доступа к админке
+	if currentID, ok := authmid.UserID(r.Context()); ok && currentID == userID {
+		response.WriteJSONError(w, r, "cannot_change_own_role", http.StatusBadRequest)
+		return
+	}
+
+	var req model.AssignRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
+		return
+	}
+
+	user, err := h.service.AssignRole(r.Context(), userID, req.Role)
+	if err != nil {
+		response.WriteError(w, r, err, "assign_role_failed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(user)
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	mock_service "film-library/internal/service/mocks"
+	authmid "film-library/internal/utils/auth_mid"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_GetRoles(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	access := mock_service.NewMockAccess(c)
+	access.EXPECT().GetRoles(gomock.Any()).Return([]model.Role{
+		{ID: 1, Name: "user", Permissions: []model.Permission{
+			{Resource: model.ResourceFilms, Actions: []string{model.ActionRead}},
+		}},
+	}, nil)
+
+	handler := NewAdminHandler(access)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/roles", nil)
+
+	rr := httptest.NewRecorder()
+	handler.GetRoles(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `[{"id": 1, "name": "user", "permissions": [{"resource": "films", "actions": ["read"]}]}]`, rr.Body.String())
+}
+
+func TestHandler_AssignRole(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockAccess, userID int, role string)
+
+	tests := []struct {
+		name                 string
+		pathParam            string
+		inputBody            string
+		inputRole            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			pathParam: "2",
+			inputBody: `{"role": "editor"}`,
+			inputRole: "editor",
+			mockBehavior: func(r *mock_service.MockAccess, userID int, role string) {
+				r.EXPECT().AssignRole(gomock.Any(), userID, role).
+					Return(model.UserResponse{ID: 2, Username: "username", Role: int(model.RoleEditor)}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"id": 2, "username": "username", "role": 3}`,
+		},
+		{
+			name:                 "Own Role",
+			pathParam:            "1",
+			inputBody:            `{"role": "user"}`,
+			mockBehavior:         func(r *mock_service.MockAccess, userID int, role string) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Cannot change own role", "code": "cannot_change_own_role"}`,
+		},
+		{
+			name:                 "Empty Role",
+			pathParam:            "2",
+			inputBody:            `{}`,
+			mockBehavior:         func(r *mock_service.MockAccess, userID int, role string) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/api/v1/admin/users/2/role", "code": "validation_failed", "errors": [{"field": "role", "rule": "required", "message": "is required"}]}`,
+		},
+		{
+			name:      "Unknown Role",
+			pathParam: "2",
+			inputBody: `{"role": "owner"}`,
+			inputRole: "owner",
+			mockBehavior: func(r *mock_service.MockAccess, userID int, role string) {
+				r.EXPECT().AssignRole(gomock.Any(), userID, role).
+					Return(model.UserResponse{}, domain.Validation("unknown_role", `неизвестная роль "owner"`).WithArgs("owner"))
+			},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"status": 422, "message": "Unknown role \"owner\"", "code": "unknown_role"}`,
+		},
+		{
+			name:      "User Not Found",
+			pathParam: "2",
+			inputBody: `{"role": "editor"}`,
+			inputRole: "editor",
+			mockBehavior: func(r *mock_service.MockAccess, userID int, role string) {
+				r.EXPECT().AssignRole(gomock.Any(), userID, role).
+					Return(model.UserResponse{}, domain.NotFound("user_not_found", "пользователь не найден"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"status": 404, "message": "User not found", "code": "user_not_found"}`,
+		},
+		{
+			name:      "Service Error",
+			pathParam: "2",
+			inputBody: `{"role": "editor"}`,
+			inputRole: "editor",
+			mockBehavior: func(r *mock_service.MockAccess, userID int, role string) {
+				r.EXPECT().AssignRole(gomock.Any(), userID, role).Return(model.UserResponse{}, errors.New("error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"status": 500, "message": "Failed to assign role", "code": "internal_error"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			access := mock_service.NewMockAccess(c)
+			tc.mockBehavior(access, 2, tc.inputRole)
+
+			handler := NewAdminHandler(access)
+
+			// Запрос выполняет администратор с id 1
+			ctx := authmid.WithUser(context.Background(), 1, int(model.RoleAdmin))
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/users/"+tc.pathParam+"/role", bytes.NewBufferString(tc.inputBody)).WithContext(ctx)
+			req.SetPathValue("id", tc.pathParam)
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			handler.AssignRole(rr, req)
+
+			require.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"film-library/internal/model"
+	"film-library/internal/service"
+	"film-library/internal/utils/response"
+	"net/http"
+)
+
+type AuditHandler struct {
+	service service.Audit
+}
+
+func NewAuditHandler(service service.Audit) AuditHandler {
+	return AuditHandler{service: service}
+}
+
+// @Summary Audit Log
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Get catalog changes, newest first. Each entry holds the author, action and changed fields before and after
+// @ID get-audit-log
+// @Accept  json
+// @Produce  json
+// @Param user_id query int false "Only changes made by this user"
+// @Param entity query string false "Entity: film, actor, genre, country or tag"
+// @Param entity_id query int false "Entity ID, requires entity"
+// @Param entity_code query string false "Country code, requires entity"
+// @Param from query string false "Period start, RFC 3339 or YYYY-MM-DD"
+// @Param to query string false "Period end (exclusive), RFC 3339 or YYYY-MM-DD"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} model.AuditPage
+// @Failure 400,401,403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/admin/audit [get]
+func (h *AuditHandler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		response.WriteError(w, r, err, "get_audit_log_failed")
+		return
+	}
+
+	if err := filter.Validate(); err != nil {
+		response.WriteError(w, r, err, "get_audit_log_failed")
+		return
+	}
+
+	page, err := h.service.GetAuditLog(r.Context(), filter)
+	if err != nil {
+		response.WriteError(w, r, err, "get_audit_log_failed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(page)
+}
+
+// parseAuditFilter разбирает query-параметры журнала изменений
+func parseAuditFilter(r *http.Request) (model.AuditFilter, error) {
+	q := r.URL.Query()
+
+	filter := model.AuditFilter{Entity: q.Get("entity"), EntityCode: q.Get("entity_code")}
+
+	var err error
+	if filter.UserID, err = queryInt(q, "user_id", 0); err != nil {
+		return filter, err
+	}
+	if filter.EntityID, err = queryInt(q, "entity_id", 0); err != nil {
+		return filter, err
+	}
+	if filter.From, err = queryTime(q, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = queryTime(q, "to"); err != nil {
+		return filter, err
+	}
+	if filter.Limit, err = queryInt(q, "limit", model.DefaultPageLimit); err != nil {
+		return filter, err
+	}
+	if filter.Offset, err = queryInt(q, "offset", 0); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
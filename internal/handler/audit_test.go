@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"encoding/json"
+	"film-library/internal/model"
+	mock_service "film-library/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_GetAuditLog(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockAudit)
+
+	userID := 1
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?user_id=1&entity=film&entity_id=7&from=2025-06-01&to=2025-06-02T12:00:00Z&limit=10",
+			mockBehavior: func(r *mock_service.MockAudit) {
+				r.EXPECT().GetAuditLog(gomock.Any(), model.AuditFilter{
+					UserID: 1, Entity: model.EntityFilm, EntityID: 7, From: &from, To: &to, Limit: 10,
+				}).Return(model.AuditPage{
+					Items: []model.AuditEntry{{
+						Id: 5, UserID: &userID, Action: model.AuditUpdate, Entity: model.EntityFilm, EntityID: 7,
+						Before:    json.RawMessage(`{"rating": 8}`),
+						After:     json.RawMessage(`{"rating": 9}`),
+						CreatedAt: from,
+					}},
+					Total: 1,
+					Limit: 10,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"items": [{"id": 5, "user_id": 1, "action": "update", "entity": "film", "entity_id": 7, "before": {"rating": 8}, "after": {"rating": 9}, "created_at": "2025-06-01T00:00:00Z"}], "total": 1, "limit": 10, "offset": 0, "next_cursor": "", "prev_cursor": ""}`,
+		},
+		{
+			name:  "Country By Code",
+			query: "?entity=country&entity_code=FR",
+			mockBehavior: func(r *mock_service.MockAudit) {
+				r.EXPECT().GetAuditLog(gomock.Any(), model.AuditFilter{
+					Entity: model.EntityCountry, EntityCode: "FR", Limit: model.DefaultPageLimit,
+				}).Return(model.AuditPage{
+					Items: []model.AuditEntry{{
+						Id: 6, UserID: &userID, Action: model.AuditUpdate, Entity: model.EntityCountry, EntityCode: "FR",
+						Before:    json.RawMessage(`{"name": "France"}`),
+						After:     json.RawMessage(`{"name": "République française"}`),
+						CreatedAt: from,
+					}},
+					Total: 1,
+					Limit: model.DefaultPageLimit,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"items": [{"id": 6, "user_id": 1, "action": "update", "entity": "country", "entity_code": "FR", "before": {"name": "France"}, "after": {"name": "République française"}, "created_at": "2025-06-01T00:00:00Z"}], "total": 1, "limit": 20, "offset": 0, "next_cursor": "", "prev_cursor": ""}`,
+		},
+		{
+			name:                 "Invalid Entity",
+			query:                "?entity=user",
+			mockBehavior:         func(r *mock_service.MockAudit) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Entity must be film, actor, genre, country or tag", "code": "invalid_audit_entity"}`,
+		},
+		{
+			name:                 "Entity ID Without Entity",
+			query:                "?entity_id=7",
+			mockBehavior:         func(r *mock_service.MockAudit) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "entity_id requires entity", "code": "entity_id_without_entity"}`,
+		},
+		{
+			name:                 "Entity Code Without Entity",
+			query:                "?entity_code=FR",
+			mockBehavior:         func(r *mock_service.MockAudit) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "entity_code requires entity", "code": "entity_code_without_entity"}`,
+		},
+		{
+			name:                 "Invalid Time",
+			query:                "?from=yesterday",
+			mockBehavior:         func(r *mock_service.MockAudit) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Parameter from must be an RFC 3339 timestamp or a YYYY-MM-DD date", "code": "invalid_time_param"}`,
+		},
+		{
+			name:                 "Inverted Range",
+			query:                "?from=2025-06-02&to=2025-06-01",
+			mockBehavior:         func(r *mock_service.MockAudit) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Period start is after its end", "code": "time_range_inverted"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			audit := mock_service.NewMockAudit(c)
+			test.mockBehavior(audit)
+
+			handler := NewAuditHandler(audit)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit"+test.query, nil)
+
+			rr := httptest.NewRecorder()
+			handler.GetAuditLog(rr, req)
+
+			require.Equal(t, test.expectedStatusCode, rr.Code)
+			require.JSONEq(t, test.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
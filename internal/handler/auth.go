@@ -2,10 +2,13 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"film-library/internal/model"
 	"film-library/internal/service"
+	authmid "film-library/internal/utils/auth_mid"
 	"film-library/internal/utils/response"
-	"fmt"
+	"film-library/internal/utils/validate"
+	"io"
 	"net/http"
 )
 
@@ -17,104 +20,89 @@ func NewAuthHandler(service service.Authorization) AuthHandler {
 	return AuthHandler{service: service}
 }
 
-//	func (h *AuthHandler) HandleAuthPost(w http.ResponseWriter, r *http.Request) {
-//		switch {
-//		case r.URL.Path == "/auth/sign_up" && r.Method == http.MethodPost:
-//			h.CreateUser(w, r)
-//		case r.URL.Path == "/auth/sign_in" && r.Method == http.MethodPost:
-//			h.VerifyUser(w, r)
-//		default:
-//			response.WriteJSONError(w, "method not allowed", http.StatusMethodNotAllowed)
-//		}
-//	}
-func (h *AuthHandler) HandleAuthPost(w http.ResponseWriter, r *http.Request) {
-	switch r.URL.Path {
-	case "/auth/sign_up":
-		h.CreateUser(w, r)
-	case "/auth/sign_in":
-		h.VerifyUser(w, r)
-	default:
-		http.Error(w, "Not found", http.StatusNotFound)
-	}
-}
-
 // @Summary SignUp
 // @Tags auth
-// @Description Create a new user account and return JWT token
+// @Description Create a new user account with the user role and return JWT token
 // @ID create-account
 // @Accept  json
 // @Produce  json
 // @Param req body model.SignUpRequest true "Account info"
-// @Success 201 {object} map[string]string
-// @Failure 400,405 {object} response.ErrorResponse
-// @Failure 500 {object} response.ErrorResponse
-// @Failure default {object} response.ErrorResponse
-// @Router /auth/sign_up [post]
+// @Success 201 {object} model.SignUpResponse
+// @Failure 400,405,409 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/auth/sign_up [post]
 func (h *AuthHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	var req model.SignUpRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.WriteJSONError(w, "Invalid request body", http.StatusBadRequest)
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
 		return
 	}
 
+	// Роль при регистрации не выбирается: её выдаёт администратор
 	user := model.User{
 		Username: req.Username,
 		Password: req.Password,
-		Role:     req.Role,
-	}
-
-	if user.Username == "" || user.Password == "" || user.Role == 0 {
-		response.WriteJSONError(w, "username, password and role are required", http.StatusBadRequest)
-		return
+		Role:     int(model.RoleUser),
 	}
 
 	result, err := h.service.CreateUser(r.Context(), user)
 	if err != nil {
-		response.WriteJSONError(w, err.Error(), http.StatusInternalServerError)
+		response.WriteError(w, r, err, "create_user_failed")
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]string{"token": result})
+	json.NewEncoder(w).Encode(model.SignUpResponse{
+		Token:     result.AccessToken,
+		TokenPair: result,
+	})
 }
 
 // @Summary SignIn
 // @Tags auth
-// @Description Authenticate user and return JWT token + user info
+// @Description Authenticate user and return access and refresh tokens + user info
 // @ID login
 // @Accept  json
 // @Produce  json
 // @Param input body model.SignInRequest true "Account info"
 // @Success 200 {object} model.AuthResponse
-// @Failure 400,405 {object} response.ErrorResponse
-// @Failure 500 {object} response.ErrorResponse
-// @Failure default {object} response.ErrorResponse
-// @Router /auth/sign_in [post]
+// @Failure 400,401,405 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/auth/sign_in [post]
 func (h *AuthHandler) VerifyUser(w http.ResponseWriter, r *http.Request) {
 	var input model.SignInRequest
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		response.WriteJSONError(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
 		return
 	}
 
-	if input.Username == "" || input.Password == "" {
-		response.WriteJSONError(w, "username or password are required", http.StatusBadRequest)
+	if err := validate.Struct(input); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
 		return
 	}
 
-	token, user, err := h.service.VerifyUser(r.Context(), input.Username, input.Password)
+	tokens, user, err := h.service.VerifyUser(r.Context(), input.Username, input.Password)
 
 	if err != nil {
-		response.WriteJSONError(w, "failed to verify user", http.StatusInternalServerError)
+		response.WriteError(w, r, err, "verify_user_failed")
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(model.AuthResponse{
-		AccessToken: token,
+		TokenPair: tokens,
 		User: model.UserResponse{
 			ID:       user.ID,
 			Username: user.Username,
@@ -122,3 +110,79 @@ func (h *AuthHandler) VerifyUser(w http.ResponseWriter, r *http.Request) {
 		},
 	})
 }
+
+// @Summary Refresh Tokens
+// @Tags auth
+// @Description Exchange refresh token for a new token pair. Each refresh token can be used once; reuse revokes the whole session
+// @ID refresh-tokens
+// @Accept  json
+// @Produce  json
+// @Param input body model.RefreshRequest true "Refresh token"
+// @Success 200 {object} model.TokenPair
+// @Failure 400,401 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/auth/refresh [post]
+func (h *AuthHandler) RefreshTokens(w http.ResponseWriter, r *http.Request) {
+	var input model.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validate.Struct(input); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
+		return
+	}
+
+	tokens, err := h.service.RefreshTokens(r.Context(), input.RefreshToken)
+	if err != nil {
+		response.WriteError(w, r, err, "refresh_tokens_failed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(tokens)
+}
+
+// @Summary Logout
+// @Security ApiKeyAuth
+// @Tags auth
+// @Description Revoke current access token and the session of the given refresh token
+// @ID logout
+// @Accept  json
+// @Produce  json
+// @Param input body model.RefreshRequest false "Refresh token"
+// @Success 200 {object} map[string]string
+// @Failure 400,401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/auth/logout [post]
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	userID, okUser := authmid.UserID(r.Context())
+	jti, expiresAt, okToken := authmid.Token(r.Context())
+	if !okUser || !okToken {
+		response.WriteJSONError(w, r, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Тело необязательно: без refresh-токена отзывается только access-токен
+	var input model.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Logout(r.Context(), userID, input.RefreshToken, jti, expiresAt); err != nil {
+		response.WriteError(w, r, err, "logout_failed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "logged out successfully",
+	})
+}
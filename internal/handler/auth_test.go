@@ -2,10 +2,13 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"film-library/internal/domain"
 	"film-library/internal/model"
 	"film-library/internal/service"
 	mock_service "film-library/internal/service/mocks"
+	authmid "film-library/internal/utils/auth_mid"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -27,17 +30,17 @@ func TestHandler_CreateUser(t *testing.T) {
 	}{
 		{
 			name:      "Ok",
-			inputBody: `{"username": "username", "password": "qwerty", "role": 1}`,
+			inputBody: `{"username": "username", "password": "qwerty123", "role": 1}`,
 			inputUser: model.User{
 				Username: "username",
-				Password: "qwerty",
+				Password: "qwerty123",
 				Role:     1,
 			},
 			mockBehavior: func(r *mock_service.MockAuthorization, user model.User) {
-				r.EXPECT().CreateUser(gomock.Any(), user).Return("1", nil)
+				r.EXPECT().CreateUser(gomock.Any(), user).Return(model.TokenPair{AccessToken: "1", RefreshToken: "r", ExpiresIn: 900}, nil)
 			},
 			expectedStatusCode:   http.StatusCreated,
-			expectedResponseBody: `{"token":"1"}`,
+			expectedResponseBody: `{"token":"1", "access_token":"1", "refresh_token":"r", "expires_in":900}`,
 		},
 		{
 			name:      "Wrong Input",
@@ -48,22 +51,51 @@ func TestHandler_CreateUser(t *testing.T) {
 				Role:     1,
 			},
 			mockBehavior:         func(r *mock_service.MockAuthorization, user model.User) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"message":"username, password and role are required"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/sign_up", "code": "validation_failed", "errors": [{"field": "username", "rule": "required", "message": "is required"}, {"field": "password", "rule": "min", "params": ["8"], "message": "must be at least 8 characters"}]}`,
+		},
+		{
+			name:      "Role Ignored",
+			inputBody: `{"username": "username", "password": "qwerty123", "role": 2}`,
+			inputUser: model.User{
+				Username: "username",
+				Password: "qwerty123",
+				Role:     1,
+			},
+			mockBehavior: func(r *mock_service.MockAuthorization, user model.User) {
+				r.EXPECT().CreateUser(gomock.Any(), user).Return(model.TokenPair{AccessToken: "1", RefreshToken: "r", ExpiresIn: 900}, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"token":"1", "access_token":"1", "refresh_token":"r", "expires_in":900}`,
+		},
+		{
+			name:      "User Exists",
+			inputBody: `{"username": "username", "password": "qwerty123"}`,
+			inputUser: model.User{
+				Username: "username",
+				Password: "qwerty123",
+				Role:     1,
+			},
+			mockBehavior: func(r *mock_service.MockAuthorization, user model.User) {
+				r.EXPECT().CreateUser(gomock.Any(), user).
+					Return(model.TokenPair{}, domain.Conflict("user_exists", "пользователь с таким именем уже существует"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"status": 409, "message": "User with this name already exists", "code": "user_exists"}`,
 		},
 		{
 			name:      "Service Error",
-			inputBody: `{"username": "username", "password": "qwerty", "role": 1}`,
+			inputBody: `{"username": "username", "password": "qwerty123", "role": 1}`,
 			inputUser: model.User{
 				Username: "username",
-				Password: "qwerty",
+				Password: "qwerty123",
 				Role:     1,
 			},
 			mockBehavior: func(r *mock_service.MockAuthorization, user model.User) {
-				r.EXPECT().CreateUser(gomock.Any(), user).Return("", errors.New("failed to create user"))
+				r.EXPECT().CreateUser(gomock.Any(), user).Return(model.TokenPair{}, errors.New("failed to create user"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"message":"failed to create user"}`,
+			expectedResponseBody: `{"status": 500, "message": "Failed to create user", "code": "internal_error"}`,
 		},
 	}
 
@@ -113,10 +145,11 @@ func TestHandler_VerifyUser(t *testing.T) {
 				Password: "qwerty",
 			},
 			mockBehavior: func(r *mock_service.MockAuthorization, user *model.User) {
-				r.EXPECT().VerifyUser(gomock.Any(), user.Username, user.Password).Return("", user, nil)
+				r.EXPECT().VerifyUser(gomock.Any(), user.Username, user.Password).
+					Return(model.TokenPair{AccessToken: "a", RefreshToken: "r", ExpiresIn: 900}, user, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `{"access_token": "", "user": {"id": 0, "username": "username", "role": 0}}`,
+			expectedResponseBody: `{"access_token": "a", "refresh_token": "r", "expires_in": 900, "user": {"id": 0, "username": "username", "role": 0}}`,
 		},
 		{
 			name:      "Wrong Input",
@@ -126,8 +159,22 @@ func TestHandler_VerifyUser(t *testing.T) {
 				Password: "qwerty",
 			},
 			mockBehavior:         func(r *mock_service.MockAuthorization, user *model.User) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"message": "username or password are required"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/sign_in", "code": "validation_failed", "errors": [{"field": "username", "rule": "required", "message": "is required"}]}`,
+		},
+		{
+			name:      "Invalid Credentials",
+			inputBody: `{"username": "username", "password": "wrong"}`,
+			inputUser: &model.User{
+				Username: "username",
+				Password: "wrong",
+			},
+			mockBehavior: func(r *mock_service.MockAuthorization, user *model.User) {
+				r.EXPECT().VerifyUser(gomock.Any(), user.Username, user.Password).
+					Return(model.TokenPair{}, nil, domain.Unauthorized("invalid_credentials", "неверное имя пользователя или пароль"))
+			},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"status": 401, "message": "Invalid username or password", "code": "invalid_credentials"}`,
 		},
 		{
 			name:      "Service Error",
@@ -137,10 +184,10 @@ func TestHandler_VerifyUser(t *testing.T) {
 				Password: "qwerty",
 			},
 			mockBehavior: func(r *mock_service.MockAuthorization, user *model.User) {
-				r.EXPECT().VerifyUser(gomock.Any(), user.Username, user.Password).Return("", user, errors.New("failed to verify user"))
+				r.EXPECT().VerifyUser(gomock.Any(), user.Username, user.Password).Return(model.TokenPair{}, user, errors.New("failed to verify user"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"message": "failed to verify user"}`,
+			expectedResponseBody: `{"status": 500, "message": "Failed to verify user", "code": "internal_error"}`,
 		},
 	}
 
@@ -166,3 +213,144 @@ func TestHandler_VerifyUser(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_RefreshTokens(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockAuthorization, refreshToken string)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		inputToken           string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:       "Ok",
+			inputBody:  `{"refresh_token": "old"}`,
+			inputToken: "old",
+			mockBehavior: func(r *mock_service.MockAuthorization, refreshToken string) {
+				r.EXPECT().RefreshTokens(gomock.Any(), refreshToken).
+					Return(model.TokenPair{AccessToken: "a", RefreshToken: "new", ExpiresIn: 900}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"access_token": "a", "refresh_token": "new", "expires_in": 900}`,
+		},
+		{
+			name:                 "Empty Token",
+			inputBody:            `{}`,
+			mockBehavior:         func(r *mock_service.MockAuthorization, refreshToken string) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/api/v1/auth/refresh", "code": "validation_failed", "errors": [{"field": "refresh_token", "rule": "required", "message": "is required"}]}`,
+		},
+		{
+			name:       "Reused Token",
+			inputBody:  `{"refresh_token": "old"}`,
+			inputToken: "old",
+			mockBehavior: func(r *mock_service.MockAuthorization, refreshToken string) {
+				r.EXPECT().RefreshTokens(gomock.Any(), refreshToken).
+					Return(model.TokenPair{}, domain.Unauthorized("refresh_token_reused", "refresh-токен уже использован, сеанс отозван"))
+			},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"status": 401, "message": "Refresh token has already been used, session revoked", "code": "refresh_token_reused"}`,
+		},
+		{
+			name:       "Service Error",
+			inputBody:  `{"refresh_token": "old"}`,
+			inputToken: "old",
+			mockBehavior: func(r *mock_service.MockAuthorization, refreshToken string) {
+				r.EXPECT().RefreshTokens(gomock.Any(), refreshToken).Return(model.TokenPair{}, errors.New("error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"status": 500, "message": "Failed to refresh tokens", "code": "internal_error"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			tc.mockBehavior(auth, tc.inputToken)
+
+			handler := NewAuthHandler(auth)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", bytes.NewBufferString(tc.inputBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			handler.RefreshTokens(rr, req)
+
+			require.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_Logout(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockAuthorization, refreshToken string)
+
+	expiresAt := parseTime("2030-01-01T00:00:00Z")
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		inputToken           string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:       "Ok",
+			inputBody:  `{"refresh_token": "refresh"}`,
+			inputToken: "refresh",
+			mockBehavior: func(r *mock_service.MockAuthorization, refreshToken string) {
+				r.EXPECT().Logout(gomock.Any(), 1, refreshToken, "jti", expiresAt).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message": "logged out successfully"}`,
+		},
+		{
+			name: "Ok Without Body",
+			mockBehavior: func(r *mock_service.MockAuthorization, refreshToken string) {
+				r.EXPECT().Logout(gomock.Any(), 1, "", "jti", expiresAt).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message": "logged out successfully"}`,
+		},
+		{
+			name:       "Service Error",
+			inputBody:  `{"refresh_token": "refresh"}`,
+			inputToken: "refresh",
+			mockBehavior: func(r *mock_service.MockAuthorization, refreshToken string) {
+				r.EXPECT().Logout(gomock.Any(), 1, refreshToken, "jti", expiresAt).Return(errors.New("error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"status": 500, "message": "Failed to logout", "code": "internal_error"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			tc.mockBehavior(auth, tc.inputToken)
+
+			handler := NewAuthHandler(auth)
+
+			ctx := authmid.WithUser(context.Background(), 1, int(model.RoleUser))
+			ctx = authmid.WithToken(ctx, "jti", expiresAt)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/logout", bytes.NewBufferString(tc.inputBody)).WithContext(ctx)
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			handler.Logout(rr, req)
+
+			require.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
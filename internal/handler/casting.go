@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"film-library/internal/model"
+	"film-library/internal/service"
+	"film-library/internal/utils/response"
+	"io"
+	"net/http"
+)
+
+type CastingHandler struct {
+	service service.Casting
+}
+
+func NewCastingHandler(service service.Casting) CastingHandler {
+	return CastingHandler{service: service}
+}
+
+// @Summary Get Film Cast
+// @Security ApiKeyAuth
+// @Tags casting
+// @Description Get film cast with characters in billing order
+// @ID get-film-cast
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Success 200 {array} model.CastMember
+// @Failure 400,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id}/actors [get]
+func (h *CastingHandler) GetCast(w http.ResponseWriter, r *http.Request) {
+	filmID, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	cast, err := h.service.GetCast(r.Context(), filmID)
+	if err != nil {
+		response.WriteError(w, r, err, "get_cast_failed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(cast)
+}
+
+// @Summary Replace Film Cast
+// @Security ApiKeyAuth
+// @Tags casting
+// @Description Replace the whole film cast. Actors must already exist
+// @ID replace-film-cast
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Param cast body []model.CastingEntry true "New cast"
+// @Success 200 {array} model.CastMember
+// @Failure 400,403,404 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id}/actors [put]
+func (h *CastingHandler) ReplaceCast(w http.ResponseWriter, r *http.Request) {
+	filmID, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	var entries []model.CastingEntry
+	if err := json.NewDecoder(r.Body).Decode(&entries); err != nil {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+
+	if err := model.ValidateCasting(entries); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
+		return
+	}
+
+	if err := h.service.ReplaceCast(r.Context(), filmID, entries); err != nil {
+		response.WriteError(w, r, err, "update_cast_failed")
+		return
+	}
+
+	h.GetCast(w, r)
+}
+
+// @Summary Add Actor To Cast
+// @Security ApiKeyAuth
+// @Tags casting
+// @Description Add actor to film cast or update his character and billing order
+// @ID add-cast-member
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Param actorId path int true "Actor ID"
+// @Param role body model.CastingEntry false "Character and billing order"
+// @Success 200 {array} model.CastMember
+// @Failure 400,403,404 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id}/actors/{actorId} [post]
+func (h *CastingHandler) AddCastMember(w http.ResponseWriter, r *http.Request) {
+	filmID, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	actorID, err := pathID(r, "actorId")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_actor_id", http.StatusBadRequest)
+		return
+	}
+
+	// Тело необязательно: актёра можно добавить без указания роли
+	var entry model.CastingEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil && !errors.Is(err, io.EOF) {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+	entry.ActorID = actorID
+
+	if err := entry.Validate(); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
+		return
+	}
+
+	if err := h.service.AddCastMember(r.Context(), filmID, entry); err != nil {
+		response.WriteError(w, r, err, "add_cast_member_failed")
+		return
+	}
+
+	h.GetCast(w, r)
+}
+
+// @Summary Remove Actor From Cast
+// @Security ApiKeyAuth
+// @Tags casting
+// @Description Remove actor from film cast
+// @ID remove-cast-member
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Param actorId path int true "Actor ID"
+// @Success 200 {object} map[string]string
+// @Failure 400,403,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id}/actors/{actorId} [delete]
+func (h *CastingHandler) RemoveCastMember(w http.ResponseWriter, r *http.Request) {
+	filmID, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	actorID, err := pathID(r, "actorId")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_actor_id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.RemoveCastMember(r.Context(), filmID, actorID); err != nil {
+		response.WriteError(w, r, err, "remove_cast_member_failed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "actor removed from cast successfully",
+	})
+}
@@ -0,0 +1,309 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	mock_service "film-library/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_GetCast(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockCasting, filmID int)
+
+	tests := []struct {
+		name                 string
+		pathParam            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			pathParam: "1",
+			mockBehavior: func(r *mock_service.MockCasting, filmID int) {
+				r.EXPECT().GetCast(gomock.Any(), filmID).Return([]model.CastMember{
+					{Actor: model.Actor{Id: 2, Name: "actor", Gender: "male", DateOfBirth: parseTime("1980-01-01T00:00:00Z")}, Character: "hero", BillingOrder: 1},
+					{Actor: model.Actor{Id: 3, Name: "extra", Gender: "female", DateOfBirth: parseTime("1990-01-01T00:00:00Z")}},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `[{"id": 2, "name": "actor", "gender": "male", "date_of_birth": "1980-01-01T00:00:00Z", "character": "hero", "billing_order": 1}, {"id": 3, "name": "extra", "gender": "female", "date_of_birth": "1990-01-01T00:00:00Z"}]`,
+		},
+		{
+			name:                 "Wrong input ID",
+			pathParam:            "first",
+			mockBehavior:         func(r *mock_service.MockCasting, filmID int) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Invalid film ID", "code": "invalid_film_id"}`,
+		},
+		{
+			name:      "Film Not Found",
+			pathParam: "1",
+			mockBehavior: func(r *mock_service.MockCasting, filmID int) {
+				r.EXPECT().GetCast(gomock.Any(), filmID).Return(nil, domain.NotFound("cast_not_found", "фильм или актёр в составе не найден"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"status": 404, "message": "Film or cast member not found", "code": "cast_not_found"}`,
+		},
+		{
+			name:      "Service Error",
+			pathParam: "1",
+			mockBehavior: func(r *mock_service.MockCasting, filmID int) {
+				r.EXPECT().GetCast(gomock.Any(), filmID).Return(nil, errors.New("error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"status": 500, "message": "Failed to get cast", "code": "internal_error"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			casting := mock_service.NewMockCasting(c)
+			tc.mockBehavior(casting, 1)
+
+			handler := NewCastingHandler(casting)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/films/"+tc.pathParam+"/actors", nil)
+			req.SetPathValue("id", tc.pathParam)
+
+			rr := httptest.NewRecorder()
+			handler.GetCast(rr, req)
+
+			require.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_ReplaceCast(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockCasting, filmID int)
+
+	cast := []model.CastMember{
+		{Actor: model.Actor{Id: 2, Name: "actor", Gender: "male", DateOfBirth: parseTime("1980-01-01T00:00:00Z")}, Character: "hero", BillingOrder: 1},
+	}
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `[{"actor_id": 2, "character": "hero", "billing_order": 1}]`,
+			mockBehavior: func(r *mock_service.MockCasting, filmID int) {
+				r.EXPECT().ReplaceCast(gomock.Any(), filmID, []model.CastingEntry{
+					{ActorID: 2, Character: "hero", BillingOrder: 1},
+				}).Return(nil)
+				r.EXPECT().GetCast(gomock.Any(), filmID).Return(cast, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `[{"id": 2, "name": "actor", "gender": "male", "date_of_birth": "1980-01-01T00:00:00Z", "character": "hero", "billing_order": 1}]`,
+		},
+		{
+			name:                 "Invalid Body",
+			inputBody:            `{"actor_id": 2}`,
+			mockBehavior:         func(r *mock_service.MockCasting, filmID int) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Invalid request body", "code": "invalid_request_body"}`,
+		},
+		{
+			name:                 "Duplicate Actor",
+			inputBody:            `[{"actor_id": 2}, {"actor_id": 2, "character": "twin"}]`,
+			mockBehavior:         func(r *mock_service.MockCasting, filmID int) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/api/v1/films/1/actors", "code": "validation_failed", "errors": [{"field": "[1].actor_id", "rule": "unique", "message": "actor 2 is listed more than once"}]}`,
+		},
+		{
+			name:      "Unknown Actor",
+			inputBody: `[{"actor_id": 42}]`,
+			mockBehavior: func(r *mock_service.MockCasting, filmID int) {
+				r.EXPECT().ReplaceCast(gomock.Any(), filmID, []model.CastingEntry{{ActorID: 42}}).
+					Return(domain.Validation("unknown_actors", "в составе указаны несуществующие актёры: [42]").WithArgs([]int64{42}))
+			},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"status": 422, "message": "Cast references unknown actors: [42]", "code": "unknown_actors"}`,
+		},
+		{
+			name:      "Film Not Found",
+			inputBody: `[]`,
+			mockBehavior: func(r *mock_service.MockCasting, filmID int) {
+				r.EXPECT().ReplaceCast(gomock.Any(), filmID, []model.CastingEntry{}).
+					Return(domain.NotFound("cast_not_found", "фильм или актёр в составе не найден"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"status": 404, "message": "Film or cast member not found", "code": "cast_not_found"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			casting := mock_service.NewMockCasting(c)
+			tc.mockBehavior(casting, 1)
+
+			handler := NewCastingHandler(casting)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/films/1/actors", bytes.NewBufferString(tc.inputBody))
+			req.SetPathValue("id", "1")
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			handler.ReplaceCast(rr, req)
+
+			require.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_AddCastMember(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockCasting, filmID int)
+
+	tests := []struct {
+		name                 string
+		actorParam           string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:       "Ok",
+			actorParam: "2",
+			inputBody:  `{"character": "hero"}`,
+			mockBehavior: func(r *mock_service.MockCasting, filmID int) {
+				r.EXPECT().AddCastMember(gomock.Any(), filmID, model.CastingEntry{ActorID: 2, Character: "hero"}).Return(nil)
+				r.EXPECT().GetCast(gomock.Any(), filmID).Return([]model.CastMember{
+					{Actor: model.Actor{Id: 2, Name: "actor", Gender: "male", DateOfBirth: parseTime("1980-01-01T00:00:00Z")}, Character: "hero", BillingOrder: 1},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `[{"id": 2, "name": "actor", "gender": "male", "date_of_birth": "1980-01-01T00:00:00Z", "character": "hero", "billing_order": 1}]`,
+		},
+		{
+			name:       "Ok Without Body",
+			actorParam: "2",
+			mockBehavior: func(r *mock_service.MockCasting, filmID int) {
+				r.EXPECT().AddCastMember(gomock.Any(), filmID, model.CastingEntry{ActorID: 2}).Return(nil)
+				r.EXPECT().GetCast(gomock.Any(), filmID).Return([]model.CastMember{
+					{Actor: model.Actor{Id: 2, Name: "actor", Gender: "male", DateOfBirth: parseTime("1980-01-01T00:00:00Z")}, BillingOrder: 1},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `[{"id": 2, "name": "actor", "gender": "male", "date_of_birth": "1980-01-01T00:00:00Z", "billing_order": 1}]`,
+		},
+		{
+			name:                 "Wrong actor ID",
+			actorParam:           "first",
+			mockBehavior:         func(r *mock_service.MockCasting, filmID int) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Invalid actor ID", "code": "invalid_actor_id"}`,
+		},
+		{
+			name:       "Unknown Actor",
+			actorParam: "42",
+			mockBehavior: func(r *mock_service.MockCasting, filmID int) {
+				r.EXPECT().AddCastMember(gomock.Any(), filmID, model.CastingEntry{ActorID: 42}).
+					Return(domain.Validation("unknown_actors", "в составе указаны несуществующие актёры: [42]").WithArgs([]int64{42}))
+			},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"status": 422, "message": "Cast references unknown actors: [42]", "code": "unknown_actors"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			casting := mock_service.NewMockCasting(c)
+			tc.mockBehavior(casting, 1)
+
+			handler := NewCastingHandler(casting)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/films/1/actors/"+tc.actorParam, bytes.NewBufferString(tc.inputBody))
+			req.SetPathValue("id", "1")
+			req.SetPathValue("actorId", tc.actorParam)
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			handler.AddCastMember(rr, req)
+
+			require.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_RemoveCastMember(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockCasting, filmID, actorID int)
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(r *mock_service.MockCasting, filmID, actorID int) {
+				r.EXPECT().RemoveCastMember(gomock.Any(), filmID, actorID).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message": "actor removed from cast successfully"}`,
+		},
+		{
+			name: "Not In Cast",
+			mockBehavior: func(r *mock_service.MockCasting, filmID, actorID int) {
+				r.EXPECT().RemoveCastMember(gomock.Any(), filmID, actorID).
+					Return(domain.NotFound("cast_not_found", "фильм или актёр в составе не найден"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"status": 404, "message": "Film or cast member not found", "code": "cast_not_found"}`,
+		},
+		{
+			name: "Service Error",
+			mockBehavior: func(r *mock_service.MockCasting, filmID, actorID int) {
+				r.EXPECT().RemoveCastMember(gomock.Any(), filmID, actorID).Return(errors.New("error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"status": 500, "message": "Failed to remove actor from cast", "code": "internal_error"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			casting := mock_service.NewMockCasting(c)
+			tc.mockBehavior(casting, 1, 2)
+
+			handler := NewCastingHandler(casting)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/films/1/actors/2", nil)
+			req.SetPathValue("id", "1")
+			req.SetPathValue("actorId", "2")
+
+			rr := httptest.NewRecorder()
+			handler.RemoveCastMember(rr, req)
+
+			require.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
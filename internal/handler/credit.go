@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"film-library/internal/model"
+	"film-library/internal/service"
+	"film-library/internal/utils/response"
+	"io"
+	"net/http"
+)
+
+type CreditHandler struct {
+	service service.Credit
+}
+
+func NewCreditHandler(service service.Credit) CreditHandler {
+	return CreditHandler{service: service}
+}
+
+// @Summary Get Film Credits
+// @Security ApiKeyAuth
+// @Tags credits
+// @Description Full film credits: directors, writers, producers, actors and other crew, in credits order
+// @ID get-film-credits
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Success 200 {array} model.Credit
+// @Failure 400,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id}/credits [get]
+func (h *CreditHandler) GetCredits(w http.ResponseWriter, r *http.Request) {
+	filmID, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	credits, err := h.service.GetCredits(r.Context(), filmID)
+	if err != nil {
+		response.WriteError(w, r, err, "get_credits_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, credits)
+}
+
+// @Summary Replace Film Credits
+// @Security ApiKeyAuth
+// @Tags credits
+// @Description Replace all film credits, including the cast. People must already exist.
+// @Description Without position people keep the order of the list within their role
+// @ID replace-film-credits
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Param credits body []model.Credit true "New credits"
+// @Success 200 {array} model.Credit
+// @Failure 400,403,404 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id}/credits [put]
+func (h *CreditHandler) ReplaceCredits(w http.ResponseWriter, r *http.Request) {
+	filmID, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	var credits []model.Credit
+	if err := json.NewDecoder(r.Body).Decode(&credits); err != nil {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+
+	if err := model.ValidateCredits(credits); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
+		return
+	}
+
+	if err := h.service.ReplaceCredits(r.Context(), filmID, credits); err != nil {
+		response.WriteError(w, r, err, "update_credits_failed")
+		return
+	}
+
+	h.GetCredits(w, r)
+}
+
+// @Summary Add Film Credit
+// @Security ApiKeyAuth
+// @Tags credits
+// @Description Add person to film credits in a role or update character and position
+// @ID add-film-credit
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Param personId path int true "Person ID"
+// @Param role path string true "actor, director, writer, producer, composer, cinematographer or editor"
+// @Param credit body model.Credit false "Character (actors only) and position"
+// @Success 200 {array} model.Credit
+// @Failure 400,403,404 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id}/credits/{personId}/{role} [post]
+func (h *CreditHandler) AddCredit(w http.ResponseWriter, r *http.Request) {
+	filmID, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	personID, err := pathID(r, "personId")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_person_id", http.StatusBadRequest)
+		return
+	}
+
+	// Тело необязательно: участника можно добавить без персонажа и позиции
+	var credit model.Credit
+	if err := json.NewDecoder(r.Body).Decode(&credit); err != nil && !errors.Is(err, io.EOF) {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+	credit.PersonID = personID
+	credit.Role = r.PathValue("role")
+
+	if err := credit.Validate(); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
+		return
+	}
+
+	if err := h.service.AddCredit(r.Context(), filmID, credit); err != nil {
+		response.WriteError(w, r, err, "add_credit_failed")
+		return
+	}
+
+	h.GetCredits(w, r)
+}
+
+// @Summary Remove Film Credit
+// @Security ApiKeyAuth
+// @Tags credits
+// @Description Remove person from film credits in one role
+// @ID remove-film-credit
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Param personId path int true "Person ID"
+// @Param role path string true "Credit role"
+// @Success 200 {object} map[string]string
+// @Failure 400,403,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id}/credits/{personId}/{role} [delete]
+func (h *CreditHandler) RemoveCredit(w http.ResponseWriter, r *http.Request) {
+	filmID, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	personID, err := pathID(r, "personId")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_person_id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.RemoveCredit(r.Context(), filmID, personID, r.PathValue("role")); err != nil {
+		response.WriteError(w, r, err, "remove_credit_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "credit removed successfully"})
+}
+
+// @Summary Person Filmography
+// @Security ApiKeyAuth
+// @Tags credits
+// @Description Person with films grouped by role: directed, written, acted in and so on
+// @ID get-person-credits
+// @Produce  json
+// @Param id path int true "Person ID"
+// @Success 200 {object} model.Filmography
+// @Failure 400,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/people/{id}/credits [get]
+func (h *CreditHandler) GetFilmography(w http.ResponseWriter, r *http.Request) {
+	personID, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_person_id", http.StatusBadRequest)
+		return
+	}
+
+	filmography, err := h.service.GetFilmography(r.Context(), personID)
+	if err != nil {
+		response.WriteError(w, r, err, "get_filmography_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, filmography)
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"bytes"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	mock_service "film-library/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_ReplaceCredits(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockCredit, filmID int)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `[{"person_id": 3, "role": "director"}, {"person_id": 7, "role": "actor", "character": "Neo"}, {"person_id": 3, "role": "writer"}]`,
+			mockBehavior: func(r *mock_service.MockCredit, filmID int) {
+				r.EXPECT().ReplaceCredits(gomock.Any(), filmID, []model.Credit{
+					{PersonID: 3, Role: "director"},
+					{PersonID: 7, Role: "actor", Character: "Neo"},
+					{PersonID: 3, Role: "writer"},
+				}).Return(nil)
+				r.EXPECT().GetCredits(gomock.Any(), filmID).Return([]model.Credit{
+					{PersonID: 3, Name: "Lana Wachowski", Role: "director", Position: 1},
+					{PersonID: 3, Name: "Lana Wachowski", Role: "writer", Position: 1},
+					{PersonID: 7, Name: "Keanu Reeves", Role: "actor", Character: "Neo", Position: 1},
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `[{"person_id": 3, "name": "Lana Wachowski", "role": "director", "position": 1},
+				{"person_id": 3, "name": "Lana Wachowski", "role": "writer", "position": 1},
+				{"person_id": 7, "name": "Keanu Reeves", "role": "actor", "character": "Neo", "position": 1}]`,
+		},
+		{
+			name:                 "Unknown Role",
+			inputBody:            `[{"person_id": 3, "role": "gaffer"}]`,
+			mockBehavior:         func(r *mock_service.MockCredit, filmID int) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/api/v1/films/1/credits", "code": "validation_failed", "errors": [{"field": "[0].role", "rule": "oneof", "params": ["actor", "director", "writer", "producer", "composer", "cinematographer", "editor"], "message": "must be one of: actor, director, writer, producer, composer, cinematographer, editor"}]}`,
+		},
+		{
+			name:                 "Character For Crew",
+			inputBody:            `[{"person_id": 3, "role": "director", "character": "Neo"}]`,
+			mockBehavior:         func(r *mock_service.MockCredit, filmID int) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/api/v1/films/1/credits", "code": "validation_failed", "errors": [{"field": "[0].character", "rule": "actor_only", "message": "only actors have a character"}]}`,
+		},
+		{
+			name:                 "Duplicate Credit",
+			inputBody:            `[{"person_id": 3, "role": "writer"}, {"person_id": 3, "role": "writer"}]`,
+			mockBehavior:         func(r *mock_service.MockCredit, filmID int) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/api/v1/films/1/credits", "code": "validation_failed", "errors": [{"field": "[1].person_id", "rule": "unique", "message": "person 3 is listed more than once as writer"}]}`,
+		},
+		{
+			name:      "Unknown Person",
+			inputBody: `[{"person_id": 42, "role": "composer"}]`,
+			mockBehavior: func(r *mock_service.MockCredit, filmID int) {
+				r.EXPECT().ReplaceCredits(gomock.Any(), filmID, []model.Credit{{PersonID: 42, Role: "composer"}}).
+					Return(domain.Validation("unknown_people", "в титрах указаны несуществующие участники: [42]").WithArgs([]int64{42}))
+			},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"status": 422, "message": "Credits reference unknown people: [42]", "code": "unknown_people"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			credit := mock_service.NewMockCredit(c)
+			test.mockBehavior(credit, 1)
+
+			handler := NewCreditHandler(credit)
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /api/v1/films/{id}/credits", handler.ReplaceCredits)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/films/1/credits", bytes.NewBufferString(test.inputBody))
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			require.Equal(t, test.expectedStatusCode, rr.Code)
+			require.JSONEq(t, test.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_RemoveCredit(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	credit := mock_service.NewMockCredit(c)
+	credit.EXPECT().RemoveCredit(gomock.Any(), 1, 3, "director").Return(nil)
+	credit.EXPECT().RemoveCredit(gomock.Any(), 1, 3, "editor").
+		Return(domain.Wrap(domain.ErrNotFound, domain.ErrNotFound, "credit_not_found", "фильм или участник в титрах не найден"))
+
+	handler := NewCreditHandler(credit)
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /api/v1/films/{id}/credits/{personId}/{role}", handler.RemoveCredit)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/api/v1/films/1/credits/3/director", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"message": "credit removed successfully"}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/api/v1/films/1/credits/3/editor", nil))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+	require.JSONEq(t, `{"status": 404, "message": "Film or credit not found", "code": "credit_not_found"}`, rr.Body.String())
+}
+
+func TestHandler_GetFilmography(t *testing.T) {
+	born := time.Date(1965, 6, 21, 0, 0, 0, 0, time.UTC)
+	released := time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	credit := mock_service.NewMockCredit(c)
+	credit.EXPECT().GetFilmography(gomock.Any(), 3).Return(model.Filmography{
+		Actor: model.Actor{Id: 3, Name: "Lana Wachowski", Gender: "female", DateOfBirth: born},
+		Roles: []model.RoleFilms{
+			{Role: "director", Films: []model.CreditFilm{{Id: 1, Name: "The Matrix", Releasedate: released, Rating: 8.7}}},
+			{Role: "writer", Films: []model.CreditFilm{{Id: 1, Name: "The Matrix", Releasedate: released, Rating: 8.7}}},
+		},
+	}, nil)
+	credit.EXPECT().GetFilmography(gomock.Any(), 404).
+		Return(model.Filmography{}, domain.Wrap(domain.ErrNotFound, domain.ErrNotFound, "person_not_found", "участник не найден"))
+
+	handler := NewCreditHandler(credit)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/people/{id}/credits", handler.GetFilmography)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/people/3/credits", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"id": 3, "name": "Lana Wachowski", "gender": "female", "date_of_birth": "1965-06-21T00:00:00Z", "roles": [
+		{"role": "director", "films": [{"id": 1, "name": "The Matrix", "release_date": "1999-03-31T00:00:00Z", "rating": 8.7}]},
+		{"role": "writer", "films": [{"id": 1, "name": "The Matrix", "release_date": "1999-03-31T00:00:00Z", "rating": 8.7}]}]}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/people/404/credits", nil))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+	require.JSONEq(t, `{"status": 404, "message": "Person not found", "code": "person_not_found"}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/people/abc/credits", nil))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.JSONEq(t, `{"status": 400, "message": "Invalid person ID", "code": "invalid_person_id"}`, rr.Body.String())
+}
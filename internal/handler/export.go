@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"film-library/internal/model"
+	"film-library/internal/service"
+	"film-library/internal/utils/records"
+	"film-library/internal/utils/response"
+	"film-library/internal/utils/sl"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type ExportHandler struct {
+	service service.Export
+}
+
+func NewExportHandler(service service.Export) ExportHandler {
+	return ExportHandler{service: service}
+}
+
+// @Summary Export Catalog
+// @Security ApiKeyAuth
+// @Tags export
+// @Description Streams films, actors or cast links as a downloadable file.
+// @Description Films accept the same filters and sorting as the film list, without pagination,
+// @Description and include user score, votes, genres, countries and tags
+// @ID export-catalog
+// @Produce  text/csv,application/x-ndjson,json
+// @Param entity query string true "What to export: films, actors or casting"
+// @Param format query string false "csv (default), ndjson or json"
+// @Param sort_by query string false "Films: name, release_date, rating (default), user_score"
+// @Param order query string false "Films: asc or desc"
+// @Param min_rating query number false "Films: minimum rating"
+// @Param max_rating query number false "Films: maximum rating"
+// @Param released_from query string false "Films: released on or after (YYYY-MM-DD)"
+// @Param released_to query string false "Films: released on or before (YYYY-MM-DD)"
+// @Param name query string false "Films and actors: name prefix"
+// @Param genre query []string false "Films: only with all these genres" collectionFormat(multi)
+// @Param country query []string false "Films: only from all these countries" collectionFormat(multi)
+// @Param tag query []string false "Films: only with all these tags" collectionFormat(multi)
+// @Param gender query string false "Actors: male or female"
+// @Param film_id query int false "Actors and casting: only this film"
+// @Param actor_id query int false "Films and casting: only this actor"
+// @Success 200 {file} file
+// @Failure 400,401,403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/export [get]
+func (h *ExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	params, err := parseExportParams(r)
+	if err != nil {
+		response.WriteError(w, r, err, "export_failed")
+		return
+	}
+
+	if err := params.Validate(); err != nil {
+		response.WriteError(w, r, err, "export_failed")
+		return
+	}
+
+	// Большая выгрузка может идти дольше общего таймаута записи сервера
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	out := &exportWriter{w: w, params: params}
+	if err := h.service.Export(r.Context(), params, out); err != nil {
+		if !out.started {
+			response.WriteError(w, r, err, "export_failed")
+			return
+		}
+
+		// Часть файла уже отправлена, статус не поменять: обрываем ответ,
+		// чтобы клиент не принял неполную выгрузку за целую
+		if r.Context().Err() == nil {
+			sl.FromContext(r.Context()).Error("export interrupted", sl.Err(err))
+		}
+		panic(http.ErrAbortHandler)
+	}
+
+	if !out.started {
+		out.start()
+	}
+}
+
+// parseExportParams разбирает query-параметры выгрузки; фильтры, не относящиеся
+// к выбранной сущности, игнорируются
+func parseExportParams(r *http.Request) (model.ExportParams, error) {
+	q := r.URL.Query()
+
+	params := model.ExportParams{
+		Entity: q.Get("entity"),
+		Format: q.Get("format"),
+		Gender: q.Get("gender"),
+	}
+	if params.Format == "" {
+		params.Format = records.FormatCSV
+	}
+
+	name := strings.TrimSpace(q.Get("name"))
+	actorID, err := queryInt(q, "actor_id", 0)
+	if err != nil {
+		return params, err
+	}
+	if params.FilmID, err = queryInt(q, "film_id", 0); err != nil {
+		return params, err
+	}
+
+	if params.Entity != model.ExportFilms {
+		params.NamePrefix = name
+		params.ActorID = actorID
+		return params, nil
+	}
+
+	params.Films = model.FilmListParams{
+		SortBy:     q.Get("sort_by"),
+		Order:      q.Get("order"),
+		NamePrefix: name,
+		ActorID:    actorID,
+		Labels:     queryLabelFilter(q),
+	}
+	if params.Films.MinRating, err = queryFloat(q, "min_rating"); err != nil {
+		return params, err
+	}
+	if params.Films.MaxRating, err = queryFloat(q, "max_rating"); err != nil {
+		return params, err
+	}
+	if params.Films.ReleasedFrom, err = queryDate(q, "released_from"); err != nil {
+		return params, err
+	}
+	if params.Films.ReleasedTo, err = queryDate(q, "released_to"); err != nil {
+		return params, err
+	}
+
+	return params, nil
+}
+
+// exportWriter откладывает заголовки ответа до первых данных: пока ничего
+// не отправлено, ошибку ещё можно вернуть обычным JSON
+type exportWriter struct {
+	w       http.ResponseWriter
+	params  model.ExportParams
+	started bool
+}
+
+func (e *exportWriter) Write(p []byte) (int, error) {
+	if !e.started {
+		e.start()
+	}
+	return e.w.Write(p)
+}
+
+func (e *exportWriter) start() {
+	e.started = true
+
+	filename := fmt.Sprintf("%s-%s.%s", e.params.Entity, time.Now().UTC().Format("20060102"), e.params.Format)
+
+	h := e.w.Header()
+	h.Set("Content-Type", records.ContentType(e.params.Format))
+	h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	e.w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"film-library/internal/model"
+	mock_service "film-library/internal/service/mocks"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_Export(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockExport)
+
+	minRating := float32(8)
+	releasedFrom := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	today := time.Now().UTC().Format("20060102")
+
+	write := func(body string) func(context.Context, model.ExportParams, io.Writer) error {
+		return func(_ context.Context, _ model.ExportParams, w io.Writer) error {
+			_, err := io.WriteString(w, body)
+			return err
+		}
+	}
+
+	tests := []struct {
+		name                string
+		query               string
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedContentType string
+		expectedDisposition string
+		expectedBody        string
+	}{
+		{
+			name:  "Films CSV With Filters",
+			query: "?entity=films&min_rating=8&released_from=1990-01-01&actor_id=7&sort_by=name",
+			mockBehavior: func(r *mock_service.MockExport) {
+				r.EXPECT().Export(gomock.Any(), model.ExportParams{
+					Entity: model.ExportFilms, Format: "csv",
+					Films: model.FilmListParams{SortBy: "name", MinRating: &minRating, ReleasedFrom: &releasedFrom, ActorID: 7},
+				}, gomock.Any()).DoAndReturn(write("id,name\n1,Matrix\n"))
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedDisposition: "attachment; filename=films-" + today + ".csv",
+			expectedBody:        "id,name\n1,Matrix\n",
+		},
+		{
+			name:  "Casting NDJSON Without Rows",
+			query: "?entity=casting&format=ndjson&film_id=3&actor_id=7",
+			mockBehavior: func(r *mock_service.MockExport) {
+				r.EXPECT().Export(gomock.Any(), model.ExportParams{
+					Entity: model.ExportCasting, Format: "ndjson", FilmID: 3, ActorID: 7,
+				}, gomock.Any()).Return(nil)
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedDisposition: "attachment; filename=casting-" + today + ".ndjson",
+		},
+		{
+			name:                "Invalid Entity",
+			query:               "?entity=users",
+			mockBehavior:        func(r *mock_service.MockExport) {},
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: "application/json",
+			expectedBody:        `{"status": 400, "message": "Export entity must be films, actors or casting", "code": "invalid_export_entity"}`,
+		},
+		{
+			name:                "Invalid Format",
+			query:               "?entity=actors&format=xml",
+			mockBehavior:        func(r *mock_service.MockExport) {},
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: "application/json",
+			expectedBody:        `{"status": 400, "message": "Export format must be csv, ndjson or json", "code": "unsupported_export_format"}`,
+		},
+		{
+			name:                "Invalid Gender",
+			query:               "?entity=actors&gender=robot",
+			mockBehavior:        func(r *mock_service.MockExport) {},
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: "application/json",
+			expectedBody:        `{"status": 400, "message": "Gender must be male or female", "code": "invalid_gender"}`,
+		},
+		{
+			name:  "Failure Before Data",
+			query: "?entity=actors&format=json",
+			mockBehavior: func(r *mock_service.MockExport) {
+				r.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+			},
+			expectedStatusCode:  http.StatusInternalServerError,
+			expectedContentType: "application/json",
+			expectedBody:        `{"status": 500, "message": "Failed to export", "code": "internal_error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			export := mock_service.NewMockExport(c)
+			test.mockBehavior(export)
+
+			handler := NewExportHandler(export)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/export"+test.query, nil)
+
+			rr := httptest.NewRecorder()
+			handler.Export(rr, req)
+
+			require.Equal(t, test.expectedStatusCode, rr.Code)
+			require.Equal(t, test.expectedContentType, rr.Header().Get("Content-Type"))
+			require.Equal(t, test.expectedDisposition, rr.Header().Get("Content-Disposition"))
+			if test.expectedContentType == "application/json" {
+				require.JSONEq(t, test.expectedBody, rr.Body.String())
+			} else {
+				require.Equal(t, test.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_ExportAbortsOnFailureAfterData(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	export := mock_service.NewMockExport(c)
+	export.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ model.ExportParams, w io.Writer) error {
+			io.WriteString(w, "id,name\n")
+			return errors.New("connection reset")
+		})
+
+	handler := NewExportHandler(export)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/export?entity=films", nil)
+	rr := httptest.NewRecorder()
+
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() { handler.Export(rr, req) })
+	require.Equal(t, http.StatusOK, rr.Code)
+}
@@ -1,47 +1,200 @@
 package handler
 
 import (
+	"film-library/internal/config"
 	"film-library/internal/middleware"
+	"film-library/internal/model"
 	"film-library/internal/service"
+	"film-library/internal/utils/response"
+	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
 
 	httpSwagger "github.com/swaggo/http-swagger"
 
 	_ "film-library/docs"
 )
 
-func InitRoute(services *service.Service) *http.ServeMux {
-	mux := http.NewServeMux()
+func InitRoute(services *service.Service, log *slog.Logger, defaultLocale string, importCfg config.Import) http.Handler {
+	router := NewRouter()
 	secret := os.Getenv("SECRET_KEY")
+	auth := middleware.RequireAuth([]byte(secret), services.Authorization)
+
+	// can - аутентификация плюс проверка права на действие с ресурсом
+	can := func(resource, action string, next http.HandlerFunc) http.HandlerFunc {
+		required := model.Permission{Resource: resource, Actions: []string{action}}
+		return auth(middleware.RequirePermission(services.Access, required)(next))
+	}
 
 	actorHandler := NewActorHandler(services.Actor)
 	movieHandler := NewMovieHandler(services.Movie)
 	actormovieHandler := NewActorMovieHandler(services.ActorMovie)
 	authHandler := NewAuthHandler(services.Authorization)
+	castingHandler := NewCastingHandler(services.Casting)
+	creditHandler := NewCreditHandler(services.Credit)
+	adminHandler := NewAdminHandler(services.Access)
+	trashHandler := NewTrashHandler(services.Trash)
+	auditHandler := NewAuditHandler(services.Audit)
+	importHandler := NewImportHandler(services.Import, importCfg)
+	exportHandler := NewExportHandler(services.Export)
+	statsHandler := NewStatsHandler(services.Stats)
+	taxonomyHandler := NewTaxonomyHandler(services.Taxonomy)
+	reviewHandler := NewReviewHandler(services.Review)
+	watchlistHandler := NewWatchlistHandler(services.Watchlist)
+	recommendationHandler := NewRecommendationHandler(services.Recommendation)
 
-	mux.Handle("/swagger/", httpSwagger.Handler(
+	router.Handle("GET /swagger/", httpSwagger.Handler(
 		httpSwagger.URL("http://localhost:8080/swagger/doc.json"),
 	))
 
 	// Актеры
-	mux.HandleFunc("/actor_create", middleware.RequireAuth([]byte(secret))(actorHandler.HandleActorPost))
-	mux.HandleFunc("/actor_update", middleware.RequireAuth([]byte(secret))(actorHandler.HandleActorPut))
-	mux.HandleFunc("/actor_delete/", middleware.RequireAuth([]byte(secret))(actorHandler.HandleActorDelete))
+	router.HandleFunc("GET /api/v1/actors", can(model.ResourceActors, model.ActionRead, actormovieHandler.GetActors))
+	router.HandleFunc("POST /api/v1/actors", can(model.ResourceActors, model.ActionCreate, actorHandler.CreateActor))
+	router.HandleFunc("GET /api/v1/actors/{id}", can(model.ResourceActors, model.ActionRead, actorHandler.GetActor))
+	router.HandleFunc("PUT /api/v1/actors/{id}", can(model.ResourceActors, model.ActionUpdate, actorHandler.UpdateActor))
+	router.HandleFunc("PATCH /api/v1/actors/{id}", can(model.ResourceActors, model.ActionUpdate, actorHandler.PatchActor))
+	router.HandleFunc("DELETE /api/v1/actors/{id}", can(model.ResourceActors, model.ActionDelete, actorHandler.DeleteActor))
+	router.HandleFunc("GET /api/v1/actors/{id}/films", can(model.ResourceActors, model.ActionRead, actormovieHandler.GetActorFilms))
+	router.HandleFunc("GET /api/v1/people/{id}/credits", can(model.ResourceActors, model.ActionRead, creditHandler.GetFilmography))
 
 	// Фильмы
-	mux.HandleFunc("/film_create", middleware.RequireAuth([]byte(secret))(movieHandler.HandleMoviePost))
-	mux.HandleFunc("/film_update", middleware.RequireAuth([]byte(secret))(movieHandler.HandleMoviePut))
-	mux.HandleFunc("/film_delete/", middleware.RequireAuth([]byte(secret))(movieHandler.HandleMovieDelete))
-	mux.HandleFunc("/films_get_list", middleware.RequireAuth([]byte(secret))(movieHandler.GetAllFilms))
-	mux.HandleFunc("/films/search", middleware.RequireAuth([]byte(secret))(movieHandler.SearchFilm))
+	router.HandleFunc("GET /api/v1/films", can(model.ResourceFilms, model.ActionRead, movieHandler.GetAllFilms))
+	router.HandleFunc("POST /api/v1/films", can(model.ResourceFilms, model.ActionCreate, movieHandler.CreateFilm))
+	router.HandleFunc("GET /api/v1/films/search", can(model.ResourceFilms, model.ActionRead, movieHandler.SearchFilms))
+	router.HandleFunc("GET /api/v1/films/facets", can(model.ResourceFilms, model.ActionRead, taxonomyHandler.GetFilmFacets))
+	router.HandleFunc("GET /api/v1/films/{id}", can(model.ResourceFilms, model.ActionRead, movieHandler.GetFilm))
+	router.HandleFunc("PUT /api/v1/films/{id}", can(model.ResourceFilms, model.ActionUpdate, movieHandler.UpdateFilm))
+	router.HandleFunc("PATCH /api/v1/films/{id}", can(model.ResourceFilms, model.ActionUpdate, movieHandler.PatchFilm))
+	router.HandleFunc("DELETE /api/v1/films/{id}", can(model.ResourceFilms, model.ActionDelete, movieHandler.DeleteFilm))
+
+	// Состав фильма
+	router.HandleFunc("GET /api/v1/films/{id}/actors", can(model.ResourceFilms, model.ActionRead, castingHandler.GetCast))
+	router.HandleFunc("PUT /api/v1/films/{id}/actors", can(model.ResourceFilms, model.ActionUpdate, castingHandler.ReplaceCast))
+	router.HandleFunc("POST /api/v1/films/{id}/actors/{actorId}", can(model.ResourceFilms, model.ActionUpdate, castingHandler.AddCastMember))
+	router.HandleFunc("DELETE /api/v1/films/{id}/actors/{actorId}", can(model.ResourceFilms, model.ActionUpdate, castingHandler.RemoveCastMember))
+
+	// Титры фильма: актёры и съёмочная группа
+	router.HandleFunc("GET /api/v1/films/{id}/credits", can(model.ResourceFilms, model.ActionRead, creditHandler.GetCredits))
+	router.HandleFunc("PUT /api/v1/films/{id}/credits", can(model.ResourceFilms, model.ActionUpdate, creditHandler.ReplaceCredits))
+	router.HandleFunc("POST /api/v1/films/{id}/credits/{personId}/{role}", can(model.ResourceFilms, model.ActionUpdate, creditHandler.AddCredit))
+	router.HandleFunc("DELETE /api/v1/films/{id}/credits/{personId}/{role}", can(model.ResourceFilms, model.ActionUpdate, creditHandler.RemoveCredit))
+
+	// Оценки и рецензии пользователей: каждый меняет только свои
+	router.HandleFunc("GET /api/v1/films/{id}/rating", can(model.ResourceReviews, model.ActionRead, reviewHandler.GetRating))
+	router.HandleFunc("PUT /api/v1/films/{id}/rating", can(model.ResourceReviews, model.ActionCreate, reviewHandler.SetRating))
+	router.HandleFunc("DELETE /api/v1/films/{id}/rating", can(model.ResourceReviews, model.ActionDelete, reviewHandler.DeleteRating))
+	router.HandleFunc("GET /api/v1/films/{id}/reviews", can(model.ResourceReviews, model.ActionRead, reviewHandler.GetFilmReviews))
+	router.HandleFunc("GET /api/v1/films/{id}/review", can(model.ResourceReviews, model.ActionRead, reviewHandler.GetUserReview))
+	router.HandleFunc("PUT /api/v1/films/{id}/review", can(model.ResourceReviews, model.ActionCreate, reviewHandler.SaveReview))
+	router.HandleFunc("DELETE /api/v1/films/{id}/review", can(model.ResourceReviews, model.ActionDelete, reviewHandler.DeleteReview))
+
+	// Личные списки, просмотренные фильмы и лента: маршруты /me работают с записями текущего пользователя
+	router.HandleFunc("GET /api/v1/me/watchlists", can(model.ResourceWatchlists, model.ActionRead, watchlistHandler.GetMyWatchlists))
+	router.HandleFunc("POST /api/v1/me/watchlists", can(model.ResourceWatchlists, model.ActionCreate, watchlistHandler.CreateWatchlist))
+	router.HandleFunc("PUT /api/v1/me/watchlists/{id}", can(model.ResourceWatchlists, model.ActionUpdate, watchlistHandler.UpdateWatchlist))
+	router.HandleFunc("DELETE /api/v1/me/watchlists/{id}", can(model.ResourceWatchlists, model.ActionDelete, watchlistHandler.DeleteWatchlist))
+	router.HandleFunc("PUT /api/v1/me/watchlists/{id}/films", can(model.ResourceWatchlists, model.ActionUpdate, watchlistHandler.ReplaceWatchlistFilms))
+	router.HandleFunc("POST /api/v1/me/watchlists/{id}/films/{filmId}", can(model.ResourceWatchlists, model.ActionUpdate, watchlistHandler.AddWatchlistFilm))
+	router.HandleFunc("DELETE /api/v1/me/watchlists/{id}/films/{filmId}", can(model.ResourceWatchlists, model.ActionUpdate, watchlistHandler.RemoveWatchlistFilm))
+	router.HandleFunc("GET /api/v1/watchlists/{id}", can(model.ResourceWatchlists, model.ActionRead, watchlistHandler.GetWatchlist))
+	router.HandleFunc("GET /api/v1/users/{id}/watchlists", can(model.ResourceWatchlists, model.ActionRead, watchlistHandler.GetUserWatchlists))
+	router.HandleFunc("GET /api/v1/me/watched", can(model.ResourceWatchlists, model.ActionRead, watchlistHandler.GetWatched))
+	router.HandleFunc("PUT /api/v1/me/watched/{filmId}", can(model.ResourceWatchlists, model.ActionCreate, watchlistHandler.SetWatched))
+	router.HandleFunc("DELETE /api/v1/me/watched/{filmId}", can(model.ResourceWatchlists, model.ActionDelete, watchlistHandler.DeleteWatched))
+	router.HandleFunc("GET /api/v1/me/feed", can(model.ResourceWatchlists, model.ActionRead, watchlistHandler.GetFeed))
+
+	// Похожие фильмы и рекомендации из таблиц, которые пересчитывает фоновая задача
+	router.HandleFunc("GET /api/v1/films/{id}/similar", can(model.ResourceFilms, model.ActionRead, recommendationHandler.GetSimilarFilms))
+	router.HandleFunc("GET /api/v1/me/recommendations", can(model.ResourceFilms, model.ActionRead, recommendationHandler.GetRecommendations))
+
+	// Жанры, страны и теги; справочники ведутся с правами на фильмы
+	router.HandleFunc("GET /api/v1/genres", can(model.ResourceFilms, model.ActionRead, taxonomyHandler.GetGenres))
+	router.HandleFunc("POST /api/v1/genres", can(model.ResourceFilms, model.ActionCreate, taxonomyHandler.CreateGenre))
+	router.HandleFunc("GET /api/v1/genres/{id}", can(model.ResourceFilms, model.ActionRead, taxonomyHandler.GetGenre))
+	router.HandleFunc("PUT /api/v1/genres/{id}", can(model.ResourceFilms, model.ActionUpdate, taxonomyHandler.UpdateGenre))
+	router.HandleFunc("DELETE /api/v1/genres/{id}", can(model.ResourceFilms, model.ActionDelete, taxonomyHandler.DeleteGenre))
+	router.HandleFunc("GET /api/v1/countries", can(model.ResourceFilms, model.ActionRead, taxonomyHandler.GetCountries))
+	router.HandleFunc("POST /api/v1/countries", can(model.ResourceFilms, model.ActionCreate, taxonomyHandler.CreateCountry))
+	router.HandleFunc("GET /api/v1/countries/{code}", can(model.ResourceFilms, model.ActionRead, taxonomyHandler.GetCountry))
+	router.HandleFunc("PUT /api/v1/countries/{code}", can(model.ResourceFilms, model.ActionUpdate, taxonomyHandler.UpdateCountry))
+	router.HandleFunc("DELETE /api/v1/countries/{code}", can(model.ResourceFilms, model.ActionDelete, taxonomyHandler.DeleteCountry))
+	router.HandleFunc("GET /api/v1/tags", can(model.ResourceFilms, model.ActionRead, taxonomyHandler.GetTags))
+	router.HandleFunc("POST /api/v1/tags", can(model.ResourceFilms, model.ActionCreate, taxonomyHandler.CreateTag))
+	router.HandleFunc("GET /api/v1/tags/{id}", can(model.ResourceFilms, model.ActionRead, taxonomyHandler.GetTag))
+	router.HandleFunc("PUT /api/v1/tags/{id}", can(model.ResourceFilms, model.ActionUpdate, taxonomyHandler.UpdateTag))
+	router.HandleFunc("DELETE /api/v1/tags/{id}", can(model.ResourceFilms, model.ActionDelete, taxonomyHandler.DeleteTag))
+
+	// Массовая загрузка и выгрузка каталога
+	router.HandleFunc("POST /api/v1/import", can(model.ResourceImport, model.ActionCreate, importHandler.Import))
+	router.HandleFunc("GET /api/v1/export", can(model.ResourceExport, model.ActionRead, exportHandler.Export))
 
-	// Актёры + фильмы
-	mux.HandleFunc("/get_list_actors_films", middleware.RequireAuth([]byte(secret))(actormovieHandler.HandleActorMovieGet))
+	// Статистика каталога
+	router.HandleFunc("GET /api/v1/stats/actors", can(model.ResourceStats, model.ActionRead, statsHandler.GetActorStats))
+	router.HandleFunc("GET /api/v1/stats/actors/{id}", can(model.ResourceStats, model.ActionRead, statsHandler.GetActorStatsByID))
+	router.HandleFunc("GET /api/v1/stats/years", can(model.ResourceStats, model.ActionRead, statsHandler.GetYearStats))
+	router.HandleFunc("GET /api/v1/stats/ratings", can(model.ResourceStats, model.ActionRead, statsHandler.GetRatingHistogram))
+	router.HandleFunc("GET /api/v1/stats/costars", can(model.ResourceStats, model.ActionRead, statsHandler.GetCostars))
 
 	// Аутентификация
-	mux.HandleFunc("/auth/sign_up", authHandler.HandleAuthPost)
-	mux.HandleFunc("/auth/sign_in", authHandler.HandleAuthPost)
+	router.HandleFunc("POST /api/v1/auth/sign_up", authHandler.CreateUser)
+	router.HandleFunc("POST /api/v1/auth/sign_in", authHandler.VerifyUser)
+	router.HandleFunc("POST /api/v1/auth/refresh", authHandler.RefreshTokens)
+	router.HandleFunc("POST /api/v1/auth/logout", auth(authHandler.Logout))
+
+	// Администрирование
+	router.HandleFunc("GET /api/v1/admin/roles", can(model.ResourceUsers, model.ActionRead, adminHandler.GetRoles))
+	router.HandleFunc("PUT /api/v1/admin/users/{id}/role", can(model.ResourceUsers, model.ActionUpdate, adminHandler.AssignRole))
+	router.HandleFunc("GET /api/v1/admin/trash", can(model.ResourceTrash, model.ActionRead, trashHandler.GetTrash))
+	router.HandleFunc("POST /api/v1/admin/trash/films/{id}/restore", can(model.ResourceTrash, model.ActionUpdate, trashHandler.RestoreFilm))
+	router.HandleFunc("POST /api/v1/admin/trash/actors/{id}/restore", can(model.ResourceTrash, model.ActionUpdate, trashHandler.RestoreActor))
+	router.HandleFunc("GET /api/v1/admin/reviews", can(model.ResourceReviews, model.ActionUpdate, reviewHandler.GetReviews))
+	router.HandleFunc("POST /api/v1/admin/reviews/{id}/approve", can(model.ResourceReviews, model.ActionUpdate, reviewHandler.ApproveReview))
+	router.HandleFunc("POST /api/v1/admin/reviews/{id}/hide", can(model.ResourceReviews, model.ActionUpdate, reviewHandler.HideReview))
+	router.HandleFunc("GET /api/v1/admin/audit", can(model.ResourceAudit, model.ActionRead, auditHandler.GetAuditLog))
+
+	// Старые адреса, оставлены для совместимости
+	registerLegacyRoutes(router, []legacyRoute{
+		{"POST /actor_create", "POST /api/v1/actors", can(model.ResourceActors, model.ActionCreate, actorHandler.CreateActor)},
+		{"PUT /actor_update", "PUT /api/v1/actors/{id}", can(model.ResourceActors, model.ActionUpdate, actorHandler.UpdateActor)},
+		{"DELETE /actor_delete/{id}", "DELETE /api/v1/actors/{id}", can(model.ResourceActors, model.ActionDelete, actorHandler.DeleteActor)},
+		{"POST /film_create", "POST /api/v1/films", can(model.ResourceFilms, model.ActionCreate, movieHandler.CreateFilm)},
+		{"PUT /film_update", "PUT /api/v1/films/{id}", can(model.ResourceFilms, model.ActionUpdate, movieHandler.UpdateFilm)},
+		{"DELETE /film_delete/{id}", "DELETE /api/v1/films/{id}", can(model.ResourceFilms, model.ActionDelete, movieHandler.DeleteFilm)},
+		{"GET /films_get_list", "GET /api/v1/films", can(model.ResourceFilms, model.ActionRead, movieHandler.LegacyGetAllFilms)},
+		{"GET /films/search", "GET /api/v1/films/search", can(model.ResourceFilms, model.ActionRead, movieHandler.LegacySearchFilm)},
+		{"GET /get_list_actors_films", "GET /api/v1/actors", can(model.ResourceActors, model.ActionRead, actormovieHandler.LegacyGetActors)},
+		{"POST /auth/sign_up", "POST /api/v1/auth/sign_up", authHandler.CreateUser},
+		{"POST /auth/sign_in", "POST /api/v1/auth/sign_in", authHandler.VerifyUser},
+	})
+
+	return middleware.RequestLogger(log)(middleware.Localize(defaultLocale)(router))
+}
+
+// ifMatch читает из If-Match ожидаемую версию записи для PUT, PATCH и DELETE.
+// Без заголовка отвечает 428; старые маршруты If-Match не присылают, для них
+// версия не проверяется (0). ETag не от этого сервера ни с чем не совпадёт - 412.
+// При ошибке ответ уже записан
+func ifMatch(w http.ResponseWriter, r *http.Request) (int, bool) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		if isLegacy(r) {
+			return 0, true
+		}
+		response.WriteJSONError(w, r, "if_match_required", http.StatusPreconditionRequired)
+		return 0, false
+	}
+
+	version, err := response.IfMatchVersion(header)
+	if err != nil {
+		response.WriteJSONError(w, r, "version_mismatch", http.StatusPreconditionFailed)
+		return 0, false
+	}
+
+	return version, true
+}
 
-	return mux
+// pathID достаёт числовой идентификатор из параметра маршрута, например {id}.
+func pathID(r *http.Request, name string) (int, error) {
+	return strconv.Atoi(r.PathValue(name))
 }
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"film-library/internal/config"
+	"film-library/internal/model"
+	"film-library/internal/service"
+	"film-library/internal/utils/records"
+	"film-library/internal/utils/response"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type ImportHandler struct {
+	service service.Import
+	cfg     config.Import
+}
+
+func NewImportHandler(service service.Import, cfg config.Import) ImportHandler {
+	return ImportHandler{service: service, cfg: cfg}
+}
+
+// @Summary Import Catalog
+// @Security ApiKeyAuth
+// @Tags import
+// @Description Bulk upsert of films, actors or cast links from CSV (with header) or JSON Lines.
+// @Description Films and actors are matched by name, cast rows reference film and actor by name.
+// @Description Invalid rows are skipped and listed in the report; with dry_run=true nothing is saved
+// @ID import-catalog
+// @Accept  text/csv,application/x-ndjson
+// @Produce  json
+// @Param kind query string true "What to import: films, actors or cast"
+// @Param format query string false "csv or ndjson, by default taken from Content-Type"
+// @Param dry_run query bool false "Validate and count without saving"
+// @Param file body string true "CSV columns: films - name, description, release_date, rating, genres, countries, tags (lists separated by |); actors - name, gender, date_of_birth; cast - film, actor, character, billing_order. Export-only columns (id, user_score, votes, film_id, actor_id) are ignored"
+// @Success 200 {object} model.ImportReport
+// @Failure 400,401,403,413,415 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/import [post]
+func (h *ImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	format := q.Get("format")
+	if format == "" {
+		format = records.FormatOf(r.Header.Get("Content-Type"))
+	}
+	if format == "" {
+		response.WriteJSONError(w, r, "unsupported_import_media_type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	dryRun, err := queryBool(q, "dry_run")
+	if err != nil {
+		response.WriteError(w, r, err, "import_failed")
+		return
+	}
+
+	req := model.ImportRequest{Kind: q.Get("kind"), Format: format, DryRun: dryRun}
+
+	// Загрузка и запись большого файла может идти дольше общих таймаутов сервера,
+	// поэтому на время импорта они заменяются своим, тоже конечным
+	if err := h.extendDeadlines(w); err != nil {
+		response.WriteError(w, r, err, "import_failed")
+		return
+	}
+	body := http.MaxBytesReader(w, r.Body, h.cfg.MaxBodyBytes)
+
+	report, err := h.service.Import(r.Context(), req, body)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		response.WriteJSONError(w, r, "import_too_large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		response.WriteError(w, r, err, "import_failed")
+		return
+	}
+
+	for i := range report.Errors {
+		report.Errors[i].Errors = response.LocalizeFieldErrors(r.Context(), report.Errors[i].Errors)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
+
+// extendDeadlines переносит дедлайны чтения и записи соединения на cfg.Timeout
+// от текущего момента. Обёртки без доступа к соединению (ErrNotSupported) пропускаются
+func (h *ImportHandler) extendDeadlines(w http.ResponseWriter) error {
+	deadline := time.Now().Add(h.cfg.Timeout)
+	rc := http.NewResponseController(w)
+
+	if err := rc.SetReadDeadline(deadline); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return fmt.Errorf("failed to set read deadline: %w", err)
+	}
+	if err := rc.SetWriteDeadline(deadline); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return fmt.Errorf("failed to set write deadline: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"film-library/internal/config"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	mock_service "film-library/internal/service/mocks"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_Import(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockImport)
+
+	tests := []struct {
+		name                 string
+		query                string
+		contentType          string
+		maxBodyBytes         int64
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Ok",
+			query:       "?kind=films&dry_run=true",
+			contentType: "text/csv; charset=utf-8",
+			mockBehavior: func(r *mock_service.MockImport) {
+				r.EXPECT().Import(gomock.Any(), model.ImportRequest{Kind: model.ImportFilms, Format: "csv", DryRun: true}, gomock.Any()).
+					Return(model.ImportReport{
+						Kind: model.ImportFilms, DryRun: true, Rows: 3, Created: 1, Updated: 1, Failed: 1,
+						Errors: []model.ImportRowError{{Line: 3, Errors: domain.FieldErrors{
+							{Field: "rating", Rule: "number", Message: "должно быть числом", Key: "validation.number"},
+						}}},
+					}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"kind": "films", "dry_run": true, "rows": 3, "created": 1, "updated": 1, "unchanged": 0, "failed": 1, "errors": [{"line": 3, "errors": [{"field": "rating", "rule": "number", "message": "must be a number"}]}]}`,
+		},
+		{
+			name:        "Format From Query",
+			query:       "?kind=actors&format=ndjson",
+			contentType: "application/octet-stream",
+			mockBehavior: func(r *mock_service.MockImport) {
+				r.EXPECT().Import(gomock.Any(), model.ImportRequest{Kind: model.ImportActors, Format: "ndjson"}, gomock.Any()).
+					Return(model.ImportReport{Kind: model.ImportActors, Rows: 2, Unchanged: 2, Errors: []model.ImportRowError{}}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"kind": "actors", "dry_run": false, "rows": 2, "created": 0, "updated": 0, "unchanged": 2, "failed": 0, "errors": []}`,
+		},
+		{
+			name:                 "Unsupported Media Type",
+			query:                "?kind=films",
+			contentType:          "application/xml",
+			mockBehavior:         func(r *mock_service.MockImport) {},
+			expectedStatusCode:   http.StatusUnsupportedMediaType,
+			expectedResponseBody: `{"status": 415, "message": "Unsupported Content-Type, use text/csv or application/x-ndjson", "code": "unsupported_import_media_type"}`,
+		},
+		{
+			name:                 "Invalid Dry Run",
+			query:                "?kind=films&dry_run=maybe",
+			contentType:          "text/csv",
+			mockBehavior:         func(r *mock_service.MockImport) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Parameter dry_run must be true or false", "code": "invalid_boolean_param"}`,
+		},
+		{
+			name:        "Unknown Column",
+			query:       "?kind=films",
+			contentType: "text/csv",
+			mockBehavior: func(r *mock_service.MockImport) {
+				r.EXPECT().Import(gomock.Any(), model.ImportRequest{Kind: model.ImportFilms, Format: "csv"}, gomock.Any()).
+					Return(model.ImportReport{}, domain.BadRequest("unknown_import_column", "неизвестная колонка \"title\"").WithArgs("title"))
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Unknown column \"title\"", "code": "unknown_import_column"}`,
+		},
+		{
+			name:         "Too Large",
+			query:        "?kind=films",
+			contentType:  "text/csv",
+			maxBodyBytes: 4,
+			mockBehavior: func(r *mock_service.MockImport) {
+				r.EXPECT().Import(gomock.Any(), model.ImportRequest{Kind: model.ImportFilms, Format: "csv"}, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ model.ImportRequest, body io.Reader) (model.ImportReport, error) {
+						_, err := io.ReadAll(body)
+						return model.ImportReport{}, fmt.Errorf("ошибка импорта: %w", err)
+					})
+			},
+			expectedStatusCode:   http.StatusRequestEntityTooLarge,
+			expectedResponseBody: `{"status": 413, "message": "Import file exceeds the size limit", "code": "import_too_large"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			imports := mock_service.NewMockImport(c)
+			test.mockBehavior(imports)
+
+			cfg := config.Import{Timeout: time.Minute, MaxBodyBytes: 1 << 20}
+			if test.maxBodyBytes > 0 {
+				cfg.MaxBodyBytes = test.maxBodyBytes
+			}
+			handler := NewImportHandler(imports, cfg)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/import"+test.query, strings.NewReader("name\nMatrix\n"))
+			req.Header.Set("Content-Type", test.contentType)
+
+			rr := httptest.NewRecorder()
+			handler.Import(rr, req)
+
+			require.Equal(t, test.expectedStatusCode, rr.Code)
+			require.JSONEq(t, test.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"film-library/internal/model"
+	"film-library/internal/utils/response"
+	"film-library/internal/utils/sl"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// legacyRoute — старый адрес API и ресурсный маршрут, который пришёл ему на смену.
+type legacyRoute struct {
+	pattern   string
+	successor string
+	handler   http.HandlerFunc
+}
+
+// registerLegacyRoutes оставляет старые адреса (/film_create, /actor_delete/{id}, ...)
+// рабочими на время миграции клиентов. Каждый вызов пишет предупреждение в лог.
+func registerLegacyRoutes(router *Router, routes []legacyRoute) {
+	for _, route := range routes {
+		router.HandleFunc(route.pattern, deprecated(route.pattern, route.successor)(route.handler))
+	}
+}
+
+// deprecated помечает ответ заголовками Deprecation и Link (RFC 8594)
+// и логирует обращение к устаревшему маршруту.
+func deprecated(pattern, successor string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			sl.FromContext(r.Context()).Warn("deprecated route called",
+				slog.String("route", pattern),
+				slog.String("successor", successor),
+				slog.String("path", r.URL.Path),
+				slog.String("remote_addr", r.RemoteAddr),
+			)
+
+			w.Header().Set("Deprecation", "true")
+			if link := expandSuccessor(successor, r); link != "" {
+				w.Header().Set("Link", "<"+link+`>; rel="successor-version"`)
+			}
+
+			next(w, r.WithContext(context.WithValue(r.Context(), legacyKey{}, true)))
+		}
+	}
+}
+
+type legacyKey struct{}
+
+// isLegacy - пришёл ли запрос через старый адрес
+func isLegacy(r *http.Request) bool {
+	legacy, _ := r.Context().Value(legacyKey{}).(bool)
+	return legacy
+}
+
+// expandSuccessor подставляет {id} из текущего запроса в новый маршрут.
+// Если идентификатор в пути старого запроса не передавался, ссылку не формируем.
+func expandSuccessor(successor string, r *http.Request) string {
+	path := successor
+	if i := strings.IndexByte(path, ' '); i >= 0 {
+		path = path[i+1:]
+	}
+
+	if strings.Contains(path, "{id}") {
+		id := r.PathValue("id")
+		if id == "" {
+			return ""
+		}
+		path = strings.ReplaceAll(path, "{id}", id)
+	}
+
+	return path
+}
+
+// Старые маршруты чтения отвечают в прежнем формате: без обёртки страницы
+// и без ограничения на размер выборки. Новые списки отдаются страницами,
+// поэтому адаптеры проходят их по курсору целиком.
+
+// LegacyGetAllFilms - /films_get_list: все фильмы голым массивом
+func (h *MovieHandler) LegacyGetAllFilms(w http.ResponseWriter, r *http.Request) {
+	params, err := parseFilmListParams(r)
+	if err != nil {
+		response.WriteError(w, r, err, "get_films_failed")
+		return
+	}
+	params.Limit, params.Offset, params.Cursor = model.MaxPageLimit, 0, nil
+
+	if err := params.Validate(); err != nil {
+		response.WriteError(w, r, err, "get_films_failed")
+		return
+	}
+
+	films := make([]model.Film, 0)
+	for {
+		page, err := h.service.GetFilms(r.Context(), params)
+		if err != nil {
+			response.WriteError(w, r, err, "get_films_failed")
+			return
+		}
+		films = append(films, page.Items...)
+
+		if page.NextCursor == "" {
+			break
+		}
+		cursor, err := model.DecodeCursor(page.NextCursor)
+		if err != nil {
+			response.WriteError(w, r, err, "get_films_failed")
+			return
+		}
+		params.Cursor = &cursor
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(films)
+}
+
+// LegacySearchFilm - /films/search: один самый релевантный фильм,
+// пустой фильм, если ничего не нашлось
+func (h *MovieHandler) LegacySearchFilm(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	params := model.FilmSearchParams{
+		Query: strings.TrimSpace(strings.TrimSpace(q.Get("movie")) + " " + strings.TrimSpace(q.Get("actor"))),
+		Limit: 1,
+	}
+
+	if err := params.Validate(); err != nil {
+		response.WriteError(w, r, err, "search_failed")
+		return
+	}
+
+	page, err := h.service.SearchFilms(r.Context(), params)
+	if err != nil {
+		response.WriteError(w, r, err, "search_failed")
+		return
+	}
+
+	var film model.Film
+	if len(page.Items) > 0 {
+		film = page.Items[0].Film
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(film)
+}
+
+// LegacyGetActors - /get_list_actors_films: актёры, у которых есть фильмы,
+// вместе с фильмографией, как раньше отдавал GetAllActorWithFilms
+func (h *ActorMovieHandler) LegacyGetActors(w http.ResponseWriter, r *http.Request) {
+	params := model.ActorListParams{IncludeFilms: true, Limit: model.MaxPageLimit}
+
+	byID := make(map[int]model.ActorWithFilms)
+	order := make([]int, 0)
+	for {
+		page, err := h.service.GetActors(r.Context(), params)
+		if err != nil {
+			response.WriteError(w, r, err, "get_actors_failed")
+			return
+		}
+
+		for _, item := range page.Items {
+			if len(item.Films) == 0 {
+				continue
+			}
+			if _, ok := byID[item.Id]; !ok {
+				order = append(order, item.Id)
+			}
+			byID[item.Id] = model.ActorWithFilms{Actor: item.Actor, Films: item.Films}
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		cursor, err := model.DecodeCursor(page.NextCursor)
+		if err != nil {
+			response.WriteError(w, r, err, "get_actors_failed")
+			return
+		}
+		params.Cursor = &cursor
+	}
+
+	// Раньше обработчик разворачивал map[int]model.ActorWithFilms в массив, так и отдаём
+	actors := make([]model.ActorWithFilms, 0, len(byID))
+	for _, id := range order {
+		actors = append(actors, byID[id])
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(actors)
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"errors"
+	"film-library/internal/model"
+	"film-library/internal/service"
+	mock_service "film-library/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_LegacyGetAllFilms(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockMovie)
+
+	next := model.Cursor{Sort: "name:asc", Key: "Matrix", ID: 1}
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "All Pages As Array",
+			query: "?sort_by=name&limit=1",
+			mockBehavior: func(r *mock_service.MockMovie) {
+				params := model.FilmListParams{SortBy: "name", Limit: model.MaxPageLimit}
+				r.EXPECT().GetFilms(gomock.Any(), params).Return(model.FilmPage{
+					Items:      []model.Film{{Id: 1, Name: "Matrix", ListActors: []model.CastMember{}}},
+					Total:      2,
+					Limit:      model.MaxPageLimit,
+					NextCursor: next.Encode(),
+				}, nil)
+
+				params.Cursor = &next
+				r.EXPECT().GetFilms(gomock.Any(), params).Return(model.FilmPage{
+					Items: []model.Film{{Id: 2, Name: "Mad Max", ListActors: []model.CastMember{}}},
+					Total: 2,
+					Limit: model.MaxPageLimit,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `[
+				{"id": 1, "name": "Matrix", "description": "", "release_date": "0001-01-01T00:00:00Z", "rating": 0, "list_actors": []},
+				{"id": 2, "name": "Mad Max", "description": "", "release_date": "0001-01-01T00:00:00Z", "rating": 0, "list_actors": []}
+			]`,
+		},
+		{
+			name: "Empty",
+			mockBehavior: func(r *mock_service.MockMovie) {
+				r.EXPECT().GetFilms(gomock.Any(), model.FilmListParams{Limit: model.MaxPageLimit}).Return(
+					model.FilmPage{Items: []model.Film{}, Limit: model.MaxPageLimit}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `[]`,
+		},
+		{
+			name:                 "Wrong Sort",
+			query:                "?sort_by=actor",
+			mockBehavior:         func(r *mock_service.MockMovie) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Invalid sort field", "code": "invalid_sort"}`,
+		},
+		{
+			name: "Server Error",
+			mockBehavior: func(r *mock_service.MockMovie) {
+				r.EXPECT().GetFilms(gomock.Any(), gomock.Any()).Return(model.FilmPage{}, errors.New("Failed to get films"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"status": 500, "message": "Failed to get films", "code": "internal_error"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			movie := mock_service.NewMockMovie(c)
+			tc.mockBehavior(movie)
+
+			handler := NewMovieHandler(&service.Service{Movie: movie})
+
+			router := NewRouter()
+			registerLegacyRoutes(router, []legacyRoute{
+				{"GET /films_get_list", "GET /api/v1/films", handler.LegacyGetAllFilms},
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/films_get_list"+tc.query, nil)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.Equal(t, "true", rr.Header().Get("Deprecation"))
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_LegacySearchFilm(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockMovie)
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Best Match",
+			query: "?actor=Дизель&movie=Форсаж",
+			mockBehavior: func(r *mock_service.MockMovie) {
+				r.EXPECT().SearchFilms(gomock.Any(), model.FilmSearchParams{Query: "Форсаж Дизель", Limit: 1}).Return(model.FilmSearchPage{
+					Items: []model.FilmSearchResult{{
+						Film: model.Film{Id: 1, Name: "Форсаж", ListActors: []model.CastMember{
+							{Actor: model.Actor{Id: 3, Name: "Вин Дизель", Gender: "male", DateOfBirth: parseTime("1967-07-18T00:00:00Z")}},
+						}},
+						Rank:       1.5,
+						Highlights: model.SearchHighlights{Name: "<mark>Форсаж</mark>", Actors: []string{}},
+					}},
+					Total: 4,
+					Limit: 1,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"id": 1, "name": "Форсаж", "description": "", "release_date": "0001-01-01T00:00:00Z", "rating": 0,
+				"list_actors": [{"id": 3, "name": "Вин Дизель", "gender": "male", "date_of_birth": "1967-07-18T00:00:00Z"}]}`,
+		},
+		{
+			name:  "Nothing Found",
+			query: "?movie=Неизвестный",
+			mockBehavior: func(r *mock_service.MockMovie) {
+				r.EXPECT().SearchFilms(gomock.Any(), model.FilmSearchParams{Query: "Неизвестный", Limit: 1}).Return(
+					model.FilmSearchPage{Items: []model.FilmSearchResult{}, Limit: 1}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"id": 0, "name": "", "description": "", "release_date": "0001-01-01T00:00:00Z", "rating": 0, "list_actors": null}`,
+		},
+		{
+			name:                 "Empty Query",
+			query:                "?actor=&movie=",
+			mockBehavior:         func(r *mock_service.MockMovie) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Search query is required", "code": "search_query_required"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			movie := mock_service.NewMockMovie(c)
+			tc.mockBehavior(movie)
+
+			handler := NewMovieHandler(&service.Service{Movie: movie})
+
+			router := NewRouter()
+			registerLegacyRoutes(router, []legacyRoute{
+				{"GET /films/search", "GET /api/v1/films/search", handler.LegacySearchFilm},
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/films/search"+tc.query, nil)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_LegacyGetActors(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockActorMovie)
+
+	born := parseTime("1964-09-02T00:00:00Z")
+	next := model.Cursor{Sort: "name:asc", Key: "Keanu Reeves", ID: 7}
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Actors With Films",
+			mockBehavior: func(r *mock_service.MockActorMovie) {
+				params := model.ActorListParams{IncludeFilms: true, Limit: model.MaxPageLimit}
+				r.EXPECT().GetActors(gomock.Any(), params).Return(model.ActorPage{
+					Items: []model.ActorListItem{
+						{
+							Actor:     model.Actor{Id: 7, Name: "Keanu Reeves", Gender: "male", DateOfBirth: born},
+							FilmCount: 1,
+							Films:     []model.Film{{Id: 1, Name: "Matrix"}},
+						},
+						{
+							Actor: model.Actor{Id: 9, Name: "Keanu Newcomer", Gender: "male", DateOfBirth: born},
+							Films: []model.Film{},
+						},
+					},
+					Total:      3,
+					Limit:      model.MaxPageLimit,
+					NextCursor: next.Encode(),
+				}, nil)
+
+				params.Cursor = &next
+				r.EXPECT().GetActors(gomock.Any(), params).Return(model.ActorPage{
+					Items: []model.ActorListItem{{
+						Actor:     model.Actor{Id: 4, Name: "Laurence Fishburne", Gender: "male", DateOfBirth: born},
+						FilmCount: 1,
+						Films:     []model.Film{{Id: 1, Name: "Matrix"}},
+					}},
+					Total: 3,
+					Limit: model.MaxPageLimit,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `[
+				{"actor": {"id": 7, "name": "Keanu Reeves", "gender": "male", "date_of_birth": "1964-09-02T00:00:00Z"},
+				 "films": [{"id": 1, "name": "Matrix", "description": "", "release_date": "0001-01-01T00:00:00Z", "rating": 0, "list_actors": null}]},
+				{"actor": {"id": 4, "name": "Laurence Fishburne", "gender": "male", "date_of_birth": "1964-09-02T00:00:00Z"},
+				 "films": [{"id": 1, "name": "Matrix", "description": "", "release_date": "0001-01-01T00:00:00Z", "rating": 0, "list_actors": null}]}
+			]`,
+		},
+		{
+			name: "Server Error",
+			mockBehavior: func(r *mock_service.MockActorMovie) {
+				r.EXPECT().GetActors(gomock.Any(), gomock.Any()).Return(model.ActorPage{}, errors.New("pq: connection refused"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"status": 500, "message": "Failed to get actors", "code": "internal_error"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			actorMovie := mock_service.NewMockActorMovie(c)
+			tc.mockBehavior(actorMovie)
+
+			handler := NewActorMovieHandler(&service.Service{ActorMovie: actorMovie})
+
+			router := NewRouter()
+			registerLegacyRoutes(router, []legacyRoute{
+				{"GET /get_list_actors_films", "GET /api/v1/actors", handler.LegacyGetActors},
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/get_list_actors_films", nil)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
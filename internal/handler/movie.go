@@ -4,11 +4,8 @@ import (
 	"encoding/json"
 	"film-library/internal/model"
 	"film-library/internal/service"
-	authmid "film-library/internal/utils/auth_mid"
 	"film-library/internal/utils/response"
-	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
 )
 
@@ -20,43 +17,6 @@ func NewMovieHandler(service service.Movie) MovieHandler {
 	return MovieHandler{service: service}
 }
 
-func (h *MovieHandler) HandleMovieGet(w http.ResponseWriter, r *http.Request) {
-	switch {
-	case r.URL.Path == "/films" && r.Method == http.MethodGet:
-		h.GetAllFilms(w, r)
-	case r.URL.Path == "/films/search" && r.Method == http.MethodGet:
-		h.SearchFilm(w, r)
-	default:
-		response.WriteJSONError(w, "Method not allowed", http.StatusNotFound)
-	}
-}
-func (h *MovieHandler) HandleMoviePost(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodPost:
-		h.CreateFilm(w, r)
-	default:
-		response.WriteJSONError(w, "Method not allowed", http.StatusNotFound)
-	}
-}
-
-func (h *MovieHandler) HandleMoviePut(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodPut:
-		h.UpdateFilm(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
-}
-
-func (h *MovieHandler) HandleMovieDelete(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodDelete:
-		h.DeleteFilm(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
-}
-
 // @Summary Create Film
 // @Security ApiKeyAuth
 // @Tags film
@@ -66,36 +26,61 @@ func (h *MovieHandler) HandleMovieDelete(w http.ResponseWriter, r *http.Request)
 // @Produce  json
 // @Param film body model.Film true "Create Film"
 // @Success 201 {object} model.Film
-// @Failure 400,403 {object} response.ErrorResponse
-// @Failure 500 {object} response.ErrorResponse
-// @Failure default {object} response.ErrorResponse
-// @Router /film_create [post]
+// @Failure 400,403,409 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films [post]
 func (h *MovieHandler) CreateFilm(w http.ResponseWriter, r *http.Request) {
-	if authmid.IsAdmin(r) {
-		response.WriteJSONError(w, "Forbidden: admin access required", http.StatusForbidden)
-		return
-	}
-
 	var film model.Film
 	if err := json.NewDecoder(r.Body).Decode(&film); err != nil {
-		response.WriteJSONError(w, "Invalid request body", http.StatusBadRequest)
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
 		return
 	}
 
 	if err := film.Validate(); err != nil {
-		response.WriteJSONError(w, fmt.Sprintf("%v", err.Error()), http.StatusBadRequest)
+		response.WriteError(w, r, err, "invalid_request_body")
 		return
 	}
 
-	err := h.service.AddMovie(r.Context(), film)
+	created, err := h.service.AddMovie(r.Context(), film)
 	if err != nil {
-		response.WriteJSONError(w, "Failed to create film", http.StatusInternalServerError)
+		response.WriteError(w, r, err, "create_film_failed")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(film)
+	response.WriteJSONVersion(w, r, http.StatusCreated, created, created.Version)
+}
+
+// @Summary Get Film
+// @Security ApiKeyAuth
+// @Tags film
+// @Description Get film with its actors. Supports conditional requests via If-None-Match
+// @ID get-film
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Param If-None-Match header string false "ETag from previous response"
+// @Success 200 {object} model.Film
+// @Success 304
+// @Failure 400,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id} [get]
+func (h *MovieHandler) GetFilm(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	film, err := h.service.GetFilmByID(r.Context(), id)
+	if err != nil {
+		response.WriteError(w, r, err, "get_film_failed")
+		return
+	}
+
+	response.WriteJSONVersioned(w, r, film, film.Version)
 }
 
 // @Summary Update Film
@@ -105,38 +90,94 @@ func (h *MovieHandler) CreateFilm(w http.ResponseWriter, r *http.Request) {
 // @ID update-film
 // @Accept  json
 // @Produce  json
+// @Param id path int true "Film ID"
+// @Param If-Match header string true "ETag from GET; * skips the version check"
 // @Param film body model.Film true "Update Film"
 // @Success 201 {object} model.Film
-// @Failure 400,403 {object} response.ErrorResponse
-// @Failure 500 {object} response.ErrorResponse
-// @Failure default {object} response.ErrorResponse
-// @Router /film_update [put]
+// @Failure 400,403,404,409,412,428 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id} [put]
 func (h *MovieHandler) UpdateFilm(w http.ResponseWriter, r *http.Request) {
-	if authmid.IsAdmin(r) {
-		response.WriteJSONError(w, "Forbidden: admin access required", http.StatusForbidden)
+	var film model.Film
+	if err := json.NewDecoder(r.Body).Decode(&film); err != nil {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
 		return
 	}
 
-	var film model.Film
-	if err := json.NewDecoder(r.Body).Decode(&film); err != nil {
-		response.WriteJSONError(w, "Invalid request body", http.StatusBadRequest)
+	// Новый маршрут передаёт id в пути, старый /film_update — в теле запроса
+	if r.PathValue("id") != "" {
+		id, err := pathID(r, "id")
+		if err != nil {
+			response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+			return
+		}
+		film.Id = id
+	}
+
+	version, ok := ifMatch(w, r)
+	if !ok {
 		return
 	}
+	film.Version = version
 
 	if err := film.Validate(); err != nil {
-		response.WriteJSONError(w, fmt.Sprintf("%v", err.Error()), http.StatusBadRequest)
+		response.WriteError(w, r, err, "invalid_request_body")
 		return
 	}
 
-	err := h.service.UpdateMovie(r.Context(), film)
+	updated, err := h.service.UpdateMovie(r.Context(), film)
 	if err != nil {
-		response.WriteJSONError(w, "Failed to update film", http.StatusInternalServerError)
+		response.WriteError(w, r, err, "update_film_failed")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(film)
+	response.WriteJSONVersion(w, r, http.StatusCreated, updated, updated.Version)
+}
+
+// @Summary Patch Film
+// @Security ApiKeyAuth
+// @Tags film
+// @Description Partially update film with JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902).
+// @Description The patched film is validated as a whole; only changed columns are written.
+// @Description Cast is read-only here, use /api/v1/films/{id}/actors
+// @ID patch-film
+// @Accept  application/merge-patch+json,application/json-patch+json,json
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Param If-Match header string true "ETag from GET; * skips the version check"
+// @Param patch body object true "Merge patch object or array of JSON Patch operations"
+// @Success 200 {object} model.Film
+// @Failure 400,403,404,409,412,415,428 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id} [patch]
+func (h *MovieHandler) PatchFilm(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	version, ok := ifMatch(w, r)
+	if !ok {
+		return
+	}
+
+	patch, ok := readPatch(w, r)
+	if !ok {
+		return
+	}
+
+	film, err := h.service.PatchMovie(r.Context(), id, version, patch)
+	if err != nil {
+		response.WriteError(w, r, err, "update_film_failed")
+		return
+	}
+
+	response.WriteJSONVersion(w, r, http.StatusOK, film, film.Version)
 }
 
 // @Summary Delete Film
@@ -146,34 +187,28 @@ func (h *MovieHandler) UpdateFilm(w http.ResponseWriter, r *http.Request) {
 // @ID delete-film
 // @Accept  json
 // @Produce  json
-// @Param id query int true "Film ID"
+// @Param id path int true "Film ID"
+// @Param If-Match header string true "ETag from GET; * skips the version check"
 // @Success 200 {object} map[string]string
-// @Failure 400,403 {object} response.ErrorResponse
-// @Failure 500 {object} response.ErrorResponse
-// @Failure default {object} response.ErrorResponse
-// @Router /film_delete/{id} [delete]
+// @Failure 400,403,404,412,428 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id} [delete]
 func (h *MovieHandler) DeleteFilm(w http.ResponseWriter, r *http.Request) {
-	if authmid.IsAdmin(r) {
-		response.WriteJSONError(w, "Forbidden: admin access required", http.StatusForbidden)
-		return
-	}
-
-	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
-	if len(parts) < 2 {
-		response.WriteJSONError(w, "Missing film ID", http.StatusBadRequest)
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
 		return
 	}
 
-	idStr := parts[len(parts)-1]
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		response.WriteJSONError(w, "Invalid film ID", http.StatusBadRequest)
+	version, ok := ifMatch(w, r)
+	if !ok {
 		return
 	}
 
-	err = h.service.DeleteMovie(r.Context(), id)
+	err = h.service.DeleteMovie(r.Context(), id, version)
 	if err != nil {
-		response.WriteJSONError(w, "Failed to delete film", http.StatusInternalServerError)
+		response.WriteError(w, r, err, "delete_film_failed")
 		return
 	}
 
@@ -190,66 +225,154 @@ func (h *MovieHandler) DeleteFilm(w http.ResponseWriter, r *http.Request) {
 // @Summary Get All Films
 // @Security ApiKeyAuth
 // @Tags film
-// @Description Get all films with optional sorting
+// @Description Get films page with filters, sorting and limit/offset or cursor pagination
 // @ID get-all-films
 // @Accept  json
 // @Produce  json
-// @Param sort_by query string false "Sort films by field (optional)"
-// @Success 200 {array} model.Film
-// @Failure 400 {object} response.ErrorResponse
-// @Failure 500 {object} response.ErrorResponse
-// @Failure default {object} response.ErrorResponse
-// @Router /films_get_list [get]
+// @Param sort_by query string false "Sort field: name, release_date, rating (default), user_score"
+// @Param order query string false "Sort direction: asc or desc"
+// @Param min_rating query number false "Minimal rating"
+// @Param max_rating query number false "Maximal rating"
+// @Param released_from query string false "Release date from (YYYY-MM-DD)"
+// @Param released_to query string false "Release date to (YYYY-MM-DD)"
+// @Param actor_id query int false "Only films with this actor"
+// @Param name query string false "Film name prefix"
+// @Param genre query []string false "Only films with all these genres (repeat or comma-separated)" collectionFormat(multi)
+// @Param country query []string false "Only films from all these countries, ISO 3166-1 alpha-2 codes" collectionFormat(multi)
+// @Param tag query []string false "Only films with all these tags" collectionFormat(multi)
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Param cursor query string false "Cursor from next_cursor or prev_cursor"
+// @Success 200 {object} model.FilmPage
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films [get]
 func (h *MovieHandler) GetAllFilms(w http.ResponseWriter, r *http.Request) {
-	var listFilms []model.Film
-	sortBy := r.URL.Query().Get("sort_by")
+	params, err := parseFilmListParams(r)
+	if err != nil {
+		response.WriteError(w, r, err, "get_films_failed")
+		return
+	}
 
-	if err := model.ValidateSortFilm(sortBy); err != nil {
-		response.WriteJSONError(w, fmt.Sprintf("%v", err.Error()), http.StatusBadRequest)
+	if err := params.Validate(); err != nil {
+		response.WriteError(w, r, err, "get_films_failed")
 		return
 	}
 
-	listFilms, err := h.service.GetFilms(r.Context(), sortBy)
+	page, err := h.service.GetFilms(r.Context(), params)
 	if err != nil {
-		response.WriteJSONError(w, "Failed to get films", http.StatusInternalServerError)
+		response.WriteError(w, r, err, "get_films_failed")
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(listFilms)
+	json.NewEncoder(w).Encode(page)
+}
+
+// parseFilmListParams разбирает query-параметры списка фильмов
+func parseFilmListParams(r *http.Request) (model.FilmListParams, error) {
+	q := r.URL.Query()
+
+	params := model.FilmListParams{
+		SortBy:     q.Get("sort_by"),
+		Order:      q.Get("order"),
+		NamePrefix: strings.TrimSpace(q.Get("name")),
+		Labels:     queryLabelFilter(q),
+	}
+
+	var err error
+	if params.Limit, err = queryInt(q, "limit", model.DefaultPageLimit); err != nil {
+		return params, err
+	}
+	if params.Offset, err = queryInt(q, "offset", 0); err != nil {
+		return params, err
+	}
+	if params.ActorID, err = queryInt(q, "actor_id", 0); err != nil {
+		return params, err
+	}
+	if params.MinRating, err = queryFloat(q, "min_rating"); err != nil {
+		return params, err
+	}
+	if params.MaxRating, err = queryFloat(q, "max_rating"); err != nil {
+		return params, err
+	}
+	if params.ReleasedFrom, err = queryDate(q, "released_from"); err != nil {
+		return params, err
+	}
+	if params.ReleasedTo, err = queryDate(q, "released_to"); err != nil {
+		return params, err
+	}
+
+	if c := q.Get("cursor"); c != "" {
+		cursor, err := model.DecodeCursor(c)
+		if err != nil {
+			return params, err
+		}
+		params.Cursor = &cursor
+	}
+
+	return params, nil
 }
 
-// @Summary Search Film
+// @Summary Search Films
 // @Security ApiKeyAuth
 // @Tags film
-// @Description Search films by actor and/or movie name
+// @Description Full-text search over film titles, descriptions and actor names with typo tolerance
 // @ID search-film
 // @Accept  json
 // @Produce  json
-// @Param actor query string false "Actor name to search for"
-// @Param movie query string false "Movie title to search for"
-// @Success 200 {array} model.Film
-// @Failure 400 {object} response.ErrorResponse
-// @Failure 500 {object} response.ErrorResponse
-// @Failure default {object} response.ErrorResponse
-// @Router /films/search [get]
-func (h *MovieHandler) SearchFilm(w http.ResponseWriter, r *http.Request) {
-	var filmSearch model.Film
+// @Param q query string false "Search query"
+// @Param lang query string false "Stemming language: ru, en (both by default)"
+// @Param movie query string false "Movie title (deprecated, use q)"
+// @Param actor query string false "Actor name (deprecated, use q)"
+// @Param genre query []string false "Only films with all these genres" collectionFormat(multi)
+// @Param country query []string false "Only films from all these countries" collectionFormat(multi)
+// @Param tag query []string false "Only films with all these tags" collectionFormat(multi)
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} model.FilmSearchPage
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/search [get]
+func (h *MovieHandler) SearchFilms(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	// Старые клиенты передают название фильма и имя актёра отдельно
+	query := strings.TrimSpace(q.Get("q"))
+	if query == "" {
+		query = strings.TrimSpace(strings.TrimSpace(q.Get("movie")) + " " + strings.TrimSpace(q.Get("actor")))
+	}
+
+	params := model.FilmSearchParams{
+		Query:  query,
+		Lang:   q.Get("lang"),
+		Labels: queryLabelFilter(q),
+	}
 
-	actor, movie := strings.TrimSpace(r.URL.Query().Get("actor")), strings.TrimSpace(r.URL.Query().Get("movie"))
+	var err error
+	if params.Limit, err = queryInt(q, "limit", model.DefaultPageLimit); err != nil {
+		response.WriteError(w, r, err, "search_failed")
+		return
+	}
+	if params.Offset, err = queryInt(q, "offset", 0); err != nil {
+		response.WriteError(w, r, err, "search_failed")
+		return
+	}
 
-	if err := filmSearch.ValidateFilmSearchParams(movie, actor); err != nil {
-		response.WriteJSONError(w, fmt.Sprintf("%v", err.Error()), http.StatusBadRequest)
+	if err := params.Validate(); err != nil {
+		response.WriteError(w, r, err, "search_failed")
 		return
 	}
 
-	films, err := h.service.SearchFilm(r.Context(), actor, movie)
+	page, err := h.service.SearchFilms(r.Context(), params)
 	if err != nil {
-		response.WriteJSONError(w, fmt.Sprintf("Search failed: %v", err), http.StatusInternalServerError)
+		response.WriteError(w, r, err, "search_failed")
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(films)
+	json.NewEncoder(w).Encode(page)
 }
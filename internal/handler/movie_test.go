@@ -3,6 +3,9 @@ package handler
 import (
 	"bytes"
 	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/i18n"
+	"film-library/internal/middleware"
 	"film-library/internal/model"
 	"film-library/internal/service"
 	mock_service "film-library/internal/service/mocks"
@@ -23,6 +26,7 @@ func TestHandler_CreateMovie(t *testing.T) {
 		inputUser            model.Film
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
+		expectedETag         string
 		expectedResponseBody string
 	}{
 		{
@@ -35,10 +39,13 @@ func TestHandler_CreateMovie(t *testing.T) {
 				Rating:      9.3,
 			},
 			mockBehavior: func(r *mock_service.MockMovie, actor model.Film) {
-				r.EXPECT().AddMovie(gomock.Any(), actor).Return(nil)
+				created := actor
+				created.Id, created.Version = 5, 1
+				r.EXPECT().AddMovie(gomock.Any(), actor).Return(created, nil)
 			},
 			expectedStatusCode:   http.StatusCreated,
-			expectedResponseBody: `{"id": 0, "name": "name", "description": "description", "release_date": "2004-04-10T21:12:05+03:00", "rating": 9.3, "list_actors": null}`,
+			expectedETag:         `^"1-[0-9a-f]{16}"$`,
+			expectedResponseBody: `{"id": 5, "name": "name", "description": "description", "release_date": "2004-04-10T21:12:05+03:00", "rating": 9.3, "list_actors": null}`,
 		},
 		{
 			name:      "Wrong input NAME",
@@ -50,8 +57,31 @@ func TestHandler_CreateMovie(t *testing.T) {
 				Rating:      9.3,
 			},
 			mockBehavior:         func(r *mock_service.MockMovie, actor model.Film) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"message": "название фильма должно быть от 1 до 150 символов"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/films", "code": "validation_failed", "errors": [{"field": "name", "rule": "required", "message": "is required"}]}`,
+		},
+		{
+			name:                 "Wrong Nested Actors",
+			inputBody:            `{"name": "name", "rating": 11, "list_actors": [{"name": "Keanu Reeves", "gender": "male"}, {"name": "", "gender": "robot"}]}`,
+			inputUser:            model.Film{},
+			mockBehavior:         func(r *mock_service.MockMovie, actor model.Film) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/films", "code": "validation_failed", "errors": [{"field": "rating", "rule": "max", "params": ["10"], "message": "must be at most 10"}, {"field": "list_actors[1].name", "rule": "required", "message": "is required"}, {"field": "list_actors[1].gender", "rule": "oneof", "params": ["male", "female"], "message": "must be one of: male, female"}]}`,
+		},
+		{
+			name:      "Film Exists",
+			inputBody: `{"name": "name", "description": "description", "release_date": "2004-04-10T21:12:05+03:00", "rating": 9.3}`,
+			inputUser: model.Film{
+				Name:        "name",
+				Description: "description",
+				Releasedate: parseTime("2004-04-10T21:12:05+03:00"),
+				Rating:      9.3,
+			},
+			mockBehavior: func(r *mock_service.MockMovie, actor model.Film) {
+				r.EXPECT().AddMovie(gomock.Any(), actor).Return(model.Film{}, domain.Conflict("film_exists", "фильм с таким названием уже существует"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"status": 409, "message": "Film with this name already exists", "code": "film_exists"}`,
 		},
 		{
 			name:      "Service Error",
@@ -63,10 +93,10 @@ func TestHandler_CreateMovie(t *testing.T) {
 				Rating:      9.3,
 			},
 			mockBehavior: func(r *mock_service.MockMovie, actor model.Film) {
-				r.EXPECT().AddMovie(gomock.Any(), actor).Return(errors.New("Failed to create film"))
+				r.EXPECT().AddMovie(gomock.Any(), actor).Return(model.Film{}, errors.New("Failed to create film"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"message": "Failed to create film"}`,
+			expectedResponseBody: `{"status": 500, "message": "Failed to create film", "code": "internal_error"}`,
 		},
 	}
 
@@ -88,6 +118,9 @@ func TestHandler_CreateMovie(t *testing.T) {
 			handler.CreateFilm(rr, req)
 
 			require.Equal(t, tc.expectedStatusCode, rr.Code)
+			if tc.expectedETag != "" {
+				require.Regexp(t, tc.expectedETag, rr.Header().Get("ETag"))
+			}
 			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
 		})
 	}
@@ -114,7 +147,7 @@ func TestHandler_UpdateMovie(t *testing.T) {
 				Rating:      9.3,
 			},
 			mockBehavior: func(r *mock_service.MockMovie, actor model.Film) {
-				r.EXPECT().UpdateMovie(gomock.Any(), actor).Return(nil)
+				r.EXPECT().UpdateMovie(gomock.Any(), actor).Return(actor, nil)
 			},
 			expectedStatusCode:   http.StatusCreated,
 			expectedResponseBody: `{"id": 0, "name": "name", "description": "description", "release_date": "2004-04-10T21:12:05+03:00", "rating": 9.3, "list_actors": null}`,
@@ -129,8 +162,8 @@ func TestHandler_UpdateMovie(t *testing.T) {
 				Rating:      9.3,
 			},
 			mockBehavior:         func(r *mock_service.MockMovie, actor model.Film) {},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"message": "название фильма должно быть от 1 до 150 символов"}`,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/films", "code": "validation_failed", "errors": [{"field": "name", "rule": "required", "message": "is required"}]}`,
 		},
 		{
 			name:      "Service Error",
@@ -142,10 +175,10 @@ func TestHandler_UpdateMovie(t *testing.T) {
 				Rating:      9.3,
 			},
 			mockBehavior: func(r *mock_service.MockMovie, actor model.Film) {
-				r.EXPECT().UpdateMovie(gomock.Any(), actor).Return(errors.New("Failed to update film"))
+				r.EXPECT().UpdateMovie(gomock.Any(), actor).Return(model.Film{}, errors.New("Failed to update film"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"message": "Failed to update film"}`,
+			expectedResponseBody: `{"status": 500, "message": "Failed to update film", "code": "internal_error"}`,
 		},
 	}
 
@@ -155,6 +188,7 @@ func TestHandler_UpdateMovie(t *testing.T) {
 			defer c.Finish()
 
 			auth := mock_service.NewMockMovie(c)
+			tc.inputUser.Version = 3 // из If-Match
 			tc.mockBehavior(auth, tc.inputUser)
 
 			services := &service.Service{Movie: auth}
@@ -162,6 +196,7 @@ func TestHandler_UpdateMovie(t *testing.T) {
 
 			req := httptest.NewRequest(http.MethodPost, "/films", bytes.NewBufferString(tc.inputBody))
 			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-Match", `"3-0123456789abcdef"`)
 
 			rr := httptest.NewRecorder()
 			handler.UpdateFilm(rr, req)
@@ -186,7 +221,7 @@ func TestHandler_DeleteMovie(t *testing.T) {
 			name:       "Ok",
 			queryParam: `0`,
 			mockBehavior: func(r *mock_service.MockMovie, id int) {
-				r.EXPECT().DeleteMovie(gomock.Any(), id).Return(nil)
+				r.EXPECT().DeleteMovie(gomock.Any(), id, 2).Return(nil)
 			},
 			expectedStatusCode:   http.StatusOK,
 			expectedResponseBody: `{"message": "movie deleted successfully"}`,
@@ -196,16 +231,25 @@ func TestHandler_DeleteMovie(t *testing.T) {
 			queryParam:           `first`,
 			mockBehavior:         func(r *mock_service.MockMovie, id int) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"message": "Invalid film ID"}`,
+			expectedResponseBody: `{"status": 400, "message": "Invalid film ID", "code": "invalid_film_id"}`,
+		},
+		{
+			name:       "Not Found",
+			queryParam: `0`,
+			mockBehavior: func(r *mock_service.MockMovie, id int) {
+				r.EXPECT().DeleteMovie(gomock.Any(), id, 2).Return(domain.NotFound("film_not_found", "фильм не найден"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"status": 404, "message": "Film not found", "code": "film_not_found"}`,
 		},
 		{
 			name:       "Error Service",
 			queryParam: `0`,
 			mockBehavior: func(r *mock_service.MockMovie, id int) {
-				r.EXPECT().DeleteMovie(gomock.Any(), id).Return(errors.New("Failed to delete film"))
+				r.EXPECT().DeleteMovie(gomock.Any(), id, 2).Return(errors.New("Failed to delete film"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"message": "Failed to delete film"}`,
+			expectedResponseBody: `{"status": 500, "message": "Failed to delete film", "code": "internal_error"}`,
 		},
 	}
 
@@ -220,7 +264,9 @@ func TestHandler_DeleteMovie(t *testing.T) {
 			services := &service.Service{Movie: auth}
 			handler := NewMovieHandler(services)
 
-			req := httptest.NewRequest(http.MethodDelete, "/film_del/"+tc.queryParam, nil)
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/films/"+tc.queryParam, nil)
+			req.Header.Set("If-Match", `"2"`)
+			req.SetPathValue("id", tc.queryParam)
 			req.Header.Set("Content-Type", "application/json")
 
 			rr := httptest.NewRecorder()
@@ -233,39 +279,99 @@ func TestHandler_DeleteMovie(t *testing.T) {
 }
 
 func TestHandler_GetAllFilms(t *testing.T) {
-	type mockBehavior func(r *mock_service.MockMovie, sortBy string)
+	type mockBehavior func(r *mock_service.MockMovie, params model.FilmListParams)
+
+	minRating := float32(7.5)
+	releasedFrom := parseTime("2000-01-01T00:00:00Z")
+	cursor := model.Cursor{Sort: "name:desc", Key: "Matrix", ID: 3}
 
 	tests := []struct {
 		name                 string
-		queryParam           string
+		query                string
+		params               model.FilmListParams
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
 		expectedResponseBody string
 	}{
 		{
-			name:       "Ok",
-			queryParam: "name",
-			mockBehavior: func(r *mock_service.MockMovie, sortBy string) {
-				r.EXPECT().GetFilms(gomock.Any(), sortBy).Return([]model.Film{}, nil)
+			name:   "Ok",
+			query:  "sort_by=name",
+			params: model.FilmListParams{SortBy: "name", Limit: model.DefaultPageLimit},
+			mockBehavior: func(r *mock_service.MockMovie, params model.FilmListParams) {
+				r.EXPECT().GetFilms(gomock.Any(), params).Return(model.FilmPage{Items: []model.Film{}, Limit: params.Limit}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"items": [], "total": 0, "limit": 20, "offset": 0, "next_cursor": "", "prev_cursor": ""}`,
+		},
+		{
+			name:  "Ok Filters",
+			query: "sort_by=name&order=desc&min_rating=7.5&released_from=2000-01-01&actor_id=4&name=Ma&limit=2&cursor=" + cursor.Encode(),
+			params: model.FilmListParams{
+				SortBy:       "name",
+				Order:        "desc",
+				MinRating:    &minRating,
+				ReleasedFrom: &releasedFrom,
+				ActorID:      4,
+				NamePrefix:   "Ma",
+				Limit:        2,
+				Cursor:       &cursor,
+			},
+			mockBehavior: func(r *mock_service.MockMovie, params model.FilmListParams) {
+				r.EXPECT().GetFilms(gomock.Any(), params).Return(model.FilmPage{
+					Items:      []model.Film{{Id: 2, Name: "Mad Max", ListActors: []model.CastMember{}}},
+					Total:      5,
+					Limit:      2,
+					NextCursor: "next",
+					PrevCursor: "prev",
+				}, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `[]`,
+			expectedResponseBody: `{"items": [{"id": 2, "name": "Mad Max", "description": "", "release_date": "0001-01-01T00:00:00Z", "rating": 0, "list_actors": []}], "total": 5, "limit": 2, "offset": 0, "next_cursor": "next", "prev_cursor": "prev"}`,
 		},
 		{
 			name:                 "Wrong Input",
-			queryParam:           "actor",
-			mockBehavior:         func(r *mock_service.MockMovie, sortBy string) {},
+			query:                "sort_by=actor",
+			mockBehavior:         func(r *mock_service.MockMovie, params model.FilmListParams) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Invalid sort field", "code": "invalid_sort"}`,
+		},
+		{
+			name:                 "Wrong Limit",
+			query:                "limit=500",
+			mockBehavior:         func(r *mock_service.MockMovie, params model.FilmListParams) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "limit must be between 1 and 100", "code": "limit_out_of_range"}`,
+		},
+		{
+			name:                 "Wrong Cursor",
+			query:                "cursor=%21%21%21",
+			mockBehavior:         func(r *mock_service.MockMovie, params model.FilmListParams) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"message": "Некорректная сортировка"}`,
+			expectedResponseBody: `{"status": 400, "message": "Invalid cursor", "code": "invalid_cursor"}`,
 		},
 		{
-			name:       "Server Error",
-			queryParam: "name",
-			mockBehavior: func(r *mock_service.MockMovie, sortBy string) {
-				r.EXPECT().GetFilms(gomock.Any(), sortBy).Return([]model.Film{}, errors.New("Failed to get films"))
+			name:                 "Cursor For Another Sort",
+			query:                "sort_by=release_date&cursor=" + cursor.Encode(),
+			mockBehavior:         func(r *mock_service.MockMovie, params model.FilmListParams) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Cursor was issued for a different sort order", "code": "cursor_sort_mismatch"}`,
+		},
+		{
+			name:                 "Wrong Date",
+			query:                "released_to=yesterday",
+			mockBehavior:         func(r *mock_service.MockMovie, params model.FilmListParams) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Parameter released_to must be a date in YYYY-MM-DD format", "code": "invalid_date_param"}`,
+		},
+		{
+			name:   "Server Error",
+			query:  "sort_by=name",
+			params: model.FilmListParams{SortBy: "name", Limit: model.DefaultPageLimit},
+			mockBehavior: func(r *mock_service.MockMovie, params model.FilmListParams) {
+				r.EXPECT().GetFilms(gomock.Any(), params).Return(model.FilmPage{}, errors.New("Failed to get films"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"message": "Failed to get films"}`,
+			expectedResponseBody: `{"status": 500, "message": "Failed to get films", "code": "internal_error"}`,
 		},
 	}
 
@@ -275,12 +381,12 @@ func TestHandler_GetAllFilms(t *testing.T) {
 			defer c.Finish()
 
 			auth := mock_service.NewMockMovie(c)
-			tc.mockBehavior(auth, tc.queryParam)
+			tc.mockBehavior(auth, tc.params)
 
 			services := &service.Service{Movie: auth}
 			handler := NewMovieHandler(services)
 
-			req := httptest.NewRequest(http.MethodGet, "/films_get_list/?sort_by="+tc.queryParam, nil)
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/films?"+tc.query, nil)
 			req.Header.Set("Content-Type", "application/json")
 
 			rr := httptest.NewRecorder()
@@ -292,45 +398,74 @@ func TestHandler_GetAllFilms(t *testing.T) {
 	}
 }
 
-func TestHandler_SearchFilm(t *testing.T) {
-	type mockBehavior func(r *mock_service.MockMovie, actor, movie string)
+func TestHandler_SearchFilms(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockMovie, params model.FilmSearchParams)
 
 	tests := []struct {
 		name                 string
-		queryParamActor      string
-		queryParamMovie      string
+		query                string
+		params               model.FilmSearchParams
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
 		expectedResponseBody string
 	}{
 		{
-			name:            "OK",
-			queryParamActor: "Дизель",
-			queryParamMovie: "Форсаж",
-			mockBehavior: func(r *mock_service.MockMovie, actor, movie string) {
-				r.EXPECT().SearchFilm(gomock.Any(), actor, movie).Return(model.Film{}, nil)
+			name:   "OK",
+			query:  "q=Форсаж&lang=ru&limit=1",
+			params: model.FilmSearchParams{Query: "Форсаж", Lang: "ru", Limit: 1},
+			mockBehavior: func(r *mock_service.MockMovie, params model.FilmSearchParams) {
+				r.EXPECT().SearchFilms(gomock.Any(), params).Return(model.FilmSearchPage{
+					Items: []model.FilmSearchResult{{
+						Film: model.Film{Id: 1, Name: "Форсаж", ListActors: []model.CastMember{}},
+						Rank: 1.5,
+						Highlights: model.SearchHighlights{
+							Name:   "<mark>Форсаж</mark>",
+							Actors: []string{},
+						},
+					}},
+					Total:      3,
+					Limit:      1,
+					NextCursor: "",
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"items": [{"id": 1, "name": "Форсаж", "description": "", "release_date": "0001-01-01T00:00:00Z", "rating": 0, "list_actors": [],
+				"rank": 1.5, "highlights": {"name": "<mark>Форсаж</mark>", "description": "", "actors": []}}],
+				"total": 3, "limit": 1, "offset": 0, "next_cursor": "", "prev_cursor": ""}`,
+		},
+		{
+			name:   "OK Legacy Params",
+			query:  "actor=Дизель&movie=Форсаж",
+			params: model.FilmSearchParams{Query: "Форсаж Дизель", Limit: model.DefaultPageLimit},
+			mockBehavior: func(r *mock_service.MockMovie, params model.FilmSearchParams) {
+				r.EXPECT().SearchFilms(gomock.Any(), params).Return(model.FilmSearchPage{Items: []model.FilmSearchResult{}, Limit: params.Limit}, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
-			expectedResponseBody: `{"id": 0, "name": "", "description": "", "rating": 0, "release_date": "0001-01-01T00:00:00Z", "list_actors": null}`,
+			expectedResponseBody: `{"items": [], "total": 0, "limit": 20, "offset": 0, "next_cursor": "", "prev_cursor": ""}`,
 		},
 		{
-			name:                 "OK",
-			queryParamActor:      "",
-			queryParamMovie:      "",
-			mockBehavior:         func(r *mock_service.MockMovie, actor, movie string) {},
+			name:                 "Empty Query",
+			query:                "actor=&movie=",
+			mockBehavior:         func(r *mock_service.MockMovie, params model.FilmSearchParams) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"message": "необходимо указать либо название фильма, либо имя актёра"}`,
+			expectedResponseBody: `{"status": 400, "message": "Search query is required", "code": "search_query_required"}`,
 		},
-
 		{
-			name:            "Service Error",
-			queryParamActor: "Дизель",
-			queryParamMovie: "ТрансфФорсажормеры",
-			mockBehavior: func(r *mock_service.MockMovie, actor, movie string) {
-				r.EXPECT().SearchFilm(gomock.Any(), actor, movie).Return(model.Film{}, errors.New("Search failed: ошибка поиска: sql: no rows in result set"))
+			name:                 "Wrong Lang",
+			query:                "q=Форсаж&lang=de",
+			mockBehavior:         func(r *mock_service.MockMovie, params model.FilmSearchParams) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Search language must be ru or en", "code": "invalid_search_lang"}`,
+		},
+		{
+			name:   "Service Error",
+			query:  "q=Трансформеры",
+			params: model.FilmSearchParams{Query: "Трансформеры", Limit: model.DefaultPageLimit},
+			mockBehavior: func(r *mock_service.MockMovie, params model.FilmSearchParams) {
+				r.EXPECT().SearchFilms(gomock.Any(), params).Return(model.FilmSearchPage{}, errors.New("ошибка поиска: pq: syntax error"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"message":"Search failed: Search failed: ошибка поиска: sql: no rows in result set"}`,
+			expectedResponseBody: `{"status": 500, "message": "Search failed", "code": "internal_error"}`,
 		},
 	}
 
@@ -340,19 +475,292 @@ func TestHandler_SearchFilm(t *testing.T) {
 			defer c.Finish()
 
 			auth := mock_service.NewMockMovie(c)
-			tc.mockBehavior(auth, tc.queryParamActor, tc.queryParamMovie)
+			tc.mockBehavior(auth, tc.params)
 
 			services := &service.Service{Movie: auth}
 			handler := NewMovieHandler(services)
 
-			req := httptest.NewRequest(http.MethodGet, "/films/search?actor="+tc.queryParamActor+"&movie="+tc.queryParamMovie, nil)
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/films/search?"+tc.query, nil)
 			req.Header.Set("Content-Type", "application/json")
 
 			rr := httptest.NewRecorder()
-			handler.SearchFilm(rr, req)
+			handler.SearchFilms(rr, req)
 
 			require.Equal(t, tc.expectedStatusCode, rr.Code)
 			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
 		})
 	}
 }
+
+func TestHandler_GetFilm(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockMovie, id int)
+
+	tests := []struct {
+		name                 string
+		pathParam            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			pathParam: "1",
+			mockBehavior: func(r *mock_service.MockMovie, id int) {
+				r.EXPECT().GetFilmByID(gomock.Any(), id).Return(model.Film{
+					Id:          1,
+					Name:        "name",
+					Description: "description",
+					Releasedate: parseTime("2004-04-10T21:12:05+03:00"),
+					Rating:      9.3,
+					ListActors:  []model.CastMember{{Actor: model.Actor{Id: 2, Name: "actor", Gender: "male", DateOfBirth: parseTime("1980-01-01T00:00:00Z")}, Character: "hero", BillingOrder: 1}},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"id": 1, "name": "name", "description": "description", "release_date": "2004-04-10T21:12:05+03:00", "rating": 9.3, "list_actors": [{"id": 2, "name": "actor", "gender": "male", "date_of_birth": "1980-01-01T00:00:00Z", "character": "hero", "billing_order": 1}]}`,
+		},
+		{
+			name:                 "Wrong input ID",
+			pathParam:            "first",
+			mockBehavior:         func(r *mock_service.MockMovie, id int) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Invalid film ID", "code": "invalid_film_id"}`,
+		},
+		{
+			name:      "Not Found",
+			pathParam: "1",
+			mockBehavior: func(r *mock_service.MockMovie, id int) {
+				r.EXPECT().GetFilmByID(gomock.Any(), id).Return(model.Film{}, domain.NotFound("film_not_found", "фильм не найден"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"status": 404, "message": "Film not found", "code": "film_not_found"}`,
+		},
+		{
+			name:      "Service Error",
+			pathParam: "1",
+			mockBehavior: func(r *mock_service.MockMovie, id int) {
+				r.EXPECT().GetFilmByID(gomock.Any(), id).Return(model.Film{}, errors.New("error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"status": 500, "message": "Failed to get film", "code": "internal_error"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockMovie(c)
+			tc.mockBehavior(auth, 1)
+
+			services := &service.Service{Movie: auth}
+			handler := NewMovieHandler(services)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/films/"+tc.pathParam, nil)
+			req.SetPathValue("id", tc.pathParam)
+
+			rr := httptest.NewRecorder()
+			handler.GetFilm(rr, req)
+
+			require.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetFilmNotModified(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	auth := mock_service.NewMockMovie(c)
+	auth.EXPECT().GetFilmByID(gomock.Any(), 1).Return(model.Film{Id: 1, Name: "name"}, nil).Times(2)
+
+	services := &service.Service{Movie: auth}
+	handler := NewMovieHandler(services)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/films/1", nil)
+	req.SetPathValue("id", "1")
+
+	rr := httptest.NewRecorder()
+	handler.GetFilm(rr, req)
+
+	etag := rr.Header().Get("ETag")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NotEmpty(t, etag)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/films/1", nil)
+	req.SetPathValue("id", "1")
+	req.Header.Set("If-None-Match", `"other", W/`+etag)
+
+	rr = httptest.NewRecorder()
+	handler.GetFilm(rr, req)
+
+	require.Equal(t, http.StatusNotModified, rr.Code)
+	require.Equal(t, etag, rr.Header().Get("ETag"))
+	require.Empty(t, rr.Body.String())
+}
+
+func TestHandler_GetFilmLocalized(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	auth := mock_service.NewMockMovie(c)
+	auth.EXPECT().GetFilmByID(gomock.Any(), 1).Return(model.Film{}, domain.NotFound("film_not_found", "фильм не найден"))
+
+	services := &service.Service{Movie: auth}
+	handler := NewMovieHandler(services)
+
+	tests := []struct {
+		name                 string
+		pathParam            string
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Domain Error",
+			pathParam:            "1",
+			expectedResponseBody: `{"status": 404, "message": "Фильм не найден", "code": "film_not_found"}`,
+		},
+		{
+			name:                 "Handler Error",
+			pathParam:            "abc",
+			expectedResponseBody: `{"status": 400, "message": "Некорректный идентификатор фильма", "code": "invalid_film_id"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/films/"+tc.pathParam, nil)
+			req.SetPathValue("id", tc.pathParam)
+			req.Header.Set("Accept-Language", "ru-RU,ru;q=0.9,en;q=0.8")
+
+			rr := httptest.NewRecorder()
+			middleware.Localize(i18n.EN)(http.HandlerFunc(handler.GetFilm)).ServeHTTP(rr, req)
+
+			require.Equal(t, "ru", rr.Header().Get("Content-Language"))
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_DeleteMoviePreconditions(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockMovie)
+
+	tests := []struct {
+		name                 string
+		path                 string
+		ifMatch              string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "If-Match Required",
+			path:                 "/api/v1/films/1",
+			mockBehavior:         func(r *mock_service.MockMovie) {},
+			expectedStatusCode:   http.StatusPreconditionRequired,
+			expectedResponseBody: `{"status": 428, "message": "If-Match header with the ETag of the record is required", "code": "if_match_required"}`,
+		},
+		{
+			name:                 "Weak ETag Never Matches",
+			path:                 "/api/v1/films/1",
+			ifMatch:              `W/"2-0123456789abcdef"`,
+			mockBehavior:         func(r *mock_service.MockMovie) {},
+			expectedStatusCode:   http.StatusPreconditionFailed,
+			expectedResponseBody: `{"status": 412, "message": "Record was changed by another request, fetch it again", "code": "version_mismatch"}`,
+		},
+		{
+			name:    "Stale Version",
+			path:    "/api/v1/films/1",
+			ifMatch: `"2-0123456789abcdef"`,
+			mockBehavior: func(r *mock_service.MockMovie) {
+				r.EXPECT().DeleteMovie(gomock.Any(), 1, 2).Return(domain.VersionMismatch())
+			},
+			expectedStatusCode:   http.StatusPreconditionFailed,
+			expectedResponseBody: `{"status": 412, "message": "Record was changed by another request, fetch it again", "code": "version_mismatch"}`,
+		},
+		{
+			name:    "Any Version",
+			path:    "/api/v1/films/1",
+			ifMatch: `*`,
+			mockBehavior: func(r *mock_service.MockMovie) {
+				r.EXPECT().DeleteMovie(gomock.Any(), 1, 0).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message": "movie deleted successfully"}`,
+		},
+		{
+			name: "Legacy Route Without If-Match",
+			path: "/film_delete/1",
+			mockBehavior: func(r *mock_service.MockMovie) {
+				r.EXPECT().DeleteMovie(gomock.Any(), 1, 0).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message": "movie deleted successfully"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockMovie(c)
+			tc.mockBehavior(auth)
+
+			services := &service.Service{Movie: auth}
+			handler := NewMovieHandler(services)
+
+			router := NewRouter()
+			router.HandleFunc("DELETE /api/v1/films/{id}", handler.DeleteFilm)
+			registerLegacyRoutes(router, []legacyRoute{
+				{"DELETE /film_delete/{id}", "DELETE /api/v1/films/{id}", handler.DeleteFilm},
+			})
+
+			req := httptest.NewRequest(http.MethodDelete, tc.path, nil)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_UpdateMovieETag(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	auth := mock_service.NewMockMovie(c)
+	auth.EXPECT().GetFilmByID(gomock.Any(), 1).Return(model.Film{Id: 1, Name: "name", Version: 4}, nil)
+	auth.EXPECT().UpdateMovie(gomock.Any(), model.Film{Id: 1, Name: "name", Rating: 9, Version: 4}).
+		DoAndReturn(func(_ any, f model.Film) (model.Film, error) {
+			f.Version++
+			return f, nil
+		})
+
+	services := &service.Service{Movie: auth}
+	handler := NewMovieHandler(services)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/films/1", nil)
+	req.SetPathValue("id", "1")
+
+	rr := httptest.NewRecorder()
+	handler.GetFilm(rr, req)
+
+	etag := rr.Header().Get("ETag")
+	require.Regexp(t, `^"4-[0-9a-f]{16}"$`, etag)
+
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/films/1", bytes.NewBufferString(`{"name": "name", "rating": 9}`))
+	req.SetPathValue("id", "1")
+	req.Header.Set("If-Match", etag)
+
+	rr = httptest.NewRecorder()
+	handler.UpdateFilm(rr, req)
+
+	require.Equal(t, http.StatusCreated, rr.Code)
+	require.Regexp(t, `^"5-[0-9a-f]{16}"$`, rr.Header().Get("ETag"))
+}
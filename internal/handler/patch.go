@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"film-library/internal/model"
+	"film-library/internal/utils/patch"
+	"film-library/internal/utils/response"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// acceptPatch - поддерживаемые форматы PATCH для заголовка Accept-Patch (RFC 5789)
+const acceptPatch = patch.MergePatchType + ", " + patch.JSONPatchType
+
+// readPatch читает тело PATCH-запроса. Обычный application/json (и запрос без типа)
+// трактуется как JSON Merge Patch. При ошибке ответ уже записан
+func readPatch(w http.ResponseWriter, r *http.Request) (model.Patch, bool) {
+	contentType := patch.MergePatchType
+	if header := r.Header.Get("Content-Type"); header != "" {
+		mediaType, _, err := mime.ParseMediaType(header)
+		switch {
+		case err != nil:
+			contentType = ""
+		case mediaType == patch.JSONPatchType:
+			contentType = patch.JSONPatchType
+		case mediaType != patch.MergePatchType && mediaType != "application/json":
+			contentType = ""
+		}
+	}
+
+	if contentType == "" {
+		w.Header().Set("Accept-Patch", acceptPatch)
+		response.WriteJSONError(w, r, "unsupported_media_type", http.StatusUnsupportedMediaType)
+		return model.Patch{}, false
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return model.Patch{}, false
+	}
+
+	return model.Patch{ContentType: contentType, Body: body}, true
+}
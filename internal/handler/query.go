@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayout - формат дат в параметрах запроса
+const dateLayout = "2006-01-02"
+
+// queryInt возвращает целочисленный параметр или def, если параметр не передан
+func queryInt(q url.Values, name string, def int) (int, error) {
+	v := q.Get(name)
+	if v == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, domain.BadRequest("invalid_integer_param", fmt.Sprintf("параметр %s должен быть целым числом", name)).WithArgs(name)
+	}
+
+	return n, nil
+}
+
+// queryOptionalInt возвращает целочисленный параметр или nil, если параметр не передан
+func queryOptionalInt(q url.Values, name string) (*int, error) {
+	if q.Get(name) == "" {
+		return nil, nil
+	}
+
+	n, err := queryInt(q, name, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &n, nil
+}
+
+// queryPage возвращает limit и offset страницы со значениями по умолчанию
+func queryPage(q url.Values) (limit, offset int, err error) {
+	if limit, err = queryInt(q, "limit", model.DefaultPageLimit); err != nil {
+		return 0, 0, err
+	}
+	if offset, err = queryInt(q, "offset", 0); err != nil {
+		return 0, 0, err
+	}
+	return limit, offset, nil
+}
+
+// queryFloat возвращает дробный параметр или nil, если параметр не передан
+func queryFloat(q url.Values, name string) (*float32, error) {
+	v := q.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+
+	f, err := strconv.ParseFloat(v, 32)
+	if err != nil {
+		return nil, domain.BadRequest("invalid_number_param", fmt.Sprintf("параметр %s должен быть числом", name)).WithArgs(name)
+	}
+
+	f32 := float32(f)
+	return &f32, nil
+}
+
+// queryDate возвращает дату в формате YYYY-MM-DD или nil, если параметр не передан
+func queryDate(q url.Values, name string) (*time.Time, error) {
+	v := q.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(dateLayout, v)
+	if err != nil {
+		return nil, domain.BadRequest("invalid_date_param", fmt.Sprintf("параметр %s должен быть датой в формате YYYY-MM-DD", name)).WithArgs(name)
+	}
+
+	return &t, nil
+}
+
+// queryTime возвращает момент времени в RFC 3339 или дату YYYY-MM-DD (начало суток UTC)
+// или nil, если параметр не передан
+func queryTime(q url.Values, name string) (*time.Time, error) {
+	v := q.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, dateLayout} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return &t, nil
+		}
+	}
+
+	return nil, domain.BadRequest("invalid_time_param", fmt.Sprintf("параметр %s должен быть временем в формате RFC 3339 или датой YYYY-MM-DD", name)).WithArgs(name)
+}
+
+// queryBool возвращает логический параметр или false, если параметр не передан
+func queryBool(q url.Values, name string) (bool, error) {
+	v := q.Get(name)
+	if v == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, domain.BadRequest("invalid_boolean_param", fmt.Sprintf("параметр %s должен быть true или false", name)).WithArgs(name)
+	}
+
+	return b, nil
+}
+
+// queryList возвращает значения параметра, переданного несколько раз или через запятую.
+// Пустые значения отбрасываются
+func queryList(q url.Values, name string) []string {
+	var values []string
+	for _, v := range q[name] {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+	}
+	return values
+}
+
+// queryLabelFilter возвращает фильтр фильмов по жанрам, странам и тегам
+func queryLabelFilter(q url.Values) model.FilmLabelFilter {
+	return model.FilmLabelFilter{
+		Genres:    queryList(q, "genre"),
+		Countries: queryList(q, "country"),
+		Tags:      queryList(q, "tag"),
+	}
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"film-library/internal/model"
+	"film-library/internal/service"
+	"film-library/internal/utils/response"
+	"net/http"
+)
+
+type RecommendationHandler struct {
+	service service.Recommendation
+}
+
+func NewRecommendationHandler(service service.Recommendation) RecommendationHandler {
+	return RecommendationHandler{service: service}
+}
+
+// @Summary Get Similar Films
+// @Security ApiKeyAuth
+// @Tags recommendations
+// @Description Films sharing the most actors with the film, weighted by rating and release year.
+// @Description Computed by a background job, so recent cast changes appear after the next refresh
+// @ID get-similar-films
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} model.SimilarPage
+// @Failure 400,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id}/similar [get]
+func (h *RecommendationHandler) GetSimilarFilms(w http.ResponseWriter, r *http.Request) {
+	filmID, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	params := model.SimilarParams{FilmID: filmID}
+	if params.Limit, params.Offset, err = queryPage(r.URL.Query()); err != nil {
+		response.WriteError(w, r, err, "get_similar_films_failed")
+		return
+	}
+
+	if err := params.Validate(); err != nil {
+		response.WriteError(w, r, err, "get_similar_films_failed")
+		return
+	}
+
+	page, err := h.service.GetSimilarFilms(r.Context(), params)
+	if err != nil {
+		response.WriteError(w, r, err, "get_similar_films_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+// @Summary My Recommendations
+// @Security ApiKeyAuth
+// @Tags recommendations
+// @Description Films recommended by cast of liked and watched films and by ratings of users with similar taste.
+// @Description Computed by a background job; films watched or rated since the last refresh are skipped
+// @ID get-recommendations
+// @Produce  json
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} model.RecommendationPage
+// @Failure 400,401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/me/recommendations [get]
+func (h *RecommendationHandler) GetRecommendations(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	params := model.RecommendationParams{UserID: userID}
+
+	var err error
+	if params.Limit, params.Offset, err = queryPage(r.URL.Query()); err != nil {
+		response.WriteError(w, r, err, "get_recommendations_failed")
+		return
+	}
+
+	if err := params.Validate(); err != nil {
+		response.WriteError(w, r, err, "get_recommendations_failed")
+		return
+	}
+
+	page, err := h.service.GetRecommendations(r.Context(), params)
+	if err != nil {
+		response.WriteError(w, r, err, "get_recommendations_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	mock_service "film-library/internal/service/mocks"
+	authmid "film-library/internal/utils/auth_mid"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_GetSimilarFilms(t *testing.T) {
+	released := time.Date(2003, 5, 15, 0, 0, 0, 0, time.UTC)
+
+	type mockBehavior func(r *mock_service.MockRecommendation)
+
+	tests := []struct {
+		name                 string
+		url                  string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			url:  "/api/v1/films/1/similar?limit=5",
+			mockBehavior: func(r *mock_service.MockRecommendation) {
+				r.EXPECT().GetSimilarFilms(gomock.Any(), model.SimilarParams{FilmID: 1, Limit: 5}).Return(model.SimilarPage{
+					Items: []model.SimilarFilm{{Id: 2, Name: "The Matrix Reloaded", Releasedate: released, Rating: 7.2, Score: 4.1, SharedActors: 3}},
+					Total: 1,
+					Limit: 5,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"items": [{"id": 2, "name": "The Matrix Reloaded", "release_date": "2003-05-15T00:00:00Z", "rating": 7.2,
+				"score": 4.1, "shared_actors": 3}], "total": 1, "limit": 5, "offset": 0, "next_cursor": "", "prev_cursor": ""}`,
+		},
+		{
+			name:                 "Invalid ID",
+			url:                  "/api/v1/films/abc/similar",
+			mockBehavior:         func(r *mock_service.MockRecommendation) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Invalid film ID", "code": "invalid_film_id"}`,
+		},
+		{
+			name:                 "Limit Out Of Range",
+			url:                  "/api/v1/films/1/similar?limit=500",
+			mockBehavior:         func(r *mock_service.MockRecommendation) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "limit must be between 1 and 100", "code": "limit_out_of_range"}`,
+		},
+		{
+			name: "Film Not Found",
+			url:  "/api/v1/films/404/similar",
+			mockBehavior: func(r *mock_service.MockRecommendation) {
+				r.EXPECT().GetSimilarFilms(gomock.Any(), model.SimilarParams{FilmID: 404, Limit: model.DefaultPageLimit}).
+					Return(model.SimilarPage{}, domain.Wrap(domain.ErrNotFound, domain.ErrNotFound, "film_not_found", "фильм не найден"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"status": 404, "message": "Film not found", "code": "film_not_found"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			recommendation := mock_service.NewMockRecommendation(c)
+			test.mockBehavior(recommendation)
+
+			handler := NewRecommendationHandler(recommendation)
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v1/films/{id}/similar", handler.GetSimilarFilms)
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, test.url, nil))
+
+			require.Equal(t, test.expectedStatusCode, rr.Code)
+			require.JSONEq(t, test.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetRecommendations(t *testing.T) {
+	released := time.Date(2014, 10, 24, 0, 0, 0, 0, time.UTC)
+
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	recommendation := mock_service.NewMockRecommendation(c)
+	recommendation.EXPECT().GetRecommendations(gomock.Any(), model.RecommendationParams{UserID: 5, Limit: 10, Offset: 10}).Return(model.RecommendationPage{
+		Items:  []model.Recommendation{{Id: 7, Name: "John Wick", Releasedate: released, Rating: 7.4, Score: 2.5, Reason: model.ReasonCastAndRatings}},
+		Total:  11,
+		Limit:  10,
+		Offset: 10,
+	}, nil)
+
+	handler := NewRecommendationHandler(recommendation)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/me/recommendations", handler.GetRecommendations)
+
+	ctx := authmid.WithUser(context.Background(), 5, int(model.RoleUser))
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/me/recommendations?limit=10&offset=10", nil).WithContext(ctx))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"items": [{"id": 7, "name": "John Wick", "release_date": "2014-10-24T00:00:00Z", "rating": 7.4,
+		"score": 2.5, "reason": "cast_and_ratings"}], "total": 11, "limit": 10, "offset": 10, "next_cursor": "", "prev_cursor": ""}`, rr.Body.String())
+
+	// Без пользователя рекомендации не строятся
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/me/recommendations", nil))
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	require.JSONEq(t, `{"status": 401, "message": "Unauthorized", "code": "unauthorized"}`, rr.Body.String())
+}
@@ -0,0 +1,393 @@
+package handler
+
+import (
+	"encoding/json"
+	"film-library/internal/model"
+	"film-library/internal/service"
+	authmid "film-library/internal/utils/auth_mid"
+	"film-library/internal/utils/response"
+	"net/http"
+)
+
+type ReviewHandler struct {
+	service service.Review
+}
+
+func NewReviewHandler(service service.Review) ReviewHandler {
+	return ReviewHandler{service: service}
+}
+
+// currentUser - id пользователя из токена. Без него ответ 401 уже записан
+func currentUser(w http.ResponseWriter, r *http.Request) (int, bool) {
+	userID, ok := authmid.UserID(r.Context())
+	if !ok {
+		response.WriteJSONError(w, r, "unauthorized", http.StatusUnauthorized)
+	}
+	return userID, ok
+}
+
+// @Summary Get Film Rating
+// @Security ApiKeyAuth
+// @Tags reviews
+// @Description Average user score and number of votes for the film, with the score of the current user if any
+// @ID get-film-rating
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Success 200 {object} model.FilmScore
+// @Failure 400,401,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id}/rating [get]
+func (h *ReviewHandler) GetRating(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	filmID, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	score, err := h.service.GetRating(r.Context(), userID, filmID)
+	if err != nil {
+		response.WriteError(w, r, err, "get_rating_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, score)
+}
+
+// @Summary Rate Film
+// @Security ApiKeyAuth
+// @Tags reviews
+// @Description Set or change own score from 1 to 10. Average user score of the film is updated at once
+// @ID set-film-rating
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Param rating body model.RatingRequest true "Score"
+// @Success 200 {object} model.FilmScore
+// @Failure 400,401,403,404 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id}/rating [put]
+func (h *ReviewHandler) SetRating(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	filmID, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	var rating model.RatingRequest
+	if err := json.NewDecoder(r.Body).Decode(&rating); err != nil {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+
+	if err := rating.Validate(); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
+		return
+	}
+
+	score, err := h.service.SetRating(r.Context(), userID, filmID, rating)
+	if err != nil {
+		response.WriteError(w, r, err, "set_rating_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, score)
+}
+
+// @Summary Delete Film Rating
+// @Security ApiKeyAuth
+// @Tags reviews
+// @Description Remove own score. Average user score of the film is updated at once
+// @ID delete-film-rating
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Success 200 {object} model.FilmScore
+// @Failure 400,401,403,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id}/rating [delete]
+func (h *ReviewHandler) DeleteRating(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	filmID, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	score, err := h.service.DeleteRating(r.Context(), userID, filmID)
+	if err != nil {
+		response.WriteError(w, r, err, "delete_rating_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, score)
+}
+
+// @Summary Get Film Reviews
+// @Security ApiKeyAuth
+// @Tags reviews
+// @Description Approved reviews of the film, newest first
+// @ID get-film-reviews
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} model.ReviewPage
+// @Failure 400,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id}/reviews [get]
+func (h *ReviewHandler) GetFilmReviews(w http.ResponseWriter, r *http.Request) {
+	filmID, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	params := model.ReviewListParams{FilmID: filmID, Status: model.ReviewApproved}
+	if params.Limit, params.Offset, err = queryPage(r.URL.Query()); err != nil {
+		response.WriteError(w, r, err, "get_reviews_failed")
+		return
+	}
+
+	if err := params.Validate(); err != nil {
+		response.WriteError(w, r, err, "get_reviews_failed")
+		return
+	}
+
+	page, err := h.service.GetFilmReviews(r.Context(), params)
+	if err != nil {
+		response.WriteError(w, r, err, "get_reviews_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+// @Summary Get Own Review
+// @Security ApiKeyAuth
+// @Tags reviews
+// @Description Own review of the film in any moderation status
+// @ID get-own-review
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Success 200 {object} model.Review
+// @Failure 400,401,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id}/review [get]
+func (h *ReviewHandler) GetUserReview(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	filmID, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.service.GetUserReview(r.Context(), userID, filmID)
+	if err != nil {
+		response.WriteError(w, r, err, "get_reviews_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, review)
+}
+
+// @Summary Write Review
+// @Security ApiKeyAuth
+// @Tags reviews
+// @Description Create or edit own review of the film. New and edited reviews are pending until a moderator approves them
+// @ID save-review
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Param review body model.ReviewRequest true "Review text"
+// @Success 200 {object} model.Review
+// @Failure 400,401,403,404 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id}/review [put]
+func (h *ReviewHandler) SaveReview(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	filmID, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	var review model.ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+
+	if err := review.Validate(); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
+		return
+	}
+
+	saved, err := h.service.SaveReview(r.Context(), userID, filmID, review)
+	if err != nil {
+		response.WriteError(w, r, err, "save_review_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, saved)
+}
+
+// @Summary Delete Own Review
+// @Security ApiKeyAuth
+// @Tags reviews
+// @Description Delete own review of the film
+// @ID delete-own-review
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Success 200 {object} map[string]string
+// @Failure 400,401,403,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/{id}/review [delete]
+func (h *ReviewHandler) DeleteReview(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	filmID, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteReview(r.Context(), userID, filmID); err != nil {
+		response.WriteError(w, r, err, "delete_review_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "review deleted successfully"})
+}
+
+// @Summary Reviews For Moderation
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Reviews of all films in the given status (pending by default), newest first
+// @ID get-reviews
+// @Produce  json
+// @Param status query string false "pending (default), approved or hidden"
+// @Param film_id query int false "Film ID"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} model.ReviewPage
+// @Failure 400,401,403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/admin/reviews [get]
+func (h *ReviewHandler) GetReviews(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	params := model.ReviewListParams{Status: q.Get("status")}
+	if params.Status == "" {
+		params.Status = model.ReviewPending
+	}
+
+	var err error
+	if params.FilmID, err = queryInt(q, "film_id", 0); err != nil {
+		response.WriteError(w, r, err, "get_reviews_failed")
+		return
+	}
+	if params.Limit, params.Offset, err = queryPage(q); err != nil {
+		response.WriteError(w, r, err, "get_reviews_failed")
+		return
+	}
+
+	if err := params.Validate(); err != nil {
+		response.WriteError(w, r, err, "get_reviews_failed")
+		return
+	}
+
+	page, err := h.service.GetReviews(r.Context(), params)
+	if err != nil {
+		response.WriteError(w, r, err, "get_reviews_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+// @Summary Approve Review
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Approve review: it becomes visible in the film reviews
+// @ID approve-review
+// @Produce  json
+// @Param id path int true "Review ID"
+// @Success 200 {object} model.Review
+// @Failure 400,401,403,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/admin/reviews/{id}/approve [post]
+func (h *ReviewHandler) ApproveReview(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, model.ReviewApproved)
+}
+
+// @Summary Hide Review
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Hide review from the film reviews. The author still sees it
+// @ID hide-review
+// @Produce  json
+// @Param id path int true "Review ID"
+// @Success 200 {object} model.Review
+// @Failure 400,401,403,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/admin/reviews/{id}/hide [post]
+func (h *ReviewHandler) HideReview(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, model.ReviewHidden)
+}
+
+func (h *ReviewHandler) moderate(w http.ResponseWriter, r *http.Request, status string) {
+	moderatorID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_review_id", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.service.ModerateReview(r.Context(), id, moderatorID, status)
+	if err != nil {
+		response.WriteError(w, r, err, "moderate_review_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, review)
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	mock_service "film-library/internal/service/mocks"
+	authmid "film-library/internal/utils/auth_mid"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_SetRating(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockReview, userID, filmID int)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"score": 8}`,
+			mockBehavior: func(r *mock_service.MockReview, userID, filmID int) {
+				r.EXPECT().SetRating(gomock.Any(), userID, filmID, model.RatingRequest{Score: 8}).
+					Return(model.FilmScore{FilmID: filmID, Score: 8, UserScore: 7.5, Votes: 4}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"film_id": 1, "score": 8, "user_score": 7.5, "votes": 4}`,
+		},
+		{
+			name:                 "Score Out Of Range",
+			inputBody:            `{"score": 11}`,
+			mockBehavior:         func(r *mock_service.MockReview, userID, filmID int) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/api/v1/films/1/rating", "code": "validation_failed", "errors": [{"field": "score", "rule": "max", "params": ["10"], "message": "must be at most 10"}]}`,
+		},
+		{
+			name:      "Film Not Found",
+			inputBody: `{"score": 3}`,
+			mockBehavior: func(r *mock_service.MockReview, userID, filmID int) {
+				r.EXPECT().SetRating(gomock.Any(), userID, filmID, model.RatingRequest{Score: 3}).
+					Return(model.FilmScore{}, domain.Wrap(domain.ErrNotFound, domain.ErrNotFound, "film_not_found", "фильм не найден"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"status": 404, "message": "Film not found", "code": "film_not_found"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			review := mock_service.NewMockReview(c)
+			test.mockBehavior(review, 5, 1)
+
+			handler := NewReviewHandler(review)
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /api/v1/films/{id}/rating", handler.SetRating)
+
+			ctx := authmid.WithUser(context.Background(), 5, int(model.RoleUser))
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/films/1/rating", bytes.NewBufferString(test.inputBody)).WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			require.Equal(t, test.expectedStatusCode, rr.Code)
+			require.JSONEq(t, test.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_SaveReview(t *testing.T) {
+	created := time.Date(2025, 8, 4, 12, 0, 0, 0, time.UTC)
+
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	review := mock_service.NewMockReview(c)
+	review.EXPECT().SaveReview(gomock.Any(), 5, 1, model.ReviewRequest{Body: "Great film"}).Return(model.Review{
+		Id: 3, FilmID: 1, UserID: 5, Username: "neo", Score: 9, Body: "Great film",
+		Status: model.ReviewPending, CreatedAt: created, UpdatedAt: created,
+	}, nil)
+
+	handler := NewReviewHandler(review)
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /api/v1/films/{id}/review", handler.SaveReview)
+
+	ctx := authmid.WithUser(context.Background(), 5, int(model.RoleUser))
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/api/v1/films/1/review", bytes.NewBufferString(`{"body": "  Great film "}`)).WithContext(ctx))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"id": 3, "film_id": 1, "user_id": 5, "username": "neo", "score": 9, "body": "Great film", "status": "pending",
+		"created_at": "2025-08-04T12:00:00Z", "updated_at": "2025-08-04T12:00:00Z"}`, rr.Body.String())
+
+	// Без пользователя в контексте рецензию не сохранить
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/api/v1/films/1/review", bytes.NewBufferString(`{"body": "Great film"}`)))
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	require.JSONEq(t, `{"status": 401, "message": "Unauthorized", "code": "unauthorized"}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/api/v1/films/1/review", bytes.NewBufferString(`{"body": "   "}`)).WithContext(ctx))
+	require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
+
+func TestHandler_GetReviews(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	review := mock_service.NewMockReview(c)
+	review.EXPECT().GetReviews(gomock.Any(), model.ReviewListParams{Status: model.ReviewPending, Limit: 20}).
+		Return(model.ReviewPage{Items: []model.Review{}, Limit: 20}, nil)
+
+	handler := NewReviewHandler(review)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/admin/reviews", handler.GetReviews)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/admin/reviews", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"items": [], "total": 0, "limit": 20, "offset": 0, "next_cursor": "", "prev_cursor": ""}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/admin/reviews?status=deleted", nil))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.JSONEq(t, `{"status": 400, "message": "Review status must be pending, approved or hidden", "code": "invalid_review_status"}`, rr.Body.String())
+}
+
+func TestHandler_HideReview(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	review := mock_service.NewMockReview(c)
+	review.EXPECT().ModerateReview(gomock.Any(), 404, 1, model.ReviewHidden).
+		Return(model.Review{}, domain.Wrap(domain.ErrNotFound, domain.ErrNotFound, "review_not_found", "рецензия не найдена"))
+
+	handler := NewReviewHandler(review)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/admin/reviews/{id}/hide", handler.HideReview)
+
+	ctx := authmid.WithUser(context.Background(), 1, int(model.RoleAdmin))
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/admin/reviews/404/hide", nil).WithContext(ctx))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+	require.JSONEq(t, `{"status": 404, "message": "Review not found", "code": "review_not_found"}`, rr.Body.String())
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"film-library/internal/utils/response"
+	"net/http"
+)
+
+// Router — обёртка над http.ServeMux (маршруты вида "GET /api/v1/films/{id}"),
+// которая отдаёт 404 и 405 в том же JSON-формате, что и остальные ошибки API.
+type Router struct {
+	mux *http.ServeMux
+}
+
+func NewRouter() *Router {
+	return &Router{mux: http.NewServeMux()}
+}
+
+func (rt *Router) Handle(pattern string, handler http.Handler) {
+	rt.mux.Handle(pattern, handler)
+}
+
+func (rt *Router) HandleFunc(pattern string, handler http.HandlerFunc) {
+	rt.mux.HandleFunc(pattern, handler)
+}
+
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h, pattern := rt.mux.Handler(r)
+	if pattern != "" {
+		rt.mux.ServeHTTP(w, r)
+		return
+	}
+
+	// Маршрут не найден: ServeMux сам решает, 404 это или 405, и для 405
+	// заполняет заголовок Allow. Забираем у него статус и заголовок,
+	// а тело ответа пишем сами.
+	capture := &statusCapture{header: make(http.Header)}
+	h.ServeHTTP(capture, r)
+
+	if capture.status == http.StatusMethodNotAllowed {
+		w.Header().Set("Allow", capture.header.Get("Allow"))
+		response.WriteJSONError(w, r, "method_not_allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	response.WriteJSONError(w, r, "not_found", http.StatusNotFound)
+}
+
+// statusCapture запоминает статус и заголовки, отбрасывая тело ответа.
+type statusCapture struct {
+	header http.Header
+	status int
+}
+
+func (c *statusCapture) Header() http.Header {
+	return c.header
+}
+
+func (c *statusCapture) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (c *statusCapture) WriteHeader(status int) {
+	c.status = status
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_Errors(t *testing.T) {
+	router := NewRouter()
+	router.HandleFunc("GET /api/v1/films/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	router.HandleFunc("DELETE /api/v1/films/{id}", func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name                 string
+		method               string
+		path                 string
+		expectedStatusCode   int
+		expectedAllow        string
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Not Found",
+			method:               http.MethodGet,
+			path:                 "/api/v1/unknown",
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"status": 404, "message": "Not found", "code": "not_found"}`,
+		},
+		{
+			name:                 "Method Not Allowed",
+			method:               http.MethodPost,
+			path:                 "/api/v1/films/1",
+			expectedStatusCode:   http.StatusMethodNotAllowed,
+			expectedAllow:        "DELETE, GET, HEAD",
+			expectedResponseBody: `{"status": 405, "message": "Method not allowed", "code": "method_not_allowed"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.Equal(t, tc.expectedAllow, rr.Header().Get("Allow"))
+			require.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestRouter_LegacyRoute(t *testing.T) {
+	router := NewRouter()
+
+	var gotID string
+	registerLegacyRoutes(router, []legacyRoute{
+		{"DELETE /film_delete/{id}", "DELETE /api/v1/films/{id}", func(w http.ResponseWriter, r *http.Request) {
+			gotID = r.PathValue("id")
+		}},
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/film_delete/7", nil)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "7", gotID)
+	require.Equal(t, "true", rr.Header().Get("Deprecation"))
+	require.Equal(t, `</api/v1/films/7>; rel="successor-version"`, rr.Header().Get("Link"))
+}
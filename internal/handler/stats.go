@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"encoding/json"
+	"film-library/internal/model"
+	"film-library/internal/service"
+	"film-library/internal/utils/response"
+	"net/http"
+	"net/url"
+)
+
+type StatsHandler struct {
+	service service.Stats
+}
+
+func NewStatsHandler(service service.Stats) StatsHandler {
+	return StatsHandler{service: service}
+}
+
+// @Summary Actor Statistics
+// @Security ApiKeyAuth
+// @Tags stats
+// @Description Film count, rating aggregates and career span per actor. Refreshed shortly after catalog changes
+// @ID get-actor-stats
+// @Produce  json
+// @Param sort_by query string false "film_count (default), avg_rating, career_years, name"
+// @Param order query string false "asc or desc (name defaults to asc, others to desc)"
+// @Param min_films query int false "Only actors with at least this many films"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} model.ActorStatsPage
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/stats/actors [get]
+func (h *StatsHandler) GetActorStats(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	params := model.ActorStatsParams{
+		SortBy: q.Get("sort_by"),
+		Order:  q.Get("order"),
+	}
+
+	var err error
+	if params.MinFilms, err = queryInt(q, "min_films", 0); err != nil {
+		response.WriteError(w, r, err, "get_stats_failed")
+		return
+	}
+	if params.Limit, params.Offset, err = queryPage(q); err != nil {
+		response.WriteError(w, r, err, "get_stats_failed")
+		return
+	}
+
+	if err := params.Validate(); err != nil {
+		response.WriteError(w, r, err, "get_stats_failed")
+		return
+	}
+
+	page, err := h.service.GetActorStats(r.Context(), params)
+	if err != nil {
+		response.WriteError(w, r, err, "get_stats_failed")
+		return
+	}
+
+	writeStats(w, page)
+}
+
+// @Summary Single Actor Statistics
+// @Security ApiKeyAuth
+// @Tags stats
+// @Description Film count, rating aggregates and career span of one actor
+// @ID get-actor-stats-by-id
+// @Produce  json
+// @Param id path int true "Actor ID"
+// @Success 200 {object} model.ActorStats
+// @Failure 400,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/stats/actors/{id} [get]
+func (h *StatsHandler) GetActorStatsByID(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_actor_id", http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.service.GetActorStatsByID(r.Context(), id)
+	if err != nil {
+		response.WriteError(w, r, err, "get_stats_failed")
+		return
+	}
+
+	writeStats(w, stats)
+}
+
+// @Summary Release Statistics by Year
+// @Security ApiKeyAuth
+// @Tags stats
+// @Description Number of released films and rating aggregates per year
+// @ID get-year-stats
+// @Produce  json
+// @Param from query int false "First year"
+// @Param to query int false "Last year"
+// @Success 200 {array} model.YearStats
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/stats/years [get]
+func (h *StatsHandler) GetYearStats(w http.ResponseWriter, r *http.Request) {
+	years, err := parseYearRange(r.URL.Query())
+	if err != nil {
+		response.WriteError(w, r, err, "get_stats_failed")
+		return
+	}
+
+	stats, err := h.service.GetYearStats(r.Context(), years)
+	if err != nil {
+		response.WriteError(w, r, err, "get_stats_failed")
+		return
+	}
+
+	writeStats(w, stats)
+}
+
+// @Summary Rating Histogram
+// @Security ApiKeyAuth
+// @Tags stats
+// @Description Number of films per rating bucket of width 1 (the last bucket includes 10), optionally for a range of release years
+// @ID get-rating-histogram
+// @Produce  json
+// @Param from query int false "First release year"
+// @Param to query int false "Last release year"
+// @Success 200 {array} model.RatingBucket
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/stats/ratings [get]
+func (h *StatsHandler) GetRatingHistogram(w http.ResponseWriter, r *http.Request) {
+	years, err := parseYearRange(r.URL.Query())
+	if err != nil {
+		response.WriteError(w, r, err, "get_stats_failed")
+		return
+	}
+
+	buckets, err := h.service.GetRatingHistogram(r.Context(), years)
+	if err != nil {
+		response.WriteError(w, r, err, "get_stats_failed")
+		return
+	}
+
+	writeStats(w, buckets)
+}
+
+// @Summary Top Co-star Pairs
+// @Security ApiKeyAuth
+// @Tags stats
+// @Description Pairs of actors who appeared together, by number of shared films
+// @ID get-costars
+// @Produce  json
+// @Param actor_id query int false "Only partners of this actor (listed first in each pair)"
+// @Param min_films query int false "Only pairs with at least this many shared films"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} model.CostarPage
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/stats/costars [get]
+func (h *StatsHandler) GetCostars(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var params model.CostarParams
+	var err error
+	if params.ActorID, err = queryInt(q, "actor_id", 0); err != nil {
+		response.WriteError(w, r, err, "get_stats_failed")
+		return
+	}
+	if params.MinFilms, err = queryInt(q, "min_films", 0); err != nil {
+		response.WriteError(w, r, err, "get_stats_failed")
+		return
+	}
+	if params.Limit, params.Offset, err = queryPage(q); err != nil {
+		response.WriteError(w, r, err, "get_stats_failed")
+		return
+	}
+
+	if err := params.Validate(); err != nil {
+		response.WriteError(w, r, err, "get_stats_failed")
+		return
+	}
+
+	page, err := h.service.GetCostars(r.Context(), params)
+	if err != nil {
+		response.WriteError(w, r, err, "get_stats_failed")
+		return
+	}
+
+	writeStats(w, page)
+}
+
+// parseYearRange читает период from/to по годам выхода
+func parseYearRange(q url.Values) (model.YearRange, error) {
+	var years model.YearRange
+	var err error
+
+	if years.From, err = queryInt(q, "from", 0); err != nil {
+		return years, err
+	}
+	if years.To, err = queryInt(q, "to", 0); err != nil {
+		return years, err
+	}
+
+	return years, years.Validate()
+}
+
+func writeStats(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	mock_service "film-library/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_GetActorStats(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockStats)
+
+	avg, min, max := 7.85, float32(7), float32(8.7)
+	first := time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC)
+	last := time.Date(2021, 12, 22, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?sort_by=avg_rating&min_films=2&limit=1",
+			mockBehavior: func(r *mock_service.MockStats) {
+				r.EXPECT().GetActorStats(gomock.Any(), model.ActorStatsParams{SortBy: "avg_rating", MinFilms: 2, Limit: 1}).
+					Return(model.ActorStatsPage{
+						Items: []model.ActorStats{{
+							ActorID: 7, Name: "Keanu Reeves", FilmCount: 2, AvgRating: &avg, MinRating: &min, MaxRating: &max,
+							FirstRelease: &first, LastRelease: &last, CareerYears: 22,
+						}},
+						Total: 5,
+						Limit: 1,
+					}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"items": [{"actor_id": 7, "name": "Keanu Reeves", "film_count": 2, "avg_rating": 7.85, "min_rating": 7, "max_rating": 8.7,
+				"first_release": "1999-03-31T00:00:00Z", "last_release": "2021-12-22T00:00:00Z", "career_years": 22}],
+				"total": 5, "limit": 1, "offset": 0, "next_cursor": "", "prev_cursor": ""}`,
+		},
+		{
+			name:                 "Invalid Sort",
+			query:                "?sort_by=gender",
+			mockBehavior:         func(r *mock_service.MockStats) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Invalid sort field", "code": "invalid_sort"}`,
+		},
+		{
+			name:                 "Negative Min Films",
+			query:                "?min_films=-1",
+			mockBehavior:         func(r *mock_service.MockStats) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "min_films cannot be negative", "code": "negative_min_films"}`,
+		},
+		{
+			name:  "Service Error",
+			query: "",
+			mockBehavior: func(r *mock_service.MockStats) {
+				r.EXPECT().GetActorStats(gomock.Any(), gomock.Any()).Return(model.ActorStatsPage{}, errors.New("relation does not exist"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"status": 500, "message": "Failed to get statistics", "code": "internal_error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			stats := mock_service.NewMockStats(c)
+			test.mockBehavior(stats)
+
+			handler := NewStatsHandler(stats)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/stats/actors"+test.query, nil)
+
+			rr := httptest.NewRecorder()
+			handler.GetActorStats(rr, req)
+
+			require.Equal(t, test.expectedStatusCode, rr.Code)
+			require.JSONEq(t, test.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetActorStatsByID(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	stats := mock_service.NewMockStats(c)
+	stats.EXPECT().GetActorStatsByID(gomock.Any(), 9).Return(model.ActorStats{ActorID: 9, Name: "Newcomer"}, nil)
+	stats.EXPECT().GetActorStatsByID(gomock.Any(), 404).Return(model.ActorStats{},
+		domain.Wrap(domain.ErrNotFound, domain.ErrNotFound, "actor_not_found", "актёр не найден"))
+
+	handler := NewStatsHandler(stats)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/stats/actors/{id}", handler.GetActorStatsByID)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/stats/actors/9", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"actor_id": 9, "name": "Newcomer", "film_count": 0, "avg_rating": null, "min_rating": null, "max_rating": null,
+		"first_release": null, "last_release": null, "career_years": 0}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/stats/actors/404", nil))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+	require.JSONEq(t, `{"status": 404, "message": "Actor not found", "code": "actor_not_found"}`, rr.Body.String())
+}
+
+func TestHandler_GetRatingHistogram(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockStats)
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?from=1990&to=1999",
+			mockBehavior: func(r *mock_service.MockStats) {
+				r.EXPECT().GetRatingHistogram(gomock.Any(), model.YearRange{From: 1990, To: 1999}).
+					Return([]model.RatingBucket{{From: 8, To: 9, FilmCount: 3}, {From: 9, To: 10, FilmCount: 1}}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `[{"from": 8, "to": 9, "film_count": 3}, {"from": 9, "to": 10, "film_count": 1}]`,
+		},
+		{
+			name:                 "Inverted Range",
+			query:                "?from=2000&to=1990",
+			mockBehavior:         func(r *mock_service.MockStats) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "First year is after the last one", "code": "year_range_inverted"}`,
+		},
+		{
+			name:                 "Invalid Year",
+			query:                "?from=nineties",
+			mockBehavior:         func(r *mock_service.MockStats) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Parameter from must be an integer", "code": "invalid_integer_param"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			stats := mock_service.NewMockStats(c)
+			test.mockBehavior(stats)
+
+			handler := NewStatsHandler(stats)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/stats/ratings"+test.query, nil)
+
+			rr := httptest.NewRecorder()
+			handler.GetRatingHistogram(rr, req)
+
+			require.Equal(t, test.expectedStatusCode, rr.Code)
+			require.JSONEq(t, test.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetCostars(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	stats := mock_service.NewMockStats(c)
+	stats.EXPECT().GetCostars(gomock.Any(), model.CostarParams{ActorID: 7, MinFilms: 2, Limit: model.DefaultPageLimit}).
+		Return(model.CostarPage{
+			Items: []model.CostarPair{{
+				Actor:     model.ActorRef{Id: 7, Name: "Keanu Reeves"},
+				Costar:    model.ActorRef{Id: 8, Name: "Carrie-Anne Moss"},
+				FilmCount: 4,
+			}},
+			Total: 1,
+			Limit: model.DefaultPageLimit,
+		}, nil)
+
+	handler := NewStatsHandler(stats)
+
+	rr := httptest.NewRecorder()
+	handler.GetCostars(rr, httptest.NewRequest(http.MethodGet, "/api/v1/stats/costars?actor_id=7&min_films=2", nil))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"items": [{"actor": {"id": 7, "name": "Keanu Reeves"}, "costar": {"id": 8, "name": "Carrie-Anne Moss"}, "film_count": 4}],
+		"total": 1, "limit": 20, "offset": 0, "next_cursor": "", "prev_cursor": ""}`, rr.Body.String())
+}
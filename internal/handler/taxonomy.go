@@ -0,0 +1,532 @@
+package handler
+
+import (
+	"encoding/json"
+	"film-library/internal/model"
+	"film-library/internal/service"
+	"film-library/internal/utils/response"
+	"net/http"
+	"strings"
+)
+
+type TaxonomyHandler struct {
+	service service.Taxonomy
+}
+
+func NewTaxonomyHandler(service service.Taxonomy) TaxonomyHandler {
+	return TaxonomyHandler{service: service}
+}
+
+// @Summary Get Genres
+// @Security ApiKeyAuth
+// @Tags taxonomy
+// @Description All genres by name with the number of films in each
+// @ID get-genres
+// @Produce  json
+// @Success 200 {array} model.Genre
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/genres [get]
+func (h *TaxonomyHandler) GetGenres(w http.ResponseWriter, r *http.Request) {
+	genres, err := h.service.GetGenres(r.Context())
+	if err != nil {
+		response.WriteError(w, r, err, "get_genres_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, genres)
+}
+
+// @Summary Get Genre
+// @Security ApiKeyAuth
+// @Tags taxonomy
+// @Description Get genre with the number of its films
+// @ID get-genre
+// @Produce  json
+// @Param id path int true "Genre ID"
+// @Success 200 {object} model.Genre
+// @Failure 400,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/genres/{id} [get]
+func (h *TaxonomyHandler) GetGenre(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_genre_id", http.StatusBadRequest)
+		return
+	}
+
+	genre, err := h.service.GetGenre(r.Context(), id)
+	if err != nil {
+		response.WriteError(w, r, err, "get_genres_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, genre)
+}
+
+// @Summary Create Genre
+// @Security ApiKeyAuth
+// @Tags taxonomy
+// @Description Add genre to the dictionary. Names are unique regardless of case
+// @ID create-genre
+// @Accept  json
+// @Produce  json
+// @Param genre body model.Genre true "Genre"
+// @Success 201 {object} model.Genre
+// @Failure 400,403,409 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/genres [post]
+func (h *TaxonomyHandler) CreateGenre(w http.ResponseWriter, r *http.Request) {
+	var genre model.Genre
+	if err := json.NewDecoder(r.Body).Decode(&genre); err != nil {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+
+	if err := genre.Validate(); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
+		return
+	}
+
+	created, err := h.service.CreateGenre(r.Context(), genre)
+	if err != nil {
+		response.WriteError(w, r, err, "create_genre_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// @Summary Update Genre
+// @Security ApiKeyAuth
+// @Tags taxonomy
+// @Description Rename genre; films keep it under the new name
+// @ID update-genre
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Genre ID"
+// @Param genre body model.Genre true "Genre"
+// @Success 200 {object} model.Genre
+// @Failure 400,403,404,409 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/genres/{id} [put]
+func (h *TaxonomyHandler) UpdateGenre(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_genre_id", http.StatusBadRequest)
+		return
+	}
+
+	var genre model.Genre
+	if err := json.NewDecoder(r.Body).Decode(&genre); err != nil {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+	genre.Id = id
+
+	if err := genre.Validate(); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
+		return
+	}
+
+	updated, err := h.service.UpdateGenre(r.Context(), genre)
+	if err != nil {
+		response.WriteError(w, r, err, "update_genre_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// @Summary Delete Genre
+// @Security ApiKeyAuth
+// @Tags taxonomy
+// @Description Delete genre. A genre assigned to films can't be deleted
+// @ID delete-genre
+// @Produce  json
+// @Param id path int true "Genre ID"
+// @Success 200 {object} map[string]string
+// @Failure 400,403,404,409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/genres/{id} [delete]
+func (h *TaxonomyHandler) DeleteGenre(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_genre_id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteGenre(r.Context(), id); err != nil {
+		response.WriteError(w, r, err, "delete_genre_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "genre deleted successfully"})
+}
+
+// @Summary Get Countries
+// @Security ApiKeyAuth
+// @Tags taxonomy
+// @Description All production countries by name with the number of films from each
+// @ID get-countries
+// @Produce  json
+// @Success 200 {array} model.Country
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/countries [get]
+func (h *TaxonomyHandler) GetCountries(w http.ResponseWriter, r *http.Request) {
+	countries, err := h.service.GetCountries(r.Context())
+	if err != nil {
+		response.WriteError(w, r, err, "get_countries_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, countries)
+}
+
+// @Summary Get Country
+// @Security ApiKeyAuth
+// @Tags taxonomy
+// @Description Get country by ISO 3166-1 alpha-2 code
+// @ID get-country
+// @Produce  json
+// @Param code path string true "Country code, e.g. US"
+// @Success 200 {object} model.Country
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/countries/{code} [get]
+func (h *TaxonomyHandler) GetCountry(w http.ResponseWriter, r *http.Request) {
+	country, err := h.service.GetCountry(r.Context(), pathCountryCode(r))
+	if err != nil {
+		response.WriteError(w, r, err, "get_countries_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, country)
+}
+
+// @Summary Create Country
+// @Security ApiKeyAuth
+// @Tags taxonomy
+// @Description Add country to the dictionary
+// @ID create-country
+// @Accept  json
+// @Produce  json
+// @Param country body model.Country true "Country"
+// @Success 201 {object} model.Country
+// @Failure 400,403,409 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/countries [post]
+func (h *TaxonomyHandler) CreateCountry(w http.ResponseWriter, r *http.Request) {
+	var country model.Country
+	if err := json.NewDecoder(r.Body).Decode(&country); err != nil {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+
+	if err := country.Validate(); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
+		return
+	}
+
+	created, err := h.service.CreateCountry(r.Context(), country)
+	if err != nil {
+		response.WriteError(w, r, err, "create_country_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// @Summary Update Country
+// @Security ApiKeyAuth
+// @Tags taxonomy
+// @Description Rename country. The code is taken from the path and can't be changed
+// @ID update-country
+// @Accept  json
+// @Produce  json
+// @Param code path string true "Country code"
+// @Param country body model.Country true "Country"
+// @Success 200 {object} model.Country
+// @Failure 400,403,404,409 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/countries/{code} [put]
+func (h *TaxonomyHandler) UpdateCountry(w http.ResponseWriter, r *http.Request) {
+	var country model.Country
+	if err := json.NewDecoder(r.Body).Decode(&country); err != nil {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+	country.Code = pathCountryCode(r)
+
+	if err := country.Validate(); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
+		return
+	}
+
+	updated, err := h.service.UpdateCountry(r.Context(), country)
+	if err != nil {
+		response.WriteError(w, r, err, "update_country_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// @Summary Delete Country
+// @Security ApiKeyAuth
+// @Tags taxonomy
+// @Description Delete country. A country assigned to films can't be deleted
+// @ID delete-country
+// @Produce  json
+// @Param code path string true "Country code"
+// @Success 200 {object} map[string]string
+// @Failure 403,404,409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/countries/{code} [delete]
+func (h *TaxonomyHandler) DeleteCountry(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteCountry(r.Context(), pathCountryCode(r)); err != nil {
+		response.WriteError(w, r, err, "delete_country_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "country deleted successfully"})
+}
+
+// @Summary Get Tags
+// @Security ApiKeyAuth
+// @Tags taxonomy
+// @Description Tags starting with q, most used first. Suitable for autocomplete
+// @ID get-tags
+// @Produce  json
+// @Param q query string false "Tag prefix"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} model.TagPage
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/tags [get]
+func (h *TaxonomyHandler) GetTags(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	params := model.TagListParams{Prefix: strings.ToLower(strings.TrimSpace(q.Get("q")))}
+
+	var err error
+	if params.Limit, params.Offset, err = queryPage(q); err != nil {
+		response.WriteError(w, r, err, "get_tags_failed")
+		return
+	}
+
+	if err := params.Validate(); err != nil {
+		response.WriteError(w, r, err, "get_tags_failed")
+		return
+	}
+
+	page, err := h.service.GetTags(r.Context(), params)
+	if err != nil {
+		response.WriteError(w, r, err, "get_tags_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+// @Summary Get Tag
+// @Security ApiKeyAuth
+// @Tags taxonomy
+// @Description Get tag with the number of its films
+// @ID get-tag
+// @Produce  json
+// @Param id path int true "Tag ID"
+// @Success 200 {object} model.Tag
+// @Failure 400,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/tags/{id} [get]
+func (h *TaxonomyHandler) GetTag(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_tag_id", http.StatusBadRequest)
+		return
+	}
+
+	tag, err := h.service.GetTag(r.Context(), id)
+	if err != nil {
+		response.WriteError(w, r, err, "get_tags_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tag)
+}
+
+// @Summary Create Tag
+// @Security ApiKeyAuth
+// @Tags taxonomy
+// @Description Create tag in advance. Tags are also created when assigned to a film
+// @ID create-tag
+// @Accept  json
+// @Produce  json
+// @Param tag body model.Tag true "Tag"
+// @Success 201 {object} model.Tag
+// @Failure 400,403,409 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/tags [post]
+func (h *TaxonomyHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	var tag model.Tag
+	if err := json.NewDecoder(r.Body).Decode(&tag); err != nil {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+
+	if err := tag.Validate(); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
+		return
+	}
+
+	created, err := h.service.CreateTag(r.Context(), tag)
+	if err != nil {
+		response.WriteError(w, r, err, "create_tag_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// @Summary Update Tag
+// @Security ApiKeyAuth
+// @Tags taxonomy
+// @Description Rename tag on all films
+// @ID update-tag
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Tag ID"
+// @Param tag body model.Tag true "Tag"
+// @Success 200 {object} model.Tag
+// @Failure 400,403,404,409 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/tags/{id} [put]
+func (h *TaxonomyHandler) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_tag_id", http.StatusBadRequest)
+		return
+	}
+
+	var tag model.Tag
+	if err := json.NewDecoder(r.Body).Decode(&tag); err != nil {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+	tag.Id = id
+
+	if err := tag.Validate(); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
+		return
+	}
+
+	updated, err := h.service.UpdateTag(r.Context(), tag)
+	if err != nil {
+		response.WriteError(w, r, err, "update_tag_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// @Summary Delete Tag
+// @Security ApiKeyAuth
+// @Tags taxonomy
+// @Description Delete tag and remove it from all films
+// @ID delete-tag
+// @Produce  json
+// @Param id path int true "Tag ID"
+// @Success 200 {object} map[string]string
+// @Failure 400,403,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/tags/{id} [delete]
+func (h *TaxonomyHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_tag_id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteTag(r.Context(), id); err != nil {
+		response.WriteError(w, r, err, "delete_tag_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "tag deleted successfully"})
+}
+
+// @Summary Film Facets
+// @Security ApiKeyAuth
+// @Tags film
+// @Description Number of films per genre, country and most used tags under the same filters as the film list
+// @ID get-film-facets
+// @Produce  json
+// @Param min_rating query number false "Minimal rating"
+// @Param max_rating query number false "Maximal rating"
+// @Param released_from query string false "Release date from (YYYY-MM-DD)"
+// @Param released_to query string false "Release date to (YYYY-MM-DD)"
+// @Param actor_id query int false "Only films with this actor"
+// @Param name query string false "Film name prefix"
+// @Param genre query []string false "Only films with all these genres" collectionFormat(multi)
+// @Param country query []string false "Only films from all these countries" collectionFormat(multi)
+// @Param tag query []string false "Only films with all these tags" collectionFormat(multi)
+// @Success 200 {object} model.FilmFacets
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/films/facets [get]
+func (h *TaxonomyHandler) GetFilmFacets(w http.ResponseWriter, r *http.Request) {
+	params, err := parseFilmListParams(r)
+	if err != nil {
+		response.WriteError(w, r, err, "get_facets_failed")
+		return
+	}
+
+	// Сортировка и страница на фасеты не влияют
+	if err := params.ValidateFilters(); err != nil {
+		response.WriteError(w, r, err, "get_facets_failed")
+		return
+	}
+
+	facets, err := h.service.GetFilmFacets(r.Context(), params)
+	if err != nil {
+		response.WriteError(w, r, err, "get_facets_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, facets)
+}
+
+// pathCountryCode - код страны из пути в верхнем регистре
+func pathCountryCode(r *http.Request) string {
+	return strings.ToUpper(r.PathValue("code"))
+}
+
+// writeJSON отдаёт v в JSON со статусом status
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
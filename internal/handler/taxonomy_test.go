@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	mock_service "film-library/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_CreateGenre(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockTaxonomy)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"name": "  Film Noir "}`,
+			mockBehavior: func(r *mock_service.MockTaxonomy) {
+				r.EXPECT().CreateGenre(gomock.Any(), model.Genre{Name: "Film Noir"}).Return(model.Genre{Id: 17, Name: "Film Noir"}, nil)
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedResponseBody: `{"id": 17, "name": "Film Noir", "film_count": 0}`,
+		},
+		{
+			name:                 "Empty Name",
+			inputBody:            `{"name": " "}`,
+			mockBehavior:         func(r *mock_service.MockTaxonomy) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/api/v1/genres", "code": "validation_failed", "errors": [{"field": "name", "rule": "required", "message": "is required"}]}`,
+		},
+		{
+			name:      "Duplicate",
+			inputBody: `{"name": "drama"}`,
+			mockBehavior: func(r *mock_service.MockTaxonomy) {
+				r.EXPECT().CreateGenre(gomock.Any(), model.Genre{Name: "drama"}).
+					Return(model.Genre{}, domain.Wrap(domain.ErrConflict, domain.ErrConflict, "genre_exists", "жанр с таким названием уже существует"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"status": 409, "message": "Genre with this name already exists", "code": "genre_exists"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			taxonomy := mock_service.NewMockTaxonomy(c)
+			test.mockBehavior(taxonomy)
+
+			handler := NewTaxonomyHandler(taxonomy)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/genres", bytes.NewBufferString(test.inputBody))
+
+			rr := httptest.NewRecorder()
+			handler.CreateGenre(rr, req)
+
+			require.Equal(t, test.expectedStatusCode, rr.Code)
+			require.JSONEq(t, test.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_DeleteGenre(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	taxonomy := mock_service.NewMockTaxonomy(c)
+	taxonomy.EXPECT().DeleteGenre(gomock.Any(), 3).Return(nil)
+	taxonomy.EXPECT().DeleteGenre(gomock.Any(), 7).
+		Return(domain.Wrap(domain.ErrValidation, domain.ErrConflict, "genre_in_use", "жанр назначен фильмам"))
+
+	handler := NewTaxonomyHandler(taxonomy)
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /api/v1/genres/{id}", handler.DeleteGenre)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/api/v1/genres/3", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"message": "genre deleted successfully"}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/api/v1/genres/7", nil))
+	require.Equal(t, http.StatusConflict, rr.Code)
+	require.JSONEq(t, `{"status": 409, "message": "Genre is assigned to films and cannot be deleted", "code": "genre_in_use"}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/api/v1/genres/drama", nil))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.JSONEq(t, `{"status": 400, "message": "Invalid genre ID", "code": "invalid_genre_id"}`, rr.Body.String())
+}
+
+func TestHandler_UpdateCountry(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockTaxonomy)
+
+	tests := []struct {
+		name                 string
+		code                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			code:      "gb",
+			inputBody: `{"name": "Great Britain"}`,
+			mockBehavior: func(r *mock_service.MockTaxonomy) {
+				r.EXPECT().UpdateCountry(gomock.Any(), model.Country{Code: "GB", Name: "Great Britain"}).
+					Return(model.Country{Code: "GB", Name: "Great Britain", FilmCount: 12}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"code": "GB", "name": "Great Britain", "film_count": 12}`,
+		},
+		{
+			name:                 "Invalid Code",
+			code:                 "GBR",
+			inputBody:            `{"name": "Great Britain"}`,
+			mockBehavior:         func(r *mock_service.MockTaxonomy) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/api/v1/countries/GBR", "code": "validation_failed", "errors": [{"field": "code", "rule": "country_code", "message": "must be a two-letter ISO 3166-1 country code"}]}`,
+		},
+		{
+			name:      "Not Found",
+			code:      "XX",
+			inputBody: `{"name": "Nowhere"}`,
+			mockBehavior: func(r *mock_service.MockTaxonomy) {
+				r.EXPECT().UpdateCountry(gomock.Any(), model.Country{Code: "XX", Name: "Nowhere"}).
+					Return(model.Country{}, domain.Wrap(domain.ErrNotFound, domain.ErrNotFound, "country_not_found", "страна не найдена"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"status": 404, "message": "Country not found", "code": "country_not_found"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			taxonomy := mock_service.NewMockTaxonomy(c)
+			test.mockBehavior(taxonomy)
+
+			handler := NewTaxonomyHandler(taxonomy)
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /api/v1/countries/{code}", handler.UpdateCountry)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/countries/"+test.code, bytes.NewBufferString(test.inputBody))
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			require.Equal(t, test.expectedStatusCode, rr.Code)
+			require.JSONEq(t, test.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetTags(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockTaxonomy)
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?q=Cult&limit=2",
+			mockBehavior: func(r *mock_service.MockTaxonomy) {
+				r.EXPECT().GetTags(gomock.Any(), model.TagListParams{Prefix: "cult", Limit: 2}).
+					Return(model.TagPage{Items: []model.Tag{{Id: 4, Name: "cult classic", FilmCount: 9}}, Total: 1, Limit: 2}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"items": [{"id": 4, "name": "cult classic", "film_count": 9}],
+				"total": 1, "limit": 2, "offset": 0, "next_cursor": "", "prev_cursor": ""}`,
+		},
+		{
+			name:                 "Invalid Limit",
+			query:                "?limit=1000",
+			mockBehavior:         func(r *mock_service.MockTaxonomy) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "limit must be between 1 and 100", "code": "limit_out_of_range"}`,
+		},
+		{
+			name:  "Service Error",
+			query: "",
+			mockBehavior: func(r *mock_service.MockTaxonomy) {
+				r.EXPECT().GetTags(gomock.Any(), gomock.Any()).Return(model.TagPage{}, errors.New("connection refused"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"status": 500, "message": "Failed to get tags", "code": "internal_error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			taxonomy := mock_service.NewMockTaxonomy(c)
+			test.mockBehavior(taxonomy)
+
+			handler := NewTaxonomyHandler(taxonomy)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tags"+test.query, nil)
+
+			rr := httptest.NewRecorder()
+			handler.GetTags(rr, req)
+
+			require.Equal(t, test.expectedStatusCode, rr.Code)
+			require.JSONEq(t, test.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetFilmFacets(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockTaxonomy)
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "Ok",
+			query: "?genre=Drama,crime&country=us&tag=cult+classic",
+			mockBehavior: func(r *mock_service.MockTaxonomy) {
+				r.EXPECT().GetFilmFacets(gomock.Any(), model.FilmListParams{
+					Limit:  model.DefaultPageLimit,
+					Labels: model.FilmLabelFilter{Genres: []string{"Drama", "crime"}, Countries: []string{"us"}, Tags: []string{"cult classic"}},
+				}).Return(model.FilmFacets{
+					Total:     2,
+					Genres:    []model.FacetCount{{Value: "Crime", Name: "Crime", FilmCount: 2}, {Value: "Drama", Name: "Drama", FilmCount: 2}},
+					Countries: []model.FacetCount{{Value: "US", Name: "United States", FilmCount: 2}},
+					Tags:      []model.FacetCount{{Value: "cult classic", Name: "cult classic", FilmCount: 2}},
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"total": 2,
+				"genres": [{"value": "Crime", "name": "Crime", "film_count": 2}, {"value": "Drama", "name": "Drama", "film_count": 2}],
+				"countries": [{"value": "US", "name": "United States", "film_count": 2}],
+				"tags": [{"value": "cult classic", "name": "cult classic", "film_count": 2}]}`,
+		},
+		{
+			name:                 "Too Many Values",
+			query:                "?tag=a,b,c,d,e,f,g,h,i,j,k",
+			mockBehavior:         func(r *mock_service.MockTaxonomy) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Filter tag accepts at most 10 values", "code": "too_many_filter_values"}`,
+		},
+		{
+			name:                 "Invalid Rating",
+			query:                "?min_rating=high",
+			mockBehavior:         func(r *mock_service.MockTaxonomy) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Parameter min_rating must be a number", "code": "invalid_number_param"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			taxonomy := mock_service.NewMockTaxonomy(c)
+			test.mockBehavior(taxonomy)
+
+			handler := NewTaxonomyHandler(taxonomy)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/films/facets"+test.query, nil)
+
+			rr := httptest.NewRecorder()
+			handler.GetFilmFacets(rr, req)
+
+			require.Equal(t, test.expectedStatusCode, rr.Code)
+			require.JSONEq(t, test.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"film-library/internal/service"
+	"film-library/internal/utils/response"
+	"net/http"
+)
+
+type TrashHandler struct {
+	service service.Trash
+}
+
+func NewTrashHandler(service service.Trash) TrashHandler {
+	return TrashHandler{service: service}
+}
+
+// @Summary List Trash
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Get soft-deleted films and actors, most recently deleted first
+// @ID list-trash
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} model.Trash
+// @Failure 401,403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/admin/trash [get]
+func (h *TrashHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	trash, err := h.service.GetTrash(r.Context())
+	if err != nil {
+		response.WriteError(w, r, err, "get_trash_failed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(trash)
+}
+
+// @Summary Restore Film
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Restore soft-deleted film together with its cast
+// @ID restore-film
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Success 200 {object} model.Film
+// @Failure 400,401,403,404,409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/admin/trash/films/{id}/restore [post]
+func (h *TrashHandler) RestoreFilm(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	film, err := h.service.RestoreFilm(r.Context(), id)
+	if err != nil {
+		response.WriteError(w, r, err, "restore_film_failed")
+		return
+	}
+
+	response.WriteJSONVersion(w, r, http.StatusOK, film, film.Version)
+}
+
+// @Summary Restore Actor
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description Restore soft-deleted actor together with their film roles
+// @ID restore-actor
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Actor ID"
+// @Success 200 {object} model.ActorWithFilms
+// @Failure 400,401,403,404,409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/admin/trash/actors/{id}/restore [post]
+func (h *TrashHandler) RestoreActor(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_actor_id", http.StatusBadRequest)
+		return
+	}
+
+	actor, err := h.service.RestoreActor(r.Context(), id)
+	if err != nil {
+		response.WriteError(w, r, err, "restore_actor_failed")
+		return
+	}
+
+	response.WriteJSONVersion(w, r, http.StatusOK, actor, actor.Actor.Version)
+}
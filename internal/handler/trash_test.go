@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	mock_service "film-library/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_GetTrash(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	trash := mock_service.NewMockTrash(c)
+	trash.EXPECT().GetTrash(gomock.Any()).Return(model.Trash{
+		Films:  []model.TrashItem{{Id: 1, Name: "name", DeletedAt: time.Date(2025, 6, 16, 9, 0, 0, 0, time.UTC)}},
+		Actors: []model.TrashItem{},
+	}, nil)
+
+	handler := NewTrashHandler(trash)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/trash", nil)
+
+	rr := httptest.NewRecorder()
+	handler.GetTrash(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"films": [{"id": 1, "name": "name", "deleted_at": "2025-06-16T09:00:00Z"}], "actors": []}`, rr.Body.String())
+}
+
+func TestHandler_RestoreFilm(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockTrash, id int)
+
+	tests := []struct {
+		name                 string
+		pathParam            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedETag         string
+	}{
+		{
+			name:      "Ok",
+			pathParam: "1",
+			mockBehavior: func(r *mock_service.MockTrash, id int) {
+				r.EXPECT().RestoreFilm(gomock.Any(), id).Return(model.Film{
+					Id: 1, Name: "name", Rating: 8, Version: 3,
+					ListActors: []model.CastMember{{Actor: model.Actor{Id: 2, Name: "actor"}, Character: "hero"}},
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"id": 1, "name": "name", "description": "", "release_date": "0001-01-01T00:00:00Z", "rating": 8, "list_actors": [{"id": 2, "name": "actor", "gender": "", "date_of_birth": "0001-01-01T00:00:00Z", "character": "hero"}]}`,
+			expectedETag:         `^"3-[0-9a-f]{16}"$`,
+		},
+		{
+			name:                 "Invalid ID",
+			pathParam:            "abc",
+			mockBehavior:         func(r *mock_service.MockTrash, id int) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"status": 400, "message": "Invalid film ID", "code": "invalid_film_id"}`,
+		},
+		{
+			name:      "Not In Trash",
+			pathParam: "1",
+			mockBehavior: func(r *mock_service.MockTrash, id int) {
+				r.EXPECT().RestoreFilm(gomock.Any(), id).Return(model.Film{}, domain.NotFound("film_not_found", "фильм не найден"))
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"status": 404, "message": "Film not found", "code": "film_not_found"}`,
+		},
+		{
+			name:      "Name Taken",
+			pathParam: "1",
+			mockBehavior: func(r *mock_service.MockTrash, id int) {
+				r.EXPECT().RestoreFilm(gomock.Any(), id).Return(model.Film{}, domain.Conflict("film_exists", "фильм с таким названием уже существует"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"status": 409, "message": "Film with this name already exists", "code": "film_exists"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			trash := mock_service.NewMockTrash(c)
+			test.mockBehavior(trash, 1)
+
+			handler := NewTrashHandler(trash)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/trash/films/"+test.pathParam+"/restore", nil)
+			req.SetPathValue("id", test.pathParam)
+
+			rr := httptest.NewRecorder()
+			handler.RestoreFilm(rr, req)
+
+			require.Equal(t, test.expectedStatusCode, rr.Code)
+			require.JSONEq(t, test.expectedResponseBody, rr.Body.String())
+			if test.expectedETag != "" {
+				require.Regexp(t, test.expectedETag, rr.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestHandler_RestoreActor(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	trash := mock_service.NewMockTrash(c)
+	trash.EXPECT().RestoreActor(gomock.Any(), 2).Return(model.ActorWithFilms{
+		Actor: model.Actor{Id: 2, Name: "actor", Version: 5},
+		Films: []model.Film{{Id: 1, Name: "name"}},
+	}, nil)
+
+	handler := NewTrashHandler(trash)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/trash/actors/2/restore", nil)
+	req.SetPathValue("id", "2")
+
+	rr := httptest.NewRecorder()
+	handler.RestoreActor(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Regexp(t, `^"5-[0-9a-f]{16}"$`, rr.Header().Get("ETag"))
+	require.JSONEq(t, `{"actor": {"id": 2, "name": "actor", "gender": "", "date_of_birth": "0001-01-01T00:00:00Z"}, "films": [{"id": 1, "name": "name", "description": "", "release_date": "0001-01-01T00:00:00Z", "rating": 0, "list_actors": null}]}`, rr.Body.String())
+}
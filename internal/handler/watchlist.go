@@ -0,0 +1,540 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"film-library/internal/model"
+	"film-library/internal/service"
+	"film-library/internal/utils/response"
+	"io"
+	"net/http"
+)
+
+type WatchlistHandler struct {
+	service service.Watchlist
+}
+
+func NewWatchlistHandler(service service.Watchlist) WatchlistHandler {
+	return WatchlistHandler{service: service}
+}
+
+// @Summary My Watchlists
+// @Security ApiKeyAuth
+// @Tags watchlists
+// @Description Own watchlists, public and private, by name
+// @ID get-my-watchlists
+// @Produce  json
+// @Success 200 {array} model.Watchlist
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/me/watchlists [get]
+func (h *WatchlistHandler) GetMyWatchlists(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	lists, err := h.service.GetWatchlists(r.Context(), userID, userID)
+	if err != nil {
+		response.WriteError(w, r, err, "get_watchlists_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, lists)
+}
+
+// @Summary User Watchlists
+// @Security ApiKeyAuth
+// @Tags watchlists
+// @Description Public watchlists of the user; own private lists are included for the owner
+// @ID get-user-watchlists
+// @Produce  json
+// @Param id path int true "User ID"
+// @Success 200 {array} model.Watchlist
+// @Failure 400,401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/users/{id}/watchlists [get]
+func (h *WatchlistHandler) GetUserWatchlists(w http.ResponseWriter, r *http.Request) {
+	viewerID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	ownerID, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_user_id", http.StatusBadRequest)
+		return
+	}
+
+	lists, err := h.service.GetWatchlists(r.Context(), viewerID, ownerID)
+	if err != nil {
+		response.WriteError(w, r, err, "get_watchlists_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, lists)
+}
+
+// @Summary Get Watchlist
+// @Security ApiKeyAuth
+// @Tags watchlists
+// @Description Watchlist with films in list order. Private lists are visible only to the owner
+// @ID get-watchlist
+// @Produce  json
+// @Param id path int true "Watchlist ID"
+// @Success 200 {object} model.WatchlistWithFilms
+// @Failure 400,401,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/watchlists/{id} [get]
+func (h *WatchlistHandler) GetWatchlist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_watchlist_id", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.service.GetWatchlist(r.Context(), id, userID)
+	if err != nil {
+		response.WriteError(w, r, err, "get_watchlists_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// @Summary Create Watchlist
+// @Security ApiKeyAuth
+// @Tags watchlists
+// @Description Create own watchlist. Names are unique per user, case-insensitive
+// @ID create-watchlist
+// @Accept  json
+// @Produce  json
+// @Param watchlist body model.Watchlist true "Name, description and visibility"
+// @Success 201 {object} model.Watchlist
+// @Failure 400,401,403,409 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/me/watchlists [post]
+func (h *WatchlistHandler) CreateWatchlist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var list model.Watchlist
+	if err := json.NewDecoder(r.Body).Decode(&list); err != nil {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+	list.UserID = userID
+
+	if err := list.Validate(); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
+		return
+	}
+
+	created, err := h.service.CreateWatchlist(r.Context(), list)
+	if err != nil {
+		response.WriteError(w, r, err, "create_watchlist_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// @Summary Update Watchlist
+// @Security ApiKeyAuth
+// @Tags watchlists
+// @Description Rename own watchlist, change its description or visibility
+// @ID update-watchlist
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Watchlist ID"
+// @Param watchlist body model.Watchlist true "Name, description and visibility"
+// @Success 200 {object} model.Watchlist
+// @Failure 400,401,403,404,409 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/me/watchlists/{id} [put]
+func (h *WatchlistHandler) UpdateWatchlist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_watchlist_id", http.StatusBadRequest)
+		return
+	}
+
+	var list model.Watchlist
+	if err := json.NewDecoder(r.Body).Decode(&list); err != nil {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+	list.Id = id
+	list.UserID = userID
+
+	if err := list.Validate(); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
+		return
+	}
+
+	updated, err := h.service.UpdateWatchlist(r.Context(), list)
+	if err != nil {
+		response.WriteError(w, r, err, "update_watchlist_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// @Summary Delete Watchlist
+// @Security ApiKeyAuth
+// @Tags watchlists
+// @Description Delete own watchlist
+// @ID delete-watchlist
+// @Produce  json
+// @Param id path int true "Watchlist ID"
+// @Success 200 {object} map[string]string
+// @Failure 400,401,403,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/me/watchlists/{id} [delete]
+func (h *WatchlistHandler) DeleteWatchlist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_watchlist_id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteWatchlist(r.Context(), id, userID); err != nil {
+		response.WriteError(w, r, err, "delete_watchlist_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "watchlist deleted successfully"})
+}
+
+// @Summary Reorder Watchlist
+// @Security ApiKeyAuth
+// @Tags watchlists
+// @Description Replace films of own watchlist with the given ones in the given order
+// @ID replace-watchlist-films
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Watchlist ID"
+// @Param order body model.WatchlistOrder true "Film IDs in list order"
+// @Success 200 {object} model.WatchlistWithFilms
+// @Failure 400,401,403,404 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/me/watchlists/{id}/films [put]
+func (h *WatchlistHandler) ReplaceWatchlistFilms(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_watchlist_id", http.StatusBadRequest)
+		return
+	}
+
+	var order model.WatchlistOrder
+	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+
+	if err := order.Validate(); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
+		return
+	}
+
+	if err := h.service.ReplaceWatchlistFilms(r.Context(), userID, id, order); err != nil {
+		response.WriteError(w, r, err, "update_watchlist_failed")
+		return
+	}
+
+	h.GetWatchlist(w, r)
+}
+
+// @Summary Add Film To Watchlist
+// @Security ApiKeyAuth
+// @Tags watchlists
+// @Description Add film to own watchlist or move it. Without position a new film goes last and an added one stays in place
+// @ID add-watchlist-film
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Watchlist ID"
+// @Param filmId path int true "Film ID"
+// @Param entry body model.WatchlistEntry false "Position in the list, from 1"
+// @Success 200 {object} model.WatchlistWithFilms
+// @Failure 400,401,403,404 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/me/watchlists/{id}/films/{filmId} [post]
+func (h *WatchlistHandler) AddWatchlistFilm(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_watchlist_id", http.StatusBadRequest)
+		return
+	}
+
+	filmID, err := pathID(r, "filmId")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	// Тело необязательно: без позиции фильм встаёт в конец списка
+	var entry model.WatchlistEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil && !errors.Is(err, io.EOF) {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+
+	if err := entry.Validate(); err != nil {
+		response.WriteError(w, r, err, "invalid_request_body")
+		return
+	}
+
+	if err := h.service.AddWatchlistFilm(r.Context(), userID, id, filmID, entry); err != nil {
+		response.WriteError(w, r, err, "update_watchlist_failed")
+		return
+	}
+
+	h.GetWatchlist(w, r)
+}
+
+// @Summary Remove Film From Watchlist
+// @Security ApiKeyAuth
+// @Tags watchlists
+// @Description Remove film from own watchlist; films after it move up
+// @ID remove-watchlist-film
+// @Produce  json
+// @Param id path int true "Watchlist ID"
+// @Param filmId path int true "Film ID"
+// @Success 200 {object} model.WatchlistWithFilms
+// @Failure 400,401,403,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/me/watchlists/{id}/films/{filmId} [delete]
+func (h *WatchlistHandler) RemoveWatchlistFilm(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := pathID(r, "id")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_watchlist_id", http.StatusBadRequest)
+		return
+	}
+
+	filmID, err := pathID(r, "filmId")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.RemoveWatchlistFilm(r.Context(), userID, id, filmID); err != nil {
+		response.WriteError(w, r, err, "update_watchlist_failed")
+		return
+	}
+
+	h.GetWatchlist(w, r)
+}
+
+// @Summary Watched Films
+// @Security ApiKeyAuth
+// @Tags watchlists
+// @Description Films the user has watched, latest first
+// @ID get-watched
+// @Produce  json
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} model.WatchedPage
+// @Failure 400,401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/me/watched [get]
+func (h *WatchlistHandler) GetWatched(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	params := model.WatchedListParams{UserID: userID}
+
+	var err error
+	if params.Limit, params.Offset, err = queryPage(r.URL.Query()); err != nil {
+		response.WriteError(w, r, err, "get_watched_failed")
+		return
+	}
+
+	if err := params.Validate(); err != nil {
+		response.WriteError(w, r, err, "get_watched_failed")
+		return
+	}
+
+	page, err := h.service.GetWatched(r.Context(), params)
+	if err != nil {
+		response.WriteError(w, r, err, "get_watched_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+// @Summary Mark Film Watched
+// @Security ApiKeyAuth
+// @Tags watchlists
+// @Description Mark film as watched on the given day (today by default). Marking again changes the date
+// @ID set-watched
+// @Accept  json
+// @Produce  json
+// @Param filmId path int true "Film ID"
+// @Param watched body model.WatchedRequest false "Date of watching"
+// @Success 200 {object} model.WatchedMark
+// @Failure 400,401,403,404 {object} model.ErrorResponse
+// @Failure 422 {object} model.Problem
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/me/watched/{filmId} [put]
+func (h *WatchlistHandler) SetWatched(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	filmID, err := pathID(r, "filmId")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	// Тело необязательно: без даты фильм просмотрен сегодня
+	var req model.WatchedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.WriteJSONError(w, r, "invalid_request_body", http.StatusBadRequest)
+		return
+	}
+
+	mark, err := h.service.SetWatched(r.Context(), userID, filmID, req)
+	if err != nil {
+		response.WriteError(w, r, err, "set_watched_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, mark)
+}
+
+// @Summary Unmark Film Watched
+// @Security ApiKeyAuth
+// @Tags watchlists
+// @Description Remove the watched mark from the film
+// @ID delete-watched
+// @Produce  json
+// @Param filmId path int true "Film ID"
+// @Success 200 {object} map[string]string
+// @Failure 400,401,403,404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/me/watched/{filmId} [delete]
+func (h *WatchlistHandler) DeleteWatched(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	filmID, err := pathID(r, "filmId")
+	if err != nil {
+		response.WriteJSONError(w, r, "invalid_film_id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteWatched(r.Context(), userID, filmID); err != nil {
+		response.WriteError(w, r, err, "delete_watched_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "watched mark removed successfully"})
+}
+
+// @Summary My Feed
+// @Security ApiKeyAuth
+// @Tags watchlists
+// @Description Not yet watched films featuring actors from films the user has watched, newest releases first.
+// @Description By default only films released during the last year
+// @ID get-feed
+// @Produce  json
+// @Param released_from query string false "Release date from (YYYY-MM-DD), default one year ago"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} model.FeedPage
+// @Failure 400,401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Failure default {object} model.ErrorResponse
+// @Router /api/v1/me/feed [get]
+func (h *WatchlistHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	params := model.FeedParams{UserID: userID}
+
+	from, err := queryDate(q, "released_from")
+	if err != nil {
+		response.WriteError(w, r, err, "get_feed_failed")
+		return
+	}
+	if from != nil {
+		params.ReleasedFrom = *from
+	}
+
+	if params.Limit, params.Offset, err = queryPage(q); err != nil {
+		response.WriteError(w, r, err, "get_feed_failed")
+		return
+	}
+
+	if err := params.Validate(); err != nil {
+		response.WriteError(w, r, err, "get_feed_failed")
+		return
+	}
+
+	page, err := h.service.GetFeed(r.Context(), params)
+	if err != nil {
+		response.WriteError(w, r, err, "get_feed_failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
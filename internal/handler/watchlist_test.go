@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	mock_service "film-library/internal/service/mocks"
+	authmid "film-library/internal/utils/auth_mid"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_CreateWatchlist(t *testing.T) {
+	created := time.Date(2025, 8, 11, 9, 0, 0, 0, time.UTC)
+
+	type mockBehavior func(r *mock_service.MockWatchlist)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"name": " Weekend ", "public": true}`,
+			mockBehavior: func(r *mock_service.MockWatchlist) {
+				r.EXPECT().CreateWatchlist(gomock.Any(), model.Watchlist{UserID: 5, Name: "Weekend", Public: true}).
+					Return(model.Watchlist{Id: 2, UserID: 5, Name: "Weekend", Public: true, CreatedAt: created, UpdatedAt: created}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponseBody: `{"id": 2, "user_id": 5, "name": "Weekend", "description": "", "public": true, "film_count": 0,
+				"created_at": "2025-08-11T09:00:00Z", "updated_at": "2025-08-11T09:00:00Z"}`,
+		},
+		{
+			name:                 "Empty Name",
+			inputBody:            `{"name": "  "}`,
+			mockBehavior:         func(r *mock_service.MockWatchlist) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type": "/problems/validation-failed", "title": "Request validation failed", "status": 422, "detail": "Fix the fields listed in errors", "instance": "/api/v1/me/watchlists", "code": "validation_failed", "errors": [{"field": "name", "rule": "required", "message": "is required"}]}`,
+		},
+		{
+			name:      "Name Taken",
+			inputBody: `{"name": "Weekend"}`,
+			mockBehavior: func(r *mock_service.MockWatchlist) {
+				r.EXPECT().CreateWatchlist(gomock.Any(), model.Watchlist{UserID: 5, Name: "Weekend"}).
+					Return(model.Watchlist{}, domain.Wrap(domain.ErrConflict, domain.ErrConflict, "watchlist_exists", "список с таким именем уже есть"))
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"status": 409, "message": "You already have a watchlist with this name", "code": "watchlist_exists"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			watchlist := mock_service.NewMockWatchlist(c)
+			test.mockBehavior(watchlist)
+
+			handler := NewWatchlistHandler(watchlist)
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /api/v1/me/watchlists", handler.CreateWatchlist)
+
+			ctx := authmid.WithUser(context.Background(), 5, int(model.RoleUser))
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/me/watchlists", bytes.NewBufferString(test.inputBody)).WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			require.Equal(t, test.expectedStatusCode, rr.Code)
+			require.JSONEq(t, test.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_AddWatchlistFilm(t *testing.T) {
+	created := time.Date(2025, 8, 11, 9, 0, 0, 0, time.UTC)
+	released := time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	watchlist := mock_service.NewMockWatchlist(c)
+	gomock.InOrder(
+		watchlist.EXPECT().AddWatchlistFilm(gomock.Any(), 5, 2, 1, model.WatchlistEntry{Position: 1}).Return(nil),
+		watchlist.EXPECT().GetWatchlist(gomock.Any(), 2, 5).Return(model.WatchlistWithFilms{
+			Watchlist: model.Watchlist{Id: 2, UserID: 5, Name: "Weekend", FilmCount: 1, CreatedAt: created, UpdatedAt: created},
+			Films:     []model.WatchlistFilm{{Id: 1, Name: "The Matrix", Releasedate: released, Rating: 8.7, Position: 1, AddedAt: created}},
+		}, nil),
+	)
+	watchlist.EXPECT().AddWatchlistFilm(gomock.Any(), 5, 2, 404, model.WatchlistEntry{}).
+		Return(domain.Validation("unknown_films", "в списке указаны несуществующие фильмы: [404]").WithArgs([]int64{404}))
+
+	handler := NewWatchlistHandler(watchlist)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/me/watchlists/{id}/films/{filmId}", handler.AddWatchlistFilm)
+
+	ctx := authmid.WithUser(context.Background(), 5, int(model.RoleUser))
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/me/watchlists/2/films/1", bytes.NewBufferString(`{"position": 1}`)).WithContext(ctx))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"id": 2, "user_id": 5, "name": "Weekend", "description": "", "public": false, "film_count": 1,
+		"created_at": "2025-08-11T09:00:00Z", "updated_at": "2025-08-11T09:00:00Z",
+		"films": [{"id": 1, "name": "The Matrix", "release_date": "1999-03-31T00:00:00Z", "rating": 8.7, "position": 1, "added_at": "2025-08-11T09:00:00Z"}]}`, rr.Body.String())
+
+	// Тело необязательно
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/me/watchlists/2/films/404", nil).WithContext(ctx))
+	require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	require.JSONEq(t, `{"status": 422, "message": "Watchlist references unknown films: [404]", "code": "unknown_films"}`, rr.Body.String())
+}
+
+func TestHandler_SetWatched(t *testing.T) {
+	watchedOn := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	watchlist := mock_service.NewMockWatchlist(c)
+	watchlist.EXPECT().SetWatched(gomock.Any(), 5, 1, model.WatchedRequest{WatchedOn: &watchedOn}).
+		Return(model.WatchedMark{FilmID: 1, WatchedOn: watchedOn}, nil)
+	watchlist.EXPECT().SetWatched(gomock.Any(), 5, 404, model.WatchedRequest{}).
+		Return(model.WatchedMark{}, domain.Wrap(domain.ErrNotFound, domain.ErrNotFound, "film_not_found", "фильм не найден"))
+
+	handler := NewWatchlistHandler(watchlist)
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /api/v1/me/watched/{filmId}", handler.SetWatched)
+
+	ctx := authmid.WithUser(context.Background(), 5, int(model.RoleUser))
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/api/v1/me/watched/1", bytes.NewBufferString(`{"watched_on": "2025-08-01T00:00:00Z"}`)).WithContext(ctx))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"film_id": 1, "watched_on": "2025-08-01T00:00:00Z"}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/api/v1/me/watched/404", nil).WithContext(ctx))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+	require.JSONEq(t, `{"status": 404, "message": "Film not found", "code": "film_not_found"}`, rr.Body.String())
+}
+
+func TestHandler_GetFeed(t *testing.T) {
+	released := time.Date(2025, 5, 16, 0, 0, 0, 0, time.UTC)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	watchlist := mock_service.NewMockWatchlist(c)
+	watchlist.EXPECT().GetFeed(gomock.Any(), model.FeedParams{UserID: 5, ReleasedFrom: from, Limit: 10}).Return(model.FeedPage{
+		Items: []model.FeedItem{{Id: 9, Name: "Ballerina", Releasedate: released, Rating: 6.9,
+			Actors: []model.FeedActor{{Id: 7, Name: "Keanu Reeves"}}}},
+		Total: 1,
+		Limit: 10,
+	}, nil)
+
+	handler := NewWatchlistHandler(watchlist)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/me/feed", handler.GetFeed)
+
+	ctx := authmid.WithUser(context.Background(), 5, int(model.RoleUser))
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/me/feed?released_from=2025-01-01&limit=10", nil).WithContext(ctx))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"items": [{"id": 9, "name": "Ballerina", "release_date": "2025-05-16T00:00:00Z", "rating": 6.9,
+		"actors": [{"id": 7, "name": "Keanu Reeves"}]}], "total": 1, "limit": 10, "offset": 0, "next_cursor": "", "prev_cursor": ""}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/me/feed?released_from=yesterday", nil).WithContext(ctx))
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.JSONEq(t, `{"status": 400, "message": "Parameter released_from must be a date in YYYY-MM-DD format", "code": "invalid_date_param"}`, rr.Body.String())
+}
@@ -0,0 +1,142 @@
+// Package i18n - каталог сообщений API на русском и английском.
+// Ключ сообщения совпадает с машиночитаемым кодом ошибки ("film_not_found"),
+// тексты лежат в locales/*.json и подставляют аргументы через fmt.
+// Язык запроса выбирает middleware.Localize по заголовку Accept-Language.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	RU = "ru"
+	EN = "en"
+
+	// Default - язык, если в контексте он не задан (фоновые задачи, тесты)
+	Default = EN
+)
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// catalog: язык -> ключ -> шаблон сообщения
+var catalog = mustLoad()
+
+func mustLoad() map[string]map[string]string {
+	files, err := locales.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	c := make(map[string]map[string]string, len(files))
+	for _, f := range files {
+		raw, err := locales.ReadFile("locales/" + f.Name())
+		if err != nil {
+			panic(err)
+		}
+
+		var messages map[string]string
+		if err := json.Unmarshal(raw, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: %s: %v", f.Name(), err))
+		}
+		c[strings.TrimSuffix(f.Name(), path.Ext(f.Name()))] = messages
+	}
+
+	return c
+}
+
+type localeKey struct{}
+
+// WithLocale кладёт язык ответа в контекст запроса
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// Locale - язык ответа из контекста или Default
+func Locale(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok {
+		return locale
+	}
+	return Default
+}
+
+// Supported - есть ли каталог для языка
+func Supported(locale string) bool {
+	_, ok := catalog[locale]
+	return ok
+}
+
+// T переводит сообщение на язык из контекста
+func T(ctx context.Context, key string, args ...any) string {
+	msg, _ := Lookup(Locale(ctx), key, args...)
+	return msg
+}
+
+// Lookup ищет сообщение на нужном языке, затем на Default.
+// Если ключа нет ни там, ни там, возвращает сам ключ и false
+func Lookup(locale, key string, args ...any) (string, bool) {
+	tmpl, ok := catalog[locale][key]
+	if !ok {
+		tmpl, ok = catalog[Default][key]
+	}
+	if !ok {
+		return key, false
+	}
+
+	if len(args) == 0 {
+		return tmpl, true
+	}
+	return fmt.Sprintf(tmpl, args...), true
+}
+
+// Negotiate выбирает язык по заголовку Accept-Language с учётом весов q.
+// "en-US" подходит под "en"; если ничего не подошло, возвращает fallback
+func Negotiate(header, fallback string) string {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		candidates = append(candidates, candidate{tag: strings.ToLower(tag), q: q})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, c := range candidates {
+		if c.tag == "*" {
+			return fallback
+		}
+		base, _, _ := strings.Cut(c.tag, "-")
+		if Supported(base) {
+			return base
+		}
+	}
+
+	return fallback
+}
@@ -0,0 +1,60 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{name: "Empty", header: "", expected: EN},
+		{name: "Exact", header: "ru", expected: RU},
+		{name: "Region", header: "ru-RU", expected: RU},
+		{name: "Weights", header: "en;q=0.5, ru;q=0.9", expected: RU},
+		{name: "Unsupported First", header: "de-DE, en;q=0.8", expected: EN},
+		{name: "Wildcard", header: "de, *;q=0.5", expected: EN},
+		{name: "Zero Weight", header: "ru;q=0, de", expected: EN},
+		{name: "Bad Weight", header: "ru;q=abc", expected: EN},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, Negotiate(tc.header, EN))
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	msg, ok := Lookup(RU, "unknown_role", "owner")
+	require.True(t, ok)
+	require.Equal(t, `Неизвестная роль "owner"`, msg)
+
+	msg, ok = Lookup("de", "film_not_found")
+	require.True(t, ok)
+	require.Equal(t, "Film not found", msg)
+
+	msg, ok = Lookup(RU, "no_such_key")
+	require.False(t, ok)
+	require.Equal(t, "no_such_key", msg)
+
+	require.Equal(t, "Фильм не найден", T(WithLocale(context.Background(), RU), "film_not_found"))
+	require.Equal(t, "Film not found", T(context.Background(), "film_not_found"))
+}
+
+// Каталоги должны переводить одни и те же ключи
+func TestCatalogsHaveSameKeys(t *testing.T) {
+	for locale, messages := range catalog {
+		for key := range catalog[Default] {
+			require.Contains(t, messages, key, "locale %s", locale)
+		}
+		for key := range messages {
+			require.Contains(t, catalog[Default], key, "locale %s", locale)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package job
+
+import (
+	"context"
+	"film-library/internal/utils/sl"
+	"log/slog"
+	"time"
+)
+
+// Every запускает fn сразу и затем с периодом interval, пока не отменён ctx.
+// Ошибка запуска только логируется: следующий тик попробует снова.
+// Интервал не больше нуля (например, 0s в конфиге) отключает задачу
+func Every(ctx context.Context, log *slog.Logger, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	log = log.With(slog.String("job", name))
+
+	if interval <= 0 {
+		log.Warn("job disabled: interval must be positive", slog.Duration("interval", interval))
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for ctx.Err() == nil {
+		run(ctx, log, fn)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Triggered запускает fn сразу, затем через delay после сигнала из trigger и
+// не реже чем раз в interval. Сигналы, пришедшие в течение delay, дают один запуск,
+// поэтому серия изменений не вызывает серию пересчётов. Интервал не больше нуля
+// отключает периодический запуск, остаются только запуски по сигналам
+func Triggered(ctx context.Context, log *slog.Logger, name string, trigger <-chan struct{}, delay, interval time.Duration, fn func(ctx context.Context) error) {
+	log = log.With(slog.String("job", name))
+
+	var ticker *time.Ticker
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker = time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	} else {
+		log.Warn("periodic run disabled: interval must be positive", slog.Duration("interval", interval))
+	}
+
+	var pending <-chan time.Time
+
+	for ctx.Err() == nil {
+		run(ctx, log, fn)
+		if ticker != nil {
+			ticker.Reset(interval)
+		}
+		pending = nil
+
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-trigger:
+				if pending == nil {
+					pending = time.After(delay)
+				}
+			case <-pending:
+				break wait
+			case <-tick:
+				break wait
+			}
+		}
+	}
+}
+
+func run(ctx context.Context, log *slog.Logger, fn func(ctx context.Context) error) {
+	start := time.Now()
+	if err := fn(ctx); err != nil && ctx.Err() == nil {
+		log.Error("job failed", sl.Err(err))
+	} else if err == nil {
+		log.Debug("job finished", slog.Duration("duration", time.Since(start)))
+	}
+}
@@ -0,0 +1,147 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvery(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var runs atomic.Int32
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Every(ctx, log, "test", time.Millisecond, func(ctx context.Context) error {
+			// Ошибка не останавливает задачу
+			if runs.Add(1) == 3 {
+				cancel()
+			}
+			return errors.New("boom")
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job did not stop after cancel")
+	}
+	require.Equal(t, int32(3), runs.Load())
+}
+
+func TestEveryDisabled(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		var runs atomic.Int32
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			Every(context.Background(), log, "test", interval, func(ctx context.Context) error {
+				runs.Add(1)
+				return nil
+			})
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("job with interval %s did not return", interval)
+		}
+		require.Zero(t, runs.Load())
+	}
+}
+
+func TestTriggered(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	trigger := make(chan struct{})
+	runs := make(chan struct{}, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Triggered(ctx, log, "test", trigger, 20*time.Millisecond, time.Hour, func(ctx context.Context) error {
+			runs <- struct{}{}
+			return nil
+		})
+	}()
+
+	waitRun := func() {
+		t.Helper()
+		select {
+		case <-runs:
+		case <-time.After(time.Second):
+			t.Fatal("job did not run")
+		}
+	}
+
+	// Первый запуск - сразу
+	waitRun()
+
+	// Серия сигналов в пределах задержки даёт один запуск
+	for range 3 {
+		trigger <- struct{}{}
+	}
+	waitRun()
+
+	select {
+	case <-runs:
+		t.Fatal("signals were not coalesced")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job did not stop after cancel")
+	}
+}
+
+func TestTriggeredWithoutInterval(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	trigger := make(chan struct{})
+	runs := make(chan struct{}, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Triggered(ctx, log, "test", trigger, time.Millisecond, 0, func(ctx context.Context) error {
+			runs <- struct{}{}
+			return nil
+		})
+	}()
+
+	waitRun := func() {
+		t.Helper()
+		select {
+		case <-runs:
+		case <-time.After(time.Second):
+			t.Fatal("job did not run")
+		}
+	}
+
+	// Без интервала задача запускается сразу и дальше только по сигналам
+	waitRun()
+	trigger <- struct{}{}
+	waitRun()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job did not stop after cancel")
+	}
+}
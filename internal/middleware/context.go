@@ -0,0 +1,20 @@
+package middleware
+
+import "context"
+
+type accessEntryKey struct{}
+
+// accessEntry - данные, которые внутренние middleware сообщают журналу запросов.
+// RequireAuth выполняется глубже RequestLogger, поэтому передаём указатель
+type accessEntry struct {
+	userID int
+}
+
+func withAccessEntry(ctx context.Context, entry *accessEntry) context.Context {
+	return context.WithValue(ctx, accessEntryKey{}, entry)
+}
+
+func accessEntryFrom(ctx context.Context) *accessEntry {
+	entry, _ := ctx.Value(accessEntryKey{}).(*accessEntry)
+	return entry
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// InFlight считает обрабатываемые запросы. После Drain сервер продолжает
+// отвечать, но просит клиентов закрыть соединение, чтобы новые запросы
+// уходили на другой экземпляр
+type InFlight struct {
+	active   atomic.Int64
+	draining atomic.Bool
+}
+
+func NewInFlight() *InFlight {
+	return &InFlight{}
+}
+
+func (t *InFlight) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.active.Add(1)
+		defer t.active.Add(-1)
+
+		if t.draining.Load() {
+			w.Header().Set("Connection", "close")
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Drain переводит трекер в режим остановки
+func (t *InFlight) Drain() {
+	t.draining.Store(true)
+}
+
+// Active - количество запросов, которые обрабатываются прямо сейчас
+func (t *InFlight) Active() int64 {
+	return t.active.Load()
+}
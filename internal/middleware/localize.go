@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"film-library/internal/i18n"
+	"net/http"
+)
+
+// Localize выбирает язык ответа по заголовку Accept-Language и кладёт его
+// в контекст. Если клиент не прислал подходящего языка, используется fallback
+func Localize(fallback string) func(http.Handler) http.Handler {
+	if !i18n.Supported(fallback) {
+		fallback = i18n.Default
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			locale := i18n.Negotiate(r.Header.Get("Accept-Language"), fallback)
+
+			w.Header().Set("Content-Language", locale)
+			w.Header().Add("Vary", "Accept-Language")
+
+			next.ServeHTTP(w, r.WithContext(i18n.WithLocale(r.Context(), locale)))
+		})
+	}
+}
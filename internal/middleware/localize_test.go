@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"film-library/internal/i18n"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalize(t *testing.T) {
+	tests := []struct {
+		name           string
+		fallback       string
+		acceptLanguage string
+		expectedLocale string
+	}{
+		{
+			name:           "From Header",
+			fallback:       i18n.EN,
+			acceptLanguage: "ru-RU,ru;q=0.9,en;q=0.8",
+			expectedLocale: i18n.RU,
+		},
+		{
+			name:           "Fallback",
+			fallback:       i18n.RU,
+			acceptLanguage: "de",
+			expectedLocale: i18n.RU,
+		},
+		{
+			name:           "Unsupported Fallback",
+			fallback:       "de",
+			expectedLocale: i18n.Default,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotLocale string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotLocale = i18n.Locale(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/films", nil)
+			req.Header.Set("Accept-Language", tc.acceptLanguage)
+
+			rr := httptest.NewRecorder()
+			Localize(tc.fallback)(next).ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedLocale, gotLocale)
+			require.Equal(t, tc.expectedLocale, rr.Header().Get("Content-Language"))
+			require.Equal(t, "Accept-Language", rr.Header().Get("Vary"))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"film-library/internal/utils/sl"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// RequestIDHeader - заголовок с идентификатором запроса, принимается от клиента и возвращается в ответе
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDPattern - входящий идентификатор принимаем только в безопасном для логов виде
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestLogger кладёт в контекст логгер с request_id, методом и путём
+// и по завершении запроса пишет статус, длительность, размер ответа и user_id
+func RequestLogger(log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestID := r.Header.Get(RequestIDHeader)
+			if !requestIDPattern.MatchString(requestID) {
+				requestID = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, requestID)
+
+			reqLog := log.With(
+				slog.String("request_id", requestID),
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+			)
+
+			entry := &accessEntry{}
+			ctx := sl.WithLogger(r.Context(), reqLog)
+			ctx = withAccessEntry(ctx, entry)
+
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(sw, r.WithContext(ctx))
+
+			attrs := []any{
+				slog.Int("status", sw.status),
+				slog.Duration("latency", time.Since(start)),
+				slog.Int64("bytes", sw.bytes),
+			}
+			if entry.userID != 0 {
+				attrs = append(attrs, slog.Int("user_id", entry.userID))
+			}
+
+			level := slog.LevelInfo
+			if sw.status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			reqLog.Log(r.Context(), level, "request completed", attrs...)
+		})
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// statusWriter запоминает код ответа и количество записанных байт
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		f.Flush()
+	}
+}
+
+// Unwrap нужен http.ResponseController, чтобы добраться до Flush и дедлайнов исходного writer
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
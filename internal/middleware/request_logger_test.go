@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"film-library/internal/utils/sl"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLogger(t *testing.T) {
+	tests := []struct {
+		name              string
+		requestID         string
+		expectedRequestID string
+	}{
+		{
+			name:              "Client Request ID",
+			requestID:         "abc-123",
+			expectedRequestID: "abc-123",
+		},
+		{
+			name:      "Generated Request ID",
+			requestID: "bad id\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := slog.New(slog.NewJSONHandler(&buf, nil))
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Имитируем RequireAuth, который сообщает пользователя журналу
+				accessEntryFrom(r.Context()).userID = 7
+				sl.FromContext(r.Context()).Info("inside handler")
+
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte("hello"))
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/films", nil)
+			req.Header.Set(RequestIDHeader, tc.requestID)
+
+			rr := httptest.NewRecorder()
+			RequestLogger(log)(next).ServeHTTP(rr, req)
+
+			requestID := rr.Header().Get(RequestIDHeader)
+			if tc.expectedRequestID != "" {
+				require.Equal(t, tc.expectedRequestID, requestID)
+			} else {
+				require.Len(t, requestID, 32)
+			}
+
+			lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+			require.Len(t, lines, 2)
+
+			var inner, access map[string]any
+			require.NoError(t, json.Unmarshal(lines[0], &inner))
+			require.NoError(t, json.Unmarshal(lines[1], &access))
+
+			require.Equal(t, requestID, inner["request_id"])
+			require.Equal(t, requestID, access["request_id"])
+			require.Equal(t, "POST", access["method"])
+			require.Equal(t, "/api/v1/films", access["path"])
+			require.Equal(t, float64(http.StatusCreated), access["status"])
+			require.Equal(t, float64(5), access["bytes"])
+			require.Equal(t, float64(7), access["user_id"])
+		})
+	}
+}
@@ -2,20 +2,28 @@ package middleware
 
 import (
 	"context"
+	authmid "film-library/internal/utils/auth_mid"
 	"film-library/internal/utils/response"
+	"film-library/internal/utils/sl"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func RequireAuth(secretKey []byte) func(http.HandlerFunc) http.HandlerFunc {
+// RevocationChecker - хранилище отозванных access-токенов
+type RevocationChecker interface {
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+func RequireAuth(secretKey []byte, revocations RevocationChecker) func(http.HandlerFunc) http.HandlerFunc {
 	return func(next http.HandlerFunc) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-				response.WriteJSONError(w, "Unauthorized", http.StatusUnauthorized)
+				response.WriteJSONError(w, r, "unauthorized", http.StatusUnauthorized)
 				return
 			}
 
@@ -29,22 +37,43 @@ func RequireAuth(secretKey []byte) func(http.HandlerFunc) http.HandlerFunc {
 			})
 
 			if err != nil || !token.Valid {
-				response.WriteJSONError(w, "Invalid token", http.StatusUnauthorized)
+				response.WriteJSONError(w, r, "invalid_token", http.StatusUnauthorized)
 				return
 			}
 
 			claims, ok := token.Claims.(jwt.MapClaims)
 			if !ok {
-				response.WriteJSONError(w, "Invalid claims", http.StatusUnauthorized)
+				response.WriteJSONError(w, r, "invalid_claims", http.StatusUnauthorized)
+				return
+			}
+
+			userID, okID := claims["user_id"].(float64)
+			role, okRole := claims["role"].(float64)
+			jti, okJTI := claims["jti"].(string)
+			exp, errExp := claims.GetExpirationTime()
+			if !okID || !okRole || !okJTI || jti == "" || errExp != nil || exp == nil {
+				response.WriteJSONError(w, r, "invalid_claims", http.StatusUnauthorized)
 				return
 			}
 
-			userID := int(claims["user_id"].(float64))
-			role := int(claims["role"].(float64)) // добавим роль в claims, если ещё не добавлена
+			// токен мог быть отозван до истечения срока (выход, кража refresh-токена)
+			revoked, err := revocations.IsTokenRevoked(r.Context(), jti)
+			if err != nil {
+				response.WriteError(w, r, err, "check_token_failed")
+				return
+			}
+			if revoked {
+				response.WriteJSONError(w, r, "token_revoked", http.StatusUnauthorized)
+				return
+			}
 
 			// кладём в контекст
-			ctx := context.WithValue(r.Context(), "user_id", userID)
-			ctx = context.WithValue(ctx, "role", role)
+			ctx := authmid.WithUser(r.Context(), int(userID), int(role))
+			ctx = authmid.WithToken(ctx, jti, exp.Time)
+			ctx = sl.WithLogger(ctx, sl.FromContext(ctx).With(slog.Int("user_id", int(userID))))
+			if entry := accessEntryFrom(ctx); entry != nil {
+				entry.userID = int(userID)
+			}
 
 			next(w, r.WithContext(ctx))
 		}
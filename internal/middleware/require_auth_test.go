@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"errors"
+	mock_service "film-library/internal/service/mocks"
+	authmid "film-library/internal/utils/auth_mid"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("secret")
+
+func signToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testSecret)
+	require.NoError(t, err)
+	return token
+}
+
+func TestRequireAuth(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockAuthorization)
+
+	exp := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name                 string
+		claims               jwt.MapClaims
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Ok",
+			claims: jwt.MapClaims{"user_id": 1, "role": 1, "jti": "jti", "exp": exp},
+			mockBehavior: func(r *mock_service.MockAuthorization) {
+				r.EXPECT().IsTokenRevoked(gomock.Any(), "jti").Return(false, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message": "ok"}`,
+		},
+		{
+			name:   "Revoked",
+			claims: jwt.MapClaims{"user_id": 1, "role": 1, "jti": "jti", "exp": exp},
+			mockBehavior: func(r *mock_service.MockAuthorization) {
+				r.EXPECT().IsTokenRevoked(gomock.Any(), "jti").Return(true, nil)
+			},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"status": 401, "message": "Token revoked", "code": "token_revoked"}`,
+		},
+		{
+			name:                 "Without JTI",
+			claims:               jwt.MapClaims{"user_id": 1, "role": 1, "exp": exp},
+			mockBehavior:         func(r *mock_service.MockAuthorization) {},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"status": 401, "message": "Invalid claims", "code": "invalid_claims"}`,
+		},
+		{
+			name:                 "Expired",
+			claims:               jwt.MapClaims{"user_id": 1, "role": 1, "jti": "jti", "exp": time.Now().Add(-time.Hour).Unix()},
+			mockBehavior:         func(r *mock_service.MockAuthorization) {},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"status": 401, "message": "Invalid token", "code": "invalid_token"}`,
+		},
+		{
+			name:   "Checker Error",
+			claims: jwt.MapClaims{"user_id": 1, "role": 1, "jti": "jti", "exp": exp},
+			mockBehavior: func(r *mock_service.MockAuthorization) {
+				r.EXPECT().IsTokenRevoked(gomock.Any(), "jti").Return(false, errors.New("error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"status": 500, "message": "Failed to check token", "code": "internal_error"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			tc.mockBehavior(auth)
+
+			next := func(w http.ResponseWriter, r *http.Request) {
+				userID, _ := authmid.UserID(r.Context())
+				jti, _, _ := authmid.Token(r.Context())
+				require.Equal(t, 1, userID)
+				require.Equal(t, "jti", jti)
+				w.Write([]byte(`{"message": "ok"}`))
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/films", nil)
+			req.Header.Set("Authorization", "Bearer "+signToken(t, tc.claims))
+
+			rr := httptest.NewRecorder()
+			RequireAuth(testSecret, auth)(next)(rr, req)
+
+			require.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
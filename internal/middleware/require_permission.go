@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"context"
+	"film-library/internal/model"
+	authmid "film-library/internal/utils/auth_mid"
+	"film-library/internal/utils/response"
+	"net/http"
+)
+
+// PermissionChecker - источник матрицы прав ролей
+type PermissionChecker interface {
+	HasPermission(ctx context.Context, role int, required model.Permission) (bool, error)
+}
+
+// RequirePermission пропускает запрос, только если роль пользователя
+// разрешает все действия над ресурсом. Ставится после RequireAuth
+func RequirePermission(checker PermissionChecker, required model.Permission) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			role, ok := authmid.Role(r.Context())
+			if !ok {
+				response.WriteJSONError(w, r, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			allowed, err := checker.HasPermission(r.Context(), role, required)
+			if err != nil {
+				response.WriteError(w, r, err, "check_permissions_failed")
+				return
+			}
+
+			if !allowed {
+				response.WriteJSONError(w, r, "forbidden", http.StatusForbidden)
+				return
+			}
+
+			next(w, r)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"film-library/internal/model"
+	mock_service "film-library/internal/service/mocks"
+	authmid "film-library/internal/utils/auth_mid"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequirePermission(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockAccess, role int, required model.Permission)
+
+	required := model.Permission{Resource: model.ResourceFilms, Actions: []string{model.ActionCreate}}
+
+	tests := []struct {
+		name                 string
+		ctx                  context.Context
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Allowed",
+			ctx:  authmid.WithUser(context.Background(), 1, int(model.RoleEditor)),
+			mockBehavior: func(r *mock_service.MockAccess, role int, required model.Permission) {
+				r.EXPECT().HasPermission(gomock.Any(), role, required).Return(true, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"message": "ok"}`,
+		},
+		{
+			name: "Forbidden",
+			ctx:  authmid.WithUser(context.Background(), 1, int(model.RoleUser)),
+			mockBehavior: func(r *mock_service.MockAccess, role int, required model.Permission) {
+				r.EXPECT().HasPermission(gomock.Any(), role, required).Return(false, nil)
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: `{"status": 403, "message": "Forbidden", "code": "forbidden"}`,
+		},
+		{
+			name:                 "No Role In Context",
+			ctx:                  context.Background(),
+			mockBehavior:         func(r *mock_service.MockAccess, role int, required model.Permission) {},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"status": 401, "message": "Unauthorized", "code": "unauthorized"}`,
+		},
+		{
+			name: "Checker Error",
+			ctx:  authmid.WithUser(context.Background(), 1, int(model.RoleAdmin)),
+			mockBehavior: func(r *mock_service.MockAccess, role int, required model.Permission) {
+				r.EXPECT().HasPermission(gomock.Any(), role, required).Return(false, errors.New("error"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"status": 500, "message": "Failed to check permissions", "code": "internal_error"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			access := mock_service.NewMockAccess(c)
+			role, _ := authmid.Role(tc.ctx)
+			tc.mockBehavior(access, role, required)
+
+			next := func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"message": "ok"}`))
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/films", nil).WithContext(tc.ctx)
+
+			rr := httptest.NewRecorder()
+			RequirePermission(access, required)(next)(rr, req)
+
+			require.Equal(t, tc.expectedStatusCode, rr.Code)
+			require.JSONEq(t, tc.expectedResponseBody, rr.Body.String())
+		})
+	}
+}
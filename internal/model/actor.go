@@ -1,40 +1,33 @@
 package model
 
 import (
-	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/utils/validate"
+	"fmt"
 	"time"
 )
 
 // Actor - основная сущность актёра
 type Actor struct {
 	Id          int       `json:"id"`
-	Name        string    `json:"name"`
-	Gender      string    `json:"gender"`
-	DateOfBirth time.Time `json:"date_of_birth"`
+	Name        string    `json:"name" validate:"required,max=100"`
+	Gender      string    `json:"gender" validate:"required,oneof=male female"`
+	DateOfBirth time.Time `json:"date_of_birth" validate:"past,min_age=5"`
+	Version     int       `json:"-"` // версия строки, отдаётся в ETag
 }
 
 // Validate - проверка корректности данных актёра
 func (a *Actor) Validate() error {
-	if a.Gender == "" {
-		return errors.New("не указан пол")
-	}
-	if a.Name == "" || len(a.Name) > 100 {
-		return errors.New("имя актера не может быть пустым и не должно превышать 100 символов")
-	}
-
-	if a.Gender != "male" && a.Gender != "female" {
-		return errors.New("несуществующий пол")
-	}
-
-	if a.DateOfBirth.After(time.Now()) {
-		return errors.New("дата рождения не может быть в будущем")
-	}
+	return validate.Struct(a)
+}
 
-	if time.Now().Year()-a.DateOfBirth.Year() < 5 {
-		return errors.New("актёр должен быть старше 5 лет")
+// Columns - колонки таблицы people, которые меняет PATCH
+func (a *Actor) Columns() map[string]any {
+	return map[string]any{
+		"name":          a.Name,
+		"gender":        a.Gender,
+		"date_of_birth": a.DateOfBirth,
 	}
-
-	return nil
 }
 
 // func NewActor(id int, name string, gender string, dateOfBirth time.Time) (*Actor, error) {
@@ -51,3 +44,93 @@ func (a *Actor) Validate() error {
 
 // 	return actor, nil
 // }
+
+// maxActorAge - верхняя граница фильтра по возрасту
+const maxActorAge = 150
+
+// ActorListParams - параметры выборки списка актёров: фильтры, сортировка и пагинация
+type ActorListParams struct {
+	SortBy string // name (по умолчанию), date_of_birth, film_count
+	Order  string // asc или desc, по умолчанию зависит от поля
+
+	Gender     string
+	MinAge     *int
+	MaxAge     *int
+	NamePrefix string
+
+	IncludeFilms bool // include=films: подгрузить фильмографию
+
+	Limit  int
+	Offset int
+	Cursor *Cursor
+}
+
+// Sort - поле сортировки с учётом значения по умолчанию
+func (p *ActorListParams) Sort() string {
+	if p.SortBy == "" {
+		return "name"
+	}
+	return p.SortBy
+}
+
+// Direction - направление сортировки: число фильмов по умолчанию по убыванию, остальное по возрастанию
+func (p *ActorListParams) Direction() string {
+	if p.Order != "" {
+		return p.Order
+	}
+	if p.Sort() == "film_count" {
+		return "desc"
+	}
+	return "asc"
+}
+
+// SortKey - идентификатор сортировки, к которой привязан курсор
+func (p *ActorListParams) SortKey() string {
+	return p.Sort() + ":" + p.Direction()
+}
+
+func (p *ActorListParams) Validate() error {
+	if p.SortBy != "" && p.SortBy != "name" && p.SortBy != "date_of_birth" && p.SortBy != "film_count" {
+		return domain.BadRequest("invalid_sort", "некорректная сортировка")
+	}
+
+	if p.Order != "" && p.Order != "asc" && p.Order != "desc" {
+		return domain.BadRequest("invalid_order", "направление сортировки должно быть asc или desc")
+	}
+
+	if p.Limit < 1 || p.Limit > MaxPageLimit {
+		return limitError()
+	}
+
+	if p.Offset < 0 {
+		return domain.BadRequest("negative_offset", "offset не может быть отрицательным")
+	}
+
+	if p.Cursor != nil {
+		if p.Offset > 0 {
+			return domain.BadRequest("cursor_with_offset", "нельзя одновременно указывать cursor и offset")
+		}
+		if p.Cursor.Sort != p.SortKey() {
+			return domain.BadRequest("cursor_sort_mismatch", "курсор выдан для другой сортировки")
+		}
+	}
+
+	if p.Gender != "" && p.Gender != "male" && p.Gender != "female" {
+		return domain.BadRequest("invalid_gender", "пол должен быть male или female")
+	}
+
+	if p.MinAge != nil && (*p.MinAge < 0 || *p.MinAge > maxActorAge) ||
+		p.MaxAge != nil && (*p.MaxAge < 0 || *p.MaxAge > maxActorAge) {
+		return domain.BadRequest("age_out_of_range", fmt.Sprintf("возраст должен быть от 0 до %d", maxActorAge)).WithArgs(maxActorAge)
+	}
+
+	if p.MinAge != nil && p.MaxAge != nil && *p.MinAge > *p.MaxAge {
+		return domain.BadRequest("age_range_inverted", "минимальный возраст больше максимального")
+	}
+
+	if len(p.NamePrefix) > 100 {
+		return domain.BadRequest("actor_name_prefix_too_long", "имя актёра слишком длинное (макс. 100 символов)").WithArgs(100)
+	}
+
+	return nil
+}
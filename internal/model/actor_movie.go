@@ -5,7 +5,9 @@ type ActorWithFilms struct {
 	Films []Film `json:"films"`
 }
 
-func ValidateGetActors() error {
-	// TODO ... ДОБАВИТЬ ВАЛИДАЦИЮ, ЕСЛИ В ЭТОМ ЕСТЬ НЕОБХОДИМОСТЬ
-	return nil
+// ActorListItem - актёр в списке: число фильмов всегда, сами фильмы - только с include=films
+type ActorListItem struct {
+	Actor
+	FilmCount int    `json:"film_count"`
+	Films     []Film `json:"films,omitzero"`
 }
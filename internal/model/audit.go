@@ -0,0 +1,82 @@
+package model
+
+import (
+	"encoding/json"
+	"film-library/internal/domain"
+	"time"
+)
+
+// Действия и сущности журнала изменений
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditPurge   = "purge"
+	AuditImport  = "import" // как update, но для строк, изменённых импортом; у созданных before нет
+
+	EntityFilm    = "film"
+	EntityActor   = "actor"
+	EntityGenre   = "genre"
+	EntityCountry = "country" // ключ - код страны в EntityCode
+	EntityTag     = "tag"
+)
+
+// AuditEntry - запись журнала: кто, что и с какой сущностью сделал.
+// Before и After содержат только изменившиеся поля
+type AuditEntry struct {
+	Id         int64           `json:"id"`
+	UserID     *int            `json:"user_id"` // nil - изменение фоновой задачи
+	Action     string          `json:"action"`
+	Entity     string          `json:"entity"`
+	EntityID   int             `json:"entity_id,omitempty"`
+	EntityCode string          `json:"entity_code,omitempty"` // у стран вместо entity_id
+	Before     json.RawMessage `json:"before" swaggertype:"object"`
+	After      json.RawMessage `json:"after" swaggertype:"object"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditPage - страница журнала, новые записи первыми
+type AuditPage = Page[AuditEntry]
+
+// AuditFilter - фильтры журнала; нулевые значения не ограничивают выборку
+type AuditFilter struct {
+	UserID     int
+	Entity     string
+	EntityID   int
+	EntityCode string
+	From       *time.Time
+	To         *time.Time
+
+	Limit  int
+	Offset int
+}
+
+func (f *AuditFilter) Validate() error {
+	switch f.Entity {
+	case "", EntityFilm, EntityActor, EntityGenre, EntityCountry, EntityTag:
+	default:
+		return domain.BadRequest("invalid_audit_entity", "сущность должна быть film, actor, genre, country или tag")
+	}
+
+	if f.EntityID != 0 && f.Entity == "" {
+		return domain.BadRequest("entity_id_without_entity", "entity_id указывается вместе с entity")
+	}
+	if f.EntityCode != "" && f.Entity == "" {
+		return domain.BadRequest("entity_code_without_entity", "entity_code указывается вместе с entity")
+	}
+
+	if f.From != nil && f.To != nil && f.From.After(*f.To) {
+		return domain.BadRequest("time_range_inverted", "начало периода позже его окончания")
+	}
+
+	if f.Limit < 1 || f.Limit > MaxPageLimit {
+		return limitError()
+	}
+
+	if f.Offset < 0 {
+		return domain.BadRequest("negative_offset", "offset не может быть отрицательным")
+	}
+
+	return nil
+}
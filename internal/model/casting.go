@@ -0,0 +1,50 @@
+package model
+
+import (
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/utils/validate"
+	"fmt"
+)
+
+// CastMember - актёр в составе фильма с ролью и местом в титрах
+type CastMember struct {
+	Actor
+	Character    string `json:"character,omitempty" validate:"max=255"`
+	BillingOrder int    `json:"billing_order,omitempty" validate:"min=0"`
+}
+
+// CastingEntry - ссылка на существующего актёра при изменении состава фильма
+type CastingEntry struct {
+	ActorID      int    `json:"actor_id" validate:"required,min=1"`
+	Character    string `json:"character" validate:"max=255"`
+	BillingOrder int    `json:"billing_order" validate:"min=0"`
+}
+
+func (c *CastingEntry) Validate() error {
+	return validate.Struct(c)
+}
+
+// ValidateCasting - проверка полного состава фильма: каждый актёр встречается один раз
+func ValidateCasting(entries []CastingEntry) error {
+	var errs domain.FieldErrors
+	if err := validate.Struct(entries); err != nil && !errors.As(err, &errs) {
+		return err
+	}
+
+	seen := make(map[int]struct{}, len(entries))
+	for i, e := range entries {
+		if _, ok := seen[e.ActorID]; ok && e.ActorID != 0 {
+			errs = append(errs, domain.FieldError{
+				Field:   fmt.Sprintf("[%d].actor_id", i),
+				Rule:    "unique",
+				Message: fmt.Sprintf("актёр %d указан несколько раз", e.ActorID),
+				Key:     "validation.unique",
+				Args:    []any{e.ActorID},
+			})
+		}
+		seen[e.ActorID] = struct{}{}
+	}
+
+	return errs.Err()
+}
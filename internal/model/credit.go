@@ -0,0 +1,114 @@
+package model
+
+import (
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/utils/validate"
+	"fmt"
+	"time"
+)
+
+// Роли участников в титрах фильма
+const (
+	CreditActor           = "actor"
+	CreditDirector        = "director"
+	CreditWriter          = "writer"
+	CreditProducer        = "producer"
+	CreditComposer        = "composer"
+	CreditCinematographer = "cinematographer"
+	CreditEditor          = "editor"
+)
+
+// CreditRoles - роли в порядке вывода в титрах и фильмографии
+var CreditRoles = []string{
+	CreditDirector, CreditWriter, CreditProducer, CreditActor,
+	CreditCinematographer, CreditComposer, CreditEditor,
+}
+
+// Credit - участник фильма в одной роли. Персонаж бывает только у актёра,
+// position - порядок внутри роли (для актёров - место в титрах)
+type Credit struct {
+	PersonID  int    `json:"person_id" validate:"required,min=1"`
+	Name      string `json:"name,omitempty"` // только в ответах
+	Role      string `json:"role" validate:"required,oneof=actor director writer producer composer cinematographer editor"`
+	Character string `json:"character,omitempty" validate:"max=255"`
+	Position  int    `json:"position,omitempty" validate:"min=0"`
+}
+
+func (c *Credit) Validate() error {
+	var errs domain.FieldErrors
+	if err := validate.Struct(c); err != nil && !errors.As(err, &errs) {
+		return err
+	}
+	if err := c.checkCharacter("character"); err != nil {
+		errs = append(errs, *err)
+	}
+	return errs.Err()
+}
+
+func (c *Credit) checkCharacter(field string) *domain.FieldError {
+	if c.Character == "" || c.Role == CreditActor {
+		return nil
+	}
+	return &domain.FieldError{
+		Field:   field,
+		Rule:    "actor_only",
+		Message: "персонаж указывается только для роли actor",
+		Key:     "validation.actor_only",
+	}
+}
+
+// ValidateCredits - проверка полного списка титров: человек встречается в каждой роли один раз
+func ValidateCredits(credits []Credit) error {
+	var errs domain.FieldErrors
+	if err := validate.Struct(credits); err != nil && !errors.As(err, &errs) {
+		return err
+	}
+
+	type key struct {
+		personID int
+		role     string
+	}
+	seen := make(map[key]struct{}, len(credits))
+	for i, c := range credits {
+		if err := c.checkCharacter(fmt.Sprintf("[%d].character", i)); err != nil {
+			errs = append(errs, *err)
+		}
+
+		k := key{c.PersonID, c.Role}
+		if _, ok := seen[k]; ok && c.PersonID != 0 {
+			errs = append(errs, domain.FieldError{
+				Field:   fmt.Sprintf("[%d].person_id", i),
+				Rule:    "unique",
+				Message: fmt.Sprintf("участник %d указан в роли %s несколько раз", c.PersonID, c.Role),
+				Key:     "validation.unique_credit",
+				Args:    []any{c.PersonID, c.Role},
+			})
+		}
+		seen[k] = struct{}{}
+	}
+
+	return errs.Err()
+}
+
+// CreditFilm - фильм в фильмографии участника
+type CreditFilm struct {
+	Id          int       `json:"id"`
+	Name        string    `json:"name"`
+	Releasedate time.Time `json:"release_date"`
+	Rating      float32   `json:"rating"`
+	Character   string    `json:"character,omitempty"`
+}
+
+// RoleFilms - фильмы участника в одной роли
+type RoleFilms struct {
+	Role  string       `json:"role"`
+	Films []CreditFilm `json:"films"`
+}
+
+// Filmography - участник и его фильмы, сгруппированные по ролям в порядке CreditRoles.
+// Роли без фильмов не выводятся
+type Filmography struct {
+	Actor
+	Roles []RoleFilms `json:"roles"`
+}
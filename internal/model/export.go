@@ -0,0 +1,97 @@
+package model
+
+import (
+	"film-library/internal/domain"
+	"film-library/internal/utils/records"
+)
+
+// Что выгружается: фильмы, актёры или связи актёров с фильмами
+const (
+	ExportFilms   = "films"
+	ExportActors  = "actors"
+	ExportCasting = "casting"
+)
+
+// ExportColumns - колонки выгрузки в порядке вывода
+var ExportColumns = map[string][]string{
+	ExportFilms:   {"id", "name", "description", "release_date", "rating", "user_score", "votes", "genres", "countries", "tags"},
+	ExportActors:  {"id", "name", "gender", "date_of_birth"},
+	ExportCasting: {"film_id", "film", "actor_id", "actor", "character", "billing_order"},
+}
+
+// ExportParams - что, в каком формате и с какими фильтрами выгружается.
+// Фильмы фильтруются и сортируются как в списке (Films), актёры - по
+// NamePrefix, Gender и FilmID, состав - по FilmID и ActorID
+type ExportParams struct {
+	Entity string
+	Format string // csv, ndjson или json
+
+	Films      FilmListParams // без пагинации
+	NamePrefix string
+	Gender     string
+	FilmID     int
+	ActorID    int
+}
+
+func (p *ExportParams) Validate() error {
+	if _, ok := ExportColumns[p.Entity]; !ok {
+		return domain.BadRequest("invalid_export_entity", "выгрузить можно films, actors или casting")
+	}
+
+	switch p.Format {
+	case records.FormatCSV, records.FormatNDJSON, records.FormatJSON:
+	default:
+		return domain.BadRequest("unsupported_export_format", "формат выгрузки должен быть csv, ndjson или json")
+	}
+
+	if err := ValidateSortFilm(p.Films.SortBy); err != nil {
+		return err
+	}
+	if p.Films.Order != "" && p.Films.Order != "asc" && p.Films.Order != "desc" {
+		return domain.BadRequest("invalid_order", "направление сортировки должно быть asc или desc")
+	}
+	if err := p.Films.ValidateFilters(); err != nil {
+		return err
+	}
+
+	if p.Gender != "" && p.Gender != "male" && p.Gender != "female" {
+		return domain.BadRequest("invalid_gender", "пол должен быть male или female")
+	}
+	if len(p.NamePrefix) > 100 {
+		return domain.BadRequest("actor_name_prefix_too_long", "имя актёра слишком длинное (макс. 100 символов)").WithArgs(100)
+	}
+
+	return nil
+}
+
+// CastingRecord - строка выгрузки состава
+type CastingRecord struct {
+	FilmID       int
+	Film         string
+	ActorID      int
+	Actor        string
+	Character    string
+	BillingOrder int
+}
+
+// Values - значения фильма в порядке ExportColumns
+func (f *Film) Values() []any {
+	return []any{f.Id, f.Name, f.Description, f.Releasedate, f.Rating, f.UserScore, f.Votes, f.Genres, f.Countries, f.Tags}
+}
+
+// Values - значения актёра в порядке ExportColumns
+func (a *Actor) Values() []any {
+	return []any{a.Id, a.Name, a.Gender, a.DateOfBirth}
+}
+
+// Values - значения связи в порядке ExportColumns; пустые роль и порядок пишутся как null
+func (c *CastingRecord) Values() []any {
+	var character, billing any
+	if c.Character != "" {
+		character = c.Character
+	}
+	if c.BillingOrder != 0 {
+		billing = c.BillingOrder
+	}
+	return []any{c.FilmID, c.Film, c.ActorID, c.Actor, character, billing}
+}
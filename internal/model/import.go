@@ -0,0 +1,269 @@
+package model
+
+import (
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/utils/records"
+	"film-library/internal/utils/validate"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Что импортируется: фильмы, актёры или связи актёров с фильмами
+const (
+	ImportFilms  = "films"
+	ImportActors = "actors"
+	ImportCast   = "cast"
+)
+
+// MaxImportErrors - сколько ошибок строк попадает в отчёт; остальные только считаются
+const MaxImportErrors = 100
+
+// ImportColumns - колонки, допустимые для каждого вида импорта
+var ImportColumns = map[string][]string{
+	ImportFilms:  {"name", "description", "release_date", "rating", "genres", "countries", "tags"},
+	ImportActors: {"name", "gender", "date_of_birth"},
+	ImportCast:   {"film", "actor", "character", "billing_order"},
+}
+
+// importSkipped - колонки выгрузки, которые импорт принимает, но не читает:
+// идентификаторы и оценки пользователей назначает база. Так выгрузку можно
+// загрузить обратно без правки заголовка
+var importSkipped = map[string][]string{
+	ImportFilms:  {"id", "user_score", "votes"},
+	ImportActors: {"id"},
+	ImportCast:   {"film_id", "actor_id"},
+}
+
+// ImportRequest - что и в каком формате загружается
+type ImportRequest struct {
+	Kind   string // films, actors или cast
+	Format string // csv или ndjson
+	DryRun bool   // только проверить и посчитать, ничего не сохраняя
+}
+
+func (r *ImportRequest) Validate() error {
+	if _, ok := ImportColumns[r.Kind]; !ok {
+		return domain.BadRequest("invalid_import_kind", "вид импорта должен быть films, actors или cast")
+	}
+	if r.Format != records.FormatCSV && r.Format != records.FormatNDJSON {
+		return domain.BadRequest("unsupported_import_format", "поддерживаются только CSV и JSON Lines")
+	}
+	return nil
+}
+
+// FilmImportRow - фильм из строки line; пустой список меток снимает все, nil оставляет как есть
+type FilmImportRow struct {
+	Line int // строка во входных данных, для отчёта
+	Film
+}
+
+// CastImportRow - связь актёра с фильмом; фильм и актёр ищутся по названию и имени
+type CastImportRow struct {
+	Line         int    `json:"-"` // строка во входных данных, для отчёта
+	Film         string `json:"film" validate:"required,max=150"`
+	Actor        string `json:"actor" validate:"required,max=100"`
+	Character    string `json:"character" validate:"max=255"`
+	BillingOrder int    `json:"billing_order" validate:"min=0"`
+}
+
+// ImportResult - итог записи в базу
+type ImportResult struct {
+	Created int
+	Updated int
+	Errors  []ImportRowError // строки, отклонённые базой, например ссылки на неизвестный фильм
+}
+
+// ImportReport - отчёт об импорте. В режиме dry_run данные проверены и записаны
+// в транзакцию, которая затем откатывается, поэтому счётчики совпадают с настоящим запуском
+type ImportReport struct {
+	Kind            string           `json:"kind"`
+	DryRun          bool             `json:"dry_run"`
+	Rows            int              `json:"rows"`      // прочитано строк
+	Created         int              `json:"created"`   // добавлено
+	Updated         int              `json:"updated"`   // изменено
+	Unchanged       int              `json:"unchanged"` // совпали с базой или повторились в файле
+	Failed          int              `json:"failed"`    // отклонены
+	Errors          []ImportRowError `json:"errors"`
+	ErrorsTruncated bool             `json:"errors_truncated,omitempty"`
+}
+
+// ImportRowError - почему отклонена строка line
+type ImportRowError struct {
+	Line   int                `json:"line"`
+	Errors domain.FieldErrors `json:"errors"`
+}
+
+// AddError отмечает строку отклонённой; в отчёт попадают первые MaxImportErrors ошибок
+func (r *ImportReport) AddError(line int, errs domain.FieldErrors) {
+	r.Failed++
+	if len(r.Errors) >= MaxImportErrors {
+		r.ErrorsTruncated = true
+		return
+	}
+	r.Errors = append(r.Errors, ImportRowError{Line: line, Errors: errs})
+}
+
+// ValidateImportColumns проверяет заголовок CSV: неизвестная колонка скорее опечатка,
+// чем данные, которые можно молча пропустить
+func ValidateImportColumns(kind string, columns []string) error {
+	for _, column := range columns {
+		if !importColumn(kind, column) {
+			return domain.BadRequest("unknown_import_column", fmt.Sprintf("неизвестная колонка %q", column)).WithArgs(column)
+		}
+	}
+	return nil
+}
+
+func importColumn(kind, column string) bool {
+	return slices.Contains(ImportColumns[kind], column) || slices.Contains(importSkipped[kind], column)
+}
+
+// ParseImportFilm собирает фильм из строки импорта line и проверяет его через Validate.
+// Жанры, страны и теги перечисляются через records.ListSeparator
+func ParseImportFilm(row map[string]string, line int) (FilmImportRow, error) {
+	p := rowParser{kind: ImportFilms, row: row}
+	film := FilmImportRow{
+		Line: line,
+		Film: Film{
+			Name:        p.string("name"),
+			Description: p.string("description"),
+			Releasedate: p.date("release_date"),
+			Rating:      float32(p.float("rating")),
+			FilmLabels: FilmLabels{
+				Genres:    p.list("genres"),
+				Countries: p.list("countries"),
+				Tags:      p.list("tags"),
+			},
+		},
+	}
+	// Validate приводит метки к каноническому виду, поэтому фильм возвращается после неё
+	err := p.finish(film.Validate)
+	return film, err
+}
+
+// ParseImportActor собирает актёра из строки импорта и проверяет его через Validate
+func ParseImportActor(row map[string]string) (Actor, error) {
+	p := rowParser{kind: ImportActors, row: row}
+	actor := Actor{
+		Name:        p.string("name"),
+		Gender:      p.string("gender"),
+		DateOfBirth: p.date("date_of_birth"),
+	}
+	return actor, p.finish(actor.Validate)
+}
+
+// ParseImportCast собирает связь актёра с фильмом из строки импорта line
+func ParseImportCast(row map[string]string, line int) (CastImportRow, error) {
+	p := rowParser{kind: ImportCast, row: row}
+	cast := CastImportRow{
+		Line:         line,
+		Film:         p.string("film"),
+		Actor:        p.string("actor"),
+		Character:    p.string("character"),
+		BillingOrder: p.int("billing_order"),
+	}
+	return cast, p.finish(cast.Validate)
+}
+
+func (c *CastImportRow) Validate() error {
+	return validate.Struct(c)
+}
+
+// rowParser переводит строковые значения в типы полей и копит ошибки
+type rowParser struct {
+	kind string
+	row  map[string]string
+	errs domain.FieldErrors
+}
+
+func (p *rowParser) string(field string) string {
+	return p.row[field]
+}
+
+// list - значения через records.ListSeparator; nil, если колонки нет, пустая ячейка - пустой список
+func (p *rowParser) list(field string) []string {
+	v, ok := p.row[field]
+	if !ok {
+		return nil
+	}
+	if v == "" {
+		return []string{}
+	}
+	return strings.Split(v, records.ListSeparator)
+}
+
+func (p *rowParser) float(field string) float64 {
+	v := p.row[field]
+	if v == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(v, 32)
+	if err != nil {
+		p.fail(field, "number", "должно быть числом", "validation.number")
+	}
+	return f
+}
+
+func (p *rowParser) int(field string) int {
+	v := p.row[field]
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		p.fail(field, "integer", "должно быть целым числом", "validation.integer")
+	}
+	return n
+}
+
+func (p *rowParser) date(field string) time.Time {
+	v := p.row[field]
+	if v == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		p.fail(field, "date", "должно быть датой в формате ГГГГ-ММ-ДД", "validation.date")
+	}
+	return t
+}
+
+func (p *rowParser) fail(field, rule, msg, key string) {
+	p.errs = append(p.errs, domain.FieldError{Field: field, Rule: rule, Message: msg, Key: key})
+}
+
+// finish дополняет ошибки разбора ошибками проверки и неизвестными полями
+func (p *rowParser) finish(validate func() error) error {
+	unknown := make([]string, 0)
+	for field := range p.row {
+		if !importColumn(p.kind, field) {
+			unknown = append(unknown, field)
+		}
+	}
+	slices.Sort(unknown)
+	for _, field := range unknown {
+		p.fail(field, "unknown", "неизвестное поле", "validation.unknown")
+	}
+
+	// Поля, которые не разобрались, повторно через Validate не проверяем
+	var errs domain.FieldErrors
+	if err := validate(); errors.As(err, &errs) {
+		for _, fe := range errs {
+			if !p.failed(fe.Field) {
+				p.errs = append(p.errs, fe)
+			}
+		}
+	} else if err != nil {
+		return err
+	}
+
+	return p.errs.Err()
+}
+
+func (p *rowParser) failed(field string) bool {
+	return slices.ContainsFunc(p.errs, func(fe domain.FieldError) bool { return fe.Field == field })
+}
@@ -1,65 +1,147 @@
 package model
 
 import (
-	"fmt"
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/utils/validate"
 	"time"
 )
 
 type Film struct {
-	Id          int       `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Releasedate time.Time `json:"release_date"`
-	Rating      float32   `json:"rating"`
-	ListActors  []Actor   `json:"list_actors"`
+	Id          int          `json:"id"`
+	Name        string       `json:"name" validate:"required,max=150"`
+	Description string       `json:"description" validate:"max=1000"`
+	Releasedate time.Time    `json:"release_date"`
+	Rating      float32      `json:"rating" validate:"min=0,max=10"`
+	UserScore   float32      `json:"user_score,omitempty"` // средняя оценка пользователей, только в ответах
+	Votes       int          `json:"votes,omitempty"`      // число оценок пользователей
+	ListActors  []CastMember `json:"list_actors"`
+	FilmLabels
+	Version int `json:"-"` // версия строки, отдаётся в ETag
 }
 
-// Validate - проверка данных фильма
+// Validate - проверка данных фильма вместе с актёрами из list_actors.
+// Жанры, страны и теги перед проверкой приводятся к каноническому виду
 func (f *Film) Validate() error {
-	if f.Name == "" || len(f.Name) > 150 {
-		return fmt.Errorf("название фильма должно быть от 1 до 150 символов")
-	}
+	f.FilmLabels.normalize()
 
-	if len(f.Description) > 1000 {
-		return fmt.Errorf("описание не должно превышать 1000 символов")
+	var errs domain.FieldErrors
+	if err := validate.Struct(f); err != nil && !errors.As(err, &errs) {
+		return err
 	}
+	errs = append(errs, f.FilmLabels.check()...)
 
-	if f.Rating < 0 || f.Rating > 10 {
-		return fmt.Errorf("рейтинг должен быть от 0 до 10")
-	}
+	return errs.Err()
+}
 
-	return nil
+// Columns - колонки таблицы films, которые меняет PATCH; состав меняется отдельными маршрутами
+func (f *Film) Columns() map[string]any {
+	return map[string]any{
+		"name":         f.Name,
+		"description":  f.Description,
+		"release_date": f.Releasedate,
+		"rating":       f.Rating,
+	}
 }
 
 func ValidateSortFilm(sortBy string) error {
-	if sortBy != "name" && sortBy != "release_date" && sortBy != "" {
-		return fmt.Errorf("Некорректная сортировка")
+	switch sortBy {
+	case "", "name", "release_date", "rating", "user_score":
+	default:
+		return domain.BadRequest("invalid_sort", "некорректная сортировка")
 	}
 	return nil
 }
 
-func (a *Film) ValidateFilmSearchParams(filmName, actorName string) error {
-	if filmName == "" && actorName == "" {
-		return fmt.Errorf("необходимо указать либо название фильма, либо имя актёра")
+// FilmListParams - параметры выборки списка фильмов: фильтры, сортировка и пагинация
+type FilmListParams struct {
+	SortBy string // name, release_date, rating (по умолчанию), user_score
+	Order  string // asc или desc, по умолчанию зависит от поля
+
+	MinRating    *float32
+	MaxRating    *float32
+	ReleasedFrom *time.Time
+	ReleasedTo   *time.Time
+	ActorID      int
+	NamePrefix   string
+	Labels       FilmLabelFilter
+
+	Limit  int
+	Offset int
+	Cursor *Cursor
+}
+
+// Sort - поле сортировки с учётом значения по умолчанию
+func (p *FilmListParams) Sort() string {
+	if p.SortBy == "" {
+		return "rating"
 	}
+	return p.SortBy
+}
 
-	if filmName != "" {
-		if len(filmName) > 150 {
-			return fmt.Errorf("название фильма слишком длинное (макс. 150 символов)")
-		}
-		if len(filmName) < 2 {
-			return fmt.Errorf("название фильма слишком короткое (мин. 2 символа)")
-		}
+// Direction - направление сортировки: рейтинги по умолчанию по убыванию, остальное по возрастанию
+func (p *FilmListParams) Direction() string {
+	if p.Order != "" {
+		return p.Order
+	}
+	if p.Sort() == "rating" || p.Sort() == "user_score" {
+		return "desc"
+	}
+	return "asc"
+}
+
+// SortKey - идентификатор сортировки, к которой привязан курсор
+func (p *FilmListParams) SortKey() string {
+	return p.Sort() + ":" + p.Direction()
+}
+
+func (p *FilmListParams) Validate() error {
+	if err := ValidateSortFilm(p.SortBy); err != nil {
+		return err
 	}
 
-	if actorName != "" {
-		if len(actorName) > 100 {
-			return fmt.Errorf("имя актёра слишком длинное (макс. 100 символов)")
+	if p.Order != "" && p.Order != "asc" && p.Order != "desc" {
+		return domain.BadRequest("invalid_order", "направление сортировки должно быть asc или desc")
+	}
+
+	if p.Limit < 1 || p.Limit > MaxPageLimit {
+		return limitError()
+	}
+
+	if p.Offset < 0 {
+		return domain.BadRequest("negative_offset", "offset не может быть отрицательным")
+	}
+
+	if p.Cursor != nil {
+		if p.Offset > 0 {
+			return domain.BadRequest("cursor_with_offset", "нельзя одновременно указывать cursor и offset")
 		}
-		if len(actorName) < 2 {
-			return fmt.Errorf("имя актёра слишком короткое (мин. 2 символа)")
+		if p.Cursor.Sort != p.SortKey() {
+			return domain.BadRequest("cursor_sort_mismatch", "курсор выдан для другой сортировки")
 		}
 	}
 
-	return nil
+	return p.ValidateFilters()
+}
+
+// ValidateFilters проверяет фильтры выборки фильмов, общие для списка и выгрузки
+func (p *FilmListParams) ValidateFilters() error {
+	if p.MinRating != nil && (*p.MinRating < 0 || *p.MinRating > 10) ||
+		p.MaxRating != nil && (*p.MaxRating < 0 || *p.MaxRating > 10) {
+		return domain.BadRequest("rating_out_of_range", "рейтинг должен быть от 0 до 10")
+	}
+
+	if p.MinRating != nil && p.MaxRating != nil && *p.MinRating > *p.MaxRating {
+		return domain.BadRequest("rating_range_inverted", "минимальный рейтинг больше максимального")
+	}
+
+	if p.ReleasedFrom != nil && p.ReleasedTo != nil && p.ReleasedFrom.After(*p.ReleasedTo) {
+		return domain.BadRequest("release_range_inverted", "начало периода выхода позже его окончания")
+	}
+
+	if len(p.NamePrefix) > 150 {
+		return domain.BadRequest("name_prefix_too_long", "название фильма слишком длинное (макс. 150 символов)").WithArgs(150)
+	}
+
+	return p.Labels.Validate()
 }
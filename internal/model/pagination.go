@@ -0,0 +1,72 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"film-library/internal/domain"
+	"fmt"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// Page - страница списка: элементы, общее число записей под фильтром
+// и курсоры для перехода на соседние страницы
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int    `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	NextCursor string `json:"next_cursor"`
+	PrevCursor string `json:"prev_cursor"`
+}
+
+func limitError() error {
+	return domain.BadRequest("limit_out_of_range", fmt.Sprintf("limit должен быть от 1 до %d", MaxPageLimit)).WithArgs(MaxPageLimit)
+}
+
+// validatePage проверяет limit и offset страницы без курсора
+func validatePage(limit, offset int) error {
+	if limit < 1 || limit > MaxPageLimit {
+		return limitError()
+	}
+	if offset < 0 {
+		return domain.BadRequest("negative_offset", "offset не может быть отрицательным")
+	}
+	return nil
+}
+
+// FilmPage - страница списка фильмов
+type FilmPage = Page[Film]
+
+// ActorPage - страница списка актёров
+type ActorPage = Page[ActorListItem]
+
+// Cursor - позиция в отсортированном списке. Для клиента это непрозрачная строка
+type Cursor struct {
+	Sort     string `json:"s"`           // сортировка, для которой выдан курсор
+	Key      string `json:"k"`           // значение поля сортировки у граничной записи
+	ID       int    `json:"i"`           // id граничной записи
+	Backward bool   `json:"b,omitempty"` // true - страница перед граничной записью
+}
+
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(s string) (Cursor, error) {
+	var c Cursor
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, domain.BadRequest("invalid_cursor", "некорректный курсор")
+	}
+	if err := json.Unmarshal(raw, &c); err != nil || c.Sort == "" {
+		return Cursor{}, domain.BadRequest("invalid_cursor", "некорректный курсор")
+	}
+
+	return c, nil
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/utils/patch"
+	"reflect"
+	"time"
+)
+
+// Patch - тело PATCH-запроса и его тип: patch.MergePatchType или patch.JSONPatchType
+type Patch struct {
+	ContentType string
+	Body        []byte
+}
+
+// Apply применяет патч к структуре по указателю v. Структура сериализуется в JSON,
+// патчится и декодируется заново, поэтому удалённые патчем поля обнуляются,
+// а поля, которых в структуре нет, считаются ошибкой
+func (p Patch) Apply(v any) error {
+	doc, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	patched, err := patch.Apply(p.ContentType, doc, p.Body)
+	switch {
+	case errors.Is(err, patch.ErrConflict):
+		return domain.Wrap(err, domain.ErrConflict, "patch_conflict", "патч не применим к текущему состоянию: "+err.Error())
+	case errors.Is(err, patch.ErrInvalid):
+		return domain.Wrap(err, domain.ErrBadRequest, "invalid_patch", "некорректный патч: "+err.Error())
+	case err != nil:
+		return err
+	}
+
+	fresh := reflect.New(reflect.TypeOf(v).Elem())
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(fresh.Interface()); err != nil {
+		return domain.Wrap(err, domain.ErrBadRequest, "invalid_patch", "некорректный патч: "+err.Error())
+	}
+	reflect.ValueOf(v).Elem().Set(fresh.Elem())
+
+	return nil
+}
+
+// ReadOnlyField - ошибка попытки изменить поле, которое PATCH не меняет
+func ReadOnlyField(field string) error {
+	return domain.Validation("read_only_field", "поле "+field+" нельзя изменить").WithArgs(field)
+}
+
+// ChangedColumns оставляет из after только колонки, значения которых отличаются от before
+func ChangedColumns(before, after map[string]any) map[string]any {
+	changed := make(map[string]any)
+	for col, v := range after {
+		if !equalColumn(before[col], v) {
+			changed[col] = v
+		}
+	}
+	return changed
+}
+
+func equalColumn(a, b any) bool {
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		return ok && ta.Equal(tb)
+	}
+	return a == b
+}
@@ -0,0 +1,66 @@
+package model
+
+import "time"
+
+const (
+	MaxSimilarFilms    = 50  // похожих фильмов хранится на один фильм
+	MaxRecommendations = 100 // рекомендаций хранится на одного пользователя
+	LikedScore         = 7   // оценка, начиная с которой фильм считается понравившимся
+)
+
+// Причины рекомендации
+const (
+	ReasonCast           = "cast"             // актёры из понравившихся или просмотренных фильмов
+	ReasonRatings        = "ratings"          // высокие оценки пользователей с похожими вкусами
+	ReasonCastAndRatings = "cast_and_ratings" // обе причины
+)
+
+// SimilarFilm - фильм с общими актёрами. Score учитывает число общих актёров,
+// рейтинг и год выхода похожего фильма
+type SimilarFilm struct {
+	Id           int       `json:"id"`
+	Name         string    `json:"name"`
+	Releasedate  time.Time `json:"release_date"`
+	Rating       float32   `json:"rating"`
+	Score        float32   `json:"score"`
+	SharedActors int       `json:"shared_actors"`
+}
+
+// SimilarPage - страница похожих фильмов, самые похожие первыми
+type SimilarPage = Page[SimilarFilm]
+
+// SimilarParams - похожие фильмы для FilmID
+type SimilarParams struct {
+	FilmID int
+	Limit  int
+	Offset int
+}
+
+func (p *SimilarParams) Validate() error {
+	return validatePage(p.Limit, p.Offset)
+}
+
+// Recommendation - рекомендованный пользователю фильм
+type Recommendation struct {
+	Id          int       `json:"id"`
+	Name        string    `json:"name"`
+	Releasedate time.Time `json:"release_date"`
+	Rating      float32   `json:"rating"`
+	Score       float32   `json:"score"`
+	Reason      string    `json:"reason"` // ReasonCast, ReasonRatings или ReasonCastAndRatings
+}
+
+// RecommendationPage - страница рекомендаций, лучшие первыми
+type RecommendationPage = Page[Recommendation]
+
+// RecommendationParams - рекомендации для UserID. Просмотренные и оценённые
+// после пересчёта фильмы не выводятся
+type RecommendationParams struct {
+	UserID int
+	Limit  int
+	Offset int
+}
+
+func (p *RecommendationParams) Validate() error {
+	return validatePage(p.Limit, p.Offset)
+}
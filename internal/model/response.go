@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"film-library/internal/domain"
+	"time"
+)
 
 type OKResponse struct {
 	Status  int    `json:"status"`
@@ -8,10 +11,24 @@ type OKResponse struct {
 	Data    any    `json:"data"`
 }
 
+// ErrorResponse - тело ответа с ошибкой. Code - машиночитаемый код
+// (например "film_not_found"), Message - текст для человека
 type ErrorResponse struct {
 	Status  int    `json:"status"`
 	Message string `json:"message"`
-	Code    int    `json:"code"`
+	Code    string `json:"code"`
+}
+
+// Problem - ошибка валидации в формате RFC 7807 (application/problem+json).
+// Errors перечисляет все неверные поля, чтобы клиент мог подсветить их в форме
+type Problem struct {
+	Type     string              `json:"type"`
+	Title    string              `json:"title"`
+	Status   int                 `json:"status"`
+	Detail   string              `json:"detail,omitempty"`
+	Instance string              `json:"instance,omitempty"`
+	Code     string              `json:"code"`
+	Errors   []domain.FieldError `json:"errors"`
 }
 
 type ActorWithMovies struct {
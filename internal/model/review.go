@@ -0,0 +1,77 @@
+package model
+
+import (
+	"film-library/internal/domain"
+	"film-library/internal/utils/validate"
+	"strings"
+	"time"
+)
+
+// Статусы рецензии: новая и изменённая ждут модерации, в списке фильма видны одобренные
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewHidden   = "hidden"
+)
+
+// RatingRequest - оценка фильма пользователем
+type RatingRequest struct {
+	Score int `json:"score" validate:"required,min=1,max=10"`
+}
+
+func (r *RatingRequest) Validate() error {
+	return validate.Struct(r)
+}
+
+// FilmScore - оценки фильма: своя оценка пользователя (0 - не ставил),
+// средняя оценка пользователей и число голосов
+type FilmScore struct {
+	FilmID    int     `json:"film_id"`
+	Score     int     `json:"score,omitempty"`
+	UserScore float32 `json:"user_score"`
+	Votes     int     `json:"votes"`
+}
+
+// Review - рецензия пользователя на фильм. Score - оценка автора, если он её ставил
+type Review struct {
+	Id        int       `json:"id"`
+	FilmID    int       `json:"film_id"`
+	UserID    int       `json:"user_id"`
+	Username  string    `json:"username"`
+	Score     int       `json:"score,omitempty"`
+	Body      string    `json:"body"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReviewRequest - текст своей рецензии
+type ReviewRequest struct {
+	Body string `json:"body" validate:"required,max=5000"`
+}
+
+// Validate убирает пробелы по краям текста и проверяет рецензию
+func (r *ReviewRequest) Validate() error {
+	r.Body = strings.TrimSpace(r.Body)
+	return validate.Struct(r)
+}
+
+// ReviewPage - страница списка рецензий
+type ReviewPage = Page[Review]
+
+// ReviewListParams - выборка рецензий: одного фильма и/или в одном статусе, новые первыми
+type ReviewListParams struct {
+	FilmID int
+	Status string
+	Limit  int
+	Offset int
+}
+
+func (p *ReviewListParams) Validate() error {
+	switch p.Status {
+	case "", ReviewPending, ReviewApproved, ReviewHidden:
+	default:
+		return domain.BadRequest("invalid_review_status", "статус рецензии должен быть pending, approved или hidden")
+	}
+	return validatePage(p.Limit, p.Offset)
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"film-library/internal/domain"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FilmSearchParams - параметры полнотекстового поиска по названию, описанию и актёрам
+type FilmSearchParams struct {
+	Query  string
+	Lang   string // ru, en или пусто - искать с обеими морфологиями
+	Labels FilmLabelFilter
+	Limit  int
+	Offset int
+}
+
+// FilmSearchResult - найденный фильм с релевантностью и подсвеченными совпадениями
+type FilmSearchResult struct {
+	Film
+	Rank       float32          `json:"rank"`
+	Highlights SearchHighlights `json:"highlights"`
+}
+
+// SearchHighlights - фрагменты с совпадениями, найденные слова обёрнуты в <mark>
+type SearchHighlights struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Actors      []string `json:"actors"`
+}
+
+// FilmSearchPage - страница результатов поиска
+type FilmSearchPage = Page[FilmSearchResult]
+
+func (p *FilmSearchParams) Validate() error {
+	length := utf8.RuneCountInString(p.Query)
+	if length == 0 {
+		return domain.BadRequest("search_query_required", "необходимо указать поисковый запрос")
+	}
+	if length < 2 {
+		return domain.BadRequest("search_query_too_short", "поисковый запрос слишком короткий (мин. 2 символа)").WithArgs(2)
+	}
+	if length > 200 {
+		return domain.BadRequest("search_query_too_long", "поисковый запрос слишком длинный (макс. 200 символов)").WithArgs(200)
+	}
+
+	if p.Lang != "" && p.Lang != "ru" && p.Lang != "en" {
+		return domain.BadRequest("invalid_search_lang", "язык поиска должен быть ru или en")
+	}
+
+	if p.Limit < 1 || p.Limit > MaxPageLimit {
+		return limitError()
+	}
+
+	if p.Offset < 0 {
+		return domain.BadRequest("negative_offset", "offset не может быть отрицательным")
+	}
+
+	return p.Labels.Validate()
+}
+
+// HighlightLang - язык для подсветки совпадений. Если язык не задан,
+// определяем его по запросу: кириллица - русский, иначе английский
+func (p *FilmSearchParams) HighlightLang() string {
+	if p.Lang != "" {
+		return p.Lang
+	}
+
+	for _, r := range p.Query {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return "ru"
+		}
+	}
+
+	return "en"
+}
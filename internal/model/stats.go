@@ -0,0 +1,138 @@
+package model
+
+import (
+	"film-library/internal/domain"
+	"time"
+)
+
+// RatingBuckets - число корзин гистограммы рейтингов шириной 1 (0-1, ..., 9-10)
+const RatingBuckets = 10
+
+// ActorStats - агрегаты по фильмографии актёра. Для актёра без фильмов
+// рейтинги и даты пустые
+type ActorStats struct {
+	ActorID      int        `json:"actor_id"`
+	Name         string     `json:"name"`
+	FilmCount    int        `json:"film_count"`
+	AvgRating    *float64   `json:"avg_rating"`
+	MinRating    *float32   `json:"min_rating"`
+	MaxRating    *float32   `json:"max_rating"`
+	FirstRelease *time.Time `json:"first_release"`
+	LastRelease  *time.Time `json:"last_release"`
+	CareerYears  int        `json:"career_years"` // лет между первым и последним фильмом
+}
+
+// ActorStatsPage - страница статистики актёров
+type ActorStatsPage = Page[ActorStats]
+
+// ActorStatsParams - сортировка, фильтр и пагинация статистики актёров
+type ActorStatsParams struct {
+	SortBy   string // film_count (по умолчанию), avg_rating, career_years, name
+	Order    string // asc или desc; по умолчанию name по возрастанию, остальное по убыванию
+	MinFilms int    // не меньше стольких фильмов
+
+	Limit  int
+	Offset int
+}
+
+// Sort - поле сортировки с учётом значения по умолчанию
+func (p *ActorStatsParams) Sort() string {
+	if p.SortBy == "" {
+		return "film_count"
+	}
+	return p.SortBy
+}
+
+// Direction - направление сортировки с учётом значения по умолчанию
+func (p *ActorStatsParams) Direction() string {
+	if p.Order != "" {
+		return p.Order
+	}
+	if p.Sort() == "name" {
+		return "asc"
+	}
+	return "desc"
+}
+
+func (p *ActorStatsParams) Validate() error {
+	switch p.SortBy {
+	case "", "film_count", "avg_rating", "career_years", "name":
+	default:
+		return domain.BadRequest("invalid_sort", "некорректная сортировка")
+	}
+
+	if p.Order != "" && p.Order != "asc" && p.Order != "desc" {
+		return domain.BadRequest("invalid_order", "направление сортировки должно быть asc или desc")
+	}
+
+	if p.MinFilms < 0 {
+		return domain.BadRequest("negative_min_films", "min_films не может быть отрицательным")
+	}
+
+	return validatePage(p.Limit, p.Offset)
+}
+
+// YearRange - период по годам выхода; 0 - граница не задана
+type YearRange struct {
+	From int
+	To   int
+}
+
+func (r *YearRange) Validate() error {
+	if r.From < 0 || r.To < 0 {
+		return domain.BadRequest("negative_year", "год не может быть отрицательным")
+	}
+	if r.From != 0 && r.To != 0 && r.From > r.To {
+		return domain.BadRequest("year_range_inverted", "начальный год больше конечного")
+	}
+	return nil
+}
+
+// YearStats - фильмы, вышедшие за год
+type YearStats struct {
+	Year      int     `json:"year"`
+	FilmCount int     `json:"film_count"`
+	AvgRating float64 `json:"avg_rating"`
+	MinRating float32 `json:"min_rating"`
+	MaxRating float32 `json:"max_rating"`
+}
+
+// RatingBucket - число фильмов с рейтингом в [From, To); последняя корзина включает 10
+type RatingBucket struct {
+	From      int `json:"from"`
+	To        int `json:"to"`
+	FilmCount int `json:"film_count"`
+}
+
+// ActorRef - краткая ссылка на актёра
+type ActorRef struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// CostarPair - два актёра и число их общих фильмов
+type CostarPair struct {
+	Actor     ActorRef `json:"actor"`
+	Costar    ActorRef `json:"costar"`
+	FilmCount int      `json:"film_count"`
+}
+
+// CostarPage - страница пар актёров
+type CostarPage = Page[CostarPair]
+
+// CostarParams - фильтры и пагинация пар актёров. С ActorID выдаются только
+// партнёры этого актёра, и он всегда стоит первым в паре
+type CostarParams struct {
+	ActorID  int
+	MinFilms int
+
+	Limit  int
+	Offset int
+}
+
+func (p *CostarParams) Validate() error {
+	if p.MinFilms < 0 {
+		return domain.BadRequest("negative_min_films", "min_films не может быть отрицательным")
+	}
+	return validatePage(p.Limit, p.Offset)
+}
@@ -0,0 +1,229 @@
+package model
+
+import (
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/utils/validate"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxLabelLength  = 50 // длина имени жанра или тега
+	MaxLabelFilters = 10 // значений в одном фильтре списка фильмов
+	FacetTagLimit   = 30 // самых частых тегов в фасетах
+)
+
+// Genre - жанр из справочника
+type Genre struct {
+	Id        int    `json:"id"`
+	Name      string `json:"name" validate:"required,max=50"`
+	FilmCount int    `json:"film_count"` // только в ответах, удалённые фильмы не считаются
+}
+
+// Validate убирает лишние пробелы в имени и проверяет жанр
+func (g *Genre) Validate() error {
+	g.Name = strings.TrimSpace(g.Name)
+	return validate.Struct(g)
+}
+
+// Country - страна производства по ISO 3166-1 alpha-2
+type Country struct {
+	Code      string `json:"code" validate:"required"`
+	Name      string `json:"name" validate:"required,max=100"`
+	FilmCount int    `json:"film_count"`
+}
+
+// Validate приводит код к верхнему регистру и проверяет страну
+func (c *Country) Validate() error {
+	c.Code = strings.ToUpper(strings.TrimSpace(c.Code))
+	c.Name = strings.TrimSpace(c.Name)
+
+	var errs domain.FieldErrors
+	if err := validate.Struct(c); err != nil && !errors.As(err, &errs) {
+		return err
+	}
+	if c.Code != "" && !IsCountryCode(c.Code) {
+		errs = append(errs, countryCodeError("code"))
+	}
+	return errs.Err()
+}
+
+// Tag - свободная метка фильма. Имя хранится в нижнем регистре
+type Tag struct {
+	Id        int    `json:"id"`
+	Name      string `json:"name" validate:"required,max=50"`
+	FilmCount int    `json:"film_count"`
+}
+
+// Validate приводит имя к нижнему регистру и проверяет тег
+func (t *Tag) Validate() error {
+	t.Name = normalizeTag(t.Name)
+	return validate.Struct(t)
+}
+
+// TagPage - страница списка тегов
+type TagPage = Page[Tag]
+
+// TagListParams - поиск тегов по началу имени, самые используемые первыми
+type TagListParams struct {
+	Prefix string
+	Limit  int
+	Offset int
+}
+
+func (p *TagListParams) Validate() error {
+	if utf8.RuneCountInString(p.Prefix) > MaxLabelLength {
+		return domain.BadRequest("tag_prefix_too_long", fmt.Sprintf("начало тега слишком длинное (макс. %d символов)", MaxLabelLength)).WithArgs(MaxLabelLength)
+	}
+	return validatePage(p.Limit, p.Offset)
+}
+
+// FilmLabels - жанры (по имени), страны (по коду) и теги фильма, не больше 20 каждого.
+// При изменении фильма nil оставляет метки как есть, пустой список снимает все
+type FilmLabels struct {
+	Genres    []string `json:"genres,omitempty" validate:"max=20"`
+	Countries []string `json:"countries,omitempty" validate:"max=20"`
+	Tags      []string `json:"tags,omitempty" validate:"max=20"`
+}
+
+// normalize убирает пустые значения и повторы, коды стран - в верхний регистр,
+// теги - в нижний. Жанры сравниваются без учёта регистра, остаётся первое написание
+func (l *FilmLabels) normalize() {
+	l.Genres = uniqueLabels(l.Genres, strings.TrimSpace, strings.ToLower)
+	l.Countries = uniqueLabels(l.Countries, func(s string) string { return strings.ToUpper(strings.TrimSpace(s)) }, nil)
+	l.Tags = uniqueLabels(l.Tags, normalizeTag, nil)
+}
+
+// check - ошибки отдельных значений, которые не выразить тегами validate
+func (l *FilmLabels) check() domain.FieldErrors {
+	var errs domain.FieldErrors
+	check := func(field string, values []string) {
+		for i, v := range values {
+			if utf8.RuneCountInString(v) > MaxLabelLength {
+				errs = append(errs, domain.FieldError{
+					Field:   fmt.Sprintf("%s[%d]", field, i),
+					Rule:    "max",
+					Params:  []string{fmt.Sprint(MaxLabelLength)},
+					Message: fmt.Sprintf("не больше %d символов", MaxLabelLength),
+					Key:     "validation.max.string",
+					Args:    []any{fmt.Sprint(MaxLabelLength)},
+				})
+			}
+		}
+	}
+
+	check("genres", l.Genres)
+	for i, code := range l.Countries {
+		if !IsCountryCode(code) {
+			errs = append(errs, countryCodeError(fmt.Sprintf("countries[%d]", i)))
+		}
+	}
+	check("tags", l.Tags)
+
+	return errs
+}
+
+// IsZero - ни один список меток не задан
+func (l FilmLabels) IsZero() bool {
+	return l.Genres == nil && l.Countries == nil && l.Tags == nil
+}
+
+// ChangedLabels оставляет из after только списки, отличающиеся от before; остальные - nil
+func ChangedLabels(before, after FilmLabels) FilmLabels {
+	changed := func(a, b []string) []string {
+		if slices.Equal(a, b) {
+			return nil
+		}
+		return b
+	}
+	return FilmLabels{
+		Genres:    changed(before.Genres, after.Genres),
+		Countries: changed(before.Countries, after.Countries),
+		Tags:      changed(before.Tags, after.Tags),
+	}
+}
+
+// IsCountryCode - две латинские буквы в верхнем регистре
+func IsCountryCode(code string) bool {
+	return len(code) == 2 && code[0] >= 'A' && code[0] <= 'Z' && code[1] >= 'A' && code[1] <= 'Z'
+}
+
+func countryCodeError(field string) domain.FieldError {
+	return domain.FieldError{
+		Field:   field,
+		Rule:    "country_code",
+		Message: "должен быть кодом страны ISO 3166-1 из двух букв",
+		Key:     "validation.country_code",
+	}
+}
+
+// normalizeTag - тег в нижнем регистре с одиночными пробелами
+func normalizeTag(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// uniqueLabels нормализует значения, отбрасывает пустые и повторы по ключу key
+// (nil - само значение). nil на входе остаётся nil
+func uniqueLabels(values []string, norm, key func(string) string) []string {
+	if values == nil {
+		return nil
+	}
+
+	out := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		v = norm(v)
+		k := v
+		if key != nil {
+			k = key(v)
+		}
+		if v == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		out = append(out, v)
+	}
+	return out
+}
+
+// FilmLabelFilter - фильтр фильмов по меткам: у фильма должны быть все перечисленные
+// жанры, страны и теги. Жанры и теги сравниваются без учёта регистра
+type FilmLabelFilter struct {
+	Genres    []string
+	Countries []string
+	Tags      []string
+}
+
+func (f *FilmLabelFilter) Validate() error {
+	filters := []struct {
+		name   string
+		values []string
+	}{{"genre", f.Genres}, {"country", f.Countries}, {"tag", f.Tags}}
+
+	for _, filter := range filters {
+		if len(filter.values) > MaxLabelFilters {
+			return domain.BadRequest("too_many_filter_values",
+				fmt.Sprintf("в фильтре %s не больше %d значений", filter.name, MaxLabelFilters)).WithArgs(filter.name, MaxLabelFilters)
+		}
+	}
+	return nil
+}
+
+// FacetCount - значение фасета и число фильмов с ним
+type FacetCount struct {
+	Value     string `json:"value"` // значение для фильтра: имя жанра или тега, код страны
+	Name      string `json:"name"`
+	FilmCount int    `json:"film_count"`
+}
+
+// FilmFacets - число фильмов под текущими фильтрами по каждому жанру, стране и тегу
+// (тегов - только самые частые). Пустые значения не выводятся
+type FilmFacets struct {
+	Total     int          `json:"total"`
+	Genres    []FacetCount `json:"genres"`
+	Countries []FacetCount `json:"countries"`
+	Tags      []FacetCount `json:"tags"`
+}
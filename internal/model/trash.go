@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// TrashItem - запись в корзине: фильм или актёр, удалённый мягко
+type TrashItem struct {
+	Id        int       `json:"id"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// Trash - содержимое корзины, новые удаления первыми
+type Trash struct {
+	Films  []TrashItem `json:"films"`
+	Actors []TrashItem `json:"actors"`
+}
+
+// PurgeResult - сколько записей окончательно удалено при очистке корзины
+type PurgeResult struct {
+	Films  int64 `json:"films"`
+	Actors int64 `json:"actors"`
+}
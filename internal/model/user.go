@@ -1,6 +1,10 @@
 package model
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
 
 type User struct {
 	ID       int    `json:"id" db:"id"`
@@ -14,15 +18,15 @@ type User struct {
 type UserRole int
 
 const (
-	RoleUser  UserRole = 1
-	RoleAdmin UserRole = 2
+	RoleUser   UserRole = 1
+	RoleAdmin  UserRole = 2
+	RoleEditor UserRole = 3
 )
 
 // РЕГИСТРАЦИЯ
 type SignUpRequest struct {
 	Username string `json:"username" validate:"required,min=3,max=50"`
 	Password string `json:"password" validate:"required,min=8"`
-	Role     int    `json:"role"`
 }
 
 // ВХОД
@@ -44,9 +48,43 @@ type UserResponse struct {
 	Role     int    `json:"role"`
 }
 
+// TokenPair - короткоживущий access-токен и refresh-токен для его обновления
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // время жизни access-токена в секундах
+}
+
 type AuthResponse struct {
-	AccessToken string       `json:"access_token"`
-	User        UserResponse `json:"user"`
+	TokenPair
+	User UserResponse `json:"user"`
+}
+
+// SignUpResponse - ответ регистрации. Token дублирует access_token для старых клиентов
+type SignUpResponse struct {
+	Token string `json:"token"`
+	TokenPair
+}
+
+// RefreshRequest - обновление пары токенов и выход
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// RefreshToken - запись о выданном refresh-токене (хранится только хеш)
+type RefreshToken struct {
+	Hash            string
+	UserID          int
+	FamilyID        string
+	AccessJTI       string
+	AccessExpiresAt time.Time
+	ExpiresAt       time.Time
+}
+
+// TokenPurgeResult - сколько истёкших записей о токенах удалено при очистке
+type TokenPurgeResult struct {
+	RefreshTokens int64
+	RevokedTokens int64
 }
 
 // JWT-данные (хранятся в токене)
@@ -56,8 +94,67 @@ type TokenClaims struct {
 	jwt.RegisteredClaims
 }
 
+// Ресурсы и действия матрицы прав
+const (
+	ResourceFilms      = "films"
+	ResourceActors     = "actors"
+	ResourceUsers      = "users"
+	ResourceTrash      = "trash"
+	ResourceAudit      = "audit"
+	ResourceImport     = "import"
+	ResourceExport     = "export"
+	ResourceStats      = "stats"
+	ResourceReviews    = "reviews"    // оценки и рецензии; update - модерация
+	ResourceWatchlists = "watchlists" // личные списки, просмотры и лента
+
+	ActionRead   = "read"
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
 // Права доступа (для middleware)
 type Permission struct {
-	Resource string   `json:"resource"` // Например, "movies", "actors"
+	Resource string   `json:"resource"` // Например, "films", "actors"
 	Actions  []string `json:"actions"`  // Например, ["create", "read"]
 }
+
+// Role - роль пользователя вместе с её правами
+type Role struct {
+	ID          int          `json:"id"`
+	Name        string       `json:"name"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// Allows проверяет, что роль может выполнить все действия над ресурсом
+func (r Role) Allows(required Permission) bool {
+	if len(required.Actions) == 0 {
+		return false
+	}
+
+	for _, action := range required.Actions {
+		if !r.can(required.Resource, action) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Role) can(resource, action string) bool {
+	for _, p := range r.Permissions {
+		if p.Resource != resource {
+			continue
+		}
+		for _, a := range p.Actions {
+			if a == action {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AssignRoleRequest - смена роли пользователя администратором
+type AssignRoleRequest struct {
+	Role string `json:"role" validate:"required"`
+}
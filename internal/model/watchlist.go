@@ -0,0 +1,174 @@
+package model
+
+import (
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/utils/validate"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	MaxWatchlistFilms = 1000 // фильмов в одном списке
+	FeedPeriod        = 365  // дней: по умолчанию в ленте фильмы, вышедшие за последний год
+)
+
+// Watchlist - именованный список фильмов пользователя. Публичный список видят все,
+// приватный - только владелец
+type Watchlist struct {
+	Id          int       `json:"id"`
+	UserID      int       `json:"user_id"`
+	Name        string    `json:"name" validate:"required,max=100"`
+	Description string    `json:"description" validate:"max=1000"`
+	Public      bool      `json:"public"`
+	FilmCount   int       `json:"film_count"` // только в ответах, фильмы из корзины не считаются
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Validate убирает лишние пробелы в имени и проверяет список
+func (w *Watchlist) Validate() error {
+	w.Name = strings.TrimSpace(w.Name)
+	return validate.Struct(w)
+}
+
+// WatchlistWithFilms - список с фильмами в порядке списка. Фильмы из корзины не выводятся
+type WatchlistWithFilms struct {
+	Watchlist
+	Films []WatchlistFilm `json:"films"`
+}
+
+// WatchlistFilm - фильм в списке, position - место в списке начиная с 1
+type WatchlistFilm struct {
+	Id          int       `json:"id"`
+	Name        string    `json:"name"`
+	Releasedate time.Time `json:"release_date"`
+	Rating      float32   `json:"rating"`
+	Position    int       `json:"position"`
+	AddedAt     time.Time `json:"added_at"`
+}
+
+// WatchlistEntry - добавление фильма в список: без position фильм встаёт в конец,
+// уже добавленный остаётся на месте
+type WatchlistEntry struct {
+	Position int `json:"position" validate:"min=0"`
+}
+
+func (e *WatchlistEntry) Validate() error {
+	return validate.Struct(e)
+}
+
+// WatchlistOrder - новое содержимое списка в нужном порядке
+type WatchlistOrder struct {
+	FilmIDs []int `json:"film_ids" validate:"max=1000,dive,min=1"`
+}
+
+// Validate проверяет порядок: каждый фильм встречается один раз
+func (o *WatchlistOrder) Validate() error {
+	var errs domain.FieldErrors
+	if err := validate.Struct(o); err != nil && !errors.As(err, &errs) {
+		return err
+	}
+
+	seen := make(map[int]struct{}, len(o.FilmIDs))
+	for i, id := range o.FilmIDs {
+		if _, ok := seen[id]; ok && id > 0 {
+			errs = append(errs, domain.FieldError{
+				Field:   fmt.Sprintf("film_ids[%d]", i),
+				Rule:    "unique",
+				Message: fmt.Sprintf("фильм %d указан несколько раз", id),
+				Key:     "validation.unique_film",
+				Args:    []any{id},
+			})
+		}
+		seen[id] = struct{}{}
+	}
+
+	return errs.Err()
+}
+
+// WatchedFilm - просмотренный фильм и дата просмотра
+type WatchedFilm struct {
+	Id          int       `json:"id"`
+	Name        string    `json:"name"`
+	Releasedate time.Time `json:"release_date"`
+	Rating      float32   `json:"rating"`
+	WatchedOn   time.Time `json:"watched_on"`
+}
+
+// WatchedPage - страница просмотренных фильмов
+type WatchedPage = Page[WatchedFilm]
+
+// WatchedRequest - отметка о просмотре; без даты - сегодня
+type WatchedRequest struct {
+	WatchedOn *time.Time `json:"watched_on"`
+}
+
+// WatchedMark - отметка о просмотре фильма
+type WatchedMark struct {
+	FilmID    int       `json:"film_id"`
+	WatchedOn time.Time `json:"watched_on"`
+}
+
+// Date - дата просмотра без времени; в будущем фильм посмотреть нельзя
+func (r *WatchedRequest) Date(now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if r.WatchedOn == nil {
+		return today, nil
+	}
+
+	d := r.WatchedOn.UTC()
+	date := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	if date.After(today) {
+		return time.Time{}, domain.FieldErrors{{
+			Field:   "watched_on",
+			Rule:    "not_future",
+			Message: "дата просмотра не может быть в будущем",
+			Key:     "validation.not_future",
+		}}.Err()
+	}
+	return date, nil
+}
+
+// WatchedListParams - страница просмотренных фильмов пользователя, последние первыми
+type WatchedListParams struct {
+	UserID int
+	Limit  int
+	Offset int
+}
+
+func (p *WatchedListParams) Validate() error {
+	return validatePage(p.Limit, p.Offset)
+}
+
+// FeedItem - новый фильм с актёрами из просмотренных пользователем фильмов
+type FeedItem struct {
+	Id          int         `json:"id"`
+	Name        string      `json:"name"`
+	Releasedate time.Time   `json:"release_date"`
+	Rating      float32     `json:"rating"`
+	Actors      []FeedActor `json:"actors"` // знакомые пользователю актёры в порядке титров
+}
+
+// FeedActor - актёр, по которому фильм попал в ленту
+type FeedActor struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// FeedPage - страница ленты
+type FeedPage = Page[FeedItem]
+
+// FeedParams - лента пользователя: непросмотренные фильмы, вышедшие не раньше ReleasedFrom,
+// новые первыми
+type FeedParams struct {
+	UserID       int
+	ReleasedFrom time.Time
+	Limit        int
+	Offset       int
+}
+
+func (p *FeedParams) Validate() error {
+	return validatePage(p.Limit, p.Offset)
+}
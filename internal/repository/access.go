@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	"fmt"
+)
+
+// ErrUnknownRole - роли с таким названием нет в базе
+var ErrUnknownRole = errors.New("unknown role")
+
+type AccessRepository interface {
+	GetRoles(ctx context.Context) ([]model.Role, error)
+	SetUserRole(ctx context.Context, userID int, role string) (model.User, error)
+}
+
+func NewAccessRepository(db *sql.DB) AccessRepository {
+	return &Storage{
+		db: db,
+	}
+}
+
+// GetRoles возвращает все роли с правами, сгруппированными по ресурсам
+func (s *Storage) GetRoles(ctx context.Context) ([]model.Role, error) {
+	const op = "storage.postgres.GetRoles"
+
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT ro.id, ro.name, p.resource, p.action
+        FROM roles ro
+        LEFT JOIN role_permissions rp ON rp.role_id = ro.id
+        LEFT JOIN permissions p ON p.id = rp.permission_id
+        ORDER BY ro.id, p.resource, p.action`)
+	if err != nil {
+		return nil, dbError(op, err)
+	}
+	defer rows.Close()
+
+	roles := []model.Role{}
+	for rows.Next() {
+		var (
+			id       int
+			name     string
+			resource sql.NullString
+			action   sql.NullString
+		)
+		if err := rows.Scan(&id, &name, &resource, &action); err != nil {
+			return nil, fmt.Errorf("%s: scan error: %w", op, translateError(err))
+		}
+
+		if len(roles) == 0 || roles[len(roles)-1].ID != id {
+			roles = append(roles, model.Role{ID: id, Name: name, Permissions: []model.Permission{}})
+		}
+		if !resource.Valid {
+			continue
+		}
+
+		role := &roles[len(roles)-1]
+		if n := len(role.Permissions); n > 0 && role.Permissions[n-1].Resource == resource.String {
+			role.Permissions[n-1].Actions = append(role.Permissions[n-1].Actions, action.String)
+			continue
+		}
+		role.Permissions = append(role.Permissions, model.Permission{
+			Resource: resource.String,
+			Actions:  []string{action.String},
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(op, err)
+	}
+
+	return roles, nil
+}
+
+func (s *Storage) SetUserRole(ctx context.Context, userID int, role string) (model.User, error) {
+	const op = "storage.postgres.SetUserRole"
+
+	var roleID int
+	err := s.db.QueryRowContext(ctx, `SELECT id FROM roles WHERE name = $1`, role).Scan(&roleID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.User{}, fmt.Errorf("%s: %w", op,
+			domain.Wrap(ErrUnknownRole, domain.ErrValidation, "unknown_role", fmt.Sprintf("неизвестная роль %q", role)).WithArgs(role))
+	}
+	if err != nil {
+		return model.User{}, dbError(op, err)
+	}
+
+	var user model.User
+	err = s.db.QueryRowContext(ctx, `
+        UPDATE users SET role_id = $2
+        WHERE id = $1
+        RETURNING id, name, role_id`, userID, roleID,
+	).Scan(&user.ID, &user.Username, &user.Role)
+	if err != nil {
+		return model.User{}, dbError(op, err)
+	}
+
+	return user, nil
+}
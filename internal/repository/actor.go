@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"film-library/internal/domain"
 	"film-library/internal/model"
 	"fmt"
 )
@@ -10,7 +12,9 @@ import (
 type ActorRepository interface {
 	CreateActor(ctx context.Context, actor *model.Actor) error
 	UpdateActor(ctx context.Context, actor *model.Actor) error
-	DeleteActor(ctx context.Context, id int) error
+	PatchActor(ctx context.Context, id, version int, changes map[string]any) (int, error)
+	DeleteActor(ctx context.Context, id, version int) error
+	GetActorByID(ctx context.Context, id int) (model.ActorWithFilms, error)
 	// GetActorsWithFilms(ctx context.Context) (map[int]model.ActorWithFilms, error)
 	ActorExistsById(ctx context.Context, id int) (bool, error)
 	ActorExistsByName(ctx context.Context, name string) (bool, error)
@@ -25,39 +29,175 @@ func NewActorRepository(db *sql.DB) ActorRepository {
 func (s *Storage) CreateActor(ctx context.Context, actor *model.Actor) error {
 	const op = "storage.postgres.AddedInfoActor"
 
-	query := `INSERT INTO actors (name, gender, date_of_birth) VALUES ($1, $2, $3) RETURNING id`
-	err := s.db.QueryRowContext(ctx, query, actor.Name, actor.Gender, actor.DateOfBirth).Scan(&actor.Id)
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO people (name, gender, date_of_birth) VALUES ($1, $2, $3) RETURNING id, version`
+	err = tx.QueryRowContext(ctx, query, actor.Name, actor.Gender, actor.DateOfBirth).Scan(&actor.Id, &actor.Version)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	created := auditChange{action: model.AuditCreate, table: "people", id: actor.Id}
+	if err := created.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
 	}
 	return nil
 }
 
+// UpdateActor перезаписывает актёра, если его версия совпадает с actor.Version
+// (0 - без проверки), и записывает в actor.Version новую версию
 func (s *Storage) UpdateActor(ctx context.Context, actor *model.Actor) error {
 	const op = "storage.postgres.ChangeInfoActor"
 
-	query := `UPDATE actors SET name = $1, gender = $2, date_of_birth = $3 WHERE id = $4`
-	// Используем ExecContext вместо Exec
-	_, err := s.db.ExecContext(ctx, query, actor.Name, actor.Gender, actor.DateOfBirth, actor.Id)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	change, err := trackChange(ctx, tx, model.AuditUpdate, "people", actor.Id)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	query := `
+		UPDATE people SET name = $1, gender = $2, date_of_birth = $3, version = version + 1
+		WHERE id = $4 AND deleted_at IS NULL AND ($5 = 0 OR version = $5)
+		RETURNING version`
+	err = tx.QueryRowContext(ctx, query, actor.Name, actor.Gender, actor.DateOfBirth, actor.Id, actor.Version).Scan(&actor.Version)
+	if errors.Is(err, sql.ErrNoRows) && actor.Version != 0 {
+		return s.checkVersioned(ctx, op, "people", actor.Id)
+	}
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return dbError(op, err)
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
 	}
 
 	return nil
 }
 
-func (s *Storage) DeleteActor(ctx context.Context, id int) error {
+// actorPatchColumns - колонки, которые можно менять через PatchActor
+var actorPatchColumns = map[string]bool{"name": true, "gender": true, "date_of_birth": true}
+
+// PatchActor меняет только колонки из changes при совпадении версии и возвращает новую версию
+func (s *Storage) PatchActor(ctx context.Context, id, version int, changes map[string]any) (int, error) {
+	const op = "storage.postgres.PatchActor"
+
+	var args queryArgs
+	set, err := setClause(changes, actorPatchColumns, &args)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	change, err := trackChange(ctx, tx, model.AuditUpdate, "people", id)
+	if err != nil {
+		return 0, dbError(op, err)
+	}
+
+	query := `UPDATE people SET ` + set + `, version = version + 1 WHERE id = ` + args.add(id) +
+		` AND deleted_at IS NULL AND version = ` + args.add(version) + ` RETURNING version`
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, s.checkVersioned(ctx, op, "people", id)
+	}
+	if err != nil {
+		return 0, dbError(op, err)
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return 0, dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return version, nil
+}
+
+// DeleteActor переносит актёра в корзину, если его версия совпадает с version (0 - без проверки).
+// Роли в фильмах остаются в film_credits и вернутся при восстановлении
+func (s *Storage) DeleteActor(ctx context.Context, id, version int) error {
 	const op = "storage.postgres.DeleteInfoActor"
 
-	query := `DELETE FROM actors WHERE id = $1`
-	_, err := s.db.ExecContext(ctx, query, id)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	change, err := trackChange(ctx, tx, model.AuditDelete, "people", id)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	query := `
+		UPDATE people SET deleted_at = now(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND ($2 = 0 OR version = $2)`
+	res, err := tx.ExecContext(ctx, query, id, version)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return dbError(op, err)
+	}
+
+	err = checkAffected(op, res)
+	if errors.Is(err, domain.ErrNotFound) && version != 0 {
+		return s.checkVersioned(ctx, op, "people", id)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
 	}
 
 	return nil
 }
 
+func (s *Storage) GetActorByID(ctx context.Context, id int) (model.ActorWithFilms, error) {
+	const op = "storage.postgres.GetActorByID"
+
+	var actor model.Actor
+
+	query := `SELECT id, name, gender, date_of_birth, version FROM people WHERE id = $1 AND deleted_at IS NULL`
+	err := s.db.QueryRowContext(ctx, query, id).Scan(&actor.Id, &actor.Name, &actor.Gender, &actor.DateOfBirth, &actor.Version)
+	if err != nil {
+		return model.ActorWithFilms{}, dbError(op, err)
+	}
+
+	films, err := s.GetFilmsByActor(ctx, id)
+	if err != nil {
+		return model.ActorWithFilms{}, dbError(op, err)
+	}
+
+	return model.ActorWithFilms{Actor: actor, Films: films}, nil
+}
+
 // вспомогательная функция для сервиса, чтобы проверять наличие актера в бд
 
 func (s *Storage) ActorExistsById(ctx context.Context, id int) (bool, error) {
@@ -65,11 +205,11 @@ func (s *Storage) ActorExistsById(ctx context.Context, id int) (bool, error) {
 
 	var exists bool
 
-	query := `SELECT EXISTS(SELECT 1 FROM actors WHERE id = $1)`
+	query := `SELECT EXISTS(SELECT 1 FROM people WHERE id = $1 AND deleted_at IS NULL)`
 
 	err := s.db.QueryRowContext(ctx, query, id).Scan(&exists)
 	if err != nil {
-		return false, fmt.Errorf("%s: %w", op, err)
+		return false, dbError(op, err)
 	}
 
 	return true, nil
@@ -80,11 +220,11 @@ func (s *Storage) ActorExistsByName(ctx context.Context, name string) (bool, err
 
 	var exists bool
 
-	query := `SELECT EXISTS(SELECT 1 FROM actors WHERE name = $1)`
+	query := `SELECT EXISTS(SELECT 1 FROM people WHERE name = $1 AND deleted_at IS NULL)`
 
 	err := s.db.QueryRowContext(ctx, query, name).Scan(&exists)
 	if err != nil {
-		return false, fmt.Errorf("%s: %w", op, err)
+		return false, dbError(op, err)
 	}
 
 	return true, nil
@@ -5,10 +5,14 @@ import (
 	"database/sql"
 	"film-library/internal/model"
 	"fmt"
+	"slices"
+
+	"github.com/lib/pq"
 )
 
 type ActorMovieRepository interface {
-	GetActorsWithFilms(ctx context.Context) (map[int]model.ActorWithFilms, error)
+	GetActors(ctx context.Context, params model.ActorListParams) (model.ActorPage, error)
+	GetFilmsByActor(ctx context.Context, actorID int) ([]model.Film, error)
 }
 
 // заменил MovieRepository ----> ActorMovieRepository
@@ -18,53 +22,229 @@ func NewActorMovieRepository(db *sql.DB) ActorMovieRepository {
 	}
 }
 
-func (s *Storage) GetActorsWithFilms(ctx context.Context) (map[int]model.ActorWithFilms, error) {
-	const op = "storage.postgres.GetActorsWithFilms"
+// actorSortColumns - колонки, по которым разрешена сортировка списка актёров
+var actorSortColumns = map[string]string{
+	"name":          "a.name",
+	"date_of_birth": "a.date_of_birth",
+	"film_count":    "a.film_count",
+}
 
-	query := `
-        SELECT a.id, a.name, a.gender, a.date_of_birth, 
-               f.id, f.name, f.description, f.release_date, f.rating
-        FROM actors a
-        JOIN actor_film af ON a.id = af.actor_id
-        JOIN films f ON f.id = af.film_id`
+// GetActors отдаёт страницу актёров с числом фильмов. Фильмы присоединяются
+// через LEFT JOIN, поэтому актёры без фильмов тоже попадают в список
+func (s *Storage) GetActors(ctx context.Context, params model.ActorListParams) (model.ActorPage, error) {
+	const op = "storage.postgres.GetActors"
+
+	page := model.ActorPage{
+		Items:  []model.ActorListItem{},
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}
 
-	rows, err := s.db.QueryContext(ctx, query)
+	var args queryArgs
+	where := actorFilterConditions(params, &args)
+
+	countQuery := "SELECT count(*) FROM people a" + whereClause(where)
+	if err := s.db.QueryRowContext(ctx, countQuery, args...).Scan(&page.Total); err != nil {
+		return page, dbError(op, err)
+	}
+
+	column := actorSortColumns[params.Sort()]
+	direction := params.Direction()
+
+	backward := params.Cursor != nil && params.Cursor.Backward
+	if backward {
+		direction = oppositeDirection(direction)
+	}
+
+	// Число фильмов считается во вложенном запросе, чтобы по нему
+	// можно было сортировать и строить курсор как по обычной колонке
+	var outer []string
+	if params.Cursor != nil {
+		cmp := ">"
+		if direction == "desc" {
+			cmp = "<"
+		}
+		outer = append(outer, fmt.Sprintf("(%s, a.id) %s (%s, %s)",
+			column, cmp, args.add(params.Cursor.Key), args.add(params.Cursor.ID)))
+	}
+
+	query := fmt.Sprintf(`
+        SELECT a.id, a.name, a.gender, a.date_of_birth, a.film_count, %s::text
+        FROM (
+            SELECT a.id, a.name, a.gender, a.date_of_birth, count(f.id) AS film_count
+            FROM people a
+            LEFT JOIN film_credits af ON af.person_id = a.id AND af.role = 'actor'
+            LEFT JOIN films f ON f.id = af.film_id AND f.deleted_at IS NULL%s
+            GROUP BY a.id
+        ) a%s
+        ORDER BY %s %s, a.id %s
+        LIMIT %s OFFSET %s`,
+		column, whereClause(where), whereClause(outer), column, direction, direction,
+		args.add(params.Limit+1), args.add(params.Offset))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+		return page, dbError(op, err)
 	}
 	defer rows.Close()
 
-	actorsWithFilms := make(map[int]model.ActorWithFilms)
+	var actors []model.ActorListItem
+	var keys []string
 
 	for rows.Next() {
-		var actor model.Actor
-		var film model.Film
+		var item model.ActorListItem
+		var key string
 
-		err = rows.Scan(
-			&actor.Id, &actor.Name, &actor.Gender, &actor.DateOfBirth,
-			&film.Id, &film.Name, &film.Description, &film.Releasedate, &film.Rating,
-		)
+		err := rows.Scan(&item.Id, &item.Name, &item.Gender, &item.DateOfBirth, &item.FilmCount, &key)
 		if err != nil {
-			return nil, fmt.Errorf("%s: %w", op, err)
+			return page, dbError(op, err)
+		}
+
+		actors = append(actors, item)
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return page, dbError(op, err)
+	}
+
+	hasMore := len(actors) > params.Limit
+	if hasMore {
+		actors, keys = actors[:params.Limit], keys[:params.Limit]
+	}
+
+	if backward {
+		slices.Reverse(actors)
+		slices.Reverse(keys)
+	}
+
+	if len(actors) == 0 {
+		return page, nil
+	}
+
+	hasNext, hasPrev := hasMore, params.Cursor != nil || params.Offset > 0
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	last := len(actors) - 1
+	if hasNext {
+		page.NextCursor = model.Cursor{Sort: params.SortKey(), Key: keys[last], ID: actors[last].Id}.Encode()
+	}
+	if hasPrev {
+		page.PrevCursor = model.Cursor{Sort: params.SortKey(), Key: keys[0], ID: actors[0].Id, Backward: true}.Encode()
+	}
+
+	if params.IncludeFilms {
+		if err := s.attachActorFilms(ctx, actors); err != nil {
+			return page, dbError(op, err)
+		}
+	}
+
+	page.Items = actors
+
+	return page, nil
+}
+
+// actorOnly - участник a снимался как актёр или ещё не указан ни в одних титрах
+// (только что добавлен через /actors). Режиссёры, сценаристы и остальная группа
+// без актёрских ролей в списки актёров не попадают
+const actorOnly = `(EXISTS (SELECT 1 FROM film_credits ac WHERE ac.person_id = a.id AND ac.role = 'actor')
+        OR NOT EXISTS (SELECT 1 FROM film_credits ac WHERE ac.person_id = a.id))`
+
+// actorFilterConditions - условия WHERE для фильтров списка актёров
+func actorFilterConditions(params model.ActorListParams, args *queryArgs) []string {
+	where := []string{"a.deleted_at IS NULL", actorOnly}
+
+	if params.Gender != "" {
+		where = append(where, "a.gender = "+args.add(params.Gender))
+	}
+	// Возраст не меньше N лет - родился не позже, чем N лет назад;
+	// не больше N лет - родился позже, чем N+1 лет назад
+	if params.MinAge != nil {
+		where = append(where, "a.date_of_birth <= current_date - make_interval(years => "+args.add(*params.MinAge)+")")
+	}
+	if params.MaxAge != nil {
+		where = append(where, "a.date_of_birth > current_date - make_interval(years => "+args.add(*params.MaxAge+1)+")")
+	}
+	if params.NamePrefix != "" {
+		where = append(where, "a.name ILIKE "+args.add(escapeLike(params.NamePrefix)+"%"))
+	}
+
+	return where
+}
+
+// attachActorFilms одним запросом подгружает фильмографию для переданных актёров
+func (s *Storage) attachActorFilms(ctx context.Context, actors []model.ActorListItem) error {
+	ids := make([]int64, 0, len(actors))
+	for _, actor := range actors {
+		ids = append(ids, int64(actor.Id))
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT af.person_id, f.id, f.name, f.description, f.release_date, f.rating
+        FROM film_credits af
+        JOIN films f ON f.id = af.film_id AND f.deleted_at IS NULL
+        WHERE af.person_id = ANY($1) AND af.role = 'actor'
+        ORDER BY f.release_date, f.id`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	films := make(map[int][]model.Film, len(actors))
+	for rows.Next() {
+		var actorID int
+		var film model.Film
+
+		if err := rows.Scan(&actorID, &film.Id, &film.Name, &film.Description, &film.Releasedate, &film.Rating); err != nil {
+			return err
 		}
+		films[actorID] = append(films[actorID], film)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
 
-		// Если актёр ещё не добавлен в мапу, добавляем его
-		if _, exists := actorsWithFilms[actor.Id]; !exists {
-			actorsWithFilms[actor.Id] = model.ActorWithFilms{
-				Actor: actor,
-				Films: []model.Film{},
-			}
+	for i := range actors {
+		actors[i].Films = films[actors[i].Id]
+		if actors[i].Films == nil {
+			actors[i].Films = []model.Film{}
 		}
+	}
+
+	return nil
+}
+
+func (s *Storage) GetFilmsByActor(ctx context.Context, actorID int) ([]model.Film, error) {
+	const op = "storage.postgres.GetFilmsByActor"
 
-		// Добавляем фильм к актёру
-		actorWithFilms := actorsWithFilms[actor.Id]
-		actorWithFilms.Films = append(actorWithFilms.Films, film)
-		actorsWithFilms[actor.Id] = actorWithFilms
+	query := `
+        SELECT f.id, f.name, f.description, f.release_date, f.rating
+        FROM films f
+        JOIN film_credits af ON f.id = af.film_id AND af.role = 'actor'
+        WHERE af.person_id = $1 AND f.deleted_at IS NULL
+        ORDER BY f.release_date, f.id`
+
+	rows, err := s.db.QueryContext(ctx, query, actorID)
+	if err != nil {
+		return nil, dbError(op, err)
+	}
+	defer rows.Close()
+
+	films := make([]model.Film, 0)
+	for rows.Next() {
+		var film model.Film
+		if err := rows.Scan(&film.Id, &film.Name, &film.Description, &film.Releasedate, &film.Rating); err != nil {
+			return nil, dbError(op, err)
+		}
+		films = append(films, film)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+		return nil, dbError(op, err)
 	}
 
-	return actorsWithFilms, nil
+	return films, nil
 }
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"film-library/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestActorFilterConditions(t *testing.T) {
+	minAge, maxAge := 30, 60
+
+	var args queryArgs
+	where := actorFilterConditions(model.ActorListParams{Gender: "female", MinAge: &minAge, MaxAge: &maxAge, NamePrefix: "50%"}, &args)
+	require.Equal(t, []string{
+		"a.deleted_at IS NULL",
+		actorOnly,
+		"a.gender = $1",
+		"a.date_of_birth <= current_date - make_interval(years => $2)",
+		"a.date_of_birth > current_date - make_interval(years => $3)",
+		"a.name ILIKE $4",
+	}, where)
+	require.Equal(t, queryArgs{"female", 30, 61, `50\%%`}, args)
+}
+
+func TestStorage_GetActorsSkipsCrew(t *testing.T) {
+	s := testStorage(t)
+	ctx := context.Background()
+
+	prefix := testName("credits")
+	director, actor, fresh := prefix+" director", prefix+" actor", prefix+" fresh"
+	released := time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	var filmID, directorID, actorID int
+	require.NoError(t, s.db.QueryRow(`INSERT INTO films (name, description, release_date, rating) VALUES ($1, '', $2, 7) RETURNING id`, prefix, released).Scan(&filmID))
+	require.NoError(t, s.db.QueryRow(`INSERT INTO people (name, gender, date_of_birth) VALUES ($1, 'male', $2) RETURNING id`, director, released).Scan(&directorID))
+	require.NoError(t, s.db.QueryRow(`INSERT INTO people (name, gender, date_of_birth) VALUES ($1, 'male', $2) RETURNING id`, actor, released).Scan(&actorID))
+	mustExec(t, s, `INSERT INTO people (name, gender, date_of_birth) VALUES ($1, 'female', $2)`, fresh, released)
+	t.Cleanup(func() {
+		mustExec(t, s, `DELETE FROM films WHERE id = $1`, filmID)
+		mustExec(t, s, `DELETE FROM people WHERE name LIKE $1`, prefix+"%")
+	})
+
+	// Актёр, который ещё и режиссёр, остаётся актёром
+	mustExec(t, s, `INSERT INTO film_credits (film_id, person_id, role) VALUES ($1, $2, 'director'), ($1, $3, 'director'), ($1, $3, 'actor')`,
+		filmID, directorID, actorID)
+
+	page, err := s.GetActors(ctx, model.ActorListParams{NamePrefix: prefix, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 2, page.Total)
+
+	var names []string
+	for _, item := range page.Items {
+		names = append(names, item.Name)
+	}
+	require.Equal(t, []string{actor, fresh}, names)
+}
+
+func TestStorage_GetTrashSkipsCrew(t *testing.T) {
+	s := testStorage(t)
+	ctx := context.Background()
+
+	prefix := testName("trash credits")
+	director, actor := prefix+" director", prefix+" actor"
+	released := time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	var filmID, directorID, actorID int
+	require.NoError(t, s.db.QueryRow(`INSERT INTO films (name, description, release_date, rating) VALUES ($1, '', $2, 7) RETURNING id`, prefix, released).Scan(&filmID))
+	require.NoError(t, s.db.QueryRow(`INSERT INTO people (name, gender, date_of_birth) VALUES ($1, 'male', $2) RETURNING id`, director, released).Scan(&directorID))
+	require.NoError(t, s.db.QueryRow(`INSERT INTO people (name, gender, date_of_birth) VALUES ($1, 'male', $2) RETURNING id`, actor, released).Scan(&actorID))
+	t.Cleanup(func() {
+		mustExec(t, s, `DELETE FROM films WHERE id = $1`, filmID)
+		mustExec(t, s, `DELETE FROM people WHERE name LIKE $1`, prefix+"%")
+	})
+
+	mustExec(t, s, `INSERT INTO film_credits (film_id, person_id, role) VALUES ($1, $2, 'director'), ($1, $3, 'actor')`,
+		filmID, directorID, actorID)
+	mustExec(t, s, `UPDATE people SET deleted_at = now() WHERE id IN ($1, $2)`, directorID, actorID)
+
+	trash, err := s.GetTrash(ctx)
+	require.NoError(t, err)
+
+	var names []string
+	for _, item := range trash.Actors {
+		if strings.HasPrefix(item.Name, prefix) {
+			names = append(names, item.Name)
+		}
+	}
+	require.Equal(t, []string{actor}, names)
+}
@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"film-library/internal/model"
+	authmid "film-library/internal/utils/auth_mid"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+type AuditRepository interface {
+	GetAuditLog(ctx context.Context, filter model.AuditFilter) (model.AuditPage, error)
+}
+
+func NewAuditRepository(db *sql.DB) AuditRepository {
+	return &Storage{
+		db: db,
+	}
+}
+
+// auditSnapshots - поля строки r, которые попадают в журнал. Состав и метки фильма входят
+// в его снимок, поэтому их изменения видны как изменение полей actors, crew, genres, countries, tags
+var auditSnapshots = map[string]string{
+	"films": `r.id, r.name, r.description, r.release_date, r.rating, r.version, r.deleted_at,
+        (SELECT COALESCE(jsonb_agg(jsonb_build_object(
+                    'actor_id', af.person_id,
+                    'character', af.character_name,
+                    'billing_order', af.position) ORDER BY af.position, af.person_id), '[]')
+         FROM film_credits af WHERE af.film_id = r.id AND af.role = 'actor') AS actors,
+        (SELECT COALESCE(jsonb_agg(jsonb_build_object(
+                    'person_id', fc.person_id,
+                    'role', fc.role,
+                    'position', fc.position) ORDER BY fc.role, fc.position, fc.person_id), '[]')
+         FROM film_credits fc WHERE fc.film_id = r.id AND fc.role <> 'actor') AS crew,
+        (SELECT COALESCE(jsonb_agg(g.name ORDER BY g.name), '[]')
+         FROM film_genres fg JOIN genres g ON g.id = fg.genre_id WHERE fg.film_id = r.id) AS genres,
+        (SELECT COALESCE(jsonb_agg(fc.country_code ORDER BY fc.country_code), '[]')
+         FROM film_countries fc WHERE fc.film_id = r.id) AS countries,
+        (SELECT COALESCE(jsonb_agg(t.name ORDER BY t.name), '[]')
+         FROM film_tags ft JOIN tags t ON t.id = ft.tag_id WHERE ft.film_id = r.id) AS tags`,
+	"people":    `r.id, r.name, r.gender, r.date_of_birth, r.version, r.deleted_at`,
+	"genres":    `r.id, r.name`,
+	"countries": `r.code, r.name`,
+	"tags":      `r.id, r.name`,
+}
+
+// auditEntities - сущность журнала для таблицы
+var auditEntities = map[string]string{
+	"films":     model.EntityFilm,
+	"people":    model.EntityActor,
+	"genres":    model.EntityGenre,
+	"countries": model.EntityCountry,
+	"tags":      model.EntityTag,
+}
+
+// auditKeys - колонка с ключом строки, если это не id. Строковый ключ
+// пишется в журнал в entity_code, числовой - в entity_id
+var auditKeys = map[string]string{
+	"countries": "code",
+}
+
+func auditKey(table string) string {
+	if key, ok := auditKeys[table]; ok {
+		return key
+	}
+	return "id"
+}
+
+// auditChange - изменение строки, которое нужно записать в журнал
+type auditChange struct {
+	action string
+	table  string
+	id     any // id строки; у стран - код
+	before map[string]any
+}
+
+// trackChange блокирует строку до конца транзакции и запоминает её состояние до изменения
+func trackChange(ctx context.Context, tx *sql.Tx, action, table string, id any) (*auditChange, error) {
+	var locked int
+	err := tx.QueryRowContext(ctx, `SELECT 1 FROM `+table+` WHERE `+auditKey(table)+` = $1 FOR UPDATE`, id).Scan(&locked)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to lock %s: %w", table, translateError(err))
+	}
+
+	before, err := snapshot(ctx, tx, table, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auditChange{action: action, table: table, id: id, before: before}, nil
+}
+
+// record дочитывает состояние строки после изменения и пишет в журнал изменившиеся поля
+func (c *auditChange) record(ctx context.Context, tx *sql.Tx) error {
+	after, err := snapshot(ctx, tx, c.table, c.id)
+	if err != nil {
+		return err
+	}
+
+	before, after := auditDiff(c.before, after)
+
+	var entityID, entityCode any = c.id, nil
+	if code, ok := c.id.(string); ok {
+		entityID, entityCode = nil, code
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO audit_log (user_id, action, entity, entity_id, entity_code, before, after)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		auditUser(ctx), c.action, auditEntities[c.table], entityID, entityCode, jsonOrNull(before), jsonOrNull(after),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", translateError(err))
+	}
+	return nil
+}
+
+// auditUser - автор изменения из контекста запроса; NULL для фоновых задач
+func auditUser(ctx context.Context) sql.NullInt64 {
+	if id, ok := authmid.UserID(ctx); ok {
+		return sql.NullInt64{Int64: int64(id), Valid: true}
+	}
+	return sql.NullInt64{}
+}
+
+// snapshot - текущее состояние строки для журнала; nil, если строки нет
+func snapshot(ctx context.Context, tx *sql.Tx, table string, id any) (map[string]any, error) {
+	var raw []byte
+	err := tx.QueryRowContext(ctx,
+		`SELECT to_jsonb(t) FROM (SELECT `+auditSnapshots[table]+` FROM `+table+` r WHERE r.`+auditKey(table)+` = $1) t`, id,
+	).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s snapshot: %w", table, translateError(err))
+	}
+
+	var row map[string]any
+	if err := json.Unmarshal(raw, &row); err != nil {
+		return nil, fmt.Errorf("failed to decode %s snapshot: %w", table, err)
+	}
+	return row, nil
+}
+
+// auditDiff оставляет в before и after только различающиеся поля.
+// Если одной из сторон нет (создание, окончательное удаление), другая остаётся целиком
+func auditDiff(before, after map[string]any) (map[string]any, map[string]any) {
+	if before == nil || after == nil {
+		return before, after
+	}
+
+	changedBefore := make(map[string]any)
+	changedAfter := make(map[string]any)
+	for key, old := range before {
+		if value, ok := after[key]; !ok || !reflect.DeepEqual(old, value) {
+			changedBefore[key] = old
+			changedAfter[key] = after[key]
+		}
+	}
+	for key, value := range after {
+		if _, ok := before[key]; !ok {
+			changedBefore[key] = nil
+			changedAfter[key] = value
+		}
+	}
+
+	return changedBefore, changedAfter
+}
+
+func jsonOrNull(row map[string]any) any {
+	if row == nil {
+		return nil
+	}
+	raw, _ := json.Marshal(row)
+	return raw
+}
+
+// GetAuditLog возвращает страницу журнала под фильтром, новые записи первыми
+func (s *Storage) GetAuditLog(ctx context.Context, filter model.AuditFilter) (model.AuditPage, error) {
+	const op = "storage.postgres.GetAuditLog"
+
+	page := model.AuditPage{
+		Items:  []model.AuditEntry{},
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}
+
+	var args queryArgs
+	where := auditFilterConditions(filter, &args)
+
+	query := `
+        SELECT id, user_id, action, entity, COALESCE(entity_id, 0), COALESCE(entity_code, ''),
+               before, after, created_at, count(*) OVER ()
+        FROM audit_log` + whereClause(where) + `
+        ORDER BY created_at DESC, id DESC
+        LIMIT ` + args.add(filter.Limit) + ` OFFSET ` + args.add(filter.Offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return page, dbError(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			entry         model.AuditEntry
+			userID        sql.NullInt64
+			before, after []byte
+		)
+		err := rows.Scan(&entry.Id, &userID, &entry.Action, &entry.Entity, &entry.EntityID, &entry.EntityCode,
+			&before, &after, &entry.CreatedAt, &page.Total)
+		if err != nil {
+			return page, fmt.Errorf("%s: scan error: %w", op, translateError(err))
+		}
+
+		if userID.Valid {
+			id := int(userID.Int64)
+			entry.UserID = &id
+		}
+		entry.Before = rawJSON(before)
+		entry.After = rawJSON(after)
+		page.Items = append(page.Items, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return page, dbError(op, err)
+	}
+
+	// Страница за пределами выдачи: общее количество считаем отдельно
+	if len(page.Items) == 0 && filter.Offset > 0 {
+		var countArgs queryArgs
+		countQuery := "SELECT count(*) FROM audit_log" + whereClause(auditFilterConditions(filter, &countArgs))
+		if err := s.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&page.Total); err != nil {
+			return page, dbError(op, err)
+		}
+	}
+
+	return page, nil
+}
+
+// auditFilterConditions - условия WHERE для фильтров журнала
+func auditFilterConditions(filter model.AuditFilter, args *queryArgs) []string {
+	var where []string
+
+	if filter.UserID != 0 {
+		where = append(where, "user_id = "+args.add(filter.UserID))
+	}
+	if filter.Entity != "" {
+		where = append(where, "entity = "+args.add(filter.Entity))
+	}
+	if filter.EntityID != 0 {
+		where = append(where, "entity_id = "+args.add(filter.EntityID))
+	}
+	if filter.EntityCode != "" {
+		where = append(where, "entity_code = "+args.add(filter.EntityCode))
+	}
+	if filter.From != nil {
+		where = append(where, "created_at >= "+args.add(*filter.From))
+	}
+	if filter.To != nil {
+		where = append(where, "created_at < "+args.add(*filter.To))
+	}
+
+	return where
+}
+
+// rawJSON - JSONB-колонка как есть; NULL превращается в JSON null
+func rawJSON(raw []byte) json.RawMessage {
+	if raw == nil {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(raw)
+}
+
+// purgeWithAudit окончательно удаляет строки таблицы из корзины старше before
+// и пишет по записи журнала на каждую. Возвращает число удалённых строк
+func purgeWithAudit(ctx context.Context, tx *sql.Tx, table string, before time.Time) (int64, error) {
+	res, err := tx.ExecContext(ctx, `
+        WITH purged AS (
+            DELETE FROM `+table+` r WHERE r.deleted_at < $1
+            RETURNING r.id, (SELECT to_jsonb(t) FROM (SELECT `+auditSnapshots[table]+`) t) AS before
+        )
+        INSERT INTO audit_log (action, entity, entity_id, before)
+        SELECT $2, $3, id, before FROM purged`,
+		before, model.AuditPurge, auditEntities[table],
+	)
+	if err != nil {
+		return 0, translateError(err)
+	}
+	return res.RowsAffected()
+}
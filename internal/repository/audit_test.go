@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"film-library/internal/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditDiff(t *testing.T) {
+	before := map[string]any{
+		"id": float64(1), "name": "Matrix", "rating": float64(8), "version": float64(1),
+		"actors": []any{map[string]any{"actor_id": float64(2), "character": "Neo"}},
+	}
+	after := map[string]any{
+		"id": float64(1), "name": "Matrix", "rating": float64(9), "version": float64(2),
+		"actors": []any{map[string]any{"actor_id": float64(2), "character": "Neo"}},
+	}
+
+	changedBefore, changedAfter := auditDiff(before, after)
+	require.Equal(t, map[string]any{"rating": float64(8), "version": float64(1)}, changedBefore)
+	require.Equal(t, map[string]any{"rating": float64(9), "version": float64(2)}, changedAfter)
+
+	// При создании записи "до" нет, "после" остаётся целиком
+	changedBefore, changedAfter = auditDiff(nil, after)
+	require.Nil(t, changedBefore)
+	require.Equal(t, after, changedAfter)
+}
+
+func TestAuditFilterConditions(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	var args queryArgs
+	where := auditFilterConditions(model.AuditFilter{UserID: 3, Entity: model.EntityFilm, EntityID: 7, From: &from}, &args)
+	require.Equal(t, []string{"user_id = $1", "entity = $2", "entity_id = $3", "created_at >= $4"}, where)
+	require.Equal(t, queryArgs{3, model.EntityFilm, 7, from}, args)
+}
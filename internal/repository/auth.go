@@ -4,13 +4,28 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"film-library/internal/domain"
 	"film-library/internal/model"
 	"fmt"
+	"time"
+)
+
+var (
+	// ErrTokenReused - refresh-токен уже был обменян или отозван
+	ErrTokenReused = errors.New("refresh token reused")
+	// ErrTokenExpired - срок действия refresh-токена истёк
+	ErrTokenExpired = errors.New("refresh token expired")
 )
 
 type AuthRepository interface {
 	CreateUser(ctx context.Context, user *model.User) error
 	VerifyUser(ctx context.Context, username string) (*model.User, error)
+	GetUserByID(ctx context.Context, id int) (*model.User, error)
+	SaveRefreshToken(ctx context.Context, token model.RefreshToken) error
+	ConsumeRefreshToken(ctx context.Context, hash string) (model.RefreshToken, error)
+	RevokeSession(ctx context.Context, userID int, refreshHash, accessJTI string, accessExpiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	PurgeExpiredTokens(ctx context.Context) (model.TokenPurgeResult, error) // удаление истёкших записей
 }
 
 func NewAuthRepository(db *sql.DB) AuthRepository {
@@ -19,15 +34,21 @@ func NewAuthRepository(db *sql.DB) AuthRepository {
 	}
 }
 
+// CreateUser регистрирует пользователя; занятое имя возвращается как ErrConflict
 func (s *Storage) CreateUser(ctx context.Context, user *model.User) error {
+	const op = "storage.postgres.CreateUser"
+
 	err := s.db.QueryRowContext(ctx, `
 		INSERT INTO users (name, password, role_id)
 		VALUES ($1, $2, $3)
 		RETURNING id
 	`, user.Username, user.Password, user.Role,
 	).Scan(&user.ID)
+	if err != nil {
+		return dbError(op, err)
+	}
 
-	return err
+	return nil
 }
 
 func (s *Storage) VerifyUser(ctx context.Context, username string) (*model.User, error) {
@@ -40,11 +61,195 @@ func (s *Storage) VerifyUser(ctx context.Context, username string) (*model.User,
 	`, username).Scan(&user.ID, &user.Username, &user.Password, &user.Role)
 
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("user not found: %w", err)
-		}
-		return nil, fmt.Errorf("failed to query user: %w", err)
+		return nil, fmt.Errorf("failed to query user: %w", translateError(err))
+	}
+
+	return &user, nil
+}
+
+func (s *Storage) GetUserByID(ctx context.Context, id int) (*model.User, error) {
+	var user model.User
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, name, role_id
+		FROM users
+		WHERE id = $1
+	`, id).Scan(&user.ID, &user.Username, &user.Role)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user: %w", translateError(err))
 	}
 
 	return &user, nil
 }
+
+func (s *Storage) SaveRefreshToken(ctx context.Context, token model.RefreshToken) error {
+	const op = "storage.postgres.SaveRefreshToken"
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO refresh_tokens (token_hash, user_id, family_id, access_jti, access_expires_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, token.Hash, token.UserID, token.FamilyID, token.AccessJTI, token.AccessExpiresAt, token.ExpiresAt)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	return nil
+}
+
+// ConsumeRefreshToken помечает refresh-токен использованным и возвращает его.
+// Повторное предъявление уже использованного или отозванного токена считается
+// кражей: вся цепочка ротаций отзывается вместе с её access-токенами
+func (s *Storage) ConsumeRefreshToken(ctx context.Context, hash string) (model.RefreshToken, error) {
+	const op = "storage.postgres.ConsumeRefreshToken"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.RefreshToken{}, fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	token := model.RefreshToken{Hash: hash}
+	var usedAt, revokedAt sql.NullTime
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT user_id, family_id, access_jti, access_expires_at, expires_at, used_at, revoked_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+		FOR UPDATE
+	`, hash).Scan(&token.UserID, &token.FamilyID, &token.AccessJTI, &token.AccessExpiresAt,
+		&token.ExpiresAt, &usedAt, &revokedAt)
+	if err != nil {
+		return model.RefreshToken{}, dbError(op, err)
+	}
+
+	if usedAt.Valid || revokedAt.Valid {
+		if err := revokeFamily(ctx, tx, token.FamilyID); err != nil {
+			return model.RefreshToken{}, dbError(op, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return model.RefreshToken{}, fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+		}
+		return model.RefreshToken{}, fmt.Errorf("%s: %w", op,
+			domain.Wrap(ErrTokenReused, domain.ErrUnauthorized, "refresh_token_reused", "refresh-токен уже использован, сеанс отозван"))
+	}
+
+	if time.Now().After(token.ExpiresAt) {
+		return model.RefreshToken{}, fmt.Errorf("%s: %w", op,
+			domain.Wrap(ErrTokenExpired, domain.ErrUnauthorized, "refresh_token_expired", "срок действия refresh-токена истёк"))
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE refresh_tokens SET used_at = now() WHERE token_hash = $1`, hash); err != nil {
+		return model.RefreshToken{}, dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.RefreshToken{}, fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return token, nil
+}
+
+// RevokeSession завершает сеанс: отзывает текущий access-токен и,
+// если refresh-токен принадлежит пользователю, всю его цепочку
+func (s *Storage) RevokeSession(ctx context.Context, userID int, refreshHash, accessJTI string, accessExpiresAt time.Time) error {
+	const op = "storage.postgres.RevokeSession"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO revoked_tokens (jti, expires_at)
+		VALUES ($1, $2)
+		ON CONFLICT (jti) DO NOTHING
+	`, accessJTI, accessExpiresAt)
+	if err != nil {
+		return fmt.Errorf("%s: failed to revoke access token: %w", op, translateError(err))
+	}
+
+	if refreshHash != "" {
+		var familyID string
+		err := tx.QueryRowContext(ctx, `
+			SELECT family_id FROM refresh_tokens
+			WHERE token_hash = $1 AND user_id = $2
+		`, refreshHash, userID).Scan(&familyID)
+
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			// чужой или неизвестный токен: выход всё равно считается успешным
+		case err != nil:
+			return dbError(op, err)
+		default:
+			if err := revokeFamily(ctx, tx, familyID); err != nil {
+				return dbError(op, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return nil
+}
+
+func (s *Storage) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	const op = "storage.postgres.IsTokenRevoked"
+
+	var revoked bool
+	err := s.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = $1)`, jti).Scan(&revoked)
+	if err != nil {
+		return false, dbError(op, err)
+	}
+
+	return revoked, nil
+}
+
+// PurgeExpiredTokens удаляет истёкшие refresh-токены и записи об отозванных
+// access-токенах: предъявить их уже нельзя, проверять отзыв не нужно
+func (s *Storage) PurgeExpiredTokens(ctx context.Context) (model.TokenPurgeResult, error) {
+	const op = "storage.postgres.PurgeExpiredTokens"
+
+	var result model.TokenPurgeResult
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM refresh_tokens WHERE expires_at < now()`)
+	if err != nil {
+		return model.TokenPurgeResult{}, dbError(op, err)
+	}
+	if result.RefreshTokens, err = res.RowsAffected(); err != nil {
+		return model.TokenPurgeResult{}, dbError(op, err)
+	}
+
+	res, err = s.db.ExecContext(ctx, `DELETE FROM revoked_tokens WHERE expires_at < now()`)
+	if err != nil {
+		return model.TokenPurgeResult{}, dbError(op, err)
+	}
+	if result.RevokedTokens, err = res.RowsAffected(); err != nil {
+		return model.TokenPurgeResult{}, dbError(op, err)
+	}
+
+	return result, nil
+}
+
+// revokeFamily отзывает все refresh-токены цепочки и ещё действующие access-токены, выданные вместе с ними
+func revokeFamily(ctx context.Context, tx *sql.Tx, familyID string) error {
+	_, err := tx.ExecContext(ctx, `
+		WITH family AS (
+			UPDATE refresh_tokens
+			SET revoked_at = COALESCE(revoked_at, now())
+			WHERE family_id = $1
+			RETURNING access_jti, access_expires_at
+		)
+		INSERT INTO revoked_tokens (jti, expires_at)
+		SELECT access_jti, access_expires_at FROM family
+		WHERE access_expires_at > now()
+		ON CONFLICT (jti) DO NOTHING
+	`, familyID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token family: %w", translateError(err))
+	}
+	return nil
+}
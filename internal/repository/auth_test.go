@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage_CreateUser(t *testing.T) {
+	s := testStorage(t)
+	ctx := context.Background()
+
+	user := model.User{Username: testName("user"), Password: "hash", Role: int(model.RoleUser)}
+	require.NoError(t, s.CreateUser(ctx, &user))
+	require.NotZero(t, user.ID)
+	t.Cleanup(func() { mustExec(t, s, `DELETE FROM users WHERE id = $1`, user.ID) })
+
+	// Занятое имя - конфликт, а не ошибка сервера
+	duplicate := model.User{Username: user.Username, Password: "other", Role: int(model.RoleUser)}
+	err := s.CreateUser(ctx, &duplicate)
+	require.ErrorIs(t, err, domain.ErrConflict)
+}
+
+func TestStorage_PurgeExpiredTokens(t *testing.T) {
+	s := testStorage(t)
+	ctx := context.Background()
+
+	user := model.User{Username: testName("user"), Password: "hash", Role: int(model.RoleUser)}
+	require.NoError(t, s.CreateUser(ctx, &user))
+	t.Cleanup(func() { mustExec(t, s, `DELETE FROM users WHERE id = $1`, user.ID) })
+
+	now := time.Now()
+	expired := model.RefreshToken{
+		Hash: fmt.Sprintf("%064d", now.UnixNano()), UserID: user.ID, FamilyID: testName("family"),
+		AccessJTI: testName("expired"), AccessExpiresAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour),
+	}
+	active := model.RefreshToken{
+		Hash: fmt.Sprintf("%064d", now.UnixNano()+1), UserID: user.ID, FamilyID: testName("family"),
+		AccessJTI: testName("active"), AccessExpiresAt: now.Add(time.Hour), ExpiresAt: now.Add(24 * time.Hour),
+	}
+	require.NoError(t, s.SaveRefreshToken(ctx, expired))
+	require.NoError(t, s.SaveRefreshToken(ctx, active))
+	require.NoError(t, s.RevokeSession(ctx, user.ID, "", expired.AccessJTI, expired.AccessExpiresAt))
+	require.NoError(t, s.RevokeSession(ctx, user.ID, "", active.AccessJTI, active.AccessExpiresAt))
+	t.Cleanup(func() {
+		mustExec(t, s, `DELETE FROM revoked_tokens WHERE jti = ANY($1)`, pq.Array([]string{expired.AccessJTI, active.AccessJTI}))
+	})
+
+	result, err := s.PurgeExpiredTokens(ctx)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, result.RefreshTokens, int64(1))
+	require.GreaterOrEqual(t, result.RevokedTokens, int64(1))
+
+	// Истёкшие записи удалены, действующие остались
+	_, err = s.ConsumeRefreshToken(ctx, expired.Hash)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	revoked, err := s.IsTokenRevoked(ctx, expired.AccessJTI)
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	revoked, err = s.IsTokenRevoked(ctx, active.AccessJTI)
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	_, err = s.ConsumeRefreshToken(ctx, active.Hash)
+	require.NoError(t, err)
+}
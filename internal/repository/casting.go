@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	"fmt"
+	"slices"
+
+	"github.com/lib/pq"
+)
+
+// ErrMissingActors - в составе указаны актёры, которых нет в базе
+var ErrMissingActors = errors.New("actors not found")
+
+type CastingRepository interface {
+	GetCast(ctx context.Context, filmID int) ([]model.CastMember, error)
+	ReplaceCast(ctx context.Context, filmID int, entries []model.CastingEntry) error
+	AddCastMember(ctx context.Context, filmID int, entry model.CastingEntry) error
+	RemoveCastMember(ctx context.Context, filmID, actorID int) error
+}
+
+func NewCastingRepository(db *sql.DB) CastingRepository {
+	return &Storage{
+		db: db,
+	}
+}
+
+func (s *Storage) GetCast(ctx context.Context, filmID int) ([]model.CastMember, error) {
+	const op = "storage.postgres.GetCast"
+
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM films WHERE id = $1 AND deleted_at IS NULL)`, filmID).Scan(&exists); err != nil {
+		return nil, dbError(op, err)
+	}
+	if !exists {
+		return nil, dbError(op, sql.ErrNoRows)
+	}
+
+	films := []model.Film{{Id: filmID}}
+	if err := s.attachFilmActors(ctx, films); err != nil {
+		return nil, dbError(op, err)
+	}
+
+	return films[0].ListActors, nil
+}
+
+// ReplaceCast приводит состав фильма к переданному списку: в одной транзакции
+// удаляет лишние связи, добавляет новые и обновляет изменившиеся роли
+func (s *Storage) ReplaceCast(ctx context.Context, filmID int, entries []model.CastingEntry) error {
+	const op = "storage.postgres.ReplaceCast"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	if err := lockFilm(ctx, tx, filmID); err != nil {
+		return dbError(op, err)
+	}
+
+	change, err := trackChange(ctx, tx, model.AuditUpdate, "films", filmID)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	actorIDs := make([]int64, 0, len(entries))
+	for _, entry := range entries {
+		actorIDs = append(actorIDs, int64(entry.ActorID))
+	}
+
+	if err := checkActorsExist(ctx, tx, actorIDs); err != nil {
+		return dbError(op, err)
+	}
+
+	// Роли актёров из корзины не трогаем, чтобы они вернулись при восстановлении
+	rows, err := tx.QueryContext(ctx, `
+        SELECT af.person_id, COALESCE(af.character_name, ''), COALESCE(af.position, 0)
+        FROM film_credits af
+        JOIN people a ON a.id = af.person_id AND a.deleted_at IS NULL
+        WHERE af.film_id = $1 AND af.role = 'actor'`, filmID)
+	if err != nil {
+		return fmt.Errorf("%s: failed to load cast: %w", op, translateError(err))
+	}
+
+	current := make(map[int]model.CastingEntry)
+	for rows.Next() {
+		var entry model.CastingEntry
+		if err := rows.Scan(&entry.ActorID, &entry.Character, &entry.BillingOrder); err != nil {
+			rows.Close()
+			return fmt.Errorf("%s: failed to load cast: %w", op, translateError(err))
+		}
+		current[entry.ActorID] = entry
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%s: failed to load cast: %w", op, translateError(err))
+	}
+
+	// Порядок в титрах по умолчанию - позиция в переданном списке
+	for i := range entries {
+		if entries[i].BillingOrder == 0 {
+			entries[i].BillingOrder = i + 1
+		}
+	}
+
+	var removed []int64
+	for actorID := range current {
+		if !containsActor(entries, actorID) {
+			removed = append(removed, int64(actorID))
+		}
+	}
+
+	if len(removed) > 0 {
+		_, err := tx.ExecContext(ctx, `DELETE FROM film_credits WHERE film_id = $1 AND person_id = ANY($2) AND role = 'actor'`,
+			filmID, pq.Array(removed))
+		if err != nil {
+			return fmt.Errorf("%s: failed to delete film-actor links: %w", op, translateError(err))
+		}
+	}
+
+	for _, entry := range entries {
+		old, exists := current[entry.ActorID]
+		if exists && old == entry {
+			continue
+		}
+
+		if err := upsertCastMember(ctx, tx, filmID, entry); err != nil {
+			return dbError(op, err)
+		}
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return nil
+}
+
+func (s *Storage) AddCastMember(ctx context.Context, filmID int, entry model.CastingEntry) error {
+	const op = "storage.postgres.AddCastMember"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	if err := lockFilm(ctx, tx, filmID); err != nil {
+		return dbError(op, err)
+	}
+
+	change, err := trackChange(ctx, tx, model.AuditUpdate, "films", filmID)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	if err := checkActorsExist(ctx, tx, []int64{int64(entry.ActorID)}); err != nil {
+		return dbError(op, err)
+	}
+
+	// Без явного порядка ставим актёра в конец титров
+	if entry.BillingOrder == 0 {
+		err := tx.QueryRowContext(ctx, `
+            SELECT COALESCE(MAX(position), 0) + 1
+            FROM film_credits
+            WHERE film_id = $1 AND person_id <> $2 AND role = 'actor'`, filmID, entry.ActorID,
+		).Scan(&entry.BillingOrder)
+		if err != nil {
+			return dbError(op, err)
+		}
+	}
+
+	if err := upsertCastMember(ctx, tx, filmID, entry); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return nil
+}
+
+func (s *Storage) RemoveCastMember(ctx context.Context, filmID, actorID int) error {
+	const op = "storage.postgres.RemoveCastMember"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	change, err := trackChange(ctx, tx, model.AuditUpdate, "films", filmID)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM film_credits WHERE film_id = $1 AND person_id = $2 AND role = 'actor'`, filmID, actorID)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return dbError(op, err)
+	}
+	if affected == 0 {
+		return dbError(op, sql.ErrNoRows)
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return nil
+}
+
+// lockFilm блокирует строку фильма до конца транзакции,
+// чтобы параллельные изменения состава шли друг за другом
+func lockFilm(ctx context.Context, tx *sql.Tx, filmID int) error {
+	var id int
+	err := tx.QueryRowContext(ctx, `SELECT id FROM films WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, filmID).Scan(&id)
+	if err != nil {
+		return fmt.Errorf("failed to lock film: %w", translateError(err))
+	}
+	return nil
+}
+
+// checkActorsExist проверяет, что все актёры есть в базе, и блокирует их от удаления
+func checkActorsExist(ctx context.Context, tx *sql.Tx, actorIDs []int64) error {
+	missing, err := missingPeople(ctx, tx, actorIDs)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return domain.Wrap(ErrMissingActors, domain.ErrValidation, "unknown_actors",
+			fmt.Sprintf("в составе указаны несуществующие актёры: %v", missing)).WithArgs(missing)
+	}
+
+	return nil
+}
+
+// missingPeople возвращает идентификаторы, которых нет среди живых участников,
+// а найденных блокирует от удаления до конца транзакции
+func missingPeople(ctx context.Context, tx *sql.Tx, ids []int64) ([]int64, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM people WHERE id = ANY($1) AND deleted_at IS NULL FOR SHARE`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check people: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	found := make(map[int64]struct{}, len(ids))
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to check people: %w", translateError(err))
+		}
+		found[id] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to check people: %w", translateError(err))
+	}
+
+	var missing []int64
+	for _, id := range ids {
+		if _, ok := found[id]; !ok && !slices.Contains(missing, id) {
+			missing = append(missing, id)
+		}
+	}
+
+	return missing, nil
+}
+
+func upsertCastMember(ctx context.Context, tx *sql.Tx, filmID int, entry model.CastingEntry) error {
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO film_credits (film_id, person_id, role, character_name, position)
+        VALUES ($1, $2, 'actor', NULLIF($3, ''), NULLIF($4, 0))
+        ON CONFLICT (film_id, person_id, role) DO UPDATE
+        SET character_name = EXCLUDED.character_name,
+            position = EXCLUDED.position`,
+		filmID, entry.ActorID, entry.Character, entry.BillingOrder,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save film-actor link: %w", translateError(err))
+	}
+	return nil
+}
+
+// Вспомогательная функция для проверки, есть ли актёр в новом составе
+func containsActor(entries []model.CastingEntry, actorID int) bool {
+	for _, e := range entries {
+		if e.ActorID == actorID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// ErrMissingPeople - в титрах указаны участники, которых нет в базе
+var ErrMissingPeople = errors.New("people not found")
+
+type CreditRepository interface {
+	GetCredits(ctx context.Context, filmID int) ([]model.Credit, error)
+	ReplaceCredits(ctx context.Context, filmID int, credits []model.Credit) error
+	AddCredit(ctx context.Context, filmID int, credit model.Credit) error
+	RemoveCredit(ctx context.Context, filmID, personID int, role string) error
+	GetFilmography(ctx context.Context, personID int) (model.Filmography, error)
+}
+
+func NewCreditRepository(db *sql.DB) CreditRepository {
+	return &Storage{
+		db: db,
+	}
+}
+
+// creditOrder - порядок титров: роли как в model.CreditRoles, внутри роли по position
+const creditOrder = `array_position($2::text[], fc.role), fc.position NULLS LAST, p.name, p.id`
+
+func (s *Storage) GetCredits(ctx context.Context, filmID int) ([]model.Credit, error) {
+	const op = "storage.postgres.GetCredits"
+
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM films WHERE id = $1 AND deleted_at IS NULL)`, filmID).Scan(&exists); err != nil {
+		return nil, dbError(op, err)
+	}
+	if !exists {
+		return nil, dbError(op, sql.ErrNoRows)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT p.id, p.name, fc.role, COALESCE(fc.character_name, ''), COALESCE(fc.position, 0)
+        FROM film_credits fc
+        JOIN people p ON p.id = fc.person_id AND p.deleted_at IS NULL
+        WHERE fc.film_id = $1
+        ORDER BY `+creditOrder, filmID, pq.Array(model.CreditRoles))
+	if err != nil {
+		return nil, dbError(op, err)
+	}
+	defer rows.Close()
+
+	credits := make([]model.Credit, 0)
+	for rows.Next() {
+		var c model.Credit
+		if err := rows.Scan(&c.PersonID, &c.Name, &c.Role, &c.Character, &c.Position); err != nil {
+			return nil, dbError(op, err)
+		}
+		credits = append(credits, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError(op, err)
+	}
+
+	return credits, nil
+}
+
+// ReplaceCredits заменяет все титры фильма, включая актёров, одной транзакцией.
+// Титры участников из корзины не трогаются, чтобы вернуться при восстановлении
+func (s *Storage) ReplaceCredits(ctx context.Context, filmID int, credits []model.Credit) error {
+	const op = "storage.postgres.ReplaceCredits"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	if err := lockFilm(ctx, tx, filmID); err != nil {
+		return dbError(op, err)
+	}
+
+	change, err := trackChange(ctx, tx, model.AuditUpdate, "films", filmID)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	if err := checkPeopleExist(ctx, tx, credits); err != nil {
+		return dbError(op, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM film_credits fc
+        USING people p
+        WHERE fc.film_id = $1 AND p.id = fc.person_id AND p.deleted_at IS NULL`, filmID)
+	if err != nil {
+		return fmt.Errorf("%s: failed to delete credits: %w", op, translateError(err))
+	}
+
+	// Порядок по умолчанию - позиция в переданном списке среди участников той же роли
+	next := make(map[string]int)
+	for _, c := range credits {
+		next[c.Role]++
+		if c.Position == 0 {
+			c.Position = next[c.Role]
+		}
+		if err := upsertCredit(ctx, tx, filmID, c); err != nil {
+			return dbError(op, err)
+		}
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return nil
+}
+
+func (s *Storage) AddCredit(ctx context.Context, filmID int, credit model.Credit) error {
+	const op = "storage.postgres.AddCredit"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	if err := lockFilm(ctx, tx, filmID); err != nil {
+		return dbError(op, err)
+	}
+
+	change, err := trackChange(ctx, tx, model.AuditUpdate, "films", filmID)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	if err := checkPeopleExist(ctx, tx, []model.Credit{credit}); err != nil {
+		return dbError(op, err)
+	}
+
+	// Без явного порядка ставим участника последним в своей роли
+	if credit.Position == 0 {
+		err := tx.QueryRowContext(ctx, `
+            SELECT COALESCE(MAX(position), 0) + 1
+            FROM film_credits
+            WHERE film_id = $1 AND role = $2 AND person_id <> $3`, filmID, credit.Role, credit.PersonID,
+		).Scan(&credit.Position)
+		if err != nil {
+			return dbError(op, err)
+		}
+	}
+
+	if err := upsertCredit(ctx, tx, filmID, credit); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return nil
+}
+
+func (s *Storage) RemoveCredit(ctx context.Context, filmID, personID int, role string) error {
+	const op = "storage.postgres.RemoveCredit"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	change, err := trackChange(ctx, tx, model.AuditUpdate, "films", filmID)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM film_credits WHERE film_id = $1 AND person_id = $2 AND role = $3`,
+		filmID, personID, role)
+	if err != nil {
+		return dbError(op, err)
+	}
+	if err := checkAffected(op, res); err != nil {
+		return err
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return nil
+}
+
+// GetFilmography возвращает участника и его живые фильмы по ролям, внутри роли - по дате выхода
+func (s *Storage) GetFilmography(ctx context.Context, personID int) (model.Filmography, error) {
+	const op = "storage.postgres.GetFilmography"
+
+	var person model.Filmography
+	err := s.db.QueryRowContext(ctx, `
+        SELECT id, name, gender, date_of_birth, version
+        FROM people
+        WHERE id = $1 AND deleted_at IS NULL`, personID,
+	).Scan(&person.Id, &person.Name, &person.Gender, &person.DateOfBirth, &person.Version)
+	if err != nil {
+		return person, dbError(op, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT fc.role, f.id, f.name, f.release_date, f.rating, COALESCE(fc.character_name, '')
+        FROM film_credits fc
+        JOIN films f ON f.id = fc.film_id AND f.deleted_at IS NULL
+        WHERE fc.person_id = $1
+        ORDER BY array_position($2::text[], fc.role), f.release_date, f.id`, personID, pq.Array(model.CreditRoles))
+	if err != nil {
+		return person, dbError(op, err)
+	}
+	defer rows.Close()
+
+	person.Roles = make([]model.RoleFilms, 0)
+	for rows.Next() {
+		var role string
+		var film model.CreditFilm
+		if err := rows.Scan(&role, &film.Id, &film.Name, &film.Releasedate, &film.Rating, &film.Character); err != nil {
+			return person, dbError(op, err)
+		}
+
+		if n := len(person.Roles); n == 0 || person.Roles[n-1].Role != role {
+			person.Roles = append(person.Roles, model.RoleFilms{Role: role})
+		}
+		last := &person.Roles[len(person.Roles)-1]
+		last.Films = append(last.Films, film)
+	}
+	if err := rows.Err(); err != nil {
+		return person, dbError(op, err)
+	}
+
+	return person, nil
+}
+
+// checkPeopleExist проверяет, что все участники титров есть в базе, и блокирует их от удаления
+func checkPeopleExist(ctx context.Context, tx *sql.Tx, credits []model.Credit) error {
+	ids := make([]int64, 0, len(credits))
+	for _, c := range credits {
+		ids = append(ids, int64(c.PersonID))
+	}
+
+	missing, err := missingPeople(ctx, tx, ids)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return domain.Wrap(ErrMissingPeople, domain.ErrValidation, "unknown_people",
+			fmt.Sprintf("в титрах указаны несуществующие участники: %v", missing)).WithArgs(missing)
+	}
+
+	return nil
+}
+
+func upsertCredit(ctx context.Context, tx *sql.Tx, filmID int, credit model.Credit) error {
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO film_credits (film_id, person_id, role, character_name, position)
+        VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, 0))
+        ON CONFLICT (film_id, person_id, role) DO UPDATE
+        SET character_name = EXCLUDED.character_name,
+            position = EXCLUDED.position`,
+		filmID, credit.PersonID, credit.Role, credit.Character, credit.Position,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save film credit: %w", translateError(err))
+	}
+	return nil
+}
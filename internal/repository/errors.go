@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"film-library/internal/domain"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// Коды ошибок PostgreSQL, которые означают ошибку клиента, а не сервера
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+	pgNotNullViolation    = "23502"
+	pgStringTooLong       = "22001"
+	pgNumericOutOfRange   = "22003"
+)
+
+// dbError дописывает op и переводит ошибки драйвера в доменные:
+// отсутствие строки - ErrNotFound, нарушение уникальности - ErrConflict,
+// ссылки и ограничения - ErrValidation. Исходная ошибка остаётся в цепочке
+func dbError(op string, err error) error {
+	return fmt.Errorf("%s: %w", op, translateError(err))
+}
+
+// checkAffected возвращает ErrNotFound, если UPDATE или DELETE не затронул ни одной строки
+func checkAffected(op string, res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return dbError(op, err)
+	}
+	if affected == 0 {
+		return dbError(op, sql.ErrNoRows)
+	}
+	return nil
+}
+
+// checkVersioned разбирает UPDATE с условием на версию, не затронувший строк:
+// если неудалённая запись есть, её версия уже другая (ErrPrecondition), иначе - ErrNotFound
+func (s *Storage) checkVersioned(ctx context.Context, op, table string, id int) error {
+	var exists bool
+	err := s.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM `+table+` WHERE id = $1 AND deleted_at IS NULL)`, id).Scan(&exists)
+	if err != nil {
+		return dbError(op, err)
+	}
+	if !exists {
+		return dbError(op, sql.ErrNoRows)
+	}
+	return fmt.Errorf("%s: %w", op, domain.VersionMismatch())
+}
+
+func translateError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Wrap(err, domain.ErrNotFound, "not_found", "запись не найдена")
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch pqErr.Code {
+	case pgUniqueViolation:
+		return domain.Wrap(err, domain.ErrConflict, "already_exists", "запись с такими данными уже существует")
+	case pgForeignKeyViolation:
+		return domain.Wrap(err, domain.ErrValidation, "invalid_reference", "ссылка на несуществующую запись")
+	case pgCheckViolation, pgNotNullViolation, pgStringTooLong, pgNumericOutOfRange:
+		return domain.Wrap(err, domain.ErrValidation, "constraint_violation", "значение нарушает ограничения")
+	}
+
+	return err
+}
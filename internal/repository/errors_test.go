@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"film-library/internal/domain"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedKind error
+		expectedCode string
+	}{
+		{
+			name:         "No Rows",
+			err:          sql.ErrNoRows,
+			expectedKind: domain.ErrNotFound,
+			expectedCode: "not_found",
+		},
+		{
+			name:         "Unique Violation",
+			err:          &pq.Error{Code: pgUniqueViolation, Constraint: "films_name_key"},
+			expectedKind: domain.ErrConflict,
+			expectedCode: "already_exists",
+		},
+		{
+			name:         "Foreign Key Violation",
+			err:          &pq.Error{Code: pgForeignKeyViolation},
+			expectedKind: domain.ErrValidation,
+			expectedCode: "invalid_reference",
+		},
+		{
+			name:         "Check Violation",
+			err:          &pq.Error{Code: pgCheckViolation},
+			expectedKind: domain.ErrValidation,
+			expectedCode: "constraint_violation",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := dbError("storage.postgres.Test", tc.err)
+
+			require.True(t, errors.Is(err, tc.expectedKind))
+			require.True(t, errors.Is(err, tc.err))
+
+			var de *domain.Error
+			require.True(t, errors.As(err, &de))
+			require.Equal(t, tc.expectedCode, de.Code)
+		})
+	}
+
+	t.Run("Other Error", func(t *testing.T) {
+		cause := errors.New("connection refused")
+		err := dbError("storage.postgres.Test", cause)
+
+		require.True(t, errors.Is(err, cause))
+		var de *domain.Error
+		require.False(t, errors.As(err, &de))
+	})
+}
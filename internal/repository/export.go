@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"film-library/internal/model"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// exportBatchSize - сколько строк забирается из курсора за один FETCH
+const exportBatchSize = 1000
+
+type ExportRepository interface {
+	ExportFilms(ctx context.Context, params model.ExportParams, emit func(model.Film) error) error
+	ExportActors(ctx context.Context, params model.ExportParams, emit func(model.Actor) error) error
+	ExportCasting(ctx context.Context, params model.ExportParams, emit func(model.CastingRecord) error) error
+}
+
+func NewExportRepository(db *sql.DB) ExportRepository {
+	return &Storage{
+		db: db,
+	}
+}
+
+// ExportFilms отдаёт фильмы по фильтрам и сортировке списка, без пагинации,
+// вместе с оценкой пользователей и метками
+func (s *Storage) ExportFilms(ctx context.Context, params model.ExportParams, emit func(model.Film) error) error {
+	const op = "storage.postgres.ExportFilms"
+
+	var args queryArgs
+	where := filmFilterConditions(params.Films, &args)
+
+	column := filmSortColumns[params.Films.Sort()]
+	direction := params.Films.Direction()
+
+	query := fmt.Sprintf(`
+        SELECT f.id, f.name, f.description, f.release_date, f.rating, f.user_score, f.votes,
+               ARRAY(SELECT g.name::text FROM film_genres fg JOIN genres g ON g.id = fg.genre_id
+                     WHERE fg.film_id = f.id ORDER BY 1),
+               ARRAY(SELECT fc.country_code::text FROM film_countries fc WHERE fc.film_id = f.id ORDER BY 1),
+               ARRAY(SELECT t.name::text FROM film_tags ft JOIN tags t ON t.id = ft.tag_id
+                     WHERE ft.film_id = f.id ORDER BY 1)
+        FROM films f%s
+        ORDER BY %s %s, f.id %s`,
+		whereClause(where), column, direction, direction)
+
+	err := s.streamRows(ctx, query, args, func(rows *sql.Rows) error {
+		var film model.Film
+		err := rows.Scan(&film.Id, &film.Name, &film.Description, &film.Releasedate, &film.Rating, &film.UserScore, &film.Votes,
+			pq.Array(&film.Genres), pq.Array(&film.Countries), pq.Array(&film.Tags))
+		if err != nil {
+			return err
+		}
+		return emit(film)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, translateError(err))
+	}
+
+	return nil
+}
+
+func (s *Storage) ExportActors(ctx context.Context, params model.ExportParams, emit func(model.Actor) error) error {
+	const op = "storage.postgres.ExportActors"
+
+	var args queryArgs
+	where := []string{"a.deleted_at IS NULL", actorOnly}
+
+	if params.NamePrefix != "" {
+		where = append(where, "a.name ILIKE "+args.add(escapeLike(params.NamePrefix)+"%"))
+	}
+	if params.Gender != "" {
+		where = append(where, "a.gender = "+args.add(params.Gender))
+	}
+	if params.FilmID != 0 {
+		where = append(where, "EXISTS (SELECT 1 FROM film_credits af WHERE af.person_id = a.id AND af.role = 'actor' AND af.film_id = "+args.add(params.FilmID)+")")
+	}
+
+	query := `
+        SELECT a.id, a.name, a.gender, a.date_of_birth
+        FROM people a` + whereClause(where) + `
+        ORDER BY a.name, a.id`
+
+	err := s.streamRows(ctx, query, args, func(rows *sql.Rows) error {
+		var actor model.Actor
+		if err := rows.Scan(&actor.Id, &actor.Name, &actor.Gender, &actor.DateOfBirth); err != nil {
+			return err
+		}
+		return emit(actor)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, translateError(err))
+	}
+
+	return nil
+}
+
+// ExportCasting отдаёт связи живых фильмов и актёров в порядке титров
+func (s *Storage) ExportCasting(ctx context.Context, params model.ExportParams, emit func(model.CastingRecord) error) error {
+	const op = "storage.postgres.ExportCasting"
+
+	var args queryArgs
+	where := []string{"af.role = 'actor'", "f.deleted_at IS NULL", "a.deleted_at IS NULL"}
+
+	if params.FilmID != 0 {
+		where = append(where, "af.film_id = "+args.add(params.FilmID))
+	}
+	if params.ActorID != 0 {
+		where = append(where, "af.person_id = "+args.add(params.ActorID))
+	}
+
+	query := `
+        SELECT f.id, f.name, a.id, a.name, COALESCE(af.character_name, ''), COALESCE(af.position, 0)
+        FROM film_credits af
+        JOIN films f ON f.id = af.film_id
+        JOIN people a ON a.id = af.person_id` + whereClause(where) + `
+        ORDER BY f.id, af.position NULLS LAST, a.name, a.id`
+
+	err := s.streamRows(ctx, query, args, func(rows *sql.Rows) error {
+		var rec model.CastingRecord
+		if err := rows.Scan(&rec.FilmID, &rec.Film, &rec.ActorID, &rec.Actor, &rec.Character, &rec.BillingOrder); err != nil {
+			return err
+		}
+		return emit(rec)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, translateError(err))
+	}
+
+	return nil
+}
+
+// streamRows читает результат запроса через серверный курсор порциями по exportBatchSize,
+// поэтому память не зависит от размера выборки. Транзакция только на чтение и с одним
+// снимком данных: выгрузка согласована, даже если каталог меняется во время чтения
+func (s *Storage) streamRows(ctx context.Context, query string, args queryArgs, scan func(rows *sql.Rows) error) error {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DECLARE export_cursor NO SCROLL CURSOR FOR "+query, args...); err != nil {
+		return fmt.Errorf("failed to declare cursor: %w", err)
+	}
+
+	fetch := fmt.Sprintf("FETCH %d FROM export_cursor", exportBatchSize)
+	for {
+		n, err := fetchBatch(ctx, tx, fetch, scan)
+		if err != nil {
+			return err
+		}
+		if n < exportBatchSize {
+			break
+		}
+	}
+
+	return tx.Commit()
+}
+
+// fetchBatch забирает одну порцию из курсора и возвращает число строк в ней
+func fetchBatch(ctx context.Context, tx *sql.Tx, fetch string, scan func(rows *sql.Rows) error) (int, error) {
+	rows, err := tx.QueryContext(ctx, fetch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch rows: %w", err)
+	}
+	defer rows.Close()
+
+	n := 0
+	for rows.Next() {
+		n++
+		if err := scan(rows); err != nil {
+			return n, err
+		}
+	}
+
+	return n, rows.Err()
+}
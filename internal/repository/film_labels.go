@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// filmLabelConditions - условия WHERE фильтра по меткам для фильмов f:
+// каждое значение должно быть у фильма
+func filmLabelConditions(filter model.FilmLabelFilter, args *queryArgs) []string {
+	var where []string
+
+	for _, genre := range filter.Genres {
+		where = append(where, `EXISTS (SELECT 1 FROM film_genres fg JOIN genres g ON g.id = fg.genre_id
+            WHERE fg.film_id = f.id AND lower(g.name) = lower(`+args.add(genre)+`))`)
+	}
+	for _, code := range filter.Countries {
+		where = append(where, `EXISTS (SELECT 1 FROM film_countries fc
+            WHERE fc.film_id = f.id AND fc.country_code = upper(`+args.add(code)+`))`)
+	}
+	for _, tag := range filter.Tags {
+		where = append(where, `EXISTS (SELECT 1 FROM film_tags ft JOIN tags t ON t.id = ft.tag_id
+            WHERE ft.film_id = f.id AND t.name = lower(`+args.add(tag)+`))`)
+	}
+
+	return where
+}
+
+// setFilmLabels заменяет жанры, страны и теги фильма; nil-списки не трогает.
+// Жанры и страны должны быть в справочниках, иначе - ошибки полей; новые теги создаются.
+// Имена жанров в labels заменяются написанием из справочника
+func setFilmLabels(ctx context.Context, tx *sql.Tx, filmID int, labels *model.FilmLabels) error {
+	var errs domain.FieldErrors
+
+	var genreIDs []int64
+	if labels.Genres != nil {
+		lowered := make([]string, len(labels.Genres))
+		for i, name := range labels.Genres {
+			lowered[i] = strings.ToLower(name)
+		}
+
+		known, err := lookupLabels(ctx, tx, `SELECT lower(name), id, name FROM genres WHERE lower(name) = ANY($1)`, lowered)
+		if err != nil {
+			return err
+		}
+
+		for i, key := range lowered {
+			genre, ok := known[key]
+			if !ok {
+				errs = append(errs, unknownLabel(fmt.Sprintf("genres[%d]", i), "жанр не найден", "validation.unknown_genre"))
+				continue
+			}
+			genreIDs = append(genreIDs, genre.id)
+			labels.Genres[i] = genre.name
+		}
+	}
+
+	if labels.Countries != nil {
+		known, err := lookupLabels(ctx, tx, `SELECT code, 0, code FROM countries WHERE code = ANY($1)`, labels.Countries)
+		if err != nil {
+			return err
+		}
+
+		for i, code := range labels.Countries {
+			if _, ok := known[code]; !ok {
+				errs = append(errs, unknownLabel(fmt.Sprintf("countries[%d]", i), "страна не найдена", "validation.unknown_country"))
+			}
+		}
+	}
+
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	if labels.Genres != nil {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM film_genres WHERE film_id = $1`, filmID); err != nil {
+			return fmt.Errorf("failed to clear genres: %w", translateError(err))
+		}
+		_, err := tx.ExecContext(ctx, `INSERT INTO film_genres (film_id, genre_id) SELECT $1, unnest($2::int[])`,
+			filmID, pq.Array(genreIDs))
+		if err != nil {
+			return fmt.Errorf("failed to set genres: %w", translateError(err))
+		}
+	}
+
+	if labels.Countries != nil {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM film_countries WHERE film_id = $1`, filmID); err != nil {
+			return fmt.Errorf("failed to clear countries: %w", translateError(err))
+		}
+		_, err := tx.ExecContext(ctx, `INSERT INTO film_countries (film_id, country_code) SELECT $1, unnest($2::text[])`,
+			filmID, pq.Array(labels.Countries))
+		if err != nil {
+			return fmt.Errorf("failed to set countries: %w", translateError(err))
+		}
+	}
+
+	if labels.Tags != nil {
+		if err := createTags(ctx, tx, model.AuditCreate, "WITH", `SELECT unnest($4::text[])`, pq.Array(labels.Tags)); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM film_tags WHERE film_id = $1`, filmID); err != nil {
+			return fmt.Errorf("failed to clear tags: %w", translateError(err))
+		}
+		_, err := tx.ExecContext(ctx, `INSERT INTO film_tags (film_id, tag_id) SELECT $1, id FROM tags WHERE name = ANY($2)`,
+			filmID, pq.Array(labels.Tags))
+		if err != nil {
+			return fmt.Errorf("failed to set tags: %w", translateError(err))
+		}
+	}
+
+	return nil
+}
+
+// createTags создаёт недостающие теги из names - запроса с колонкой имени после
+// CTE with - и пишет в журнал запись action на каждый созданный тег.
+// $1-$3 заняты автором, действием и сущностью журнала, args начинаются с $4
+func createTags(ctx context.Context, tx *sql.Tx, action, with, names string, args ...any) error {
+	_, err := tx.ExecContext(ctx, with+`
+        created AS (
+            INSERT INTO tags AS r (name) `+names+`
+            ON CONFLICT (name) DO NOTHING
+            RETURNING r.id, (SELECT to_jsonb(t) FROM (SELECT `+auditSnapshots["tags"]+`) t) AS after
+        )
+        INSERT INTO audit_log (user_id, action, entity, entity_id, after)
+        SELECT $1, $2, $3, id, after FROM created ORDER BY id`,
+		append([]any{auditUser(ctx), action, model.EntityTag}, args...)...,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create tags: %w", translateError(err))
+	}
+	return nil
+}
+
+type knownLabel struct {
+	id   int64
+	name string
+}
+
+// lookupLabels выполняет запрос (ключ, id, имя) по списку значений $1 и возвращает найденное по ключу
+func lookupLabels(ctx context.Context, tx *sql.Tx, query string, values []string) (map[string]knownLabel, error) {
+	rows, err := tx.QueryContext(ctx, query, pq.Array(values))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up labels: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	known := make(map[string]knownLabel, len(values))
+	for rows.Next() {
+		var key string
+		var label knownLabel
+		if err := rows.Scan(&key, &label.id, &label.name); err != nil {
+			return nil, err
+		}
+		known[key] = label
+	}
+
+	return known, rows.Err()
+}
+
+func unknownLabel(field, message, key string) domain.FieldError {
+	return domain.FieldError{Field: field, Rule: "exists", Message: message, Key: key}
+}
+
+// attachFilmLabels одним запросом подгружает жанры, страны и теги переданных фильмов
+func (s *Storage) attachFilmLabels(ctx context.Context, films []model.Film) error {
+	ids := make([]int64, 0, len(films))
+	for _, film := range films {
+		ids = append(ids, int64(film.Id))
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT fg.film_id, 'genres', g.name::text
+        FROM film_genres fg JOIN genres g ON g.id = fg.genre_id
+        WHERE fg.film_id = ANY($1)
+        UNION ALL
+        SELECT fc.film_id, 'countries', fc.country_code::text
+        FROM film_countries fc
+        WHERE fc.film_id = ANY($1)
+        UNION ALL
+        SELECT ft.film_id, 'tags', t.name::text
+        FROM film_tags ft JOIN tags t ON t.id = ft.tag_id
+        WHERE ft.film_id = ANY($1)
+        ORDER BY 1, 2, 3`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	labels := make(map[int]*model.FilmLabels, len(films))
+	for rows.Next() {
+		var filmID int
+		var kind, value string
+		if err := rows.Scan(&filmID, &kind, &value); err != nil {
+			return err
+		}
+
+		l := labels[filmID]
+		if l == nil {
+			l = &model.FilmLabels{}
+			labels[filmID] = l
+		}
+
+		switch kind {
+		case "genres":
+			l.Genres = append(l.Genres, value)
+		case "countries":
+			l.Countries = append(l.Countries, value)
+		case "tags":
+			l.Tags = append(l.Tags, value)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range films {
+		if l := labels[films[i].Id]; l != nil {
+			films[i].FilmLabels = *l
+		}
+	}
+
+	return nil
+}
+
+// attachFilmDetails подгружает к фильмам состав и метки
+func (s *Storage) attachFilmDetails(ctx context.Context, films []model.Film) error {
+	if err := s.attachFilmActors(ctx, films); err != nil {
+		return err
+	}
+	return s.attachFilmLabels(ctx, films)
+}
@@ -0,0 +1,551 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	"fmt"
+	"slices"
+
+	"github.com/lib/pq"
+)
+
+// importBatchSize - сколько строк уходит в базу одним COPY
+const importBatchSize = 5000
+
+type ImportRepository interface {
+	ImportFilms(ctx context.Context, rows func(yield func(model.FilmImportRow) bool) error, dryRun bool) (model.ImportResult, error)
+	ImportActors(ctx context.Context, rows func(yield func(model.Actor) bool) error, dryRun bool) (model.ImportResult, error)
+	ImportCast(ctx context.Context, rows func(yield func(model.CastImportRow) bool) error, dryRun bool) (model.ImportResult, error)
+}
+
+func NewImportRepository(db *sql.DB) ImportRepository {
+	return &Storage{
+		db: db,
+	}
+}
+
+// ImportFilms добавляет фильмы и обновляет существующие с тем же названием.
+// Если название в файле повторяется, побеждает последняя строка. Заданные списки
+// меток заменяют метки фильма; строки с неизвестным жанром или страной попадают
+// в ошибки результата, новые теги создаются
+func (s *Storage) ImportFilms(ctx context.Context, rows func(yield func(model.FilmImportRow) bool) error, dryRun bool) (model.ImportResult, error) {
+	const op = "storage.postgres.ImportFilms"
+
+	staging := stagingTable{
+		name: "import_films",
+		ddl: `line INT, name TEXT, description TEXT, release_date DATE, rating NUMERIC(2, 1),
+              genres TEXT[], countries TEXT[], tags TEXT[]`,
+		columns: []string{"line", "name", "description", "release_date", "rating", "genres", "countries", "tags"},
+	}
+
+	fill := func(c *batchCopier) error {
+		return copyRows(c, rows, func(f model.FilmImportRow) []any {
+			return []any{f.Line, f.Name, f.Description, f.Releasedate, f.Rating,
+				pq.Array(f.Genres), pq.Array(f.Countries), pq.Array(f.Tags)}
+		})
+	}
+
+	merge := func(ctx context.Context, tx *sql.Tx) (model.ImportResult, error) {
+		var result model.ImportResult
+
+		unknown, err := rejectUnknownLabels(ctx, tx)
+		if err != nil {
+			return result, err
+		}
+		result.Errors = unknown
+
+		if err := snapshotImported(ctx, tx, "films", `r.name IN (SELECT name FROM import_films)`); err != nil {
+			return result, err
+		}
+
+		// Фильм с теми же полями, но другими метками тоже считается изменённым
+		err = tx.QueryRowContext(ctx, `
+            WITH latest AS (
+                SELECT DISTINCT ON (name) name, description, release_date, rating, genres, countries, tags
+                FROM import_films
+                ORDER BY name, row_no DESC
+            ),
+            relabeled AS (
+                SELECT l.name
+                FROM latest l
+                JOIN films f ON f.name = l.name AND f.deleted_at IS NULL
+                WHERE `+filmLabelsDiffer+`
+            ),
+            upserted AS (
+                INSERT INTO films AS f (name, description, release_date, rating)
+                SELECT name, description, release_date, rating FROM latest
+                ON CONFLICT (name) WHERE deleted_at IS NULL DO UPDATE
+                SET description = EXCLUDED.description,
+                    release_date = EXCLUDED.release_date,
+                    rating = EXCLUDED.rating,
+                    version = f.version + 1
+                WHERE (f.description, f.release_date, f.rating)
+                      IS DISTINCT FROM (EXCLUDED.description, EXCLUDED.release_date, EXCLUDED.rating)
+                   OR EXCLUDED.name IN (SELECT name FROM relabeled)
+                RETURNING f.id, (xmax = 0) AS inserted
+            ),
+            marked AS (`+markImported+` SELECT id FROM upserted`+markImportedConflict+`)
+            SELECT count(*) FILTER (WHERE inserted), count(*) FILTER (WHERE NOT inserted)
+            FROM upserted`,
+		).Scan(&result.Created, &result.Updated)
+		if err != nil {
+			return result, err
+		}
+
+		if err := importFilmLabels(ctx, tx); err != nil {
+			return result, err
+		}
+
+		return result, recordImported(ctx, tx, "films")
+	}
+
+	return s.runImport(ctx, op, staging, fill, merge, dryRun)
+}
+
+// filmLabelsDiffer - заданный в строке импорта l список меток отличается от меток фильма f
+const filmLabelsDiffer = `
+                    (l.genres IS NOT NULL AND
+                     ARRAY(SELECT lower(x) FROM unnest(l.genres) x ORDER BY 1) IS DISTINCT FROM
+                     ARRAY(SELECT lower(g.name) FROM film_genres fg JOIN genres g ON g.id = fg.genre_id
+                           WHERE fg.film_id = f.id ORDER BY 1))
+                 OR (l.countries IS NOT NULL AND
+                     ARRAY(SELECT x FROM unnest(l.countries) x ORDER BY 1) IS DISTINCT FROM
+                     ARRAY(SELECT fc.country_code::text FROM film_countries fc WHERE fc.film_id = f.id ORDER BY 1))
+                 OR (l.tags IS NOT NULL AND
+                     ARRAY(SELECT x FROM unnest(l.tags) x ORDER BY 1) IS DISTINCT FROM
+                     ARRAY(SELECT t.name::text FROM film_tags ft JOIN tags t ON t.id = ft.tag_id
+                           WHERE ft.film_id = f.id ORDER BY 1))`
+
+// rejectUnknownLabels убирает из слияния строки фильмов с жанрами или странами,
+// которых нет в справочниках, и возвращает их как ошибки по позициям в списке
+func rejectUnknownLabels(ctx context.Context, tx *sql.Tx) ([]model.ImportRowError, error) {
+	rows, err := tx.QueryContext(ctx, `
+        WITH unknown AS (
+            SELECT s.row_no,
+                   ARRAY(SELECT x.n - 1 FROM unnest(s.genres) WITH ORDINALITY x(name, n)
+                         WHERE NOT EXISTS (SELECT 1 FROM genres g WHERE lower(g.name) = lower(x.name))
+                         ORDER BY x.n) AS genres,
+                   ARRAY(SELECT x.n - 1 FROM unnest(s.countries) WITH ORDINALITY x(code, n)
+                         WHERE NOT EXISTS (SELECT 1 FROM countries c WHERE c.code = x.code)
+                         ORDER BY x.n) AS countries
+            FROM import_films s
+            WHERE s.genres IS NOT NULL OR s.countries IS NOT NULL
+        )
+        DELETE FROM import_films s
+        USING unknown u
+        WHERE s.row_no = u.row_no AND (cardinality(u.genres) > 0 OR cardinality(u.countries) > 0)
+        RETURNING s.line, u.genres, u.countries`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []model.ImportRowError
+	for rows.Next() {
+		var (
+			line              int
+			genres, countries []int64
+			errs              domain.FieldErrors
+		)
+		if err := rows.Scan(&line, pq.Array(&genres), pq.Array(&countries)); err != nil {
+			return nil, err
+		}
+		for _, i := range genres {
+			errs = append(errs, unknownLabel(fmt.Sprintf("genres[%d]", i), "жанр не найден", "validation.unknown_genre"))
+		}
+		for _, i := range countries {
+			errs = append(errs, unknownLabel(fmt.Sprintf("countries[%d]", i), "страна не найдена", "validation.unknown_country"))
+		}
+		result = append(result, model.ImportRowError{Line: line, Errors: errs})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING не гарантирует порядок
+	slices.SortFunc(result, func(a, b model.ImportRowError) int { return a.Line - b.Line })
+	return result, nil
+}
+
+// importFilmLabels заменяет метки фильмов, изменённых слиянием, заданными в
+// последней строке импорта списками. Незаданные списки не трогаются
+func importFilmLabels(ctx context.Context, tx *sql.Tx) error {
+	// target - фильмы с заданным списком column и сам список (labels)
+	target := func(column string) string {
+		return `
+            WITH target AS (
+                SELECT f.id, l.` + column + ` AS labels
+                FROM (SELECT DISTINCT ON (name) name, ` + column + `
+                      FROM import_films
+                      ORDER BY name, row_no DESC) l
+                JOIN films f ON f.name = l.name AND f.deleted_at IS NULL
+                JOIN import_audit i ON i.id = f.id AND i.changed
+                WHERE l.` + column + ` IS NOT NULL
+            )`
+	}
+
+	steps := []struct {
+		name  string
+		query string
+	}{
+		{"clear genres", target("genres") + `
+            DELETE FROM film_genres fg USING target t WHERE fg.film_id = t.id`},
+		{"set genres", target("genres") + `
+            INSERT INTO film_genres (film_id, genre_id)
+            SELECT DISTINCT t.id, g.id
+            FROM target t CROSS JOIN unnest(t.labels) AS x(name)
+            JOIN genres g ON lower(g.name) = lower(x.name)`},
+		{"clear countries", target("countries") + `
+            DELETE FROM film_countries fc USING target t WHERE fc.film_id = t.id`},
+		{"set countries", target("countries") + `
+            INSERT INTO film_countries (film_id, country_code)
+            SELECT DISTINCT t.id, x.code
+            FROM target t CROSS JOIN unnest(t.labels) AS x(code)`},
+		{"clear tags", target("tags") + `
+            DELETE FROM film_tags ft USING target t WHERE ft.film_id = t.id`},
+		{"set tags", target("tags") + `
+            INSERT INTO film_tags (film_id, tag_id)
+            SELECT DISTINCT t.id, tg.id
+            FROM target t CROSS JOIN unnest(t.labels) AS x(name)
+            JOIN tags tg ON tg.name = x.name`},
+	}
+	// Новые теги создаются до того, как их назначить фильмам
+	err := createTags(ctx, tx, model.AuditImport, target("tags")+",",
+		`SELECT DISTINCT x.name FROM target t CROSS JOIN unnest(t.labels) AS x(name)`)
+	if err != nil {
+		return err
+	}
+
+	for _, step := range steps {
+		if _, err := tx.ExecContext(ctx, step.query); err != nil {
+			return fmt.Errorf("failed to %s: %w", step.name, err)
+		}
+	}
+
+	return nil
+}
+
+// ImportActors добавляет актёров и обновляет существующих с тем же именем
+func (s *Storage) ImportActors(ctx context.Context, rows func(yield func(model.Actor) bool) error, dryRun bool) (model.ImportResult, error) {
+	const op = "storage.postgres.ImportActors"
+
+	staging := stagingTable{
+		name:    "import_actors",
+		ddl:     `name TEXT, gender TEXT, date_of_birth DATE`,
+		columns: []string{"name", "gender", "date_of_birth"},
+	}
+
+	fill := func(c *batchCopier) error {
+		return copyRows(c, rows, func(a model.Actor) []any {
+			return []any{a.Name, a.Gender, a.DateOfBirth}
+		})
+	}
+
+	merge := func(ctx context.Context, tx *sql.Tx) (model.ImportResult, error) {
+		var result model.ImportResult
+		if err := snapshotImported(ctx, tx, "people", `r.name IN (SELECT name FROM import_actors)`); err != nil {
+			return result, err
+		}
+
+		err := tx.QueryRowContext(ctx, `
+            WITH latest AS (
+                SELECT DISTINCT ON (name) name, gender, date_of_birth
+                FROM import_actors
+                ORDER BY name, row_no DESC
+            ),
+            upserted AS (
+                INSERT INTO people AS a (name, gender, date_of_birth)
+                SELECT name, gender, date_of_birth FROM latest
+                ON CONFLICT (name) WHERE deleted_at IS NULL DO UPDATE
+                SET gender = EXCLUDED.gender,
+                    date_of_birth = EXCLUDED.date_of_birth,
+                    version = a.version + 1
+                WHERE (a.gender, a.date_of_birth) IS DISTINCT FROM (EXCLUDED.gender, EXCLUDED.date_of_birth)
+                RETURNING a.id, (xmax = 0) AS inserted
+            ),
+            marked AS (`+markImported+` SELECT id FROM upserted`+markImportedConflict+`)
+            SELECT count(*) FILTER (WHERE inserted), count(*) FILTER (WHERE NOT inserted)
+            FROM upserted`,
+		).Scan(&result.Created, &result.Updated)
+		if err != nil {
+			return result, err
+		}
+
+		return result, recordImported(ctx, tx, "people")
+	}
+
+	return s.runImport(ctx, op, staging, fill, merge, dryRun)
+}
+
+// ImportCast связывает актёров с фильмами по названию и имени. Строки со ссылкой
+// на неизвестный фильм или актёра попадают в ошибки результата
+func (s *Storage) ImportCast(ctx context.Context, rows func(yield func(model.CastImportRow) bool) error, dryRun bool) (model.ImportResult, error) {
+	const op = "storage.postgres.ImportCast"
+
+	staging := stagingTable{
+		name:    "import_cast",
+		ddl:     `line INT, film TEXT, actor TEXT, character TEXT, billing_order INT`,
+		columns: []string{"line", "film", "actor", "character", "billing_order"},
+	}
+
+	fill := func(c *batchCopier) error {
+		return copyRows(c, rows, func(r model.CastImportRow) []any {
+			return []any{r.Line, r.Film, r.Actor, r.Character, r.BillingOrder}
+		})
+	}
+
+	merge := func(ctx context.Context, tx *sql.Tx) (model.ImportResult, error) {
+		var result model.ImportResult
+
+		unknown, err := unknownCastRefs(ctx, tx)
+		if err != nil {
+			return result, err
+		}
+		result.Errors = unknown
+
+		// Состав входит в снимок фильма, поэтому в журнал попадает изменение фильма
+		if err := snapshotImported(ctx, tx, "films", `r.name IN (SELECT film FROM import_cast)`); err != nil {
+			return result, err
+		}
+
+		err = tx.QueryRowContext(ctx, `
+            WITH latest AS (
+                SELECT DISTINCT ON (f.id, a.id) f.id AS film_id, a.id AS actor_id, s.character, s.billing_order
+                FROM import_cast s
+                JOIN films f ON f.name = s.film AND f.deleted_at IS NULL
+                JOIN people a ON a.name = s.actor AND a.deleted_at IS NULL
+                ORDER BY f.id, a.id, s.row_no DESC
+            ),
+            upserted AS (
+                INSERT INTO film_credits AS af (film_id, person_id, role, character_name, position)
+                SELECT film_id, actor_id, 'actor', NULLIF(character, ''), NULLIF(billing_order, 0) FROM latest
+                ON CONFLICT (film_id, person_id, role) DO UPDATE
+                SET character_name = EXCLUDED.character_name,
+                    position = EXCLUDED.position
+                WHERE (af.character_name, af.position)
+                      IS DISTINCT FROM (EXCLUDED.character_name, EXCLUDED.position)
+                RETURNING af.film_id, (xmax = 0) AS inserted
+            ),
+            marked AS (`+markImported+` SELECT DISTINCT film_id FROM upserted`+markImportedConflict+`)
+            SELECT count(*) FILTER (WHERE inserted), count(*) FILTER (WHERE NOT inserted)
+            FROM upserted`,
+		).Scan(&result.Created, &result.Updated)
+		if err != nil {
+			return result, err
+		}
+
+		return result, recordImported(ctx, tx, "films")
+	}
+
+	return s.runImport(ctx, op, staging, fill, merge, dryRun)
+}
+
+// Журнал импорта ведётся теми же снимками, что и record, но сразу для всех строк:
+// snapshotImported до слияния запоминает в import_audit состояние затрагиваемых строк,
+// слияние помечает изменённые строки через markImported, recordImported пишет в журнал
+// изменившиеся поля. У созданных строк before нет, after содержит снимок целиком.
+// Строки, добавленные через markImported, - новые, поэтому changed по умолчанию true
+const (
+	markImported         = `INSERT INTO import_audit (id)`
+	markImportedConflict = ` ON CONFLICT (id) DO UPDATE SET changed = true`
+)
+
+// snapshotImported блокирует неудалённые строки table под условием where
+// (псевдоним r) и запоминает их состояние до слияния
+func snapshotImported(ctx context.Context, tx *sql.Tx, table, where string) error {
+	_, err := tx.ExecContext(ctx, `
+        CREATE TEMP TABLE import_audit (
+            id INT PRIMARY KEY,
+            before JSONB,
+            changed BOOLEAN NOT NULL DEFAULT true
+        ) ON COMMIT DROP`)
+	if err != nil {
+		return fmt.Errorf("failed to create audit staging table: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO import_audit (id, before, changed)
+        SELECT r.id, (SELECT to_jsonb(t) FROM (SELECT `+auditSnapshots[table]+`) t), false
+        FROM `+table+` r
+        WHERE r.deleted_at IS NULL AND `+where+`
+        FOR UPDATE OF r`)
+	if err != nil {
+		return fmt.Errorf("failed to read %s snapshots: %w", table, err)
+	}
+	return nil
+}
+
+// recordImported пишет в журнал строки table, изменённые слиянием, с изменившимися полями
+func recordImported(ctx context.Context, tx *sql.Tx, table string) error {
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO audit_log (user_id, action, entity, entity_id, before, after)
+        SELECT $1, $2, $3, c.id,
+               (SELECT jsonb_object_agg(e.key, e.value) FROM jsonb_each(c.before) e
+                WHERE e.value IS DISTINCT FROM c.after -> e.key),
+               (SELECT jsonb_object_agg(e.key, e.value) FROM jsonb_each(c.after) e
+                WHERE e.value IS DISTINCT FROM c.before -> e.key)
+        FROM (
+            SELECT i.id, i.before,
+                   (SELECT to_jsonb(t) FROM (SELECT `+auditSnapshots[table]+` FROM `+table+` r WHERE r.id = i.id) t) AS after
+            FROM import_audit i
+            WHERE i.changed
+            ORDER BY i.id
+        ) c`,
+		auditUser(ctx), model.AuditImport, auditEntities[table],
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// unknownCastRefs - строки состава, ссылающиеся на отсутствующие фильмы или актёров
+func unknownCastRefs(ctx context.Context, tx *sql.Tx) ([]model.ImportRowError, error) {
+	rows, err := tx.QueryContext(ctx, `
+        SELECT s.line, f.id IS NULL, a.id IS NULL
+        FROM import_cast s
+        LEFT JOIN films f ON f.name = s.film AND f.deleted_at IS NULL
+        LEFT JOIN people a ON a.name = s.actor AND a.deleted_at IS NULL
+        WHERE f.id IS NULL OR a.id IS NULL
+        ORDER BY s.row_no`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []model.ImportRowError
+	for rows.Next() {
+		var (
+			line            int
+			noFilm, noActor bool
+			errs            domain.FieldErrors
+		)
+		if err := rows.Scan(&line, &noFilm, &noActor); err != nil {
+			return nil, err
+		}
+		if noFilm {
+			errs = append(errs, domain.FieldError{Field: "film", Rule: "exists", Message: "фильм не найден", Key: "validation.unknown_film"})
+		}
+		if noActor {
+			errs = append(errs, domain.FieldError{Field: "actor", Rule: "exists", Message: "актёр не найден", Key: "validation.unknown_actor"})
+		}
+		result = append(result, model.ImportRowError{Line: line, Errors: errs})
+	}
+
+	return result, rows.Err()
+}
+
+// stagingTable - временная таблица, куда строки импорта копируются до слияния
+type stagingTable struct {
+	name    string
+	ddl     string   // колонки данных; row_no добавляется сам
+	columns []string // колонки данных в порядке COPY
+}
+
+// runImport копирует строки во временную таблицу и одним запросом сливает их
+// с основной в одной транзакции. При dryRun транзакция откатывается
+func (s *Storage) runImport(
+	ctx context.Context,
+	op string,
+	staging stagingTable,
+	fill func(c *batchCopier) error,
+	merge func(ctx context.Context, tx *sql.Tx) (model.ImportResult, error),
+	dryRun bool,
+) (model.ImportResult, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.ImportResult{}, fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `CREATE TEMP TABLE `+staging.name+` (row_no INT, `+staging.ddl+`) ON COMMIT DROP`)
+	if err != nil {
+		return model.ImportResult{}, fmt.Errorf("%s: failed to create staging table: %w", op, translateError(err))
+	}
+
+	copier := &batchCopier{ctx: ctx, tx: tx, table: staging.name, columns: append([]string{"row_no"}, staging.columns...)}
+	if err := fill(copier); err != nil {
+		return model.ImportResult{}, fmt.Errorf("%s: failed to copy rows: %w", op, translateError(err))
+	}
+	if err := copier.flush(); err != nil {
+		return model.ImportResult{}, fmt.Errorf("%s: failed to copy rows: %w", op, translateError(err))
+	}
+
+	result, err := merge(ctx, tx)
+	if err != nil {
+		return model.ImportResult{}, fmt.Errorf("%s: failed to merge rows: %w", op, translateError(err))
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.ImportResult{}, fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return result, nil
+}
+
+// copyRows передаёт строки источника в COPY. Ошибка чтения источника прерывает импорт
+func copyRows[T any](c *batchCopier, rows func(yield func(T) bool) error, values func(T) []any) error {
+	var copyErr error
+	err := rows(func(row T) bool {
+		copyErr = c.add(values(row)...)
+		return copyErr == nil
+	})
+	if copyErr != nil {
+		return copyErr
+	}
+	return err
+}
+
+// batchCopier пишет строки во временную таблицу через COPY порциями по importBatchSize
+type batchCopier struct {
+	ctx     context.Context
+	tx      *sql.Tx
+	table   string
+	columns []string
+
+	stmt    *sql.Stmt
+	pending int
+	rows    int
+}
+
+func (c *batchCopier) add(values ...any) error {
+	if c.stmt == nil {
+		stmt, err := c.tx.PrepareContext(c.ctx, pq.CopyIn(c.table, c.columns...))
+		if err != nil {
+			return err
+		}
+		c.stmt = stmt
+	}
+
+	c.rows++
+	if _, err := c.stmt.ExecContext(c.ctx, append([]any{c.rows}, values...)...); err != nil {
+		return err
+	}
+
+	c.pending++
+	if c.pending >= importBatchSize {
+		return c.flush()
+	}
+	return nil
+}
+
+// flush завершает текущий COPY
+func (c *batchCopier) flush() error {
+	if c.stmt == nil {
+		return nil
+	}
+
+	_, err := c.stmt.ExecContext(c.ctx)
+	if closeErr := c.stmt.Close(); err == nil {
+		err = closeErr
+	}
+	c.stmt = nil
+	c.pending = 0
+	return err
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage_ImportFilmsAudit(t *testing.T) {
+	s := testStorage(t)
+	ctx := context.Background()
+
+	existing, created := testName("import existing"), testName("import created")
+	released := time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	var id int
+	err := s.db.QueryRow(`INSERT INTO films (name, description, release_date, rating) VALUES ($1, 'text', $2, 5.5) RETURNING id`,
+		existing, released).Scan(&id)
+	require.NoError(t, err)
+	t.Cleanup(func() { mustExec(t, s, `DELETE FROM films WHERE name IN ($1, $2)`, existing, created) })
+
+	rows := func(yield func(model.FilmImportRow) bool) error {
+		_ = yield(model.FilmImportRow{Line: 2, Film: model.Film{Name: existing, Description: "text", Releasedate: released, Rating: 7.5}}) &&
+			yield(model.FilmImportRow{Line: 3, Film: model.Film{Name: created, Description: "new", Releasedate: released, Rating: 6}})
+		return nil
+	}
+	result, err := s.ImportFilms(ctx, rows, false)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Created)
+	require.Equal(t, 1, result.Updated)
+
+	// Как и при обычном изменении, в журнале только изменившиеся поля до и после
+	var before, after string
+	err = s.db.QueryRow(`
+        SELECT before::text, after::text FROM audit_log
+        WHERE action = $1 AND entity = $2 AND entity_id = $3`, model.AuditImport, model.EntityFilm, id).Scan(&before, &after)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"rating": 5.5, "version": 1}`, before)
+	require.JSONEq(t, `{"rating": 7.5, "version": 2}`, after)
+
+	// У созданного фильма before нет
+	var hasBefore bool
+	var name string
+	err = s.db.QueryRow(`
+        SELECT l.before IS NOT NULL, l.after->>'name' FROM audit_log l
+        JOIN films f ON f.id = l.entity_id
+        WHERE l.action = $1 AND l.entity = $2 AND f.name = $3`, model.AuditImport, model.EntityFilm, created).Scan(&hasBefore, &name)
+	require.NoError(t, err)
+	require.False(t, hasBefore)
+	require.Equal(t, created, name)
+}
+
+func TestStorage_ImportFilmsLabels(t *testing.T) {
+	s := testStorage(t)
+	ctx := context.Background()
+
+	relabeled, rejected := testName("import relabeled"), testName("import rejected")
+	tag := testName("import tag")
+	released := time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	var id int
+	err := s.db.QueryRow(`INSERT INTO films (name, description, release_date, rating) VALUES ($1, 'text', $2, 5.5) RETURNING id`,
+		relabeled, released).Scan(&id)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		mustExec(t, s, `DELETE FROM films WHERE name IN ($1, $2)`, relabeled, rejected)
+		mustExec(t, s, `DELETE FROM tags WHERE name = $1`, tag)
+	})
+
+	film := model.Film{Name: relabeled, Description: "text", Releasedate: released, Rating: 5.5,
+		FilmLabels: model.FilmLabels{Genres: []string{"drama"}, Countries: []string{"US"}, Tags: []string{tag}}}
+	rows := func(yield func(model.FilmImportRow) bool) error {
+		_ = yield(model.FilmImportRow{Line: 2, Film: film}) &&
+			yield(model.FilmImportRow{Line: 3, Film: model.Film{Name: rejected, Releasedate: released,
+				FilmLabels: model.FilmLabels{Genres: []string{"Drama", "Nonexistent"}, Countries: []string{"ZZ"}}}})
+		return nil
+	}
+	result, err := s.ImportFilms(ctx, rows, false)
+	require.NoError(t, err)
+
+	// Поля фильма совпали, но метки изменились - это обновление
+	require.Equal(t, 0, result.Created)
+	require.Equal(t, 1, result.Updated)
+	require.Equal(t, []model.ImportRowError{{Line: 3, Errors: domain.FieldErrors{
+		unknownLabel("genres[1]", "жанр не найден", "validation.unknown_genre"),
+		unknownLabel("countries[0]", "страна не найдена", "validation.unknown_country"),
+	}}}, result.Errors)
+
+	films := []model.Film{{Id: id}}
+	require.NoError(t, s.attachFilmLabels(ctx, films))
+	require.Equal(t, model.FilmLabels{Genres: []string{"Drama"}, Countries: []string{"US"}, Tags: []string{tag}}, films[0].FilmLabels)
+
+	var exists bool
+	require.NoError(t, s.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM films WHERE name = $1)`, rejected).Scan(&exists))
+	require.False(t, exists)
+
+	// Повторный импорт тех же меток ничего не меняет
+	result, err = s.ImportFilms(ctx, func(yield func(model.FilmImportRow) bool) error {
+		yield(model.FilmImportRow{Line: 2, Film: film})
+		return nil
+	}, false)
+	require.NoError(t, err)
+	require.Equal(t, model.ImportResult{}, result)
+}
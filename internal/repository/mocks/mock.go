@@ -8,6 +8,7 @@ import (
 	context "context"
 	model "film-library/internal/model"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,6 +36,21 @@ func (m *MockAuthorization) EXPECT() *MockAuthorizationMockRecorder {
 	return m.recorder
 }
 
+// ConsumeRefreshToken mocks base method.
+func (m *MockAuthorization) ConsumeRefreshToken(ctx context.Context, hash string) (model.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeRefreshToken", ctx, hash)
+	ret0, _ := ret[0].(model.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeRefreshToken indicates an expected call of ConsumeRefreshToken.
+func (mr *MockAuthorizationMockRecorder) ConsumeRefreshToken(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeRefreshToken", reflect.TypeOf((*MockAuthorization)(nil).ConsumeRefreshToken), ctx, hash)
+}
+
 // CreateUser mocks base method.
 func (m *MockAuthorization) CreateUser(ctx context.Context, user *model.User) error {
 	m.ctrl.T.Helper()
@@ -49,6 +65,79 @@ func (mr *MockAuthorizationMockRecorder) CreateUser(ctx, user interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAuthorization)(nil).CreateUser), ctx, user)
 }
 
+// GetUserByID mocks base method.
+func (m *MockAuthorization) GetUserByID(ctx context.Context, id int) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", ctx, id)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockAuthorizationMockRecorder) GetUserByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockAuthorization)(nil).GetUserByID), ctx, id)
+}
+
+// IsTokenRevoked mocks base method.
+func (m *MockAuthorization) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenRevoked", ctx, jti)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenRevoked indicates an expected call of IsTokenRevoked.
+func (mr *MockAuthorizationMockRecorder) IsTokenRevoked(ctx, jti interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenRevoked", reflect.TypeOf((*MockAuthorization)(nil).IsTokenRevoked), ctx, jti)
+}
+
+// PurgeExpiredTokens mocks base method.
+func (m *MockAuthorization) PurgeExpiredTokens(ctx context.Context) (model.TokenPurgeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpiredTokens", ctx)
+	ret0, _ := ret[0].(model.TokenPurgeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpiredTokens indicates an expected call of PurgeExpiredTokens.
+func (mr *MockAuthorizationMockRecorder) PurgeExpiredTokens(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpiredTokens", reflect.TypeOf((*MockAuthorization)(nil).PurgeExpiredTokens), ctx)
+}
+
+// RevokeSession mocks base method.
+func (m *MockAuthorization) RevokeSession(ctx context.Context, userID int, refreshHash, accessJTI string, accessExpiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, userID, refreshHash, accessJTI, accessExpiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockAuthorizationMockRecorder) RevokeSession(ctx, userID, refreshHash, accessJTI, accessExpiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockAuthorization)(nil).RevokeSession), ctx, userID, refreshHash, accessJTI, accessExpiresAt)
+}
+
+// SaveRefreshToken mocks base method.
+func (m *MockAuthorization) SaveRefreshToken(ctx context.Context, token model.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRefreshToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRefreshToken indicates an expected call of SaveRefreshToken.
+func (mr *MockAuthorizationMockRecorder) SaveRefreshToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRefreshToken", reflect.TypeOf((*MockAuthorization)(nil).SaveRefreshToken), ctx, token)
+}
+
 // VerifyUser mocks base method.
 func (m *MockAuthorization) VerifyUser(ctx context.Context, username string) (*model.User, error) {
 	m.ctrl.T.Helper()
@@ -132,17 +221,47 @@ func (mr *MockActorMockRecorder) CreateActor(ctx, actor interface{}) *gomock.Cal
 }
 
 // DeleteActor mocks base method.
-func (m *MockActor) DeleteActor(ctx context.Context, id int) error {
+func (m *MockActor) DeleteActor(ctx context.Context, id, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteActor", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteActor", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteActor indicates an expected call of DeleteActor.
-func (mr *MockActorMockRecorder) DeleteActor(ctx, id interface{}) *gomock.Call {
+func (mr *MockActorMockRecorder) DeleteActor(ctx, id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActor", reflect.TypeOf((*MockActor)(nil).DeleteActor), ctx, id, version)
+}
+
+// GetActorByID mocks base method.
+func (m *MockActor) GetActorByID(ctx context.Context, id int) (model.ActorWithFilms, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActorByID", ctx, id)
+	ret0, _ := ret[0].(model.ActorWithFilms)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActorByID indicates an expected call of GetActorByID.
+func (mr *MockActorMockRecorder) GetActorByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorByID", reflect.TypeOf((*MockActor)(nil).GetActorByID), ctx, id)
+}
+
+// PatchActor mocks base method.
+func (m *MockActor) PatchActor(ctx context.Context, id, version int, changes map[string]any) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchActor", ctx, id, version, changes)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchActor indicates an expected call of PatchActor.
+func (mr *MockActorMockRecorder) PatchActor(ctx, id, version, changes interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActor", reflect.TypeOf((*MockActor)(nil).DeleteActor), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchActor", reflect.TypeOf((*MockActor)(nil).PatchActor), ctx, id, version, changes)
 }
 
 // UpdateActor mocks base method.
@@ -197,32 +316,47 @@ func (mr *MockMovieMockRecorder) CreateFilm(ctx, film interface{}) *gomock.Call
 }
 
 // DeleteFilm mocks base method.
-func (m *MockMovie) DeleteFilm(ctx context.Context, id int) error {
+func (m *MockMovie) DeleteFilm(ctx context.Context, id, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteFilm", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteFilm", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteFilm indicates an expected call of DeleteFilm.
-func (mr *MockMovieMockRecorder) DeleteFilm(ctx, id interface{}) *gomock.Call {
+func (mr *MockMovieMockRecorder) DeleteFilm(ctx, id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFilm", reflect.TypeOf((*MockMovie)(nil).DeleteFilm), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFilm", reflect.TypeOf((*MockMovie)(nil).DeleteFilm), ctx, id, version)
 }
 
 // GetAllFilms mocks base method.
-func (m *MockMovie) GetAllFilms(ctx context.Context, sortBy string) ([]model.Film, error) {
+func (m *MockMovie) GetAllFilms(ctx context.Context, params model.FilmListParams) (model.FilmPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllFilms", ctx, sortBy)
-	ret0, _ := ret[0].([]model.Film)
+	ret := m.ctrl.Call(m, "GetAllFilms", ctx, params)
+	ret0, _ := ret[0].(model.FilmPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllFilms indicates an expected call of GetAllFilms.
-func (mr *MockMovieMockRecorder) GetAllFilms(ctx, sortBy interface{}) *gomock.Call {
+func (mr *MockMovieMockRecorder) GetAllFilms(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllFilms", reflect.TypeOf((*MockMovie)(nil).GetAllFilms), ctx, params)
+}
+
+// GetFilmByID mocks base method.
+func (m *MockMovie) GetFilmByID(ctx context.Context, id int) (model.Film, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilmByID", ctx, id)
+	ret0, _ := ret[0].(model.Film)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFilmByID indicates an expected call of GetFilmByID.
+func (mr *MockMovieMockRecorder) GetFilmByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllFilms", reflect.TypeOf((*MockMovie)(nil).GetAllFilms), ctx, sortBy)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmByID", reflect.TypeOf((*MockMovie)(nil).GetFilmByID), ctx, id)
 }
 
 // MovieExistsById mocks base method.
@@ -255,19 +389,34 @@ func (mr *MockMovieMockRecorder) MovieExistsByName(ctx, name interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MovieExistsByName", reflect.TypeOf((*MockMovie)(nil).MovieExistsByName), ctx, name)
 }
 
-// SearchFilm mocks base method.
-func (m *MockMovie) SearchFilm(ctx context.Context, actor, film string) (model.Film, error) {
+// PatchFilm mocks base method.
+func (m *MockMovie) PatchFilm(ctx context.Context, id, version int, changes map[string]any, labels *model.FilmLabels) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SearchFilm", ctx, actor, film)
-	ret0, _ := ret[0].(model.Film)
+	ret := m.ctrl.Call(m, "PatchFilm", ctx, id, version, changes, labels)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchFilm indicates an expected call of PatchFilm.
+func (mr *MockMovieMockRecorder) PatchFilm(ctx, id, version, changes, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchFilm", reflect.TypeOf((*MockMovie)(nil).PatchFilm), ctx, id, version, changes, labels)
+}
+
+// SearchFilms mocks base method.
+func (m *MockMovie) SearchFilms(ctx context.Context, params model.FilmSearchParams) (model.FilmSearchPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchFilms", ctx, params)
+	ret0, _ := ret[0].(model.FilmSearchPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SearchFilm indicates an expected call of SearchFilm.
-func (mr *MockMovieMockRecorder) SearchFilm(ctx, actor, film interface{}) *gomock.Call {
+// SearchFilms indicates an expected call of SearchFilms.
+func (mr *MockMovieMockRecorder) SearchFilms(ctx, params interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchFilm", reflect.TypeOf((*MockMovie)(nil).SearchFilm), ctx, actor, film)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchFilms", reflect.TypeOf((*MockMovie)(nil).SearchFilms), ctx, params)
 }
 
 // UpdateFilm mocks base method.
@@ -307,17 +456,1284 @@ func (m *MockActorMovie) EXPECT() *MockActorMovieMockRecorder {
 	return m.recorder
 }
 
-// GetActorsWithFilms mocks base method.
-func (m *MockActorMovie) GetActorsWithFilms(ctx context.Context) (map[int]model.ActorWithFilms, error) {
+// GetActors mocks base method.
+func (m *MockActorMovie) GetActors(ctx context.Context, params model.ActorListParams) (model.ActorPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActors", ctx, params)
+	ret0, _ := ret[0].(model.ActorPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActors indicates an expected call of GetActors.
+func (mr *MockActorMovieMockRecorder) GetActors(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActors", reflect.TypeOf((*MockActorMovie)(nil).GetActors), ctx, params)
+}
+
+// GetFilmsByActor mocks base method.
+func (m *MockActorMovie) GetFilmsByActor(ctx context.Context, actorID int) ([]model.Film, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilmsByActor", ctx, actorID)
+	ret0, _ := ret[0].([]model.Film)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFilmsByActor indicates an expected call of GetFilmsByActor.
+func (mr *MockActorMovieMockRecorder) GetFilmsByActor(ctx, actorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmsByActor", reflect.TypeOf((*MockActorMovie)(nil).GetFilmsByActor), ctx, actorID)
+}
+
+// MockCasting is a mock of Casting interface.
+type MockCasting struct {
+	ctrl     *gomock.Controller
+	recorder *MockCastingMockRecorder
+}
+
+// MockCastingMockRecorder is the mock recorder for MockCasting.
+type MockCastingMockRecorder struct {
+	mock *MockCasting
+}
+
+// NewMockCasting creates a new mock instance.
+func NewMockCasting(ctrl *gomock.Controller) *MockCasting {
+	mock := &MockCasting{ctrl: ctrl}
+	mock.recorder = &MockCastingMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCasting) EXPECT() *MockCastingMockRecorder {
+	return m.recorder
+}
+
+// AddCastMember mocks base method.
+func (m *MockCasting) AddCastMember(ctx context.Context, filmID int, entry model.CastingEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCastMember", ctx, filmID, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCastMember indicates an expected call of AddCastMember.
+func (mr *MockCastingMockRecorder) AddCastMember(ctx, filmID, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCastMember", reflect.TypeOf((*MockCasting)(nil).AddCastMember), ctx, filmID, entry)
+}
+
+// GetCast mocks base method.
+func (m *MockCasting) GetCast(ctx context.Context, filmID int) ([]model.CastMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCast", ctx, filmID)
+	ret0, _ := ret[0].([]model.CastMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCast indicates an expected call of GetCast.
+func (mr *MockCastingMockRecorder) GetCast(ctx, filmID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCast", reflect.TypeOf((*MockCasting)(nil).GetCast), ctx, filmID)
+}
+
+// RemoveCastMember mocks base method.
+func (m *MockCasting) RemoveCastMember(ctx context.Context, filmID, actorID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCastMember", ctx, filmID, actorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCastMember indicates an expected call of RemoveCastMember.
+func (mr *MockCastingMockRecorder) RemoveCastMember(ctx, filmID, actorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCastMember", reflect.TypeOf((*MockCasting)(nil).RemoveCastMember), ctx, filmID, actorID)
+}
+
+// ReplaceCast mocks base method.
+func (m *MockCasting) ReplaceCast(ctx context.Context, filmID int, entries []model.CastingEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceCast", ctx, filmID, entries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceCast indicates an expected call of ReplaceCast.
+func (mr *MockCastingMockRecorder) ReplaceCast(ctx, filmID, entries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceCast", reflect.TypeOf((*MockCasting)(nil).ReplaceCast), ctx, filmID, entries)
+}
+
+// MockCredit is a mock of Credit interface.
+type MockCredit struct {
+	ctrl     *gomock.Controller
+	recorder *MockCreditMockRecorder
+}
+
+// MockCreditMockRecorder is the mock recorder for MockCredit.
+type MockCreditMockRecorder struct {
+	mock *MockCredit
+}
+
+// NewMockCredit creates a new mock instance.
+func NewMockCredit(ctrl *gomock.Controller) *MockCredit {
+	mock := &MockCredit{ctrl: ctrl}
+	mock.recorder = &MockCreditMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCredit) EXPECT() *MockCreditMockRecorder {
+	return m.recorder
+}
+
+// AddCredit mocks base method.
+func (m *MockCredit) AddCredit(ctx context.Context, filmID int, credit model.Credit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCredit", ctx, filmID, credit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCredit indicates an expected call of AddCredit.
+func (mr *MockCreditMockRecorder) AddCredit(ctx, filmID, credit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCredit", reflect.TypeOf((*MockCredit)(nil).AddCredit), ctx, filmID, credit)
+}
+
+// GetCredits mocks base method.
+func (m *MockCredit) GetCredits(ctx context.Context, filmID int) ([]model.Credit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCredits", ctx, filmID)
+	ret0, _ := ret[0].([]model.Credit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCredits indicates an expected call of GetCredits.
+func (mr *MockCreditMockRecorder) GetCredits(ctx, filmID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCredits", reflect.TypeOf((*MockCredit)(nil).GetCredits), ctx, filmID)
+}
+
+// GetFilmography mocks base method.
+func (m *MockCredit) GetFilmography(ctx context.Context, personID int) (model.Filmography, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilmography", ctx, personID)
+	ret0, _ := ret[0].(model.Filmography)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFilmography indicates an expected call of GetFilmography.
+func (mr *MockCreditMockRecorder) GetFilmography(ctx, personID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmography", reflect.TypeOf((*MockCredit)(nil).GetFilmography), ctx, personID)
+}
+
+// RemoveCredit mocks base method.
+func (m *MockCredit) RemoveCredit(ctx context.Context, filmID, personID int, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCredit", ctx, filmID, personID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCredit indicates an expected call of RemoveCredit.
+func (mr *MockCreditMockRecorder) RemoveCredit(ctx, filmID, personID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCredit", reflect.TypeOf((*MockCredit)(nil).RemoveCredit), ctx, filmID, personID, role)
+}
+
+// ReplaceCredits mocks base method.
+func (m *MockCredit) ReplaceCredits(ctx context.Context, filmID int, credits []model.Credit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceCredits", ctx, filmID, credits)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceCredits indicates an expected call of ReplaceCredits.
+func (mr *MockCreditMockRecorder) ReplaceCredits(ctx, filmID, credits interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceCredits", reflect.TypeOf((*MockCredit)(nil).ReplaceCredits), ctx, filmID, credits)
+}
+
+// MockAccess is a mock of Access interface.
+type MockAccess struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessMockRecorder
+}
+
+// MockAccessMockRecorder is the mock recorder for MockAccess.
+type MockAccessMockRecorder struct {
+	mock *MockAccess
+}
+
+// NewMockAccess creates a new mock instance.
+func NewMockAccess(ctrl *gomock.Controller) *MockAccess {
+	mock := &MockAccess{ctrl: ctrl}
+	mock.recorder = &MockAccessMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccess) EXPECT() *MockAccessMockRecorder {
+	return m.recorder
+}
+
+// GetRoles mocks base method.
+func (m *MockAccess) GetRoles(ctx context.Context) ([]model.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoles", ctx)
+	ret0, _ := ret[0].([]model.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoles indicates an expected call of GetRoles.
+func (mr *MockAccessMockRecorder) GetRoles(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoles", reflect.TypeOf((*MockAccess)(nil).GetRoles), ctx)
+}
+
+// SetUserRole mocks base method.
+func (m *MockAccess) SetUserRole(ctx context.Context, userID int, role string) (model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserRole", ctx, userID, role)
+	ret0, _ := ret[0].(model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserRole indicates an expected call of SetUserRole.
+func (mr *MockAccessMockRecorder) SetUserRole(ctx, userID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRole", reflect.TypeOf((*MockAccess)(nil).SetUserRole), ctx, userID, role)
+}
+
+// MockTrash is a mock of Trash interface.
+type MockTrash struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrashMockRecorder
+}
+
+// MockTrashMockRecorder is the mock recorder for MockTrash.
+type MockTrashMockRecorder struct {
+	mock *MockTrash
+}
+
+// NewMockTrash creates a new mock instance.
+func NewMockTrash(ctrl *gomock.Controller) *MockTrash {
+	mock := &MockTrash{ctrl: ctrl}
+	mock.recorder = &MockTrashMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrash) EXPECT() *MockTrashMockRecorder {
+	return m.recorder
+}
+
+// GetTrash mocks base method.
+func (m *MockTrash) GetTrash(ctx context.Context) (model.Trash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", ctx)
+	ret0, _ := ret[0].(model.Trash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockTrashMockRecorder) GetTrash(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockTrash)(nil).GetTrash), ctx)
+}
+
+// PurgeTrash mocks base method.
+func (m *MockTrash) PurgeTrash(ctx context.Context, before time.Time) (model.PurgeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", ctx, before)
+	ret0, _ := ret[0].(model.PurgeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockTrashMockRecorder) PurgeTrash(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockTrash)(nil).PurgeTrash), ctx, before)
+}
+
+// RestoreActor mocks base method.
+func (m *MockTrash) RestoreActor(ctx context.Context, id int) (model.ActorWithFilms, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetActorsWithFilms", ctx)
-	ret0, _ := ret[0].(map[int]model.ActorWithFilms)
+	ret := m.ctrl.Call(m, "RestoreActor", ctx, id)
+	ret0, _ := ret[0].(model.ActorWithFilms)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetActorsWithFilms indicates an expected call of GetActorsWithFilms.
-func (mr *MockActorMovieMockRecorder) GetActorsWithFilms(ctx interface{}) *gomock.Call {
+// RestoreActor indicates an expected call of RestoreActor.
+func (mr *MockTrashMockRecorder) RestoreActor(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreActor", reflect.TypeOf((*MockTrash)(nil).RestoreActor), ctx, id)
+}
+
+// RestoreFilm mocks base method.
+func (m *MockTrash) RestoreFilm(ctx context.Context, id int) (model.Film, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreFilm", ctx, id)
+	ret0, _ := ret[0].(model.Film)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreFilm indicates an expected call of RestoreFilm.
+func (mr *MockTrashMockRecorder) RestoreFilm(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreFilm", reflect.TypeOf((*MockTrash)(nil).RestoreFilm), ctx, id)
+}
+
+// MockImport is a mock of Import interface.
+type MockImport struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportMockRecorder
+}
+
+// MockImportMockRecorder is the mock recorder for MockImport.
+type MockImportMockRecorder struct {
+	mock *MockImport
+}
+
+// NewMockImport creates a new mock instance.
+func NewMockImport(ctrl *gomock.Controller) *MockImport {
+	mock := &MockImport{ctrl: ctrl}
+	mock.recorder = &MockImportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImport) EXPECT() *MockImportMockRecorder {
+	return m.recorder
+}
+
+// ImportActors mocks base method.
+func (m *MockImport) ImportActors(ctx context.Context, rows func(func(model.Actor) bool) error, dryRun bool) (model.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportActors", ctx, rows, dryRun)
+	ret0, _ := ret[0].(model.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportActors indicates an expected call of ImportActors.
+func (mr *MockImportMockRecorder) ImportActors(ctx, rows, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportActors", reflect.TypeOf((*MockImport)(nil).ImportActors), ctx, rows, dryRun)
+}
+
+// ImportCast mocks base method.
+func (m *MockImport) ImportCast(ctx context.Context, rows func(func(model.CastImportRow) bool) error, dryRun bool) (model.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportCast", ctx, rows, dryRun)
+	ret0, _ := ret[0].(model.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportCast indicates an expected call of ImportCast.
+func (mr *MockImportMockRecorder) ImportCast(ctx, rows, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportCast", reflect.TypeOf((*MockImport)(nil).ImportCast), ctx, rows, dryRun)
+}
+
+// ImportFilms mocks base method.
+func (m *MockImport) ImportFilms(ctx context.Context, rows func(func(model.FilmImportRow) bool) error, dryRun bool) (model.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportFilms", ctx, rows, dryRun)
+	ret0, _ := ret[0].(model.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportFilms indicates an expected call of ImportFilms.
+func (mr *MockImportMockRecorder) ImportFilms(ctx, rows, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportFilms", reflect.TypeOf((*MockImport)(nil).ImportFilms), ctx, rows, dryRun)
+}
+
+// MockExport is a mock of Export interface.
+type MockExport struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportMockRecorder
+}
+
+// MockExportMockRecorder is the mock recorder for MockExport.
+type MockExportMockRecorder struct {
+	mock *MockExport
+}
+
+// NewMockExport creates a new mock instance.
+func NewMockExport(ctrl *gomock.Controller) *MockExport {
+	mock := &MockExport{ctrl: ctrl}
+	mock.recorder = &MockExportMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExport) EXPECT() *MockExportMockRecorder {
+	return m.recorder
+}
+
+// ExportActors mocks base method.
+func (m *MockExport) ExportActors(ctx context.Context, params model.ExportParams, emit func(model.Actor) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportActors", ctx, params, emit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportActors indicates an expected call of ExportActors.
+func (mr *MockExportMockRecorder) ExportActors(ctx, params, emit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportActors", reflect.TypeOf((*MockExport)(nil).ExportActors), ctx, params, emit)
+}
+
+// ExportCasting mocks base method.
+func (m *MockExport) ExportCasting(ctx context.Context, params model.ExportParams, emit func(model.CastingRecord) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportCasting", ctx, params, emit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportCasting indicates an expected call of ExportCasting.
+func (mr *MockExportMockRecorder) ExportCasting(ctx, params, emit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportCasting", reflect.TypeOf((*MockExport)(nil).ExportCasting), ctx, params, emit)
+}
+
+// ExportFilms mocks base method.
+func (m *MockExport) ExportFilms(ctx context.Context, params model.ExportParams, emit func(model.Film) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportFilms", ctx, params, emit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportFilms indicates an expected call of ExportFilms.
+func (mr *MockExportMockRecorder) ExportFilms(ctx, params, emit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportFilms", reflect.TypeOf((*MockExport)(nil).ExportFilms), ctx, params, emit)
+}
+
+// MockAudit is a mock of Audit interface.
+type MockAudit struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditMockRecorder
+}
+
+// MockAuditMockRecorder is the mock recorder for MockAudit.
+type MockAuditMockRecorder struct {
+	mock *MockAudit
+}
+
+// NewMockAudit creates a new mock instance.
+func NewMockAudit(ctrl *gomock.Controller) *MockAudit {
+	mock := &MockAudit{ctrl: ctrl}
+	mock.recorder = &MockAuditMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAudit) EXPECT() *MockAuditMockRecorder {
+	return m.recorder
+}
+
+// GetAuditLog mocks base method.
+func (m *MockAudit) GetAuditLog(ctx context.Context, filter model.AuditFilter) (model.AuditPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLog", ctx, filter)
+	ret0, _ := ret[0].(model.AuditPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLog indicates an expected call of GetAuditLog.
+func (mr *MockAuditMockRecorder) GetAuditLog(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLog", reflect.TypeOf((*MockAudit)(nil).GetAuditLog), ctx, filter)
+}
+
+// MockStats is a mock of Stats interface.
+type MockStats struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatsMockRecorder
+}
+
+// MockStatsMockRecorder is the mock recorder for MockStats.
+type MockStatsMockRecorder struct {
+	mock *MockStats
+}
+
+// NewMockStats creates a new mock instance.
+func NewMockStats(ctrl *gomock.Controller) *MockStats {
+	mock := &MockStats{ctrl: ctrl}
+	mock.recorder = &MockStatsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStats) EXPECT() *MockStatsMockRecorder {
+	return m.recorder
+}
+
+// GetActorStats mocks base method.
+func (m *MockStats) GetActorStats(ctx context.Context, params model.ActorStatsParams) (model.ActorStatsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActorStats", ctx, params)
+	ret0, _ := ret[0].(model.ActorStatsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActorStats indicates an expected call of GetActorStats.
+func (mr *MockStatsMockRecorder) GetActorStats(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorStats", reflect.TypeOf((*MockStats)(nil).GetActorStats), ctx, params)
+}
+
+// GetActorStatsByID mocks base method.
+func (m *MockStats) GetActorStatsByID(ctx context.Context, id int) (model.ActorStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActorStatsByID", ctx, id)
+	ret0, _ := ret[0].(model.ActorStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActorStatsByID indicates an expected call of GetActorStatsByID.
+func (mr *MockStatsMockRecorder) GetActorStatsByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorStatsByID", reflect.TypeOf((*MockStats)(nil).GetActorStatsByID), ctx, id)
+}
+
+// GetCostars mocks base method.
+func (m *MockStats) GetCostars(ctx context.Context, params model.CostarParams) (model.CostarPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCostars", ctx, params)
+	ret0, _ := ret[0].(model.CostarPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCostars indicates an expected call of GetCostars.
+func (mr *MockStatsMockRecorder) GetCostars(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCostars", reflect.TypeOf((*MockStats)(nil).GetCostars), ctx, params)
+}
+
+// GetRatingHistogram mocks base method.
+func (m *MockStats) GetRatingHistogram(ctx context.Context, years model.YearRange) ([]model.RatingBucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRatingHistogram", ctx, years)
+	ret0, _ := ret[0].([]model.RatingBucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRatingHistogram indicates an expected call of GetRatingHistogram.
+func (mr *MockStatsMockRecorder) GetRatingHistogram(ctx, years interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRatingHistogram", reflect.TypeOf((*MockStats)(nil).GetRatingHistogram), ctx, years)
+}
+
+// GetYearStats mocks base method.
+func (m *MockStats) GetYearStats(ctx context.Context, years model.YearRange) ([]model.YearStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetYearStats", ctx, years)
+	ret0, _ := ret[0].([]model.YearStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetYearStats indicates an expected call of GetYearStats.
+func (mr *MockStatsMockRecorder) GetYearStats(ctx, years interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetYearStats", reflect.TypeOf((*MockStats)(nil).GetYearStats), ctx, years)
+}
+
+// RefreshStats mocks base method.
+func (m *MockStats) RefreshStats(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshStats", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshStats indicates an expected call of RefreshStats.
+func (mr *MockStatsMockRecorder) RefreshStats(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshStats", reflect.TypeOf((*MockStats)(nil).RefreshStats), ctx)
+}
+
+// MockTaxonomy is a mock of Taxonomy interface.
+type MockTaxonomy struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaxonomyMockRecorder
+}
+
+// MockTaxonomyMockRecorder is the mock recorder for MockTaxonomy.
+type MockTaxonomyMockRecorder struct {
+	mock *MockTaxonomy
+}
+
+// NewMockTaxonomy creates a new mock instance.
+func NewMockTaxonomy(ctrl *gomock.Controller) *MockTaxonomy {
+	mock := &MockTaxonomy{ctrl: ctrl}
+	mock.recorder = &MockTaxonomyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaxonomy) EXPECT() *MockTaxonomyMockRecorder {
+	return m.recorder
+}
+
+// CreateCountry mocks base method.
+func (m *MockTaxonomy) CreateCountry(ctx context.Context, country *model.Country) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCountry", ctx, country)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCountry indicates an expected call of CreateCountry.
+func (mr *MockTaxonomyMockRecorder) CreateCountry(ctx, country interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCountry", reflect.TypeOf((*MockTaxonomy)(nil).CreateCountry), ctx, country)
+}
+
+// CreateGenre mocks base method.
+func (m *MockTaxonomy) CreateGenre(ctx context.Context, genre *model.Genre) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGenre", ctx, genre)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateGenre indicates an expected call of CreateGenre.
+func (mr *MockTaxonomyMockRecorder) CreateGenre(ctx, genre interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGenre", reflect.TypeOf((*MockTaxonomy)(nil).CreateGenre), ctx, genre)
+}
+
+// CreateTag mocks base method.
+func (m *MockTaxonomy) CreateTag(ctx context.Context, tag *model.Tag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTag", ctx, tag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTag indicates an expected call of CreateTag.
+func (mr *MockTaxonomyMockRecorder) CreateTag(ctx, tag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTag", reflect.TypeOf((*MockTaxonomy)(nil).CreateTag), ctx, tag)
+}
+
+// DeleteCountry mocks base method.
+func (m *MockTaxonomy) DeleteCountry(ctx context.Context, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCountry", ctx, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCountry indicates an expected call of DeleteCountry.
+func (mr *MockTaxonomyMockRecorder) DeleteCountry(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCountry", reflect.TypeOf((*MockTaxonomy)(nil).DeleteCountry), ctx, code)
+}
+
+// DeleteGenre mocks base method.
+func (m *MockTaxonomy) DeleteGenre(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGenre", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGenre indicates an expected call of DeleteGenre.
+func (mr *MockTaxonomyMockRecorder) DeleteGenre(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGenre", reflect.TypeOf((*MockTaxonomy)(nil).DeleteGenre), ctx, id)
+}
+
+// DeleteTag mocks base method.
+func (m *MockTaxonomy) DeleteTag(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *MockTaxonomyMockRecorder) DeleteTag(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockTaxonomy)(nil).DeleteTag), ctx, id)
+}
+
+// GetCountries mocks base method.
+func (m *MockTaxonomy) GetCountries(ctx context.Context) ([]model.Country, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCountries", ctx)
+	ret0, _ := ret[0].([]model.Country)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCountries indicates an expected call of GetCountries.
+func (mr *MockTaxonomyMockRecorder) GetCountries(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCountries", reflect.TypeOf((*MockTaxonomy)(nil).GetCountries), ctx)
+}
+
+// GetCountry mocks base method.
+func (m *MockTaxonomy) GetCountry(ctx context.Context, code string) (model.Country, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCountry", ctx, code)
+	ret0, _ := ret[0].(model.Country)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCountry indicates an expected call of GetCountry.
+func (mr *MockTaxonomyMockRecorder) GetCountry(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCountry", reflect.TypeOf((*MockTaxonomy)(nil).GetCountry), ctx, code)
+}
+
+// GetFilmFacets mocks base method.
+func (m *MockTaxonomy) GetFilmFacets(ctx context.Context, params model.FilmListParams) (model.FilmFacets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilmFacets", ctx, params)
+	ret0, _ := ret[0].(model.FilmFacets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFilmFacets indicates an expected call of GetFilmFacets.
+func (mr *MockTaxonomyMockRecorder) GetFilmFacets(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmFacets", reflect.TypeOf((*MockTaxonomy)(nil).GetFilmFacets), ctx, params)
+}
+
+// GetGenre mocks base method.
+func (m *MockTaxonomy) GetGenre(ctx context.Context, id int) (model.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenre", ctx, id)
+	ret0, _ := ret[0].(model.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenre indicates an expected call of GetGenre.
+func (mr *MockTaxonomyMockRecorder) GetGenre(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenre", reflect.TypeOf((*MockTaxonomy)(nil).GetGenre), ctx, id)
+}
+
+// GetGenres mocks base method.
+func (m *MockTaxonomy) GetGenres(ctx context.Context) ([]model.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenres", ctx)
+	ret0, _ := ret[0].([]model.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenres indicates an expected call of GetGenres.
+func (mr *MockTaxonomyMockRecorder) GetGenres(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenres", reflect.TypeOf((*MockTaxonomy)(nil).GetGenres), ctx)
+}
+
+// GetTag mocks base method.
+func (m *MockTaxonomy) GetTag(ctx context.Context, id int) (model.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTag", ctx, id)
+	ret0, _ := ret[0].(model.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTag indicates an expected call of GetTag.
+func (mr *MockTaxonomyMockRecorder) GetTag(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTag", reflect.TypeOf((*MockTaxonomy)(nil).GetTag), ctx, id)
+}
+
+// GetTags mocks base method.
+func (m *MockTaxonomy) GetTags(ctx context.Context, params model.TagListParams) (model.TagPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTags", ctx, params)
+	ret0, _ := ret[0].(model.TagPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTags indicates an expected call of GetTags.
+func (mr *MockTaxonomyMockRecorder) GetTags(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockTaxonomy)(nil).GetTags), ctx, params)
+}
+
+// UpdateCountry mocks base method.
+func (m *MockTaxonomy) UpdateCountry(ctx context.Context, country *model.Country) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCountry", ctx, country)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCountry indicates an expected call of UpdateCountry.
+func (mr *MockTaxonomyMockRecorder) UpdateCountry(ctx, country interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCountry", reflect.TypeOf((*MockTaxonomy)(nil).UpdateCountry), ctx, country)
+}
+
+// UpdateGenre mocks base method.
+func (m *MockTaxonomy) UpdateGenre(ctx context.Context, genre *model.Genre) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGenre", ctx, genre)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGenre indicates an expected call of UpdateGenre.
+func (mr *MockTaxonomyMockRecorder) UpdateGenre(ctx, genre interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGenre", reflect.TypeOf((*MockTaxonomy)(nil).UpdateGenre), ctx, genre)
+}
+
+// UpdateTag mocks base method.
+func (m *MockTaxonomy) UpdateTag(ctx context.Context, tag *model.Tag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTag", ctx, tag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTag indicates an expected call of UpdateTag.
+func (mr *MockTaxonomyMockRecorder) UpdateTag(ctx, tag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTag", reflect.TypeOf((*MockTaxonomy)(nil).UpdateTag), ctx, tag)
+}
+
+// MockReview is a mock of Review interface.
+type MockReview struct {
+	ctrl     *gomock.Controller
+	recorder *MockReviewMockRecorder
+}
+
+// MockReviewMockRecorder is the mock recorder for MockReview.
+type MockReviewMockRecorder struct {
+	mock *MockReview
+}
+
+// NewMockReview creates a new mock instance.
+func NewMockReview(ctrl *gomock.Controller) *MockReview {
+	mock := &MockReview{ctrl: ctrl}
+	mock.recorder = &MockReviewMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReview) EXPECT() *MockReviewMockRecorder {
+	return m.recorder
+}
+
+// DeleteRating mocks base method.
+func (m *MockReview) DeleteRating(ctx context.Context, userID, filmID int) (model.FilmScore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRating", ctx, userID, filmID)
+	ret0, _ := ret[0].(model.FilmScore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRating indicates an expected call of DeleteRating.
+func (mr *MockReviewMockRecorder) DeleteRating(ctx, userID, filmID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRating", reflect.TypeOf((*MockReview)(nil).DeleteRating), ctx, userID, filmID)
+}
+
+// DeleteReview mocks base method.
+func (m *MockReview) DeleteReview(ctx context.Context, userID, filmID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReview", ctx, userID, filmID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReview indicates an expected call of DeleteReview.
+func (mr *MockReviewMockRecorder) DeleteReview(ctx, userID, filmID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReview", reflect.TypeOf((*MockReview)(nil).DeleteReview), ctx, userID, filmID)
+}
+
+// GetRating mocks base method.
+func (m *MockReview) GetRating(ctx context.Context, userID, filmID int) (model.FilmScore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRating", ctx, userID, filmID)
+	ret0, _ := ret[0].(model.FilmScore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRating indicates an expected call of GetRating.
+func (mr *MockReviewMockRecorder) GetRating(ctx, userID, filmID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRating", reflect.TypeOf((*MockReview)(nil).GetRating), ctx, userID, filmID)
+}
+
+// GetReviews mocks base method.
+func (m *MockReview) GetReviews(ctx context.Context, params model.ReviewListParams) (model.ReviewPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviews", ctx, params)
+	ret0, _ := ret[0].(model.ReviewPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviews indicates an expected call of GetReviews.
+func (mr *MockReviewMockRecorder) GetReviews(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviews", reflect.TypeOf((*MockReview)(nil).GetReviews), ctx, params)
+}
+
+// GetUserReview mocks base method.
+func (m *MockReview) GetUserReview(ctx context.Context, userID, filmID int) (model.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserReview", ctx, userID, filmID)
+	ret0, _ := ret[0].(model.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserReview indicates an expected call of GetUserReview.
+func (mr *MockReviewMockRecorder) GetUserReview(ctx, userID, filmID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserReview", reflect.TypeOf((*MockReview)(nil).GetUserReview), ctx, userID, filmID)
+}
+
+// ModerateReview mocks base method.
+func (m *MockReview) ModerateReview(ctx context.Context, id, moderatorID int, status string) (model.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModerateReview", ctx, id, moderatorID, status)
+	ret0, _ := ret[0].(model.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModerateReview indicates an expected call of ModerateReview.
+func (mr *MockReviewMockRecorder) ModerateReview(ctx, id, moderatorID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModerateReview", reflect.TypeOf((*MockReview)(nil).ModerateReview), ctx, id, moderatorID, status)
+}
+
+// SaveReview mocks base method.
+func (m *MockReview) SaveReview(ctx context.Context, userID, filmID int, body string) (model.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveReview", ctx, userID, filmID, body)
+	ret0, _ := ret[0].(model.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveReview indicates an expected call of SaveReview.
+func (mr *MockReviewMockRecorder) SaveReview(ctx, userID, filmID, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveReview", reflect.TypeOf((*MockReview)(nil).SaveReview), ctx, userID, filmID, body)
+}
+
+// SetRating mocks base method.
+func (m *MockReview) SetRating(ctx context.Context, userID, filmID, score int) (model.FilmScore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRating", ctx, userID, filmID, score)
+	ret0, _ := ret[0].(model.FilmScore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetRating indicates an expected call of SetRating.
+func (mr *MockReviewMockRecorder) SetRating(ctx, userID, filmID, score interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRating", reflect.TypeOf((*MockReview)(nil).SetRating), ctx, userID, filmID, score)
+}
+
+// MockWatchlist is a mock of Watchlist interface.
+type MockWatchlist struct {
+	ctrl     *gomock.Controller
+	recorder *MockWatchlistMockRecorder
+}
+
+// MockWatchlistMockRecorder is the mock recorder for MockWatchlist.
+type MockWatchlistMockRecorder struct {
+	mock *MockWatchlist
+}
+
+// NewMockWatchlist creates a new mock instance.
+func NewMockWatchlist(ctrl *gomock.Controller) *MockWatchlist {
+	mock := &MockWatchlist{ctrl: ctrl}
+	mock.recorder = &MockWatchlistMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWatchlist) EXPECT() *MockWatchlistMockRecorder {
+	return m.recorder
+}
+
+// AddWatchlistFilm mocks base method.
+func (m *MockWatchlist) AddWatchlistFilm(ctx context.Context, userID, id, filmID, position int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWatchlistFilm", ctx, userID, id, filmID, position)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddWatchlistFilm indicates an expected call of AddWatchlistFilm.
+func (mr *MockWatchlistMockRecorder) AddWatchlistFilm(ctx, userID, id, filmID, position interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWatchlistFilm", reflect.TypeOf((*MockWatchlist)(nil).AddWatchlistFilm), ctx, userID, id, filmID, position)
+}
+
+// CreateWatchlist mocks base method.
+func (m *MockWatchlist) CreateWatchlist(ctx context.Context, list *model.Watchlist) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWatchlist", ctx, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWatchlist indicates an expected call of CreateWatchlist.
+func (mr *MockWatchlistMockRecorder) CreateWatchlist(ctx, list interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWatchlist", reflect.TypeOf((*MockWatchlist)(nil).CreateWatchlist), ctx, list)
+}
+
+// DeleteWatched mocks base method.
+func (m *MockWatchlist) DeleteWatched(ctx context.Context, userID, filmID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWatched", ctx, userID, filmID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWatched indicates an expected call of DeleteWatched.
+func (mr *MockWatchlistMockRecorder) DeleteWatched(ctx, userID, filmID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWatched", reflect.TypeOf((*MockWatchlist)(nil).DeleteWatched), ctx, userID, filmID)
+}
+
+// DeleteWatchlist mocks base method.
+func (m *MockWatchlist) DeleteWatchlist(ctx context.Context, id, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWatchlist", ctx, id, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWatchlist indicates an expected call of DeleteWatchlist.
+func (mr *MockWatchlistMockRecorder) DeleteWatchlist(ctx, id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWatchlist", reflect.TypeOf((*MockWatchlist)(nil).DeleteWatchlist), ctx, id, userID)
+}
+
+// GetFeed mocks base method.
+func (m *MockWatchlist) GetFeed(ctx context.Context, params model.FeedParams) (model.FeedPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeed", ctx, params)
+	ret0, _ := ret[0].(model.FeedPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeed indicates an expected call of GetFeed.
+func (mr *MockWatchlistMockRecorder) GetFeed(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeed", reflect.TypeOf((*MockWatchlist)(nil).GetFeed), ctx, params)
+}
+
+// GetWatched mocks base method.
+func (m *MockWatchlist) GetWatched(ctx context.Context, params model.WatchedListParams) (model.WatchedPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatched", ctx, params)
+	ret0, _ := ret[0].(model.WatchedPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatched indicates an expected call of GetWatched.
+func (mr *MockWatchlistMockRecorder) GetWatched(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatched", reflect.TypeOf((*MockWatchlist)(nil).GetWatched), ctx, params)
+}
+
+// GetWatchlist mocks base method.
+func (m *MockWatchlist) GetWatchlist(ctx context.Context, id, viewerID int) (model.WatchlistWithFilms, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatchlist", ctx, id, viewerID)
+	ret0, _ := ret[0].(model.WatchlistWithFilms)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatchlist indicates an expected call of GetWatchlist.
+func (mr *MockWatchlistMockRecorder) GetWatchlist(ctx, id, viewerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchlist", reflect.TypeOf((*MockWatchlist)(nil).GetWatchlist), ctx, id, viewerID)
+}
+
+// GetWatchlists mocks base method.
+func (m *MockWatchlist) GetWatchlists(ctx context.Context, ownerID int, publicOnly bool) ([]model.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatchlists", ctx, ownerID, publicOnly)
+	ret0, _ := ret[0].([]model.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatchlists indicates an expected call of GetWatchlists.
+func (mr *MockWatchlistMockRecorder) GetWatchlists(ctx, ownerID, publicOnly interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchlists", reflect.TypeOf((*MockWatchlist)(nil).GetWatchlists), ctx, ownerID, publicOnly)
+}
+
+// RemoveWatchlistFilm mocks base method.
+func (m *MockWatchlist) RemoveWatchlistFilm(ctx context.Context, userID, id, filmID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveWatchlistFilm", ctx, userID, id, filmID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveWatchlistFilm indicates an expected call of RemoveWatchlistFilm.
+func (mr *MockWatchlistMockRecorder) RemoveWatchlistFilm(ctx, userID, id, filmID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveWatchlistFilm", reflect.TypeOf((*MockWatchlist)(nil).RemoveWatchlistFilm), ctx, userID, id, filmID)
+}
+
+// ReplaceWatchlistFilms mocks base method.
+func (m *MockWatchlist) ReplaceWatchlistFilms(ctx context.Context, userID, id int, filmIDs []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceWatchlistFilms", ctx, userID, id, filmIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceWatchlistFilms indicates an expected call of ReplaceWatchlistFilms.
+func (mr *MockWatchlistMockRecorder) ReplaceWatchlistFilms(ctx, userID, id, filmIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceWatchlistFilms", reflect.TypeOf((*MockWatchlist)(nil).ReplaceWatchlistFilms), ctx, userID, id, filmIDs)
+}
+
+// SetWatched mocks base method.
+func (m *MockWatchlist) SetWatched(ctx context.Context, userID, filmID int, watchedOn time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWatched", ctx, userID, filmID, watchedOn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetWatched indicates an expected call of SetWatched.
+func (mr *MockWatchlistMockRecorder) SetWatched(ctx, userID, filmID, watchedOn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWatched", reflect.TypeOf((*MockWatchlist)(nil).SetWatched), ctx, userID, filmID, watchedOn)
+}
+
+// UpdateWatchlist mocks base method.
+func (m *MockWatchlist) UpdateWatchlist(ctx context.Context, list *model.Watchlist) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWatchlist", ctx, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWatchlist indicates an expected call of UpdateWatchlist.
+func (mr *MockWatchlistMockRecorder) UpdateWatchlist(ctx, list interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWatchlist", reflect.TypeOf((*MockWatchlist)(nil).UpdateWatchlist), ctx, list)
+}
+
+// MockRecommendation is a mock of Recommendation interface.
+type MockRecommendation struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecommendationMockRecorder
+}
+
+// MockRecommendationMockRecorder is the mock recorder for MockRecommendation.
+type MockRecommendationMockRecorder struct {
+	mock *MockRecommendation
+}
+
+// NewMockRecommendation creates a new mock instance.
+func NewMockRecommendation(ctrl *gomock.Controller) *MockRecommendation {
+	mock := &MockRecommendation{ctrl: ctrl}
+	mock.recorder = &MockRecommendationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecommendation) EXPECT() *MockRecommendationMockRecorder {
+	return m.recorder
+}
+
+// GetRecommendations mocks base method.
+func (m *MockRecommendation) GetRecommendations(ctx context.Context, params model.RecommendationParams) (model.RecommendationPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecommendations", ctx, params)
+	ret0, _ := ret[0].(model.RecommendationPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecommendations indicates an expected call of GetRecommendations.
+func (mr *MockRecommendationMockRecorder) GetRecommendations(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecommendations", reflect.TypeOf((*MockRecommendation)(nil).GetRecommendations), ctx, params)
+}
+
+// GetSimilarFilms mocks base method.
+func (m *MockRecommendation) GetSimilarFilms(ctx context.Context, params model.SimilarParams) (model.SimilarPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSimilarFilms", ctx, params)
+	ret0, _ := ret[0].(model.SimilarPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSimilarFilms indicates an expected call of GetSimilarFilms.
+func (mr *MockRecommendationMockRecorder) GetSimilarFilms(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSimilarFilms", reflect.TypeOf((*MockRecommendation)(nil).GetSimilarFilms), ctx, params)
+}
+
+// RefreshRecommendations mocks base method.
+func (m *MockRecommendation) RefreshRecommendations(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshRecommendations", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshRecommendations indicates an expected call of RefreshRecommendations.
+func (mr *MockRecommendationMockRecorder) RefreshRecommendations(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorsWithFilms", reflect.TypeOf((*MockActorMovie)(nil).GetActorsWithFilms), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshRecommendations", reflect.TypeOf((*MockRecommendation)(nil).RefreshRecommendations), ctx)
 }
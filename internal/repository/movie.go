@@ -3,17 +3,24 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"film-library/internal/domain"
 	"film-library/internal/model"
 	"fmt"
+	"slices"
+
+	"github.com/lib/pq"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@2.50.1 --name=MovieRepository
 type MovieRepository interface {
 	CreateFilm(ctx context.Context, film *model.Film) error
 	UpdateFilm(ctx context.Context, film *model.Film) error
-	DeleteFilm(ctx context.Context, id int) error
-	GetAllFilms(ctx context.Context, sortBy string) ([]model.Film, error)   // получение списка фильмов
-	SearchFilm(ctx context.Context, actor, film string) (model.Film, error) // поиск фильмов
+	PatchFilm(ctx context.Context, id, version int, changes map[string]any, labels *model.FilmLabels) (int, error)
+	DeleteFilm(ctx context.Context, id, version int) error
+	GetFilmByID(ctx context.Context, id int) (model.Film, error)
+	GetAllFilms(ctx context.Context, params model.FilmListParams) (model.FilmPage, error)         // получение списка фильмов
+	SearchFilms(ctx context.Context, params model.FilmSearchParams) (model.FilmSearchPage, error) // поиск фильмов
 	MovieExistsById(ctx context.Context, id int) (bool, error)
 	MovieExistsByName(ctx context.Context, name string) (bool, error)
 }
@@ -24,13 +31,15 @@ func NewMovieRepository(db *sql.DB) MovieRepository {
 	}
 }
 
+// CreateFilm добавляет фильм вместе с актёрами и метками и записывает
+// в film его id, версию и id актёров
 func (s *Storage) CreateFilm(ctx context.Context, film *model.Film) error {
 	const op = "storage.postgres.AddedInfoFilm"
 
 	// Начинаем транзакцию
 	tx, err := s.db.Begin()
 	if err != nil {
-		return fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
 	}
 	defer tx.Rollback()
 
@@ -39,20 +48,22 @@ func (s *Storage) CreateFilm(ctx context.Context, film *model.Film) error {
 	err = tx.QueryRowContext(ctx, `
         INSERT INTO films (name, description, release_date, rating)
         VALUES ($1, $2, $3, $4)
-        RETURNING id`,
+        RETURNING id, version`,
 		film.Name, film.Description, film.Releasedate, film.Rating,
-	).Scan(&filmID)
+	).Scan(&filmID, &film.Version)
 	if err != nil {
-		return fmt.Errorf("%s: failed to insert film: %w", op, err)
+		return fmt.Errorf("%s: failed to insert film: %w", op, translateError(err))
 	}
 
+	film.Id = filmID
+
 	// Добавляем актёров
-	for _, actor := range film.ListActors {
+	for i, actor := range film.ListActors {
 		var actorID int
 
 		// Проверяем, существует ли актёр
 		err := tx.QueryRow(`
-            SELECT id FROM actors WHERE name = $1`,
+            SELECT id FROM people WHERE name = $1 AND deleted_at IS NULL`,
 			actor.Name,
 		).Scan(&actorID)
 
@@ -60,179 +71,402 @@ func (s *Storage) CreateFilm(ctx context.Context, film *model.Film) error {
 			if err == sql.ErrNoRows {
 				// Актёр не существует, создаём нового
 				err = tx.QueryRow(`
-                    INSERT INTO actors (name, gender, date_of_birth)
+                    INSERT INTO people (name, gender, date_of_birth)
                     VALUES ($1, $2, $3)
                     RETURNING id`,
 					actor.Name, actor.Gender, actor.DateOfBirth,
 				).Scan(&actorID)
 				if err != nil {
-					return fmt.Errorf("%s: failed to insert actor: %w", op, err)
+					return fmt.Errorf("%s: failed to insert actor: %w", op, translateError(err))
+				}
+
+				created := auditChange{action: model.AuditCreate, table: "people", id: actorID}
+				if err := created.record(ctx, tx); err != nil {
+					return dbError(op, err)
 				}
 			} else {
-				return fmt.Errorf("%s: failed to check actor existence: %w", op, err)
+				return fmt.Errorf("%s: failed to check actor existence: %w", op, translateError(err))
 			}
 		}
 
+		film.ListActors[i].Id = actorID
+
 		// Связываем фильм и актёра
 		_, err = tx.Exec(`
-            INSERT INTO actor_film (film_id, actor_id)
-            VALUES ($1, $2)`,
-			filmID, actorID,
+            INSERT INTO film_credits (film_id, person_id, role, character_name, position)
+            VALUES ($1, $2, 'actor', NULLIF($3, ''), NULLIF($4, 0))`,
+			filmID, actorID, actor.Character, actor.BillingOrder,
 		)
 		if err != nil {
-			return fmt.Errorf("%s: failed to insert film-actor link: %w", op, err)
+			return fmt.Errorf("%s: failed to insert film-actor link: %w", op, translateError(err))
 		}
 	}
 
+	if err := setFilmLabels(ctx, tx, filmID, &film.FilmLabels); err != nil {
+		return dbError(op, err)
+	}
+
+	created := auditChange{action: model.AuditCreate, table: "films", id: filmID}
+	if err := created.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
 	// Коммитим транзакцию
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
 	}
 
 	return nil
 }
 
+// UpdateFilm перезаписывает фильм, если его версия совпадает с film.Version
+// (0 - без проверки), и записывает в film.Version новую версию.
+// Не переданные (nil) жанры, страны и теги остаются прежними
 func (s *Storage) UpdateFilm(ctx context.Context, film *model.Film) error {
 	const op = "storage.postgres.ChangeInfoFilm"
 
-	query := `UPDATE films SET name = $1, description = $2, release_date = $3, rating = $4 WHERE id = $5`
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
 
-	_, err := s.db.ExecContext(ctx, query, film.Name, film.Description, film.Releasedate, film.Rating, film.Id)
+	change, err := trackChange(ctx, tx, model.AuditUpdate, "films", film.Id)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return dbError(op, err)
+	}
+
+	query := `
+		UPDATE films SET name = $1, description = $2, release_date = $3, rating = $4, version = version + 1
+		WHERE id = $5 AND deleted_at IS NULL AND ($6 = 0 OR version = $6)
+		RETURNING version`
+
+	err = tx.QueryRowContext(ctx, query, film.Name, film.Description, film.Releasedate, film.Rating, film.Id, film.Version).Scan(&film.Version)
+	if errors.Is(err, sql.ErrNoRows) && film.Version != 0 {
+		return s.checkVersioned(ctx, op, "films", film.Id)
+	}
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	if err := setFilmLabels(ctx, tx, film.Id, &film.FilmLabels); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
 	}
 
 	return nil
 }
 
-func (s *Storage) DeleteFilm(ctx context.Context, id int) error {
+// filmPatchColumns - колонки, которые можно менять через PatchFilm
+var filmPatchColumns = map[string]bool{"name": true, "description": true, "release_date": true, "rating": true}
+
+// PatchFilm меняет только колонки из changes и не-nil метки из labels при совпадении версии
+// и возвращает новую версию
+func (s *Storage) PatchFilm(ctx context.Context, id, version int, changes map[string]any, labels *model.FilmLabels) (int, error) {
+	const op = "storage.postgres.PatchFilm"
+
+	var args queryArgs
+	set, err := setClause(changes, filmPatchColumns, &args)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	// Изменились только метки - версия всё равно растёт
+	if set != "" {
+		set += ", "
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	change, err := trackChange(ctx, tx, model.AuditUpdate, "films", id)
+	if err != nil {
+		return 0, dbError(op, err)
+	}
+
+	query := `UPDATE films SET ` + set + `version = version + 1 WHERE id = ` + args.add(id) +
+		` AND deleted_at IS NULL AND version = ` + args.add(version) + ` RETURNING version`
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, s.checkVersioned(ctx, op, "films", id)
+	}
+	if err != nil {
+		return 0, dbError(op, err)
+	}
+
+	if err := setFilmLabels(ctx, tx, id, labels); err != nil {
+		return 0, dbError(op, err)
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return 0, dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return version, nil
+}
+
+// DeleteFilm переносит фильм в корзину, если его версия совпадает с version (0 - без проверки).
+// Состав и съёмочная группа остаются в film_credits и вернётся при восстановлении
+func (s *Storage) DeleteFilm(ctx context.Context, id, version int) error {
 	const op = "storage.postgres.DeleteInfoFilm"
 
-	query := `DELETE FROM films WHERE id = $1`
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	change, err := trackChange(ctx, tx, model.AuditDelete, "films", id)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	query := `
+		UPDATE films SET deleted_at = now(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND ($2 = 0 OR version = $2)`
+
+	res, err := tx.ExecContext(ctx, query, id, version)
+	if err != nil {
+		return dbError(op, err)
+	}
 
-	_, err := s.db.ExecContext(ctx, query, id)
+	err = checkAffected(op, res)
+	if errors.Is(err, domain.ErrNotFound) && version != 0 {
+		return s.checkVersioned(ctx, op, "films", id)
+	}
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return err
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
 	}
 
 	return nil
 }
 
-func (s *Storage) GetAllFilms(ctx context.Context, sortBy string) ([]model.Film, error) {
+func (s *Storage) GetFilmByID(ctx context.Context, id int) (model.Film, error) {
+	const op = "storage.postgres.GetFilmByID"
+
+	var film model.Film
+
+	query := `SELECT id, name, description, release_date, rating, user_score, votes, version FROM films WHERE id = $1 AND deleted_at IS NULL`
+	err := s.db.QueryRowContext(ctx, query, id).Scan(&film.Id, &film.Name, &film.Description, &film.Releasedate, &film.Rating,
+		&film.UserScore, &film.Votes, &film.Version)
+	if err != nil {
+		return model.Film{}, dbError(op, err)
+	}
+
+	films := []model.Film{film}
+	if err := s.attachFilmDetails(ctx, films); err != nil {
+		return model.Film{}, dbError(op, err)
+	}
+
+	return films[0], nil
+}
+
+// filmSortColumns - колонки, по которым разрешена сортировка списка фильмов
+var filmSortColumns = map[string]string{
+	"name":         "f.name",
+	"release_date": "f.release_date",
+	"rating":       "f.rating",
+	"user_score":   "f.user_score",
+}
+
+func (s *Storage) GetAllFilms(ctx context.Context, params model.FilmListParams) (model.FilmPage, error) {
 	const op = "storage.postgres.GetAllFilms"
 
-	orderClause := "ORDER BY f.rating DESC" // По умолчанию сортировка по рейтингу
-	switch sortBy {
-	case "name":
-		orderClause = "ORDER BY f.name ASC" // Явно указываем таблицу films
-	case "release_date":
-		orderClause = "ORDER BY f.release_date"
+	page := model.FilmPage{
+		Items:  []model.Film{},
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}
+
+	var args queryArgs
+	where := filmFilterConditions(params, &args)
+
+	// Общее количество считаем только по фильтрам, без учёта курсора
+	countQuery := "SELECT count(*) FROM films f" + whereClause(where)
+	if err := s.db.QueryRowContext(ctx, countQuery, args...).Scan(&page.Total); err != nil {
+		return page, dbError(op, err)
 	}
 
-	// Остальной код остается без изменений
+	column := filmSortColumns[params.Sort()]
+	direction := params.Direction()
+
+	// Для предыдущей страницы идём от курсора в обратную сторону,
+	// а затем разворачиваем результат
+	backward := params.Cursor != nil && params.Cursor.Backward
+	if backward {
+		direction = oppositeDirection(direction)
+	}
+
+	if params.Cursor != nil {
+		cmp := ">"
+		if direction == "desc" {
+			cmp = "<"
+		}
+		where = append(where, fmt.Sprintf("(%s, f.id) %s (%s, %s)",
+			column, cmp, args.add(params.Cursor.Key), args.add(params.Cursor.ID)))
+	}
+
+	// Берём на одну запись больше, чтобы понять, есть ли следующая страница
 	query := fmt.Sprintf(`
-        SELECT f.id, f.name, f.description, f.release_date, f.rating, 
-               a.id, a.name, a.gender, a.date_of_birth
-        FROM films f
-        JOIN actor_film af ON f.id = af.film_id
-        JOIN actors a ON a.id = af.actor_id
-        %s`, orderClause)
-
-	rows, err := s.db.QueryContext(ctx, query)
+        SELECT f.id, f.name, f.description, f.release_date, f.rating, f.user_score, f.votes, %s::text
+        FROM films f%s
+        ORDER BY %s %s, f.id %s
+        LIMIT %s OFFSET %s`,
+		column, whereClause(where), column, direction, direction,
+		args.add(params.Limit+1), args.add(params.Offset))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+		return page, dbError(op, err)
 	}
 	defer rows.Close()
 
-	filmMap := make(map[int]*model.Film)
+	var films []model.Film
+	var keys []string
 
 	for rows.Next() {
 		var film model.Film
-		var actor model.Actor
+		var key string
 
-		err = rows.Scan(
-			&film.Id, &film.Name, &film.Description, &film.Releasedate, &film.Rating,
-			&actor.Id, &actor.Name, &actor.Gender, &actor.DateOfBirth,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("%s: %w", op, err)
+		if err := rows.Scan(&film.Id, &film.Name, &film.Description, &film.Releasedate, &film.Rating, &film.UserScore, &film.Votes, &key); err != nil {
+			return page, dbError(op, err)
 		}
 
-		// Если фильм ещё не добавлен в filmMap, добавляем его
-		if _, exists := filmMap[film.Id]; !exists {
-			film.ListActors = []model.Actor{} // Инициализируем пустой слайс актёров
-			filmMap[film.Id] = &film
-		}
+		films = append(films, film)
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return page, dbError(op, err)
+	}
+
+	hasMore := len(films) > params.Limit
+	if hasMore {
+		films, keys = films[:params.Limit], keys[:params.Limit]
+	}
+
+	if backward {
+		slices.Reverse(films)
+		slices.Reverse(keys)
+	}
+
+	if len(films) == 0 {
+		return page, nil
+	}
+
+	hasNext, hasPrev := hasMore, params.Cursor != nil || params.Offset > 0
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
 
-		// Добавляем актёра к фильму
-		filmMap[film.Id].ListActors = append(filmMap[film.Id].ListActors, actor)
+	last := len(films) - 1
+	if hasNext {
+		page.NextCursor = model.Cursor{Sort: params.SortKey(), Key: keys[last], ID: films[last].Id}.Encode()
+	}
+	if hasPrev {
+		page.PrevCursor = model.Cursor{Sort: params.SortKey(), Key: keys[0], ID: films[0].Id, Backward: true}.Encode()
 	}
 
-	// Преобразуем filmMap в слайс фильмов
-	films := make([]model.Film, 0, len(filmMap))
-	for _, film := range filmMap {
-		films = append(films, *film)
+	if err := s.attachFilmDetails(ctx, films); err != nil {
+		return page, dbError(op, err)
 	}
 
-	return films, nil
+	page.Items = films
+
+	return page, nil
 }
 
-func (s *Storage) SearchFilm(ctx context.Context, actor, film string) (model.Film, error) {
-	const op = "storage.postgres.SearchFilm"
+// filmFilterConditions - условия WHERE для фильтров списка фильмов
+func filmFilterConditions(params model.FilmListParams, args *queryArgs) []string {
+	where := []string{"f.deleted_at IS NULL"}
 
-	query := `
-        SELECT 
-            f.id, f.name, f.description, f.release_date, f.rating,
-            a.id, a.name, a.gender, a.date_of_birth
-        FROM films f
-        JOIN actor_film af ON f.id = af.film_id
-        JOIN actors a ON af.actor_id = a.id
-        WHERE a.name ILIKE $1 AND f.name ILIKE $2
-        ORDER BY f.id` // Сортируем по ID фильма для группировки
-
-	rows, err := s.db.QueryContext(ctx, query, "%"+actor+"%", "%"+film+"%")
+	if params.MinRating != nil {
+		where = append(where, "f.rating >= "+args.add(*params.MinRating))
+	}
+	if params.MaxRating != nil {
+		where = append(where, "f.rating <= "+args.add(*params.MaxRating))
+	}
+	if params.ReleasedFrom != nil {
+		where = append(where, "f.release_date >= "+args.add(*params.ReleasedFrom))
+	}
+	if params.ReleasedTo != nil {
+		where = append(where, "f.release_date <= "+args.add(*params.ReleasedTo))
+	}
+	if params.ActorID != 0 {
+		where = append(where, "EXISTS (SELECT 1 FROM film_credits af WHERE af.film_id = f.id AND af.role = 'actor' AND af.person_id = "+args.add(params.ActorID)+")")
+	}
+	if params.NamePrefix != "" {
+		where = append(where, "f.name ILIKE "+args.add(escapeLike(params.NamePrefix)+"%"))
+	}
+
+	return append(where, filmLabelConditions(params.Labels, args)...)
+}
+
+// attachFilmActors одним запросом подгружает состав актёров для переданных фильмов
+func (s *Storage) attachFilmActors(ctx context.Context, films []model.Film) error {
+	ids := make([]int64, 0, len(films))
+	for _, film := range films {
+		ids = append(ids, int64(film.Id))
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT af.film_id, a.id, a.name, a.gender, a.date_of_birth,
+               COALESCE(af.character_name, ''), COALESCE(af.position, 0)
+        FROM film_credits af
+        JOIN people a ON a.id = af.person_id AND a.deleted_at IS NULL
+        WHERE af.film_id = ANY($1) AND af.role = 'actor'
+        ORDER BY af.position NULLS LAST, a.name, a.id`, pq.Array(ids))
 	if err != nil {
-		return model.Film{}, fmt.Errorf("%s: %w", op, err)
+		return err
 	}
 	defer rows.Close()
 
-	var filmFound model.Film
-	filmFound.ListActors = make([]model.Actor, 0)
-
+	cast := make(map[int][]model.CastMember, len(films))
 	for rows.Next() {
-		var currentActor model.Actor
+		var filmID int
+		var member model.CastMember
 
-		err := rows.Scan(
-			&filmFound.Id, &filmFound.Name, &filmFound.Description,
-			&filmFound.Releasedate, &filmFound.Rating,
-			&currentActor.Id, &currentActor.Name,
-			&currentActor.Gender, &currentActor.DateOfBirth,
-		)
+		err := rows.Scan(&filmID, &member.Id, &member.Name, &member.Gender, &member.DateOfBirth,
+			&member.Character, &member.BillingOrder)
 		if err != nil {
-			return model.Film{}, fmt.Errorf("%s: scan error: %w", op, err)
-		}
-
-		// Добавляем актёра только если он ещё не добавлен
-		if !containsActor(filmFound.ListActors, currentActor.Id) {
-			filmFound.ListActors = append(filmFound.ListActors, currentActor)
+			return err
 		}
+		cast[filmID] = append(cast[filmID], member)
 	}
 
-	if filmFound.Id == 0 {
-		return model.Film{}, sql.ErrNoRows
+	if err := rows.Err(); err != nil {
+		return err
 	}
 
-	return filmFound, nil
-}
-
-// Вспомогательная функция для проверки дубликатов актёров
-func containsActor(actors []model.Actor, actorID int) bool {
-	for _, a := range actors {
-		if a.Id == actorID {
-			return true
+	for i := range films {
+		films[i].ListActors = cast[films[i].Id]
+		if films[i].ListActors == nil {
+			films[i].ListActors = []model.CastMember{}
 		}
 	}
-	return false
+
+	return nil
 }
 
 func (s *Storage) MovieExistsById(ctx context.Context, id int) (bool, error) {
@@ -240,11 +474,11 @@ func (s *Storage) MovieExistsById(ctx context.Context, id int) (bool, error) {
 
 	var exists bool
 
-	query := `SELECT EXISTS(SELECT 1 FROM films WHERE id = $1)`
+	query := `SELECT EXISTS(SELECT 1 FROM films WHERE id = $1 AND deleted_at IS NULL)`
 
 	err := s.db.QueryRowContext(ctx, query, id).Scan(&exists)
 	if err != nil {
-		return false, fmt.Errorf("%s: %w", op, err)
+		return false, dbError(op, err)
 	}
 
 	return true, nil
@@ -255,11 +489,11 @@ func (s *Storage) MovieExistsByName(ctx context.Context, name string) (bool, err
 
 	var exists bool
 
-	query := `SELECT EXISTS(SELECT 1 FROM films WHERE name = $1)`
+	query := `SELECT EXISTS(SELECT 1 FROM films WHERE name = $1 AND deleted_at IS NULL)`
 
 	err := s.db.QueryRowContext(ctx, query, name).Scan(&exists)
 	if err != nil {
-		return false, fmt.Errorf("%s: %w", op, err)
+		return false, dbError(op, err)
 	}
 
 	return true, nil
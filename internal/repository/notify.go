@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"film-library/internal/utils/sl"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// catalogChannel - канал NOTIFY, в который триггеры сообщают об изменении
+// фильмов, актёров и составов
+const catalogChannel = "catalog_changed"
+
+// listenerPingInterval - как часто проверять соединение слушателя без уведомлений
+const listenerPingInterval = 90 * time.Second
+
+// WatchCatalog возвращает канал, в который приходит сигнал после каждой
+// зафиксированной транзакции, изменившей каталог. Сигналы не копятся: пока
+// прошлый не прочитан, новые схлопываются в него. После переподключения к базе
+// тоже приходит сигнал - уведомления за время разрыва потеряны.
+// Слушатель закрывается с отменой ctx
+func (s *Storage) WatchCatalog(ctx context.Context, log *slog.Logger) (<-chan struct{}, error) {
+	const op = "storage.postgres.WatchCatalog"
+
+	log = log.With(slog.String("channel", catalogChannel))
+
+	listener := pq.NewListener(s.dsn, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected, pq.ListenerEventConnectionAttemptFailed:
+			log.Warn("catalog listener disconnected", sl.Err(err))
+		case pq.ListenerEventReconnected:
+			log.Info("catalog listener reconnected")
+		}
+	})
+	if err := listener.Listen(catalogChannel); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer listener.Close()
+
+		ping := time.NewTicker(listenerPingInterval)
+		defer ping.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-listener.Notify:
+				// nil вместо уведомления означает переподключение
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			case <-ping.C:
+				go listener.Ping()
+			}
+		}
+	}()
+
+	return changes, nil
+}
@@ -5,7 +5,7 @@ import (
 	"database/sql"
 	"film-library/internal/config"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
@@ -16,14 +16,20 @@ import (
 )
 
 type Storage struct {
-	db *sql.DB
+	db  *sql.DB
+	dsn string // нужна отдельному соединению для LISTEN
 }
 
 func (s *Storage) DB() *sql.DB {
 	return s.db
 }
 
-func Connect(cfg config.Database) (*Storage, error) {
+// Close закрывает пул соединений с базой
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+func Connect(cfg config.Database, log *slog.Logger) (*Storage, error) {
 	const op = "storage.postgre.New"
 
 	// Формируем строку подключения с актуальными значениями
@@ -35,7 +41,7 @@ func Connect(cfg config.Database) (*Storage, error) {
 		getEnv("DB_NAME", cfg.Dbname),
 	)
 
-	log.Printf("Attempting to connect to database with: %s", hidePassword(sqlInfo))
+	log.Info("connecting to database", slog.String("dsn", hidePassword(sqlInfo)))
 
 	db, err := sql.Open("postgres", sqlInfo)
 	if err != nil {
@@ -49,7 +55,7 @@ func Connect(cfg config.Database) (*Storage, error) {
 		return nil, fmt.Errorf("%s: failed to ping database: %w", op, err)
 	}
 
-	log.Println("Database connection established")
+	log.Info("database connection established")
 
 	// Получаем абсолютный путь к миграциям
 	migrationsDir, err := filepath.Abs("./migrations")
@@ -57,12 +63,12 @@ func Connect(cfg config.Database) (*Storage, error) {
 		return nil, fmt.Errorf("%s: failed to get migrations path: %w", op, err)
 	}
 
-	log.Printf("Applying migrations from: %s", migrationsDir)
+	log.Info("applying migrations", slog.String("dir", migrationsDir))
 	if err := goose.Up(db, migrationsDir); err != nil {
 		return nil, fmt.Errorf("%s: failed to apply migrations: %w", op, err)
 	}
 
-	return &Storage{db: db}, nil
+	return &Storage{db: db, dsn: sqlInfo}, nil
 }
 
 // getEnv возвращает значение переменной окружения или значение по умолчанию
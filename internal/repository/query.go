@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// queryArgs собирает позиционные параметры запроса ($1, $2, ...)
+type queryArgs []any
+
+// add добавляет значение и возвращает его плейсхолдер
+func (a *queryArgs) add(v any) string {
+	*a = append(*a, v)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+// whereClause склеивает условия через AND
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// setClause собирает "col = $n, ..." для изменённых колонок в стабильном порядке.
+// Колонки вне allowed отклоняются: имена подставляются в SQL как есть
+func setClause(changes map[string]any, allowed map[string]bool, args *queryArgs) (string, error) {
+	cols := make([]string, 0, len(changes))
+	for col := range changes {
+		if !allowed[col] {
+			return "", fmt.Errorf("column %q cannot be updated", col)
+		}
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+
+	parts := make([]string, len(cols))
+	for i, col := range cols {
+		parts[i] = col + " = " + args.add(changes[col])
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// oppositeDirection разворачивает направление сортировки
+func oppositeDirection(dir string) string {
+	if dir == "desc" {
+		return "asc"
+	}
+	return "desc"
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetClause(t *testing.T) {
+	date := time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	var args queryArgs
+	set, err := setClause(map[string]any{"rating": float32(9), "name": "Matrix", "release_date": date}, filmPatchColumns, &args)
+	require.NoError(t, err)
+	require.Equal(t, "name = $1, rating = $2, release_date = $3", set)
+	require.Equal(t, queryArgs{"Matrix", float32(9), date}, args)
+
+	_, err = setClause(map[string]any{"id; DROP TABLE films": 1}, filmPatchColumns, &args)
+	require.Error(t, err)
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"film-library/internal/model"
+	"fmt"
+)
+
+type RecommendationRepository interface {
+	GetSimilarFilms(ctx context.Context, params model.SimilarParams) (model.SimilarPage, error)
+	GetRecommendations(ctx context.Context, params model.RecommendationParams) (model.RecommendationPage, error)
+	RefreshRecommendations(ctx context.Context) error
+}
+
+func NewRecommendationRepository(db *sql.DB) RecommendationRepository {
+	return &Storage{
+		db: db,
+	}
+}
+
+// refreshSimilar пересчитывает похожие фильмы. Вес пары - число общих актёров,
+// умноженное на (1 + рейтинг/10) и на множитель свежести: 1 для фильмов этого
+// года, 0.75 для вышедших 10 лет назад, для старых фильмов стремится к 0.5.
+// Фильмы и актёры из корзины не учитываются. $1 - сколько похожих фильмов оставить на фильм
+const refreshSimilar = `
+        WITH film_cast AS (
+            SELECT DISTINCT fc.film_id, fc.person_id
+            FROM film_credits fc
+            JOIN films f ON f.id = fc.film_id AND f.deleted_at IS NULL
+            JOIN people p ON p.id = fc.person_id AND p.deleted_at IS NULL
+            WHERE fc.role = 'actor'
+        ), shared AS (
+            SELECT a.film_id, b.film_id AS similar_id, count(*) AS shared_actors
+            FROM film_cast a
+            JOIN film_cast b ON b.person_id = a.person_id AND b.film_id <> a.film_id
+            GROUP BY a.film_id, b.film_id
+        ), scored AS (
+            SELECT s.film_id, s.similar_id, s.shared_actors,
+                   s.shared_actors * (1 + f.rating / 10) *
+                   (1 + 10 / (10 + GREATEST(extract(year FROM current_date) - extract(year FROM f.release_date), 0))) / 2 AS score
+            FROM shared s
+            JOIN films f ON f.id = s.similar_id
+        ), ranked AS (
+            SELECT *, row_number() OVER (PARTITION BY film_id ORDER BY score DESC, similar_id) AS n
+            FROM scored
+        )
+        INSERT INTO film_similar (film_id, similar_id, score, shared_actors)
+        SELECT film_id, similar_id, score, shared_actors
+        FROM ranked
+        WHERE n <= $1`
+
+// refreshUserRecommendations пересчитывает рекомендации из двух источников:
+//   - cast: похожие фильмы для понравившихся (оценка от $2) и просмотренных без
+//     оценки фильмов, вес похожести умножается на оценку/10 или 0.5 за просмотр;
+//   - ratings: фильмы, которые высоко оценили пользователи, совпавшие с
+//     пользователем в высоких оценках; вес - число совпадений на оценку/10.
+//
+// Веса из обоих источников складываются. Просмотренные, оценённые и удалённые
+// в корзину фильмы не рекомендуются. $1 - сколько рекомендаций оставить на пользователя
+const refreshUserRecommendations = `
+        WITH seeds AS (
+            SELECT r.user_id, r.film_id, r.score / 10.0 AS weight
+            FROM user_ratings r
+            WHERE r.score >= $2
+            UNION ALL
+            SELECT w.user_id, w.film_id, 0.5
+            FROM watched_films w
+            WHERE NOT EXISTS (SELECT 1 FROM user_ratings r WHERE r.user_id = w.user_id AND r.film_id = w.film_id)
+        ), by_cast AS (
+            SELECT s.user_id, fs.similar_id AS film_id, sum(fs.score * s.weight) AS score
+            FROM seeds s
+            JOIN film_similar fs ON fs.film_id = s.film_id
+            GROUP BY s.user_id, fs.similar_id
+        ), neighbours AS (
+            SELECT a.user_id, b.user_id AS neighbour_id, count(*) AS common
+            FROM user_ratings a
+            JOIN user_ratings b ON b.film_id = a.film_id AND b.user_id <> a.user_id AND b.score >= $2
+            WHERE a.score >= $2
+            GROUP BY a.user_id, b.user_id
+        ), by_ratings AS (
+            SELECT n.user_id, r.film_id, sum(n.common * r.score / 10.0) AS score
+            FROM neighbours n
+            JOIN user_ratings r ON r.user_id = n.neighbour_id AND r.score >= $2
+            GROUP BY n.user_id, r.film_id
+        ), candidates AS (
+            SELECT user_id, film_id, sum(score) AS score,
+                   bool_or(source = 'cast') AS from_cast, bool_or(source = 'ratings') AS from_ratings
+            FROM (
+                SELECT user_id, film_id, score, 'cast' AS source FROM by_cast
+                UNION ALL
+                SELECT user_id, film_id, score, 'ratings' FROM by_ratings
+            ) c
+            GROUP BY user_id, film_id
+        ), ranked AS (
+            SELECT c.*, row_number() OVER (PARTITION BY c.user_id ORDER BY c.score DESC, c.film_id) AS n
+            FROM candidates c
+            JOIN films f ON f.id = c.film_id AND f.deleted_at IS NULL
+            WHERE NOT EXISTS (SELECT 1 FROM watched_films w WHERE w.user_id = c.user_id AND w.film_id = c.film_id)
+              AND NOT EXISTS (SELECT 1 FROM user_ratings r WHERE r.user_id = c.user_id AND r.film_id = c.film_id)
+        )
+        INSERT INTO user_recommendations (user_id, film_id, score, reason)
+        SELECT user_id, film_id, score,
+               CASE WHEN from_cast AND from_ratings THEN 'cast_and_ratings'
+                    WHEN from_cast THEN 'cast'
+                    ELSE 'ratings' END
+        FROM ranked
+        WHERE n <= $1`
+
+// RefreshRecommendations пересчитывает похожие фильмы и рекомендации одной
+// транзакцией: до её завершения запросы видят прежние данные. Блокировка
+// EXCLUSIVE не мешает чтению, но не даёт двум пересчётам идти одновременно
+func (s *Storage) RefreshRecommendations(ctx context.Context) error {
+	const op = "storage.postgres.RefreshRecommendations"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	steps := []struct {
+		name  string
+		query string
+		args  []any
+	}{
+		{"lock", `LOCK TABLE film_similar, user_recommendations IN EXCLUSIVE MODE`, nil},
+		{"clear similar films", `DELETE FROM film_similar`, nil},
+		{"fill similar films", refreshSimilar, []any{model.MaxSimilarFilms}},
+		{"clear recommendations", `DELETE FROM user_recommendations`, nil},
+		{"fill recommendations", refreshUserRecommendations, []any{model.MaxRecommendations, model.LikedScore}},
+	}
+	for _, step := range steps {
+		if _, err := tx.ExecContext(ctx, step.query, step.args...); err != nil {
+			return fmt.Errorf("%s: failed to %s: %w", op, step.name, translateError(err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+	return nil
+}
+
+// GetSimilarFilms - похожие фильмы из последнего пересчёта; фильмы, попавшие
+// с тех пор в корзину, пропускаются
+func (s *Storage) GetSimilarFilms(ctx context.Context, params model.SimilarParams) (model.SimilarPage, error) {
+	const op = "storage.postgres.GetSimilarFilms"
+
+	page := model.SimilarPage{
+		Items:  []model.SimilarFilm{},
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}
+
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM films WHERE id = $1 AND deleted_at IS NULL)`, params.FilmID).Scan(&exists); err != nil {
+		return page, dbError(op, err)
+	}
+	if !exists {
+		return page, dbError(op, sql.ErrNoRows)
+	}
+
+	const from = `
+        FROM film_similar s
+        JOIN films f ON f.id = s.similar_id AND f.deleted_at IS NULL
+        WHERE s.film_id = $1`
+
+	if err := s.db.QueryRowContext(ctx, `SELECT count(*)`+from, params.FilmID).Scan(&page.Total); err != nil {
+		return page, dbError(op, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT f.id, f.name, f.release_date, f.rating, s.score, s.shared_actors`+from+`
+        ORDER BY s.score DESC, s.similar_id
+        LIMIT $2 OFFSET $3`, params.FilmID, params.Limit, params.Offset)
+	if err != nil {
+		return page, dbError(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var film model.SimilarFilm
+		if err := rows.Scan(&film.Id, &film.Name, &film.Releasedate, &film.Rating, &film.Score, &film.SharedActors); err != nil {
+			return page, dbError(op, err)
+		}
+		page.Items = append(page.Items, film)
+	}
+	if err := rows.Err(); err != nil {
+		return page, dbError(op, err)
+	}
+
+	return page, nil
+}
+
+// recommendationFrom - рекомендации пользователя $1 без фильмов, которые он
+// посмотрел или оценил после пересчёта
+const recommendationFrom = `
+        FROM user_recommendations r
+        JOIN films f ON f.id = r.film_id AND f.deleted_at IS NULL
+        WHERE r.user_id = $1
+          AND NOT EXISTS (SELECT 1 FROM watched_films w WHERE w.user_id = r.user_id AND w.film_id = r.film_id)
+          AND NOT EXISTS (SELECT 1 FROM user_ratings ur WHERE ur.user_id = r.user_id AND ur.film_id = r.film_id)`
+
+// GetRecommendations - рекомендации пользователя из последнего пересчёта, лучшие первыми
+func (s *Storage) GetRecommendations(ctx context.Context, params model.RecommendationParams) (model.RecommendationPage, error) {
+	const op = "storage.postgres.GetRecommendations"
+
+	page := model.RecommendationPage{
+		Items:  []model.Recommendation{},
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}
+
+	if err := s.db.QueryRowContext(ctx, `SELECT count(*)`+recommendationFrom, params.UserID).Scan(&page.Total); err != nil {
+		return page, dbError(op, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT f.id, f.name, f.release_date, f.rating, r.score, r.reason`+recommendationFrom+`
+        ORDER BY r.score DESC, r.film_id
+        LIMIT $2 OFFSET $3`, params.UserID, params.Limit, params.Offset)
+	if err != nil {
+		return page, dbError(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rec model.Recommendation
+		if err := rows.Scan(&rec.Id, &rec.Name, &rec.Releasedate, &rec.Rating, &rec.Score, &rec.Reason); err != nil {
+			return page, dbError(op, err)
+		}
+		page.Items = append(page.Items, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return page, dbError(op, err)
+	}
+
+	return page, nil
+}
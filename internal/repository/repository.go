@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"film-library/internal/model"
+	"time"
 )
 
 //go:generate mockgen -source=repository.go -destination=mocks/mock.go
@@ -12,13 +13,21 @@ import (
 type Authorization interface {
 	CreateUser(ctx context.Context, user *model.User) error
 	VerifyUser(ctx context.Context, username string) (*model.User, error)
+	GetUserByID(ctx context.Context, id int) (*model.User, error)
+	SaveRefreshToken(ctx context.Context, token model.RefreshToken) error
+	ConsumeRefreshToken(ctx context.Context, hash string) (model.RefreshToken, error)
+	RevokeSession(ctx context.Context, userID int, refreshHash, accessJTI string, accessExpiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	PurgeExpiredTokens(ctx context.Context) (model.TokenPurgeResult, error) // удаление истёкших записей
 }
 
 // ActorRepository
 type Actor interface {
 	CreateActor(ctx context.Context, actor *model.Actor) error
 	UpdateActor(ctx context.Context, actor *model.Actor) error
-	DeleteActor(ctx context.Context, id int) error
+	PatchActor(ctx context.Context, id, version int, changes map[string]any) (int, error) // только переданные колонки
+	DeleteActor(ctx context.Context, id, version int) error
+	GetActorByID(ctx context.Context, id int) (model.ActorWithFilms, error)
 	ActorExistsById(ctx context.Context, id int) (bool, error)
 	ActorExistsByName(ctx context.Context, name string) (bool, error)
 }
@@ -27,16 +36,135 @@ type Actor interface {
 type Movie interface {
 	CreateFilm(ctx context.Context, film *model.Film) error
 	UpdateFilm(ctx context.Context, film *model.Film) error
-	DeleteFilm(ctx context.Context, id int) error
-	GetAllFilms(ctx context.Context, sortBy string) ([]model.Film, error)   // получение списка фильмов
-	SearchFilm(ctx context.Context, actor, film string) (model.Film, error) // поиск фильмов
+	PatchFilm(ctx context.Context, id, version int, changes map[string]any, labels *model.FilmLabels) (int, error) // только переданные колонки и метки
+	DeleteFilm(ctx context.Context, id, version int) error
+	GetFilmByID(ctx context.Context, id int) (model.Film, error)
+	GetAllFilms(ctx context.Context, params model.FilmListParams) (model.FilmPage, error)         // получение списка фильмов
+	SearchFilms(ctx context.Context, params model.FilmSearchParams) (model.FilmSearchPage, error) // поиск фильмов
 	MovieExistsById(ctx context.Context, id int) (bool, error)
 	MovieExistsByName(ctx context.Context, name string) (bool, error)
 }
 
 // ActorMovieRepository
 type ActorMovie interface {
-	GetActorsWithFilms(ctx context.Context) (map[int]model.ActorWithFilms, error)
+	GetActors(ctx context.Context, params model.ActorListParams) (model.ActorPage, error)
+	GetFilmsByActor(ctx context.Context, actorID int) ([]model.Film, error)
+}
+
+// CastingRepository
+type Casting interface {
+	GetCast(ctx context.Context, filmID int) ([]model.CastMember, error)
+	ReplaceCast(ctx context.Context, filmID int, entries []model.CastingEntry) error
+	AddCastMember(ctx context.Context, filmID int, entry model.CastingEntry) error
+	RemoveCastMember(ctx context.Context, filmID, actorID int) error
+}
+
+// CreditRepository
+type Credit interface {
+	GetCredits(ctx context.Context, filmID int) ([]model.Credit, error)
+	ReplaceCredits(ctx context.Context, filmID int, credits []model.Credit) error
+	AddCredit(ctx context.Context, filmID int, credit model.Credit) error
+	RemoveCredit(ctx context.Context, filmID, personID int, role string) error
+	GetFilmography(ctx context.Context, personID int) (model.Filmography, error) // фильмы участника по ролям
+}
+
+// AccessRepository
+type Access interface {
+	GetRoles(ctx context.Context) ([]model.Role, error)
+	SetUserRole(ctx context.Context, userID int, role string) (model.User, error)
+}
+
+// TrashRepository
+type Trash interface {
+	GetTrash(ctx context.Context) (model.Trash, error)
+	RestoreFilm(ctx context.Context, id int) (model.Film, error)
+	RestoreActor(ctx context.Context, id int) (model.ActorWithFilms, error)
+	PurgeTrash(ctx context.Context, before time.Time) (model.PurgeResult, error) // окончательное удаление
+}
+
+// ImportRepository - массовая загрузка: rows отдаёт строки по одной, ошибка rows отменяет импорт
+type Import interface {
+	ImportFilms(ctx context.Context, rows func(yield func(model.FilmImportRow) bool) error, dryRun bool) (model.ImportResult, error)
+	ImportActors(ctx context.Context, rows func(yield func(model.Actor) bool) error, dryRun bool) (model.ImportResult, error)
+	ImportCast(ctx context.Context, rows func(yield func(model.CastImportRow) bool) error, dryRun bool) (model.ImportResult, error)
+}
+
+// ExportRepository - выгрузка: emit вызывается для каждой строки, его ошибка прерывает выгрузку
+type Export interface {
+	ExportFilms(ctx context.Context, params model.ExportParams, emit func(model.Film) error) error
+	ExportActors(ctx context.Context, params model.ExportParams, emit func(model.Actor) error) error
+	ExportCasting(ctx context.Context, params model.ExportParams, emit func(model.CastingRecord) error) error
+}
+
+// AuditRepository - чтение журнала; пишут в него сами изменения каталога
+type Audit interface {
+	GetAuditLog(ctx context.Context, filter model.AuditFilter) (model.AuditPage, error)
+}
+
+// StatsRepository - статистика из материализованных представлений
+type Stats interface {
+	GetActorStats(ctx context.Context, params model.ActorStatsParams) (model.ActorStatsPage, error)
+	GetActorStatsByID(ctx context.Context, id int) (model.ActorStats, error)
+	GetYearStats(ctx context.Context, years model.YearRange) ([]model.YearStats, error)
+	GetRatingHistogram(ctx context.Context, years model.YearRange) ([]model.RatingBucket, error)
+	GetCostars(ctx context.Context, params model.CostarParams) (model.CostarPage, error)
+	RefreshStats(ctx context.Context) error // пересчёт представлений
+}
+
+// TaxonomyRepository - справочники жанров и стран, теги и фасеты списка фильмов
+type Taxonomy interface {
+	GetGenres(ctx context.Context) ([]model.Genre, error)
+	GetGenre(ctx context.Context, id int) (model.Genre, error)
+	CreateGenre(ctx context.Context, genre *model.Genre) error
+	UpdateGenre(ctx context.Context, genre *model.Genre) error
+	DeleteGenre(ctx context.Context, id int) error
+	GetCountries(ctx context.Context) ([]model.Country, error)
+	GetCountry(ctx context.Context, code string) (model.Country, error)
+	CreateCountry(ctx context.Context, country *model.Country) error
+	UpdateCountry(ctx context.Context, country *model.Country) error
+	DeleteCountry(ctx context.Context, code string) error
+	GetTags(ctx context.Context, params model.TagListParams) (model.TagPage, error)
+	GetTag(ctx context.Context, id int) (model.Tag, error)
+	CreateTag(ctx context.Context, tag *model.Tag) error
+	UpdateTag(ctx context.Context, tag *model.Tag) error
+	DeleteTag(ctx context.Context, id int) error
+	GetFilmFacets(ctx context.Context, params model.FilmListParams) (model.FilmFacets, error) // счётчики по меткам
+}
+
+// ReviewRepository - оценки и рецензии пользователей
+type Review interface {
+	GetRating(ctx context.Context, userID, filmID int) (model.FilmScore, error)
+	SetRating(ctx context.Context, userID, filmID, score int) (model.FilmScore, error) // пересчитывает среднюю оценку фильма
+	DeleteRating(ctx context.Context, userID, filmID int) (model.FilmScore, error)
+	GetReviews(ctx context.Context, params model.ReviewListParams) (model.ReviewPage, error)
+	GetUserReview(ctx context.Context, userID, filmID int) (model.Review, error)
+	SaveReview(ctx context.Context, userID, filmID int, body string) (model.Review, error) // изменённая рецензия снова ждёт модерации
+	DeleteReview(ctx context.Context, userID, filmID int) error
+	ModerateReview(ctx context.Context, id, moderatorID int, status string) (model.Review, error)
+}
+
+// WatchlistRepository - личные списки фильмов, отметки о просмотре и лента пользователя.
+// Изменять можно только свои записи, чужие для пользователя не существуют
+type Watchlist interface {
+	GetWatchlists(ctx context.Context, ownerID int, publicOnly bool) ([]model.Watchlist, error)
+	GetWatchlist(ctx context.Context, id, viewerID int) (model.WatchlistWithFilms, error) // свой или публичный
+	CreateWatchlist(ctx context.Context, list *model.Watchlist) error
+	UpdateWatchlist(ctx context.Context, list *model.Watchlist) error
+	DeleteWatchlist(ctx context.Context, id, userID int) error
+	AddWatchlistFilm(ctx context.Context, userID, id, filmID, position int) error
+	RemoveWatchlistFilm(ctx context.Context, userID, id, filmID int) error
+	ReplaceWatchlistFilms(ctx context.Context, userID, id int, filmIDs []int) error // содержимое и порядок
+	GetWatched(ctx context.Context, params model.WatchedListParams) (model.WatchedPage, error)
+	SetWatched(ctx context.Context, userID, filmID int, watchedOn time.Time) error
+	DeleteWatched(ctx context.Context, userID, filmID int) error
+	GetFeed(ctx context.Context, params model.FeedParams) (model.FeedPage, error)
+}
+
+// RecommendationRepository - похожие фильмы и рекомендации, заранее посчитанные фоновой задачей
+type Recommendation interface {
+	GetSimilarFilms(ctx context.Context, params model.SimilarParams) (model.SimilarPage, error)
+	GetRecommendations(ctx context.Context, params model.RecommendationParams) (model.RecommendationPage, error)
+	RefreshRecommendations(ctx context.Context) error // пересчёт обеих таблиц
 }
 
 type Repository struct {
@@ -44,13 +172,37 @@ type Repository struct {
 	Actor
 	Movie
 	ActorMovie
+	Casting
+	Credit
+	Access
+	Trash
+	Audit
+	Import
+	Export
+	Stats
+	Taxonomy
+	Review
+	Watchlist
+	Recommendation
 }
 
 func NewRepository(db *sql.DB) *Repository {
 	return &Repository{
-		Authorization: NewAuthRepository(db),
-		Actor:         NewActorRepository(db),
-		Movie:         NewMovieRepository(db),
-		ActorMovie:    NewActorMovieRepository(db),
+		Authorization:  NewAuthRepository(db),
+		Actor:          NewActorRepository(db),
+		Movie:          NewMovieRepository(db),
+		ActorMovie:     NewActorMovieRepository(db),
+		Casting:        NewCastingRepository(db),
+		Credit:         NewCreditRepository(db),
+		Access:         NewAccessRepository(db),
+		Trash:          NewTrashRepository(db),
+		Audit:          NewAuditRepository(db),
+		Import:         NewImportRepository(db),
+		Export:         NewExportRepository(db),
+		Stats:          NewStatsRepository(db),
+		Taxonomy:       NewTaxonomyRepository(db),
+		Review:         NewReviewRepository(db),
+		Watchlist:      NewWatchlistRepository(db),
+		Recommendation: NewRecommendationRepository(db),
 	}
 }
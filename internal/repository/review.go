@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"film-library/internal/model"
+	"fmt"
+)
+
+type ReviewRepository interface {
+	GetRating(ctx context.Context, userID, filmID int) (model.FilmScore, error)
+	SetRating(ctx context.Context, userID, filmID, score int) (model.FilmScore, error)
+	DeleteRating(ctx context.Context, userID, filmID int) (model.FilmScore, error)
+	GetReviews(ctx context.Context, params model.ReviewListParams) (model.ReviewPage, error)
+	GetUserReview(ctx context.Context, userID, filmID int) (model.Review, error)
+	SaveReview(ctx context.Context, userID, filmID int, body string) (model.Review, error)
+	DeleteReview(ctx context.Context, userID, filmID int) error
+	ModerateReview(ctx context.Context, id, moderatorID int, status string) (model.Review, error)
+}
+
+func NewReviewRepository(db *sql.DB) ReviewRepository {
+	return &Storage{
+		db: db,
+	}
+}
+
+// reviewSelect - рецензия с именем автора и его оценкой фильма
+const reviewSelect = `
+        SELECT r.id, r.film_id, r.user_id, u.name, COALESCE(ur.score, 0), r.body, r.status, r.created_at, r.updated_at
+        FROM reviews r
+        JOIN users u ON u.id = r.user_id
+        LEFT JOIN user_ratings ur ON ur.user_id = r.user_id AND ur.film_id = r.film_id`
+
+// scanner - общее у *sql.Row и *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanReview(row scanner) (model.Review, error) {
+	var r model.Review
+	err := row.Scan(&r.Id, &r.FilmID, &r.UserID, &r.Username, &r.Score, &r.Body, &r.Status, &r.CreatedAt, &r.UpdatedAt)
+	return r, err
+}
+
+func (s *Storage) GetRating(ctx context.Context, userID, filmID int) (model.FilmScore, error) {
+	const op = "storage.postgres.GetRating"
+
+	score := model.FilmScore{FilmID: filmID}
+	err := s.db.QueryRowContext(ctx, `
+        SELECT f.user_score, f.votes, COALESCE(ur.score, 0)
+        FROM films f
+        LEFT JOIN user_ratings ur ON ur.film_id = f.id AND ur.user_id = $2
+        WHERE f.id = $1 AND f.deleted_at IS NULL`, filmID, userID,
+	).Scan(&score.UserScore, &score.Votes, &score.Score)
+	if err != nil {
+		return model.FilmScore{}, dbError(op, err)
+	}
+
+	return score, nil
+}
+
+// SetRating ставит или меняет оценку пользователя и в той же транзакции пересчитывает
+// среднюю оценку фильма. Блокировка строки фильма выстраивает оценки одного фильма в очередь
+func (s *Storage) SetRating(ctx context.Context, userID, filmID, score int) (model.FilmScore, error) {
+	const op = "storage.postgres.SetRating"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.FilmScore{}, fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	if err := lockFilm(ctx, tx, filmID); err != nil {
+		return model.FilmScore{}, dbError(op, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO user_ratings (user_id, film_id, score)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (user_id, film_id) DO UPDATE
+        SET score = EXCLUDED.score, updated_at = now()`, userID, filmID, score)
+	if err != nil {
+		return model.FilmScore{}, dbError(op, err)
+	}
+
+	result, err := updateFilmScore(ctx, tx, filmID)
+	if err != nil {
+		return model.FilmScore{}, dbError(op, err)
+	}
+	result.Score = score
+
+	if err := tx.Commit(); err != nil {
+		return model.FilmScore{}, fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return result, nil
+}
+
+func (s *Storage) DeleteRating(ctx context.Context, userID, filmID int) (model.FilmScore, error) {
+	const op = "storage.postgres.DeleteRating"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.FilmScore{}, fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	if err := lockFilm(ctx, tx, filmID); err != nil {
+		return model.FilmScore{}, dbError(op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM user_ratings WHERE user_id = $1 AND film_id = $2`, userID, filmID)
+	if err != nil {
+		return model.FilmScore{}, dbError(op, err)
+	}
+	if err := checkAffected(op, res); err != nil {
+		return model.FilmScore{}, err
+	}
+
+	result, err := updateFilmScore(ctx, tx, filmID)
+	if err != nil {
+		return model.FilmScore{}, dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.FilmScore{}, fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return result, nil
+}
+
+// updateFilmScore пересчитывает среднюю оценку и число голосов фильма по всем оценкам.
+// Версия фильма не меняется: голоса пользователей не должны мешать правкам редактора
+func updateFilmScore(ctx context.Context, tx *sql.Tx, filmID int) (model.FilmScore, error) {
+	score := model.FilmScore{FilmID: filmID}
+	err := tx.QueryRowContext(ctx, `
+        UPDATE films f
+        SET user_score = s.avg_score, votes = s.votes
+        FROM (SELECT COALESCE(round(avg(score), 2), 0) AS avg_score, count(*) AS votes
+              FROM user_ratings WHERE film_id = $1) s
+        WHERE f.id = $1
+        RETURNING f.user_score, f.votes`, filmID,
+	).Scan(&score.UserScore, &score.Votes)
+	if err != nil {
+		return model.FilmScore{}, fmt.Errorf("failed to update film score: %w", translateError(err))
+	}
+	return score, nil
+}
+
+// GetReviews - рецензии под фильтром, новые первыми. Для фильма сначала проверяется,
+// что он есть и не в корзине
+func (s *Storage) GetReviews(ctx context.Context, params model.ReviewListParams) (model.ReviewPage, error) {
+	const op = "storage.postgres.GetReviews"
+
+	page := model.ReviewPage{
+		Items:  []model.Review{},
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}
+
+	var args queryArgs
+	var where []string
+	if params.FilmID != 0 {
+		var exists bool
+		if err := s.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM films WHERE id = $1 AND deleted_at IS NULL)`, params.FilmID).Scan(&exists); err != nil {
+			return page, dbError(op, err)
+		}
+		if !exists {
+			return page, dbError(op, sql.ErrNoRows)
+		}
+		where = append(where, "r.film_id = "+args.add(params.FilmID))
+	}
+	if params.Status != "" {
+		where = append(where, "r.status = "+args.add(params.Status))
+	}
+
+	countQuery := "SELECT count(*) FROM reviews r" + whereClause(where)
+	if err := s.db.QueryRowContext(ctx, countQuery, args...).Scan(&page.Total); err != nil {
+		return page, dbError(op, err)
+	}
+
+	query := fmt.Sprintf(`%s%s
+        ORDER BY r.created_at DESC, r.id DESC
+        LIMIT %s OFFSET %s`,
+		reviewSelect, whereClause(where), args.add(params.Limit), args.add(params.Offset))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return page, dbError(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return page, dbError(op, err)
+		}
+		page.Items = append(page.Items, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return page, dbError(op, err)
+	}
+
+	return page, nil
+}
+
+// GetUserReview - своя рецензия пользователя в любом статусе
+func (s *Storage) GetUserReview(ctx context.Context, userID, filmID int) (model.Review, error) {
+	const op = "storage.postgres.GetUserReview"
+
+	review, err := scanReview(s.db.QueryRowContext(ctx, reviewSelect+`
+        JOIN films f ON f.id = r.film_id AND f.deleted_at IS NULL
+        WHERE r.user_id = $1 AND r.film_id = $2`, userID, filmID))
+	if err != nil {
+		return model.Review{}, dbError(op, err)
+	}
+
+	return review, nil
+}
+
+// SaveReview создаёт или меняет рецензию пользователя. Изменённая рецензия,
+// даже одобренная или скрытая, снова уходит на модерацию
+func (s *Storage) SaveReview(ctx context.Context, userID, filmID int, body string) (model.Review, error) {
+	const op = "storage.postgres.SaveReview"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.Review{}, fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	// Рецензию на фильм из корзины не принимаем, а удалить его до коммита не дадим
+	var id int
+	err = tx.QueryRowContext(ctx, `SELECT id FROM films WHERE id = $1 AND deleted_at IS NULL FOR SHARE`, filmID).Scan(&id)
+	if err != nil {
+		return model.Review{}, dbError(op, err)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO reviews (user_id, film_id, body)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (user_id, film_id) DO UPDATE
+        SET body = EXCLUDED.body, status = 'pending', moderated_by = NULL, moderated_at = NULL, updated_at = now()
+        RETURNING id`, userID, filmID, body,
+	).Scan(&id)
+	if err != nil {
+		return model.Review{}, dbError(op, err)
+	}
+
+	review, err := scanReview(tx.QueryRowContext(ctx, reviewSelect+` WHERE r.id = $1`, id))
+	if err != nil {
+		return model.Review{}, dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Review{}, fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return review, nil
+}
+
+func (s *Storage) DeleteReview(ctx context.Context, userID, filmID int) error {
+	const op = "storage.postgres.DeleteReview"
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM reviews WHERE user_id = $1 AND film_id = $2`, userID, filmID)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	return checkAffected(op, res)
+}
+
+// ModerateReview одобряет или скрывает рецензию и запоминает модератора
+func (s *Storage) ModerateReview(ctx context.Context, id, moderatorID int, status string) (model.Review, error) {
+	const op = "storage.postgres.ModerateReview"
+
+	res, err := s.db.ExecContext(ctx, `
+        UPDATE reviews
+        SET status = $2, moderated_by = $3, moderated_at = now()
+        WHERE id = $1`, id, status, moderatorID)
+	if err != nil {
+		return model.Review{}, dbError(op, err)
+	}
+	if err := checkAffected(op, res); err != nil {
+		return model.Review{}, err
+	}
+
+	review, err := scanReview(s.db.QueryRowContext(ctx, reviewSelect+` WHERE r.id = $1`, id))
+	if err != nil {
+		return model.Review{}, dbError(op, err)
+	}
+
+	return review, nil
+}
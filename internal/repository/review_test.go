@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"film-library/internal/model"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage_Reviews(t *testing.T) {
+	s := testStorage(t)
+	ctx := context.Background()
+
+	username, film := testName("reviewer"), testName("reviewed")
+
+	var userID, filmID int
+	err := s.db.QueryRow(`
+        INSERT INTO users (name, password, role_id)
+        SELECT $1, 'hash', id FROM roles WHERE name = 'user'
+        RETURNING id`, username).Scan(&userID)
+	require.NoError(t, err)
+	err = s.db.QueryRow(`INSERT INTO films (name, description, release_date, rating) VALUES ($1, '', $2, 7) RETURNING id`,
+		film, time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC)).Scan(&filmID)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		mustExec(t, s, `DELETE FROM films WHERE id = $1`, filmID)
+		mustExec(t, s, `DELETE FROM users WHERE id = $1`, userID)
+	})
+
+	_, err = s.SetRating(ctx, userID, filmID, 8)
+	require.NoError(t, err)
+
+	// Автор рецензии берётся из users.name вместе с его оценкой
+	review, err := s.SaveReview(ctx, userID, filmID, "Great")
+	require.NoError(t, err)
+	require.Equal(t, username, review.Username)
+	require.Equal(t, 8, review.Score)
+	require.Equal(t, model.ReviewPending, review.Status)
+
+	own, err := s.GetUserReview(ctx, userID, filmID)
+	require.NoError(t, err)
+	require.Equal(t, review.Id, own.Id)
+
+	moderated, err := s.ModerateReview(ctx, review.Id, userID, model.ReviewApproved)
+	require.NoError(t, err)
+	require.Equal(t, model.ReviewApproved, moderated.Status)
+	require.Equal(t, username, moderated.Username)
+
+	page, err := s.GetReviews(ctx, model.ReviewListParams{FilmID: filmID, Status: model.ReviewApproved, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 1, page.Total)
+	require.Len(t, page.Items, 1)
+	require.Equal(t, username, page.Items[0].Username)
+	require.Equal(t, "Great", page.Items[0].Body)
+}
+
+func TestStorage_RatingDoesNotNotifyCatalog(t *testing.T) {
+	s := testStorage(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var userID, filmID int
+	err := s.db.QueryRow(`
+        INSERT INTO users (name, password, role_id)
+        SELECT $1, 'hash', id FROM roles WHERE name = 'user'
+        RETURNING id`, testName("voter")).Scan(&userID)
+	require.NoError(t, err)
+	err = s.db.QueryRow(`INSERT INTO films (name, description, release_date, rating) VALUES ($1, '', $2, 7) RETURNING id`,
+		testName("voted"), time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC)).Scan(&filmID)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		mustExec(t, s, `DELETE FROM films WHERE id = $1`, filmID)
+		mustExec(t, s, `DELETE FROM users WHERE id = $1`, userID)
+	})
+
+	changes, err := s.WatchCatalog(ctx, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	// Пересчёт user_score и votes не меняет каталог и не пересобирает статистику
+	_, err = s.SetRating(ctx, userID, filmID, 9)
+	require.NoError(t, err)
+
+	select {
+	case <-changes:
+		t.Fatal("rating triggered catalog_changed")
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	mustExec(t, s, `UPDATE films SET rating = 8 WHERE id = $1`, filmID)
+
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("catalog change was not notified")
+	}
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"film-library/internal/model"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// searchConfigs - конфигурации текстового поиска PostgreSQL для поддерживаемых языков
+var searchConfigs = map[string]string{
+	"en": "english",
+	"ru": "russian",
+}
+
+// searchMatchesQuery - общая часть поискового запроса: совпадения по векторам фильма,
+// триграммам названия (опечатки) и именам актёров. Параметр $1 - строка запроса,
+// %[3]s - дополнительные условия на фильм f
+const searchMatchesQuery = `
+    WITH q AS (
+        SELECT websearch_to_tsquery('english', $1) AS en,
+               websearch_to_tsquery('russian', $1) AS ru,
+               websearch_to_tsquery('simple', $1) AS simple,
+               $1::text AS raw
+    ),
+    actor_hits AS (
+        SELECT af.film_id,
+               max(greatest(ts_rank(a.search_vector, q.simple), word_similarity(q.raw, a.name))) AS score,
+               array_agg(DISTINCT a.name ORDER BY a.name) AS names
+        FROM people a
+        JOIN film_credits af ON af.person_id = a.id AND af.role = 'actor'
+        CROSS JOIN q
+        WHERE a.deleted_at IS NULL AND (a.search_vector @@ q.simple OR q.raw <%% a.name)
+        GROUP BY af.film_id
+    ),
+    matches AS (
+        SELECT f.id, f.name, f.description, f.release_date, f.rating,
+               %[1]s + word_similarity(q.raw, f.name) + coalesce(ah.score, 0) AS rank,
+               coalesce(ah.names, '{}') AS actor_names
+        FROM films f
+        CROSS JOIN q
+        LEFT JOIN actor_hits ah ON ah.film_id = f.id
+        WHERE f.deleted_at IS NULL AND (%[2]s OR q.raw <%% f.name OR ah.film_id IS NOT NULL)%[3]s
+    )`
+
+// searchLangClauses возвращает выражение ранга и условие совпадения
+// для выбранного языка. Без языка учитываем обе морфологии
+func searchLangClauses(lang string) (rank, match string) {
+	switch lang {
+	case "en":
+		return "ts_rank(f.search_en, q.en)", "f.search_en @@ q.en"
+	case "ru":
+		return "ts_rank(f.search_ru, q.ru)", "f.search_ru @@ q.ru"
+	default:
+		return "greatest(ts_rank(f.search_en, q.en), ts_rank(f.search_ru, q.ru))",
+			"(f.search_en @@ q.en OR f.search_ru @@ q.ru)"
+	}
+}
+
+func (s *Storage) SearchFilms(ctx context.Context, params model.FilmSearchParams) (model.FilmSearchPage, error) {
+	const op = "storage.postgres.SearchFilms"
+
+	page := model.FilmSearchPage{
+		Items:  []model.FilmSearchResult{},
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}
+
+	var args queryArgs
+	args.add(params.Query)
+
+	var filters string
+	for _, cond := range filmLabelConditions(params.Labels, &args) {
+		filters += " AND " + cond
+	}
+	matchArgs := len(args)
+
+	rank, match := searchLangClauses(params.Lang)
+	matches := fmt.Sprintf(searchMatchesQuery, rank, match, filters)
+	config := searchConfigs[params.HighlightLang()]
+
+	// Подсветку считаем только для строк текущей страницы: ts_headline дорогой
+	query := matches + fmt.Sprintf(`,
+    page AS (
+        SELECT m.*, count(*) OVER () AS total
+        FROM matches m
+        ORDER BY m.rank DESC, m.id
+        LIMIT %[3]s OFFSET %[4]s
+    )
+    SELECT p.id, p.name, p.description, p.release_date, p.rating, p.rank, p.total,
+           ts_headline('%[1]s', p.name, q.%[2]s, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'),
+           ts_headline('%[1]s', p.description, q.%[2]s, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=25, MinWords=10'),
+           p.actor_names
+    FROM page p
+    CROSS JOIN q
+    ORDER BY p.rank DESC, p.id`, config, params.HighlightLang(), args.add(params.Limit), args.add(params.Offset))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return page, dbError(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var result model.FilmSearchResult
+		var actorNames pq.StringArray
+
+		err := rows.Scan(
+			&result.Id, &result.Name, &result.Description, &result.Releasedate, &result.Rating,
+			&result.Rank, &page.Total,
+			&result.Highlights.Name, &result.Highlights.Description, &actorNames,
+		)
+		if err != nil {
+			return page, fmt.Errorf("%s: scan error: %w", op, translateError(err))
+		}
+
+		result.Highlights.Actors = []string(actorNames)
+		page.Items = append(page.Items, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return page, dbError(op, err)
+	}
+
+	// Страница за пределами выдачи: общее количество считаем отдельно
+	if len(page.Items) == 0 && params.Offset > 0 {
+		countQuery := matches + " SELECT count(*) FROM matches"
+		if err := s.db.QueryRowContext(ctx, countQuery, args[:matchArgs]...).Scan(&page.Total); err != nil {
+			return page, dbError(op, err)
+		}
+	}
+
+	if len(page.Items) == 0 {
+		return page, nil
+	}
+
+	films := make([]model.Film, len(page.Items))
+	for i := range page.Items {
+		films[i] = page.Items[i].Film
+	}
+
+	if err := s.attachFilmDetails(ctx, films); err != nil {
+		return page, dbError(op, err)
+	}
+
+	for i := range page.Items {
+		page.Items[i].ListActors = films[i].ListActors
+		page.Items[i].FilmLabels = films[i].FilmLabels
+	}
+
+	return page, nil
+}
@@ -0,0 +1,316 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"film-library/internal/model"
+	"fmt"
+)
+
+type StatsRepository interface {
+	GetActorStats(ctx context.Context, params model.ActorStatsParams) (model.ActorStatsPage, error)
+	GetActorStatsByID(ctx context.Context, id int) (model.ActorStats, error)
+	GetYearStats(ctx context.Context, years model.YearRange) ([]model.YearStats, error)
+	GetRatingHistogram(ctx context.Context, years model.YearRange) ([]model.RatingBucket, error)
+	GetCostars(ctx context.Context, params model.CostarParams) (model.CostarPage, error)
+	RefreshStats(ctx context.Context) error
+}
+
+func NewStatsRepository(db *sql.DB) StatsRepository {
+	return &Storage{
+		db: db,
+	}
+}
+
+// statsViews - материализованные представления статистики в порядке обновления
+var statsViews = []string{"actor_stats", "film_year_stats", "film_rating_buckets", "costar_pairs"}
+
+// Статистика берётся для актёров из таблицы: актёр, добавленный после последнего
+// обновления представлений, отдаётся с нулём фильмов, а не пропадает
+const (
+	actorStatsColumns = `a.id, a.name, COALESCE(s.film_count, 0), s.avg_rating, s.min_rating, s.max_rating,
+               s.first_release, s.last_release, ` + careerYears
+	actorStatsFrom = `
+        FROM people a
+        LEFT JOIN actor_stats s ON s.actor_id = a.id`
+)
+
+const careerYears = `COALESCE(extract(year FROM s.last_release) - extract(year FROM s.first_release), 0)::int`
+
+// actorStatsSortColumns - выражения, по которым разрешена сортировка статистики актёров
+var actorStatsSortColumns = map[string]string{
+	"film_count":   "COALESCE(s.film_count, 0)",
+	"avg_rating":   "s.avg_rating",
+	"career_years": careerYears,
+	"name":         "a.name",
+}
+
+func (s *Storage) GetActorStats(ctx context.Context, params model.ActorStatsParams) (model.ActorStatsPage, error) {
+	const op = "storage.postgres.GetActorStats"
+
+	page := model.ActorStatsPage{
+		Items:  []model.ActorStats{},
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}
+
+	var args queryArgs
+	where := actorStatsConditions(params, &args)
+
+	column := actorStatsSortColumns[params.Sort()]
+	direction := params.Direction()
+
+	query := fmt.Sprintf(`
+        SELECT %s, count(*) OVER ()%s%s
+        ORDER BY %s %s NULLS LAST, a.id
+        LIMIT %s OFFSET %s`,
+		actorStatsColumns, actorStatsFrom, whereClause(where), column, direction,
+		args.add(params.Limit), args.add(params.Offset))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return page, dbError(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stats model.ActorStats
+		if err := scanActorStats(rows, &stats, &page.Total); err != nil {
+			return page, fmt.Errorf("%s: scan error: %w", op, translateError(err))
+		}
+		page.Items = append(page.Items, stats)
+	}
+
+	if err := rows.Err(); err != nil {
+		return page, dbError(op, err)
+	}
+
+	// Страница за пределами выдачи: общее количество считаем отдельно
+	if len(page.Items) == 0 && params.Offset > 0 {
+		var countArgs queryArgs
+		countQuery := "SELECT count(*)" + actorStatsFrom + whereClause(actorStatsConditions(params, &countArgs))
+		if err := s.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&page.Total); err != nil {
+			return page, dbError(op, err)
+		}
+	}
+
+	return page, nil
+}
+
+func (s *Storage) GetActorStatsByID(ctx context.Context, id int) (model.ActorStats, error) {
+	const op = "storage.postgres.GetActorStatsByID"
+
+	var stats model.ActorStats
+	query := "SELECT " + actorStatsColumns + actorStatsFrom + `
+        WHERE a.id = $1 AND a.deleted_at IS NULL AND ` + actorOnly
+	row := s.db.QueryRowContext(ctx, query, id)
+	if err := scanActorStats(row, &stats); err != nil {
+		return model.ActorStats{}, dbError(op, err)
+	}
+
+	return stats, nil
+}
+
+// actorStatsConditions - условия WHERE для статистики актёров
+func actorStatsConditions(params model.ActorStatsParams, args *queryArgs) []string {
+	where := []string{"a.deleted_at IS NULL", actorOnly}
+
+	if params.MinFilms > 0 {
+		where = append(where, "COALESCE(s.film_count, 0) >= "+args.add(params.MinFilms))
+	}
+
+	return where
+}
+
+// scanActorStats читает колонки actorStatsColumns; extra - дополнительные колонки после неё
+func scanActorStats(row interface{ Scan(dest ...any) error }, stats *model.ActorStats, extra ...any) error {
+	var (
+		avg, min, max sql.NullFloat64
+		first, last   sql.NullTime
+	)
+
+	dest := append([]any{&stats.ActorID, &stats.Name, &stats.FilmCount, &avg, &min, &max,
+		&first, &last, &stats.CareerYears}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+
+	if avg.Valid {
+		stats.AvgRating = &avg.Float64
+	}
+	if min.Valid {
+		v := float32(min.Float64)
+		stats.MinRating = &v
+	}
+	if max.Valid {
+		v := float32(max.Float64)
+		stats.MaxRating = &v
+	}
+	if first.Valid {
+		stats.FirstRelease = &first.Time
+	}
+	if last.Valid {
+		stats.LastRelease = &last.Time
+	}
+
+	return nil
+}
+
+func (s *Storage) GetYearStats(ctx context.Context, years model.YearRange) ([]model.YearStats, error) {
+	const op = "storage.postgres.GetYearStats"
+
+	var args queryArgs
+	query := `
+        SELECT year, film_count, avg_rating, min_rating, max_rating
+        FROM film_year_stats` + whereClause(yearConditions(years, &args)) + `
+        ORDER BY year`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, dbError(op, err)
+	}
+	defer rows.Close()
+
+	stats := []model.YearStats{}
+	for rows.Next() {
+		var year model.YearStats
+		if err := rows.Scan(&year.Year, &year.FilmCount, &year.AvgRating, &year.MinRating, &year.MaxRating); err != nil {
+			return nil, dbError(op, err)
+		}
+		stats = append(stats, year)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(op, err)
+	}
+
+	return stats, nil
+}
+
+// GetRatingHistogram отдаёт все RatingBuckets корзин, в том числе пустые
+func (s *Storage) GetRatingHistogram(ctx context.Context, years model.YearRange) ([]model.RatingBucket, error) {
+	const op = "storage.postgres.GetRatingHistogram"
+
+	var args queryArgs
+	query := `
+        SELECT bucket, sum(film_count)
+        FROM film_rating_buckets` + whereClause(yearConditions(years, &args)) + `
+        GROUP BY bucket`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, dbError(op, err)
+	}
+	defer rows.Close()
+
+	buckets := make([]model.RatingBucket, model.RatingBuckets)
+	for i := range buckets {
+		buckets[i] = model.RatingBucket{From: i, To: i + 1}
+	}
+
+	for rows.Next() {
+		var bucket, count int
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, dbError(op, err)
+		}
+		if bucket >= 0 && bucket < len(buckets) {
+			buckets[bucket].FilmCount = count
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(op, err)
+	}
+
+	return buckets, nil
+}
+
+// yearConditions - условия WHERE для периода по годам выхода
+func yearConditions(years model.YearRange, args *queryArgs) []string {
+	var where []string
+
+	if years.From != 0 {
+		where = append(where, "year >= "+args.add(years.From))
+	}
+	if years.To != 0 {
+		where = append(where, "year <= "+args.add(years.To))
+	}
+
+	return where
+}
+
+// GetCostars отдаёт пары актёров по убыванию числа общих фильмов
+func (s *Storage) GetCostars(ctx context.Context, params model.CostarParams) (model.CostarPage, error) {
+	const op = "storage.postgres.GetCostars"
+
+	page := model.CostarPage{
+		Items:  []model.CostarPair{},
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}
+
+	var args queryArgs
+	pairs := `SELECT actor_id, costar_id, film_count FROM costar_pairs`
+	if params.ActorID != 0 {
+		// В представлении пара хранится один раз; разворачиваем её так,
+		// чтобы запрошенный актёр был первым
+		id := args.add(params.ActorID)
+		pairs = fmt.Sprintf(`
+            SELECT %[1]s::int AS actor_id,
+                   CASE WHEN actor_id = %[1]s THEN costar_id ELSE actor_id END AS costar_id,
+                   film_count
+            FROM costar_pairs
+            WHERE actor_id = %[1]s OR costar_id = %[1]s`, id)
+	}
+
+	var where []string
+	if params.MinFilms > 0 {
+		where = append(where, "p.film_count >= "+args.add(params.MinFilms))
+	}
+
+	// Актёры, удалённые после обновления представления, отсекаются соединением
+	query := fmt.Sprintf(`
+        SELECT p.actor_id, a1.name, p.costar_id, a2.name, p.film_count, count(*) OVER ()
+        FROM (%s) p
+        JOIN people a1 ON a1.id = p.actor_id AND a1.deleted_at IS NULL
+        JOIN people a2 ON a2.id = p.costar_id AND a2.deleted_at IS NULL%s
+        ORDER BY p.film_count DESC, p.actor_id, p.costar_id
+        LIMIT %s OFFSET %s`,
+		pairs, whereClause(where), args.add(params.Limit), args.add(params.Offset))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return page, dbError(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pair model.CostarPair
+		err := rows.Scan(&pair.Actor.Id, &pair.Actor.Name, &pair.Costar.Id, &pair.Costar.Name,
+			&pair.FilmCount, &page.Total)
+		if err != nil {
+			return page, fmt.Errorf("%s: scan error: %w", op, translateError(err))
+		}
+		page.Items = append(page.Items, pair)
+	}
+
+	if err := rows.Err(); err != nil {
+		return page, dbError(op, err)
+	}
+
+	return page, nil
+}
+
+// RefreshStats пересчитывает представления статистики. CONCURRENTLY не
+// блокирует чтение: до конца пересчёта запросы видят прежние данные
+func (s *Storage) RefreshStats(ctx context.Context) error {
+	const op = "storage.postgres.RefreshStats"
+
+	for _, view := range statsViews {
+		if _, err := s.db.ExecContext(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY "+view); err != nil {
+			return fmt.Errorf("%s: failed to refresh %s: %w", op, view, translateError(err))
+		}
+	}
+
+	return nil
+}
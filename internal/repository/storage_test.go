@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/pressly/goose"
+	"github.com/stretchr/testify/require"
+)
+
+// testDSNEnv - строка подключения к отдельной тестовой базе. Тесты, которые
+// выполняют настоящие запросы, без неё пропускаются
+const testDSNEnv = "TEST_DATABASE_DSN"
+
+// testStorage подключается к тестовой базе и накатывает миграции
+func testStorage(t *testing.T) *Storage {
+	t.Helper()
+
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrationsDir, err := filepath.Abs("../../migrations")
+	require.NoError(t, err)
+	require.NoError(t, goose.Up(db, migrationsDir))
+
+	return &Storage{db: db, dsn: dsn}
+}
+
+// testName - уникальное имя записи, чтобы тесты не мешали друг другу и прошлым запускам
+func testName(prefix string) string {
+	return fmt.Sprintf("%s %d", prefix, time.Now().UnixNano())
+}
+
+// mustExec выполняет вспомогательный запрос подготовки или очистки данных
+func mustExec(t *testing.T, s *Storage, query string, args ...any) {
+	t.Helper()
+
+	_, err := s.db.Exec(query, args...)
+	require.NoError(t, err)
+}
@@ -0,0 +1,540 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"film-library/internal/model"
+	"fmt"
+)
+
+type TaxonomyRepository interface {
+	GetGenres(ctx context.Context) ([]model.Genre, error)
+	GetGenre(ctx context.Context, id int) (model.Genre, error)
+	CreateGenre(ctx context.Context, genre *model.Genre) error
+	UpdateGenre(ctx context.Context, genre *model.Genre) error
+	DeleteGenre(ctx context.Context, id int) error
+	GetCountries(ctx context.Context) ([]model.Country, error)
+	GetCountry(ctx context.Context, code string) (model.Country, error)
+	CreateCountry(ctx context.Context, country *model.Country) error
+	UpdateCountry(ctx context.Context, country *model.Country) error
+	DeleteCountry(ctx context.Context, code string) error
+	GetTags(ctx context.Context, params model.TagListParams) (model.TagPage, error)
+	GetTag(ctx context.Context, id int) (model.Tag, error)
+	CreateTag(ctx context.Context, tag *model.Tag) error
+	UpdateTag(ctx context.Context, tag *model.Tag) error
+	DeleteTag(ctx context.Context, id int) error
+	GetFilmFacets(ctx context.Context, params model.FilmListParams) (model.FilmFacets, error)
+}
+
+func NewTaxonomyRepository(db *sql.DB) TaxonomyRepository {
+	return &Storage{
+		db: db,
+	}
+}
+
+// Число неудалённых фильмов с жанром, страной или тегом строки l
+const (
+	genreFilmCount = `(SELECT count(*) FROM film_genres fg JOIN films f ON f.id = fg.film_id
+                        WHERE fg.genre_id = l.id AND f.deleted_at IS NULL)`
+	countryFilmCount = `(SELECT count(*) FROM film_countries fc JOIN films f ON f.id = fc.film_id
+                          WHERE fc.country_code = l.code AND f.deleted_at IS NULL)`
+	tagFilmCount = `(SELECT count(*) FROM film_tags ft JOIN films f ON f.id = ft.film_id
+                      WHERE ft.tag_id = l.id AND f.deleted_at IS NULL)`
+)
+
+func (s *Storage) GetGenres(ctx context.Context) ([]model.Genre, error) {
+	const op = "storage.postgres.GetGenres"
+
+	rows, err := s.db.QueryContext(ctx, `SELECT l.id, l.name, `+genreFilmCount+` FROM genres l ORDER BY l.name`)
+	if err != nil {
+		return nil, dbError(op, err)
+	}
+	defer rows.Close()
+
+	genres := []model.Genre{}
+	for rows.Next() {
+		var genre model.Genre
+		if err := rows.Scan(&genre.Id, &genre.Name, &genre.FilmCount); err != nil {
+			return nil, dbError(op, err)
+		}
+		genres = append(genres, genre)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(op, err)
+	}
+
+	return genres, nil
+}
+
+func (s *Storage) GetGenre(ctx context.Context, id int) (model.Genre, error) {
+	const op = "storage.postgres.GetGenre"
+
+	var genre model.Genre
+	err := s.db.QueryRowContext(ctx, `SELECT l.id, l.name, `+genreFilmCount+` FROM genres l WHERE l.id = $1`, id).
+		Scan(&genre.Id, &genre.Name, &genre.FilmCount)
+	if err != nil {
+		return model.Genre{}, dbError(op, err)
+	}
+
+	return genre, nil
+}
+
+func (s *Storage) CreateGenre(ctx context.Context, genre *model.Genre) error {
+	const op = "storage.postgres.CreateGenre"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `INSERT INTO genres (name) VALUES ($1) RETURNING id`, genre.Name).Scan(&genre.Id)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	created := auditChange{action: model.AuditCreate, table: "genres", id: genre.Id}
+	if err := created.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	genre.FilmCount = 0
+	return nil
+}
+
+// UpdateGenre переименовывает жанр; фильмы ссылаются на него по id и остаются с ним
+func (s *Storage) UpdateGenre(ctx context.Context, genre *model.Genre) error {
+	const op = "storage.postgres.UpdateGenre"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	change, err := trackChange(ctx, tx, model.AuditUpdate, "genres", genre.Id)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+        WITH l AS (UPDATE genres SET name = $1 WHERE id = $2 RETURNING id)
+        SELECT `+genreFilmCount+` FROM l`, genre.Name, genre.Id,
+	).Scan(&genre.FilmCount)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return nil
+}
+
+// DeleteGenre удаляет жанр. Жанр, назначенный фильмам (в том числе удалённым), не удаляется
+func (s *Storage) DeleteGenre(ctx context.Context, id int) error {
+	const op = "storage.postgres.DeleteGenre"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	change, err := trackChange(ctx, tx, model.AuditDelete, "genres", id)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM genres WHERE id = $1`, id)
+	if err != nil {
+		return dbError(op, err)
+	}
+	if err := checkAffected(op, res); err != nil {
+		return err
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return nil
+}
+
+func (s *Storage) GetCountries(ctx context.Context) ([]model.Country, error) {
+	const op = "storage.postgres.GetCountries"
+
+	rows, err := s.db.QueryContext(ctx, `SELECT l.code, l.name, `+countryFilmCount+` FROM countries l ORDER BY l.name`)
+	if err != nil {
+		return nil, dbError(op, err)
+	}
+	defer rows.Close()
+
+	countries := []model.Country{}
+	for rows.Next() {
+		var country model.Country
+		if err := rows.Scan(&country.Code, &country.Name, &country.FilmCount); err != nil {
+			return nil, dbError(op, err)
+		}
+		countries = append(countries, country)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dbError(op, err)
+	}
+
+	return countries, nil
+}
+
+func (s *Storage) GetCountry(ctx context.Context, code string) (model.Country, error) {
+	const op = "storage.postgres.GetCountry"
+
+	var country model.Country
+	err := s.db.QueryRowContext(ctx, `SELECT l.code, l.name, `+countryFilmCount+` FROM countries l WHERE l.code = $1`, code).
+		Scan(&country.Code, &country.Name, &country.FilmCount)
+	if err != nil {
+		return model.Country{}, dbError(op, err)
+	}
+
+	return country, nil
+}
+
+func (s *Storage) CreateCountry(ctx context.Context, country *model.Country) error {
+	const op = "storage.postgres.CreateCountry"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO countries (code, name) VALUES ($1, $2)`, country.Code, country.Name)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	created := auditChange{action: model.AuditCreate, table: "countries", id: country.Code}
+	if err := created.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	country.FilmCount = 0
+	return nil
+}
+
+// UpdateCountry меняет название страны; код - ключ и не меняется
+func (s *Storage) UpdateCountry(ctx context.Context, country *model.Country) error {
+	const op = "storage.postgres.UpdateCountry"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	change, err := trackChange(ctx, tx, model.AuditUpdate, "countries", country.Code)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+        WITH l AS (UPDATE countries SET name = $1 WHERE code = $2 RETURNING code)
+        SELECT `+countryFilmCount+` FROM l`, country.Name, country.Code,
+	).Scan(&country.FilmCount)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return nil
+}
+
+// DeleteCountry удаляет страну, если она не указана ни у одного фильма
+func (s *Storage) DeleteCountry(ctx context.Context, code string) error {
+	const op = "storage.postgres.DeleteCountry"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	change, err := trackChange(ctx, tx, model.AuditDelete, "countries", code)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM countries WHERE code = $1`, code)
+	if err != nil {
+		return dbError(op, err)
+	}
+	if err := checkAffected(op, res); err != nil {
+		return err
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return nil
+}
+
+// GetTags - теги с началом имени params.Prefix, самые используемые первыми
+func (s *Storage) GetTags(ctx context.Context, params model.TagListParams) (model.TagPage, error) {
+	const op = "storage.postgres.GetTags"
+
+	page := model.TagPage{
+		Items:  []model.Tag{},
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}
+
+	var args queryArgs
+	var where []string
+	if params.Prefix != "" {
+		where = append(where, "l.name LIKE "+args.add(escapeLike(params.Prefix)+"%"))
+	}
+
+	query := fmt.Sprintf(`
+        SELECT id, name, film_count, count(*) OVER ()
+        FROM (SELECT l.id, l.name, %s AS film_count FROM tags l%s) t
+        ORDER BY film_count DESC, name
+        LIMIT %s OFFSET %s`,
+		tagFilmCount, whereClause(where), args.add(params.Limit), args.add(params.Offset))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return page, dbError(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag model.Tag
+		if err := rows.Scan(&tag.Id, &tag.Name, &tag.FilmCount, &page.Total); err != nil {
+			return page, dbError(op, err)
+		}
+		page.Items = append(page.Items, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return page, dbError(op, err)
+	}
+
+	// Страница за пределами списка: общее количество считаем отдельно
+	if len(page.Items) == 0 && params.Offset > 0 {
+		countQuery := "SELECT count(*) FROM tags l" + whereClause(where)
+		if err := s.db.QueryRowContext(ctx, countQuery, args[:len(args)-2]...).Scan(&page.Total); err != nil {
+			return page, dbError(op, err)
+		}
+	}
+
+	return page, nil
+}
+
+func (s *Storage) GetTag(ctx context.Context, id int) (model.Tag, error) {
+	const op = "storage.postgres.GetTag"
+
+	var tag model.Tag
+	err := s.db.QueryRowContext(ctx, `SELECT l.id, l.name, `+tagFilmCount+` FROM tags l WHERE l.id = $1`, id).
+		Scan(&tag.Id, &tag.Name, &tag.FilmCount)
+	if err != nil {
+		return model.Tag{}, dbError(op, err)
+	}
+
+	return tag, nil
+}
+
+func (s *Storage) CreateTag(ctx context.Context, tag *model.Tag) error {
+	const op = "storage.postgres.CreateTag"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `INSERT INTO tags (name) VALUES ($1) RETURNING id`, tag.Name).Scan(&tag.Id)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	created := auditChange{action: model.AuditCreate, table: "tags", id: tag.Id}
+	if err := created.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	tag.FilmCount = 0
+	return nil
+}
+
+// UpdateTag переименовывает тег. Если тег с таким именем уже есть - ErrConflict
+func (s *Storage) UpdateTag(ctx context.Context, tag *model.Tag) error {
+	const op = "storage.postgres.UpdateTag"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	change, err := trackChange(ctx, tx, model.AuditUpdate, "tags", tag.Id)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+        WITH l AS (UPDATE tags SET name = $1 WHERE id = $2 RETURNING id)
+        SELECT `+tagFilmCount+` FROM l`, tag.Name, tag.Id,
+	).Scan(&tag.FilmCount)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return nil
+}
+
+// DeleteTag удаляет тег и снимает его со всех фильмов
+func (s *Storage) DeleteTag(ctx context.Context, id int) error {
+	const op = "storage.postgres.DeleteTag"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	change, err := trackChange(ctx, tx, model.AuditDelete, "tags", id)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM tags WHERE id = $1`, id)
+	if err != nil {
+		return dbError(op, err)
+	}
+	if err := checkAffected(op, res); err != nil {
+		return err
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return nil
+}
+
+// GetFilmFacets считает фильмы под фильтрами params по каждому жанру, стране и тегу.
+// Сортировка и пагинация params не учитываются
+func (s *Storage) GetFilmFacets(ctx context.Context, params model.FilmListParams) (model.FilmFacets, error) {
+	const op = "storage.postgres.GetFilmFacets"
+
+	facets := model.FilmFacets{
+		Genres:    []model.FacetCount{},
+		Countries: []model.FacetCount{},
+		Tags:      []model.FacetCount{},
+	}
+
+	var args queryArgs
+	where := filmFilterConditions(params, &args)
+
+	query := fmt.Sprintf(`
+        WITH matched AS (SELECT f.id FROM films f%s)
+        SELECT 'total', '', '', count(*) FROM matched
+        UNION ALL
+        (SELECT 'genres', g.name::text, g.name::text, count(*)
+         FROM matched m
+         JOIN film_genres fg ON fg.film_id = m.id
+         JOIN genres g ON g.id = fg.genre_id
+         GROUP BY g.id, g.name)
+        UNION ALL
+        (SELECT 'countries', c.code::text, c.name::text, count(*)
+         FROM matched m
+         JOIN film_countries fc ON fc.film_id = m.id
+         JOIN countries c ON c.code = fc.country_code
+         GROUP BY c.code, c.name)
+        UNION ALL
+        (SELECT 'tags', t.name::text, t.name::text, count(*)
+         FROM matched m
+         JOIN film_tags ft ON ft.film_id = m.id
+         JOIN tags t ON t.id = ft.tag_id
+         GROUP BY t.id, t.name
+         ORDER BY count(*) DESC, t.name
+         LIMIT %s)
+        ORDER BY 1, 4 DESC, 3`, whereClause(where), args.add(model.FacetTagLimit))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return facets, dbError(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var kind string
+		var facet model.FacetCount
+		if err := rows.Scan(&kind, &facet.Value, &facet.Name, &facet.FilmCount); err != nil {
+			return facets, dbError(op, err)
+		}
+
+		switch kind {
+		case "total":
+			facets.Total = facet.FilmCount
+		case "genres":
+			facets.Genres = append(facets.Genres, facet)
+		case "countries":
+			facets.Countries = append(facets.Countries, facet)
+		case "tags":
+			facets.Tags = append(facets.Tags, facet)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return facets, dbError(op, err)
+	}
+
+	return facets, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"film-library/internal/model"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage_TaxonomyAudit(t *testing.T) {
+	s := testStorage(t)
+	ctx := context.Background()
+
+	// Код страны - две буквы, поэтому берём свободный код из диапазона для личного пользования
+	code := fmt.Sprintf("X%c", 'A'+time.Now().UnixNano()%26)
+	mustExec(t, s, `DELETE FROM countries WHERE code = $1`, code)
+	t.Cleanup(func() {
+		mustExec(t, s, `DELETE FROM audit_log WHERE entity = $1 AND entity_code = $2`, model.EntityCountry, code)
+	})
+
+	country := model.Country{Code: code, Name: testName("country")}
+	require.NoError(t, s.CreateCountry(ctx, &country))
+	renamed := country
+	renamed.Name = testName("renamed")
+	require.NoError(t, s.UpdateCountry(ctx, &renamed))
+	require.NoError(t, s.DeleteCountry(ctx, code))
+
+	rows, err := s.db.Query(`
+        SELECT action, COALESCE(before::text, 'null'), COALESCE(after::text, 'null') FROM audit_log
+        WHERE entity = $1 AND entity_code = $2 AND entity_id IS NULL
+        ORDER BY id`, model.EntityCountry, code)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	type entry struct{ action, before, after string }
+	var entries []entry
+	for rows.Next() {
+		var e entry
+		require.NoError(t, rows.Scan(&e.action, &e.before, &e.after))
+		entries = append(entries, e)
+	}
+	require.NoError(t, rows.Err())
+
+	require.Len(t, entries, 3)
+	require.Equal(t, model.AuditCreate, entries[0].action)
+	require.JSONEq(t, fmt.Sprintf(`{"code": %q, "name": %q}`, code, country.Name), entries[0].after)
+	require.Equal(t, model.AuditUpdate, entries[1].action)
+	require.JSONEq(t, fmt.Sprintf(`{"name": %q}`, country.Name), entries[1].before)
+	require.JSONEq(t, fmt.Sprintf(`{"name": %q}`, renamed.Name), entries[1].after)
+	require.Equal(t, model.AuditDelete, entries[2].action)
+	require.JSONEq(t, fmt.Sprintf(`{"code": %q, "name": %q}`, code, renamed.Name), entries[2].before)
+	require.Equal(t, "null", entries[2].after)
+}
+
+func TestStorage_FilmTagsAudit(t *testing.T) {
+	s := testStorage(t)
+	ctx := context.Background()
+
+	tag := testName("new tag")
+	film := model.Film{Name: testName("tagged"), Releasedate: time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC),
+		FilmLabels: model.FilmLabels{Tags: []string{tag}}}
+	t.Cleanup(func() {
+		mustExec(t, s, `DELETE FROM films WHERE name = $1`, film.Name)
+		mustExec(t, s, `DELETE FROM tags WHERE name = $1`, tag)
+	})
+	require.NoError(t, s.CreateFilm(ctx, &film))
+
+	// Тег, созданный вместе с фильмом, попадает в журнал отдельной записью
+	var name string
+	err := s.db.QueryRow(`
+        SELECT l.after->>'name' FROM audit_log l JOIN tags t ON t.id = l.entity_id
+        WHERE l.action = $1 AND l.entity = $2 AND t.name = $3`, model.AuditCreate, model.EntityTag, tag).Scan(&name)
+	require.NoError(t, err)
+	require.Equal(t, tag, name)
+}
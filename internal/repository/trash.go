@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"film-library/internal/model"
+	"fmt"
+	"time"
+)
+
+type TrashRepository interface {
+	GetTrash(ctx context.Context) (model.Trash, error)
+	RestoreFilm(ctx context.Context, id int) (model.Film, error)
+	RestoreActor(ctx context.Context, id int) (model.ActorWithFilms, error)
+	PurgeTrash(ctx context.Context, before time.Time) (model.PurgeResult, error)
+}
+
+func NewTrashRepository(db *sql.DB) TrashRepository {
+	return &Storage{
+		db: db,
+	}
+}
+
+// GetTrash возвращает мягко удалённые фильмы и актёров. Удалённые участники
+// съёмочной группы без актёрских ролей в актёры не попадают
+func (s *Storage) GetTrash(ctx context.Context) (model.Trash, error) {
+	const op = "storage.postgres.GetTrash"
+
+	var (
+		trash model.Trash
+		err   error
+	)
+
+	if trash.Films, err = s.trashItems(ctx, "films", ""); err != nil {
+		return model.Trash{}, dbError(op, err)
+	}
+	if trash.Actors, err = s.trashItems(ctx, "people", actorOnly); err != nil {
+		return model.Trash{}, dbError(op, err)
+	}
+
+	return trash, nil
+}
+
+// trashItems - удалённые строки таблицы films или people (псевдоним a),
+// подходящие под дополнительное условие filter, если оно задано
+func (s *Storage) trashItems(ctx context.Context, table, filter string) ([]model.TrashItem, error) {
+	where := "a.deleted_at IS NOT NULL"
+	if filter != "" {
+		where += " AND " + filter
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT a.id, a.name, a.deleted_at
+        FROM `+table+` a
+        WHERE `+where+`
+        ORDER BY a.deleted_at DESC, a.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []model.TrashItem{}
+	for rows.Next() {
+		var item model.TrashItem
+		if err := rows.Scan(&item.Id, &item.Name, &item.DeletedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// RestoreFilm возвращает фильм из корзины вместе с составом: связи в film_credits
+// при мягком удалении не трогаются. Если название уже занято - ErrConflict
+func (s *Storage) RestoreFilm(ctx context.Context, id int) (model.Film, error) {
+	const op = "storage.postgres.RestoreFilm"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.Film{}, fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	change, err := trackChange(ctx, tx, model.AuditRestore, "films", id)
+	if err != nil {
+		return model.Film{}, dbError(op, err)
+	}
+
+	var film model.Film
+	err = tx.QueryRowContext(ctx, `
+        UPDATE films SET deleted_at = NULL, version = version + 1
+        WHERE id = $1 AND deleted_at IS NOT NULL
+        RETURNING id, name, description, release_date, rating, version`, id,
+	).Scan(&film.Id, &film.Name, &film.Description, &film.Releasedate, &film.Rating, &film.Version)
+	if err != nil {
+		return model.Film{}, dbError(op, err)
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return model.Film{}, dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Film{}, fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	films := []model.Film{film}
+	if err := s.attachFilmDetails(ctx, films); err != nil {
+		return model.Film{}, dbError(op, err)
+	}
+
+	return films[0], nil
+}
+
+// RestoreActor возвращает актёра из корзины вместе с ролями в фильмах.
+// Если имя уже занято - ErrConflict
+func (s *Storage) RestoreActor(ctx context.Context, id int) (model.ActorWithFilms, error) {
+	const op = "storage.postgres.RestoreActor"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.ActorWithFilms{}, fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	change, err := trackChange(ctx, tx, model.AuditRestore, "people", id)
+	if err != nil {
+		return model.ActorWithFilms{}, dbError(op, err)
+	}
+
+	var actor model.Actor
+	err = tx.QueryRowContext(ctx, `
+        UPDATE people SET deleted_at = NULL, version = version + 1
+        WHERE id = $1 AND deleted_at IS NOT NULL
+        RETURNING id, name, gender, date_of_birth, version`, id,
+	).Scan(&actor.Id, &actor.Name, &actor.Gender, &actor.DateOfBirth, &actor.Version)
+	if err != nil {
+		return model.ActorWithFilms{}, dbError(op, err)
+	}
+
+	if err := change.record(ctx, tx); err != nil {
+		return model.ActorWithFilms{}, dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.ActorWithFilms{}, fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	films, err := s.GetFilmsByActor(ctx, id)
+	if err != nil {
+		return model.ActorWithFilms{}, dbError(op, err)
+	}
+
+	return model.ActorWithFilms{Actor: actor, Films: films}, nil
+}
+
+// PurgeTrash окончательно удаляет записи, попавшие в корзину раньше before.
+// Связи film_credits уходят каскадом, последнее состояние каждой записи остаётся в журнале
+func (s *Storage) PurgeTrash(ctx context.Context, before time.Time) (model.PurgeResult, error) {
+	const op = "storage.postgres.PurgeTrash"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.PurgeResult{}, fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	var result model.PurgeResult
+	if result.Films, err = purgeWithAudit(ctx, tx, "films", before); err != nil {
+		return model.PurgeResult{}, dbError(op, err)
+	}
+	if result.Actors, err = purgeWithAudit(ctx, tx, "people", before); err != nil {
+		return model.PurgeResult{}, dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.PurgeResult{}, fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return result, nil
+}
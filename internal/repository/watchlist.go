@@ -0,0 +1,555 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ErrMissingFilms - в списке указаны фильмы, которых нет в базе или которые в корзине
+var ErrMissingFilms = errors.New("films not found")
+
+type WatchlistRepository interface {
+	GetWatchlists(ctx context.Context, ownerID int, publicOnly bool) ([]model.Watchlist, error)
+	GetWatchlist(ctx context.Context, id, viewerID int) (model.WatchlistWithFilms, error)
+	CreateWatchlist(ctx context.Context, list *model.Watchlist) error
+	UpdateWatchlist(ctx context.Context, list *model.Watchlist) error
+	DeleteWatchlist(ctx context.Context, id, userID int) error
+	AddWatchlistFilm(ctx context.Context, userID, id, filmID, position int) error
+	RemoveWatchlistFilm(ctx context.Context, userID, id, filmID int) error
+	ReplaceWatchlistFilms(ctx context.Context, userID, id int, filmIDs []int) error
+	GetWatched(ctx context.Context, params model.WatchedListParams) (model.WatchedPage, error)
+	SetWatched(ctx context.Context, userID, filmID int, watchedOn time.Time) error
+	DeleteWatched(ctx context.Context, userID, filmID int) error
+	GetFeed(ctx context.Context, params model.FeedParams) (model.FeedPage, error)
+}
+
+func NewWatchlistRepository(db *sql.DB) WatchlistRepository {
+	return &Storage{
+		db: db,
+	}
+}
+
+// watchlistSelect - список с числом фильмов не из корзины
+const watchlistSelect = `
+        SELECT w.id, w.user_id, w.name, w.description, w.is_public,
+               (SELECT count(*) FROM watchlist_films wf
+                JOIN films f ON f.id = wf.film_id AND f.deleted_at IS NULL
+                WHERE wf.watchlist_id = w.id),
+               w.created_at, w.updated_at
+        FROM watchlists w`
+
+func scanWatchlist(row scanner) (model.Watchlist, error) {
+	var w model.Watchlist
+	err := row.Scan(&w.Id, &w.UserID, &w.Name, &w.Description, &w.Public, &w.FilmCount, &w.CreatedAt, &w.UpdatedAt)
+	return w, err
+}
+
+// GetWatchlists - списки пользователя по имени; чужие запрашиваются с publicOnly
+func (s *Storage) GetWatchlists(ctx context.Context, ownerID int, publicOnly bool) ([]model.Watchlist, error) {
+	const op = "storage.postgres.GetWatchlists"
+
+	rows, err := s.db.QueryContext(ctx, watchlistSelect+`
+        WHERE w.user_id = $1 AND (w.is_public OR NOT $2)
+        ORDER BY lower(w.name), w.id`, ownerID, publicOnly)
+	if err != nil {
+		return nil, dbError(op, err)
+	}
+	defer rows.Close()
+
+	lists := make([]model.Watchlist, 0)
+	for rows.Next() {
+		list, err := scanWatchlist(rows)
+		if err != nil {
+			return nil, dbError(op, err)
+		}
+		lists = append(lists, list)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError(op, err)
+	}
+
+	return lists, nil
+}
+
+// GetWatchlist - список с фильмами. Чужой приватный список для viewerID не существует
+func (s *Storage) GetWatchlist(ctx context.Context, id, viewerID int) (model.WatchlistWithFilms, error) {
+	const op = "storage.postgres.GetWatchlist"
+
+	var result model.WatchlistWithFilms
+	list, err := scanWatchlist(s.db.QueryRowContext(ctx, watchlistSelect+`
+        WHERE w.id = $1 AND (w.user_id = $2 OR w.is_public)`, id, viewerID))
+	if err != nil {
+		return result, dbError(op, err)
+	}
+	result.Watchlist = list
+
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT f.id, f.name, f.release_date, f.rating, wf.position, wf.added_at
+        FROM watchlist_films wf
+        JOIN films f ON f.id = wf.film_id AND f.deleted_at IS NULL
+        WHERE wf.watchlist_id = $1
+        ORDER BY wf.position, wf.added_at, f.id`, id)
+	if err != nil {
+		return result, dbError(op, err)
+	}
+	defer rows.Close()
+
+	result.Films = make([]model.WatchlistFilm, 0)
+	for rows.Next() {
+		var film model.WatchlistFilm
+		if err := rows.Scan(&film.Id, &film.Name, &film.Releasedate, &film.Rating, &film.Position, &film.AddedAt); err != nil {
+			return result, dbError(op, err)
+		}
+		result.Films = append(result.Films, film)
+	}
+	if err := rows.Err(); err != nil {
+		return result, dbError(op, err)
+	}
+
+	return result, nil
+}
+
+func (s *Storage) CreateWatchlist(ctx context.Context, list *model.Watchlist) error {
+	const op = "storage.postgres.CreateWatchlist"
+
+	err := s.db.QueryRowContext(ctx, `
+        INSERT INTO watchlists (user_id, name, description, is_public)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at, updated_at`,
+		list.UserID, list.Name, list.Description, list.Public,
+	).Scan(&list.Id, &list.CreatedAt, &list.UpdatedAt)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	list.FilmCount = 0
+	return nil
+}
+
+// UpdateWatchlist меняет имя, описание и видимость своего списка
+func (s *Storage) UpdateWatchlist(ctx context.Context, list *model.Watchlist) error {
+	const op = "storage.postgres.UpdateWatchlist"
+
+	res, err := s.db.ExecContext(ctx, `
+        UPDATE watchlists
+        SET name = $3, description = $4, is_public = $5, updated_at = now()
+        WHERE id = $1 AND user_id = $2`,
+		list.Id, list.UserID, list.Name, list.Description, list.Public)
+	if err != nil {
+		return dbError(op, err)
+	}
+	if err := checkAffected(op, res); err != nil {
+		return err
+	}
+
+	updated, err := scanWatchlist(s.db.QueryRowContext(ctx, watchlistSelect+` WHERE w.id = $1`, list.Id))
+	if err != nil {
+		return dbError(op, err)
+	}
+	*list = updated
+
+	return nil
+}
+
+func (s *Storage) DeleteWatchlist(ctx context.Context, id, userID int) error {
+	const op = "storage.postgres.DeleteWatchlist"
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM watchlists WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	return checkAffected(op, res)
+}
+
+// AddWatchlistFilm добавляет фильм в свой список или переставляет его на position.
+// Без position новый фильм встаёт в конец, уже добавленный остаётся на месте
+func (s *Storage) AddWatchlistFilm(ctx context.Context, userID, id, filmID, position int) error {
+	const op = "storage.postgres.AddWatchlistFilm"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	if err := touchWatchlist(ctx, tx, id, userID); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := checkFilmsExist(ctx, tx, []int64{int64(filmID)}); err != nil {
+		return dbError(op, err)
+	}
+
+	order, err := watchlistOrder(ctx, tx, id)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	// Уже добавленный фильм без новой позиции остаётся на месте
+	present := slices.Contains(order, int64(filmID))
+	if present && position == 0 {
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+		}
+		return nil
+	}
+	if !present && len(order) >= model.MaxWatchlistFilms {
+		return fmt.Errorf("%s: %w", op, domain.Validation("watchlist_full",
+			fmt.Sprintf("в списке не может быть больше %d фильмов", model.MaxWatchlistFilms)).WithArgs(model.MaxWatchlistFilms))
+	}
+
+	order = slices.DeleteFunc(order, func(v int64) bool { return v == int64(filmID) })
+	if position == 0 || position > len(order) {
+		order = append(order, int64(filmID))
+	} else {
+		order = slices.Insert(order, position-1, int64(filmID))
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO watchlist_films (watchlist_id, film_id, position)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (watchlist_id, film_id) DO NOTHING`, id, filmID, len(order))
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	if err := setWatchlistOrder(ctx, tx, id, order); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return nil
+}
+
+func (s *Storage) RemoveWatchlistFilm(ctx context.Context, userID, id, filmID int) error {
+	const op = "storage.postgres.RemoveWatchlistFilm"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	if err := touchWatchlist(ctx, tx, id, userID); err != nil {
+		return dbError(op, err)
+	}
+
+	var position int
+	err = tx.QueryRowContext(ctx, `
+        DELETE FROM watchlist_films WHERE watchlist_id = $1 AND film_id = $2
+        RETURNING position`, id, filmID).Scan(&position)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	// Фильмы после удалённого сдвигаются на его место
+	_, err = tx.ExecContext(ctx, `
+        UPDATE watchlist_films SET position = position - 1
+        WHERE watchlist_id = $1 AND position > $2`, id, position)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return nil
+}
+
+// ReplaceWatchlistFilms делает содержимое списка равным filmIDs в том же порядке.
+// Фильмы из корзины остаются в списке последними, чтобы вернуться при восстановлении
+func (s *Storage) ReplaceWatchlistFilms(ctx context.Context, userID, id int, filmIDs []int) error {
+	const op = "storage.postgres.ReplaceWatchlistFilms"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, translateError(err))
+	}
+	defer tx.Rollback()
+
+	if err := touchWatchlist(ctx, tx, id, userID); err != nil {
+		return dbError(op, err)
+	}
+
+	ids := make([]int64, 0, len(filmIDs))
+	for _, filmID := range filmIDs {
+		ids = append(ids, int64(filmID))
+	}
+
+	if err := checkFilmsExist(ctx, tx, ids); err != nil {
+		return dbError(op, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM watchlist_films wf
+        USING films f
+        WHERE wf.watchlist_id = $1 AND f.id = wf.film_id AND f.deleted_at IS NULL
+          AND wf.film_id <> ALL($2)`, id, pq.Array(ids))
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO watchlist_films (watchlist_id, film_id, position)
+        SELECT $1, film_id, n FROM unnest($2::int[]) WITH ORDINALITY AS o(film_id, n)
+        ON CONFLICT (watchlist_id, film_id) DO NOTHING`, id, pq.Array(ids))
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	current, err := watchlistOrder(ctx, tx, id)
+	if err != nil {
+		return dbError(op, err)
+	}
+	order := slices.Clone(ids)
+	for _, filmID := range current {
+		if !slices.Contains(ids, filmID) {
+			order = append(order, filmID)
+		}
+	}
+
+	if err := setWatchlistOrder(ctx, tx, id, order); err != nil {
+		return dbError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, translateError(err))
+	}
+
+	return nil
+}
+
+// GetWatched - просмотренные фильмы пользователя, последние просмотры первыми
+func (s *Storage) GetWatched(ctx context.Context, params model.WatchedListParams) (model.WatchedPage, error) {
+	const op = "storage.postgres.GetWatched"
+
+	page := model.WatchedPage{
+		Items:  []model.WatchedFilm{},
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}
+
+	err := s.db.QueryRowContext(ctx, `
+        SELECT count(*)
+        FROM watched_films wf
+        JOIN films f ON f.id = wf.film_id AND f.deleted_at IS NULL
+        WHERE wf.user_id = $1`, params.UserID).Scan(&page.Total)
+	if err != nil {
+		return page, dbError(op, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT f.id, f.name, f.release_date, f.rating, wf.watched_on
+        FROM watched_films wf
+        JOIN films f ON f.id = wf.film_id AND f.deleted_at IS NULL
+        WHERE wf.user_id = $1
+        ORDER BY wf.watched_on DESC, f.id DESC
+        LIMIT $2 OFFSET $3`, params.UserID, params.Limit, params.Offset)
+	if err != nil {
+		return page, dbError(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var film model.WatchedFilm
+		if err := rows.Scan(&film.Id, &film.Name, &film.Releasedate, &film.Rating, &film.WatchedOn); err != nil {
+			return page, dbError(op, err)
+		}
+		page.Items = append(page.Items, film)
+	}
+	if err := rows.Err(); err != nil {
+		return page, dbError(op, err)
+	}
+
+	return page, nil
+}
+
+// SetWatched отмечает фильм просмотренным; повторная отметка меняет дату
+func (s *Storage) SetWatched(ctx context.Context, userID, filmID int, watchedOn time.Time) error {
+	const op = "storage.postgres.SetWatched"
+
+	res, err := s.db.ExecContext(ctx, `
+        INSERT INTO watched_films (user_id, film_id, watched_on)
+        SELECT $1, f.id, $3 FROM films f WHERE f.id = $2 AND f.deleted_at IS NULL
+        ON CONFLICT (user_id, film_id) DO UPDATE SET watched_on = EXCLUDED.watched_on`,
+		userID, filmID, watchedOn)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	return checkAffected(op, res)
+}
+
+func (s *Storage) DeleteWatched(ctx context.Context, userID, filmID int) error {
+	const op = "storage.postgres.DeleteWatched"
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM watched_films WHERE user_id = $1 AND film_id = $2`, userID, filmID)
+	if err != nil {
+		return dbError(op, err)
+	}
+
+	return checkAffected(op, res)
+}
+
+// feedFrom - непросмотренные фильмы, в которых играют актёры из просмотренных фильмов
+const feedFrom = `
+        FROM films f
+        JOIN film_credits fc ON fc.film_id = f.id AND fc.role = 'actor'
+        JOIN people p ON p.id = fc.person_id AND p.deleted_at IS NULL
+        WHERE f.deleted_at IS NULL
+          AND f.release_date >= $2
+          AND NOT EXISTS (SELECT 1 FROM watched_films w WHERE w.user_id = $1 AND w.film_id = f.id)
+          AND fc.person_id IN (
+              SELECT seen.person_id
+              FROM watched_films w
+              JOIN film_credits seen ON seen.film_id = w.film_id AND seen.role = 'actor'
+              WHERE w.user_id = $1)`
+
+// GetFeed - лента пользователя: новые фильмы с актёрами из уже просмотренных, новые первыми
+func (s *Storage) GetFeed(ctx context.Context, params model.FeedParams) (model.FeedPage, error) {
+	const op = "storage.postgres.GetFeed"
+
+	page := model.FeedPage{
+		Items:  []model.FeedItem{},
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}
+
+	err := s.db.QueryRowContext(ctx, `SELECT count(DISTINCT f.id)`+feedFrom, params.UserID, params.ReleasedFrom).Scan(&page.Total)
+	if err != nil {
+		return page, dbError(op, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+        SELECT f.id, f.name, f.release_date, f.rating,
+               jsonb_agg(jsonb_build_object('id', p.id, 'name', p.name) ORDER BY fc.position NULLS LAST, p.name, p.id)`+
+		feedFrom+`
+        GROUP BY f.id
+        ORDER BY f.release_date DESC, f.id DESC
+        LIMIT $3 OFFSET $4`, params.UserID, params.ReleasedFrom, params.Limit, params.Offset)
+	if err != nil {
+		return page, dbError(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item model.FeedItem
+		var actors []byte
+		if err := rows.Scan(&item.Id, &item.Name, &item.Releasedate, &item.Rating, &actors); err != nil {
+			return page, dbError(op, err)
+		}
+		if err := json.Unmarshal(actors, &item.Actors); err != nil {
+			return page, fmt.Errorf("%s: failed to decode actors: %w", op, err)
+		}
+		page.Items = append(page.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return page, dbError(op, err)
+	}
+
+	return page, nil
+}
+
+// touchWatchlist блокирует свой список пользователя и обновляет время его изменения.
+// Чужой список для пользователя не существует
+func touchWatchlist(ctx context.Context, tx *sql.Tx, id, userID int) error {
+	var locked int
+	err := tx.QueryRowContext(ctx, `
+        UPDATE watchlists SET updated_at = now()
+        WHERE id = $1 AND user_id = $2
+        RETURNING id`, id, userID).Scan(&locked)
+	if err != nil {
+		return fmt.Errorf("failed to lock watchlist: %w", translateError(err))
+	}
+	return nil
+}
+
+// watchlistOrder - фильмы списка, включая фильмы из корзины, в текущем порядке
+func watchlistOrder(ctx context.Context, tx *sql.Tx, id int) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx, `
+        SELECT film_id FROM watchlist_films
+        WHERE watchlist_id = $1
+        ORDER BY position, added_at, film_id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watchlist order: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	var order []int64
+	for rows.Next() {
+		var filmID int64
+		if err := rows.Scan(&filmID); err != nil {
+			return nil, fmt.Errorf("failed to read watchlist order: %w", translateError(err))
+		}
+		order = append(order, filmID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read watchlist order: %w", translateError(err))
+	}
+
+	return order, nil
+}
+
+// setWatchlistOrder нумерует фильмы списка с 1 в порядке order
+func setWatchlistOrder(ctx context.Context, tx *sql.Tx, id int, order []int64) error {
+	_, err := tx.ExecContext(ctx, `
+        UPDATE watchlist_films wf SET position = o.n
+        FROM unnest($2::int[]) WITH ORDINALITY AS o(film_id, n)
+        WHERE wf.watchlist_id = $1 AND wf.film_id = o.film_id`, id, pq.Array(order))
+	if err != nil {
+		return fmt.Errorf("failed to reorder watchlist: %w", translateError(err))
+	}
+	return nil
+}
+
+// checkFilmsExist проверяет, что все фильмы есть в базе и не в корзине, и блокирует их от удаления
+func checkFilmsExist(ctx context.Context, tx *sql.Tx, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM films WHERE id = ANY($1) AND deleted_at IS NULL FOR SHARE`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to check films: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	found := make(map[int64]struct{}, len(ids))
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return fmt.Errorf("failed to check films: %w", translateError(err))
+		}
+		found[id] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to check films: %w", translateError(err))
+	}
+
+	var missing []int64
+	for _, id := range ids {
+		if _, ok := found[id]; !ok && !slices.Contains(missing, id) {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return domain.Wrap(ErrMissingFilms, domain.ErrValidation, "unknown_films",
+			fmt.Sprintf("в списке указаны несуществующие фильмы: %v", missing)).WithArgs(missing)
+	}
+
+	return nil
+}
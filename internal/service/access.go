@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	"film-library/internal/repository"
+	"film-library/internal/utils/sl"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// rolesCacheTTL - как долго матрица прав живёт в памяти.
+// Она меняется только миграциями, поэтому на каждый запрос в базу не ходим
+const rolesCacheTTL = time.Minute
+
+type AccessService struct {
+	repo repository.Access
+
+	mu       sync.RWMutex
+	roles    map[int]model.Role
+	loadedAt time.Time
+}
+
+func NewAccessService(repo repository.Access) *AccessService {
+	return &AccessService{repo: repo}
+}
+
+func (s *AccessService) HasPermission(ctx context.Context, role int, required model.Permission) (bool, error) {
+	roles, err := s.cachedRoles(ctx)
+	if err != nil {
+		return false, fmt.Errorf("ошибка загрузки прав: %w", err)
+	}
+
+	r, ok := roles[role]
+	if !ok {
+		return false, nil
+	}
+
+	return r.Allows(required), nil
+}
+
+func (s *AccessService) GetRoles(ctx context.Context) ([]model.Role, error) {
+	roles, err := s.repo.GetRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения ролей: %w", err)
+	}
+
+	return roles, nil
+}
+
+func (s *AccessService) AssignRole(ctx context.Context, userID int, role string) (model.UserResponse, error) {
+	user, err := s.repo.SetUserRole(ctx, userID, role)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return model.UserResponse{}, domain.Wrap(err, domain.ErrNotFound, "user_not_found", "пользователь не найден")
+	case err != nil:
+		// Неизвестная роль приходит из хранилища уже как ошибка валидации
+		return model.UserResponse{}, fmt.Errorf("ошибка смены роли: %w", err)
+	}
+
+	return model.UserResponse{ID: user.ID, Username: user.Username, Role: user.Role}, nil
+}
+
+func (s *AccessService) cachedRoles(ctx context.Context) (map[int]model.Role, error) {
+	s.mu.RLock()
+	if s.roles != nil && time.Since(s.loadedAt) < rolesCacheTTL {
+		roles := s.roles
+		s.mu.RUnlock()
+		return roles, nil
+	}
+	s.mu.RUnlock()
+
+	list, err := s.repo.GetRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make(map[int]model.Role, len(list))
+	for _, r := range list {
+		roles[r.ID] = r
+	}
+
+	s.mu.Lock()
+	s.roles = roles
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+
+	sl.FromContext(ctx).Debug("permission matrix reloaded", slog.Int("roles", len(roles)))
+
+	return roles, nil
+}
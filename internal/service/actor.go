@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"film-library/internal/domain"
 	"film-library/internal/model"
 	"film-library/internal/repository"
 	"fmt"
@@ -18,34 +19,92 @@ func NewActorService(repo repository.Actor) *ActorService {
 }
 
 func (s *ActorService) AddActor(ctx context.Context, actor model.Actor) error {
-	exists, err := s.repo.ActorExistsByName(ctx, actor.Name)
-	if err != nil {
-		return fmt.Errorf("ошибка проверки актёра: %w", err)
+	// Повтор имени ловит уникальный индекс: отдельная проверка заранее не защищает от гонки
+	if err := s.repo.CreateActor(ctx, &actor); err != nil {
+		return actorError("ошибка создания актёра", err)
 	}
-	if !exists {
-		return errors.New("актёр не найден")
+
+	return nil
+}
+
+// UpdateActor перезаписывает актёра. actor.Version - ожидаемая версия (0 - без проверки),
+// в ответе - новая
+func (s *ActorService) UpdateActor(ctx context.Context, actor model.Actor) (model.Actor, error) {
+	if err := actor.Validate(); err != nil {
+		return model.Actor{}, err
+	}
+
+	if err := s.repo.UpdateActor(ctx, &actor); err != nil {
+		return model.Actor{}, actorError("ошибка изменения актёра", err)
 	}
 
-	return s.repo.CreateActor(ctx, &actor)
+	return actor, nil
 }
 
-func (s *ActorService) UpdateActor(ctx context.Context, actor model.Actor) error {
-	exists, err := s.repo.ActorExistsById(ctx, actor.Id)
+// PatchActor применяет патч к текущему актёру, проверяет результат целиком
+// и сохраняет только изменившиеся колонки. version - ожидаемая версия (0 - без проверки)
+func (s *ActorService) PatchActor(ctx context.Context, id, version int, patch model.Patch) (model.Actor, error) {
+	current, err := s.repo.GetActorByID(ctx, id)
 	if err != nil {
-		return fmt.Errorf("ошибка проверки актёра: %w", err)
+		return model.Actor{}, actorError("ошибка получения актёра", err)
 	}
-	if !exists {
-		return errors.New("актёр не найден")
+	if version != 0 && current.Actor.Version != version {
+		return model.Actor{}, domain.VersionMismatch()
 	}
 
+	actor := current.Actor
+	if err := patch.Apply(&actor); err != nil {
+		return model.Actor{}, err
+	}
+	if actor.Id != id {
+		return model.Actor{}, model.ReadOnlyField("id")
+	}
+	actor.Version = current.Actor.Version
+
 	if err := actor.Validate(); err != nil {
-		return fmt.Errorf("ошибка валидации актёра: %w", err)
+		return model.Actor{}, err
 	}
 
-	return s.repo.UpdateActor(ctx, &actor)
+	changes := model.ChangedColumns(current.Actor.Columns(), actor.Columns())
+	if len(changes) == 0 {
+		return actor, nil
+	}
+
+	// Версия, с которой читали, защищает от записи между чтением и обновлением
+	actor.Version, err = s.repo.PatchActor(ctx, id, current.Actor.Version, changes)
+	if err != nil {
+		return model.Actor{}, actorError("ошибка изменения актёра", err)
+	}
+
+	return actor, nil
+}
+
+func (s *ActorService) GetActorByID(ctx context.Context, id int) (model.ActorWithFilms, error) {
+	actor, err := s.repo.GetActorByID(ctx, id)
+	if err != nil {
+		return model.ActorWithFilms{}, actorError("ошибка получения актёра", err)
+	}
+
+	return actor, nil
 }
 
-func (s *ActorService) DeleteActor(ctx context.Context, id int) error {
+func (s *ActorService) DeleteActor(ctx context.Context, id, version int) error {
 	// TODO: ... могу ли я удалять актера, есть он привязан к какому-либо фильму??
-	return s.repo.DeleteActor(ctx, id)
+	if err := s.repo.DeleteActor(ctx, id, version); err != nil {
+		return actorError("ошибка удаления актёра", err)
+	}
+
+	return nil
+}
+
+// actorError уточняет код и сообщение ошибок хранилища для актёра
+func actorError(msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return domain.Wrap(err, domain.ErrNotFound, "actor_not_found", "актёр не найден")
+	case errors.Is(err, domain.ErrConflict):
+		return domain.Wrap(err, domain.ErrConflict, "actor_exists", "актёр с таким именем уже существует")
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
 }
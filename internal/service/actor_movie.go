@@ -16,15 +16,20 @@ func NewActorMovieService(repo repository.ActorMovie) *ActorMovieService {
 	return &ActorMovieService{repo: repo}
 }
 
-func (s *ActorMovieService) GetAllActorWithFilms(ctx context.Context) (map[int]model.ActorWithFilms, error) {
-	ListActors, err := s.repo.GetActorsWithFilms(ctx)
+func (s *ActorMovieService) GetActors(ctx context.Context, params model.ActorListParams) (model.ActorPage, error) {
+	page, err := s.repo.GetActors(ctx, params)
 	if err != nil {
-		return map[int]model.ActorWithFilms{}, fmt.Errorf("Ошибка получения списка всех актеров: %w", err)
+		return model.ActorPage{}, fmt.Errorf("Ошибка получения списка актёров: %w", err)
 	}
 
-	if len(ListActors) == 0 {
-		return map[int]model.ActorWithFilms{}, nil
+	return page, nil
+}
+
+func (s *ActorMovieService) GetActorFilms(ctx context.Context, actorID int) ([]model.Film, error) {
+	films, err := s.repo.GetFilmsByActor(ctx, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("Ошибка получения фильмов актёра: %w", err)
 	}
 
-	return ListActors, nil
+	return films, nil
 }
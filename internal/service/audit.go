@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"film-library/internal/model"
+	"film-library/internal/repository"
+	"fmt"
+)
+
+type AuditService struct {
+	repo repository.Audit
+}
+
+func NewAuditService(repo repository.Audit) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+func (s *AuditService) GetAuditLog(ctx context.Context, filter model.AuditFilter) (model.AuditPage, error) {
+	page, err := s.repo.GetAuditLog(ctx, filter)
+	if err != nil {
+		return model.AuditPage{}, fmt.Errorf("ошибка получения журнала: %w", err)
+	}
+
+	return page, nil
+}
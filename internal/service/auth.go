@@ -2,8 +2,16 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"film-library/internal/config"
+	"film-library/internal/domain"
 	"film-library/internal/model"
 	"film-library/internal/repository"
+	"film-library/internal/utils/sl"
 	"fmt"
 	"time"
 
@@ -11,73 +19,171 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-const tokenTTL = 24 * time.Hour
-
 type AuthService struct {
 	// repo      repository.AuthRepository
-	repo      repository.Authorization
-	secretKey []byte
+	repo       repository.Authorization
+	secretKey  []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
 }
 
-func NewAuthService(repo repository.Authorization, secret string) *AuthService {
+func NewAuthService(repo repository.Authorization, secret string, cfg config.Auth) *AuthService {
 	return &AuthService{
-		repo:      repo,
-		secretKey: []byte(secret),
+		repo:       repo,
+		secretKey:  []byte(secret),
+		accessTTL:  cfg.AccessTokenTTL,
+		refreshTTL: cfg.RefreshTokenTTL,
 	}
 }
 
-func (s *AuthService) CreateUser(ctx context.Context, user model.User) (string, error) {
+func (s *AuthService) CreateUser(ctx context.Context, user model.User) (model.TokenPair, error) {
 	hashedPassword, err := hashPassword(user.Password)
 	if err != nil {
-		return "", fmt.Errorf("failed to hash password: %w", err)
+		return model.TokenPair{}, fmt.Errorf("failed to hash password: %w", err)
 	}
 
 	user.Password = hashedPassword
 
 	if err := s.repo.CreateUser(ctx, &user); err != nil {
-		return "", fmt.Errorf("failed to create user: %w", err)
+		if errors.Is(err, domain.ErrConflict) {
+			return model.TokenPair{}, domain.Wrap(err, domain.ErrConflict, "user_exists", "пользователь с таким именем уже существует")
+		}
+		return model.TokenPair{}, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256,
-		jwt.MapClaims{
-			"user_id":  user.ID,
-			"username": user.Username,
-			"role":     user.Role,
-			"exp":      time.Now().Add(tokenTTL).Unix(),
-		})
+	return s.issueTokens(ctx, &user, "")
+}
+
+func (s *AuthService) VerifyUser(ctx context.Context, username, password string) (model.TokenPair, *model.User, error) {
+	// Неизвестный пользователь и неверный пароль неотличимы для клиента
+	user, err := s.repo.VerifyUser(ctx, username)
+	if errors.Is(err, domain.ErrNotFound) {
+		return model.TokenPair{}, nil, domain.Wrap(err, domain.ErrUnauthorized, "invalid_credentials", "неверное имя пользователя или пароль")
+	}
+	if err != nil {
+		return model.TokenPair{}, nil, fmt.Errorf("failed to verify user: %w", err)
+	}
 
-	tokenString, err := token.SignedString(s.secretKey)
+	if !checkPasswordHash(password, user.Password) {
+		return model.TokenPair{}, nil, domain.Unauthorized("invalid_credentials", "неверное имя пользователя или пароль")
+	}
+
+	tokens, err := s.issueTokens(ctx, user, "")
 	if err != nil {
-		return "", fmt.Errorf("failed to generate token: %w", err)
+		return model.TokenPair{}, nil, err
 	}
 
-	return tokenString, nil
+	return tokens, user, nil
 }
 
-func (s *AuthService) VerifyUser(ctx context.Context, username, password string) (string, *model.User, error) {
-	user, err := s.repo.VerifyUser(ctx, username)
+// RefreshTokens обменивает refresh-токен на новую пару. Старый токен
+// становится недействительным, роль берётся из базы на момент обмена
+func (s *AuthService) RefreshTokens(ctx context.Context, refreshToken string) (model.TokenPair, error) {
+	old, err := s.repo.ConsumeRefreshToken(ctx, hashToken(refreshToken))
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to verify user: %w", err)
+		if errors.Is(err, repository.ErrTokenReused) {
+			sl.FromContext(ctx).Warn("refresh token reuse detected, session revoked", sl.Err(err))
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			return model.TokenPair{}, domain.Wrap(err, domain.ErrUnauthorized, "invalid_refresh_token", "недействительный refresh-токен")
+		}
+		// Повторное использование и истечение срока хранилище уже отдаёт как ErrUnauthorized
+		return model.TokenPair{}, fmt.Errorf("failed to refresh tokens: %w", err)
 	}
 
-	if !checkPasswordHash(password, user.Password) {
-		return "", nil, fmt.Errorf("invalid credentials")
+	user, err := s.repo.GetUserByID(ctx, old.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return model.TokenPair{}, domain.Wrap(err, domain.ErrUnauthorized, "invalid_refresh_token", "пользователь удалён")
+		}
+		return model.TokenPair{}, fmt.Errorf("failed to refresh tokens: %w", err)
+	}
+
+	return s.issueTokens(ctx, user, old.FamilyID)
+}
+
+// Logout отзывает текущий access-токен и цепочку переданного refresh-токена
+func (s *AuthService) Logout(ctx context.Context, userID int, refreshToken, accessJTI string, accessExpiresAt time.Time) error {
+	var refreshHash string
+	if refreshToken != "" {
+		refreshHash = hashToken(refreshToken)
 	}
 
+	if err := s.repo.RevokeSession(ctx, userID, refreshHash, accessJTI, accessExpiresAt); err != nil {
+		return fmt.Errorf("failed to logout: %w", err)
+	}
+
+	return nil
+}
+
+func (s *AuthService) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	return s.repo.IsTokenRevoked(ctx, jti)
+}
+
+// PurgeExpiredTokens удаляет записи о токенах с истёкшим сроком действия
+func (s *AuthService) PurgeExpiredTokens(ctx context.Context) (model.TokenPurgeResult, error) {
+	result, err := s.repo.PurgeExpiredTokens(ctx)
+	if err != nil {
+		return model.TokenPurgeResult{}, fmt.Errorf("failed to purge expired tokens: %w", err)
+	}
+
+	return result, nil
+}
+
+// issueTokens выпускает access-токен и refresh-токен. Пустой familyID
+// начинает новую цепочку ротаций (вход или регистрация)
+func (s *AuthService) issueTokens(ctx context.Context, user *model.User, familyID string) (model.TokenPair, error) {
+	jti, err := randomToken(16)
+	if err != nil {
+		return model.TokenPair{}, fmt.Errorf("failed to generate token id: %w", err)
+	}
+
+	if familyID == "" {
+		if familyID, err = randomToken(16); err != nil {
+			return model.TokenPair{}, fmt.Errorf("failed to generate token family: %w", err)
+		}
+	}
+
+	now := time.Now()
+	accessExpiresAt := now.Add(s.accessTTL)
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256,
 		jwt.MapClaims{
 			"user_id":  user.ID,
 			"username": user.Username,
 			"role":     user.Role,
-			"exp":      time.Now().Add(24 * time.Hour).Unix(),
+			"jti":      jti,
+			"iat":      now.Unix(),
+			"exp":      accessExpiresAt.Unix(),
 		})
 
-	tokenString, err := token.SignedString(s.secretKey)
+	accessToken, err := token.SignedString(s.secretKey)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to generate token: %w", err)
+		return model.TokenPair{}, fmt.Errorf("failed to generate token: %w", err)
 	}
 
-	return tokenString, user, nil
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return model.TokenPair{}, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	err = s.repo.SaveRefreshToken(ctx, model.RefreshToken{
+		Hash:            hashToken(refreshToken),
+		UserID:          user.ID,
+		FamilyID:        familyID,
+		AccessJTI:       jti,
+		AccessExpiresAt: accessExpiresAt,
+		ExpiresAt:       now.Add(s.refreshTTL),
+	})
+	if err != nil {
+		return model.TokenPair{}, fmt.Errorf("failed to save refresh token: %w", err)
+	}
+
+	return model.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.accessTTL.Seconds()),
+	}, nil
 }
 
 //
@@ -101,7 +207,6 @@ func (s *AuthService) VerifyToken(tokenString string) (*model.TokenClaims, error
 //
 
 func hashPassword(password string) (string, error) {
-	fmt.Println("Password to hash:", fmt.Sprintf("%q", password)) // добавил %q чтобы увидеть пробелы
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return "", fmt.Errorf("failed to hash password: %w", err)
@@ -109,12 +214,21 @@ func hashPassword(password string) (string, error) {
 	return string(hashedPassword), nil
 }
 
-func checkPasswordHash(password, hash string) bool {
-	fmt.Println("Password to check:", password)
-	fmt.Println("Hash to compare:", hash)
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	if err != nil {
-		fmt.Println("Password comparison failed:", err)
+// randomToken - случайная строка из n байт в base64url
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
 	}
-	return err == nil
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken - в базе хранится только sha256 от refresh-токена
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func checkPasswordHash(password, hash string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
 }
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"film-library/internal/config"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	mock_repository "film-library/internal/repository/mocks"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthService_CreateUserDuplicate(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	// Так хранилище возвращает нарушение уникальности имени
+	duplicate := fmt.Errorf("storage.postgres.CreateUser: %w",
+		domain.Wrap(&pq.Error{Code: "23505", Constraint: "users_name_key"}, domain.ErrConflict, "already_exists", "запись с такими данными уже существует"))
+
+	repo := mock_repository.NewMockAuthorization(c)
+	repo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(duplicate)
+
+	svc := NewAuthService(repo, "secret", config.Auth{})
+	_, err := svc.CreateUser(context.Background(), model.User{Username: "neo", Password: "password"})
+
+	require.ErrorIs(t, err, domain.ErrConflict)
+	var de *domain.Error
+	require.True(t, errors.As(err, &de))
+	require.Equal(t, "user_exists", de.Code)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	"film-library/internal/repository"
+	"fmt"
+)
+
+type CastingService struct {
+	repo repository.Casting
+}
+
+func NewCastingService(repo repository.Casting) *CastingService {
+	return &CastingService{repo: repo}
+}
+
+func (s *CastingService) GetCast(ctx context.Context, filmID int) ([]model.CastMember, error) {
+	cast, err := s.repo.GetCast(ctx, filmID)
+	if err != nil {
+		return nil, castingError("ошибка получения состава фильма", err)
+	}
+
+	return cast, nil
+}
+
+func (s *CastingService) ReplaceCast(ctx context.Context, filmID int, entries []model.CastingEntry) error {
+	if err := model.ValidateCasting(entries); err != nil {
+		return err
+	}
+
+	if err := s.repo.ReplaceCast(ctx, filmID, entries); err != nil {
+		return castingError("ошибка изменения состава фильма", err)
+	}
+
+	return nil
+}
+
+func (s *CastingService) AddCastMember(ctx context.Context, filmID int, entry model.CastingEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.repo.AddCastMember(ctx, filmID, entry); err != nil {
+		return castingError("ошибка добавления актёра в состав", err)
+	}
+
+	return nil
+}
+
+func (s *CastingService) RemoveCastMember(ctx context.Context, filmID, actorID int) error {
+	if err := s.repo.RemoveCastMember(ctx, filmID, actorID); err != nil {
+		return castingError("ошибка удаления актёра из состава", err)
+	}
+
+	return nil
+}
+
+// castingError уточняет код и сообщение для отсутствующего фильма или связи.
+// Ссылки на несуществующих актёров хранилище уже отдаёт как ошибку валидации
+func castingError(msg string, err error) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Wrap(err, domain.ErrNotFound, "cast_not_found", "фильм или актёр в составе не найден")
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
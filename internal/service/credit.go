@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	"film-library/internal/repository"
+	"fmt"
+)
+
+type CreditService struct {
+	repo repository.Credit
+}
+
+func NewCreditService(repo repository.Credit) *CreditService {
+	return &CreditService{repo: repo}
+}
+
+func (s *CreditService) GetCredits(ctx context.Context, filmID int) ([]model.Credit, error) {
+	credits, err := s.repo.GetCredits(ctx, filmID)
+	if err != nil {
+		return nil, creditError("ошибка получения титров фильма", err)
+	}
+
+	return credits, nil
+}
+
+func (s *CreditService) ReplaceCredits(ctx context.Context, filmID int, credits []model.Credit) error {
+	if err := model.ValidateCredits(credits); err != nil {
+		return err
+	}
+
+	if err := s.repo.ReplaceCredits(ctx, filmID, credits); err != nil {
+		return creditError("ошибка изменения титров фильма", err)
+	}
+
+	return nil
+}
+
+func (s *CreditService) AddCredit(ctx context.Context, filmID int, credit model.Credit) error {
+	if err := credit.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.repo.AddCredit(ctx, filmID, credit); err != nil {
+		return creditError("ошибка добавления участника в титры", err)
+	}
+
+	return nil
+}
+
+func (s *CreditService) RemoveCredit(ctx context.Context, filmID, personID int, role string) error {
+	if err := s.repo.RemoveCredit(ctx, filmID, personID, role); err != nil {
+		return creditError("ошибка удаления участника из титров", err)
+	}
+
+	return nil
+}
+
+func (s *CreditService) GetFilmography(ctx context.Context, personID int) (model.Filmography, error) {
+	filmography, err := s.repo.GetFilmography(ctx, personID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return model.Filmography{}, domain.Wrap(err, domain.ErrNotFound, "person_not_found", "участник не найден")
+	}
+	if err != nil {
+		return model.Filmography{}, fmt.Errorf("ошибка получения фильмографии: %w", err)
+	}
+
+	return filmography, nil
+}
+
+// creditError уточняет код и сообщение для отсутствующего фильма или записи титров.
+// Ссылки на несуществующих участников хранилище уже отдаёт как ошибку валидации
+func creditError(msg string, err error) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Wrap(err, domain.ErrNotFound, "credit_not_found", "фильм или участник в титрах не найден")
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
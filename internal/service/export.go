@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"film-library/internal/model"
+	"film-library/internal/repository"
+	"film-library/internal/utils/records"
+	"fmt"
+	"io"
+)
+
+type ExportService struct {
+	repo repository.Export
+}
+
+func NewExportService(repo repository.Export) *ExportService {
+	return &ExportService{repo: repo}
+}
+
+// Export пишет выгрузку в w по мере чтения из базы. Параметры уже проверены вызывающим
+func (s *ExportService) Export(ctx context.Context, params model.ExportParams, w io.Writer) error {
+	out, err := records.NewWriter(params.Format, w, model.ExportColumns[params.Entity])
+	if err != nil {
+		return fmt.Errorf("ошибка выгрузки: %w", err)
+	}
+
+	switch params.Entity {
+	case model.ExportFilms:
+		err = s.repo.ExportFilms(ctx, params, func(film model.Film) error {
+			return out.Write(film.Values())
+		})
+	case model.ExportActors:
+		err = s.repo.ExportActors(ctx, params, func(actor model.Actor) error {
+			return out.Write(actor.Values())
+		})
+	case model.ExportCasting:
+		err = s.repo.ExportCasting(ctx, params, func(rec model.CastingRecord) error {
+			return out.Write(rec.Values())
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка выгрузки: %w", err)
+	}
+
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("ошибка выгрузки: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"film-library/internal/model"
+	mock_repository "film-library/internal/repository/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportService_Export(t *testing.T) {
+	type mockBehavior func(r *mock_repository.MockExport)
+
+	tests := []struct {
+		name         string
+		params       model.ExportParams
+		mockBehavior mockBehavior
+		expected     string
+		expectedErr  bool
+	}{
+		{
+			name:   "Films CSV",
+			params: model.ExportParams{Entity: model.ExportFilms, Format: "csv"},
+			mockBehavior: func(r *mock_repository.MockExport) {
+				r.EXPECT().ExportFilms(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ model.ExportParams, emit func(model.Film) error) error {
+						return emit(model.Film{Id: 1, Name: "Matrix", Releasedate: time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC), Rating: 8.7,
+							UserScore: 8.25, Votes: 4, FilmLabels: model.FilmLabels{
+								Genres: []string{"Action", "Science Fiction"}, Countries: []string{"US"}, Tags: []string{},
+							}})
+					})
+			},
+			expected: "id,name,description,release_date,rating,user_score,votes,genres,countries,tags\n" +
+				"1,Matrix,,1999-03-31,8.7,8.25,4,Action|Science Fiction,US,\n",
+		},
+		{
+			name:   "Casting NDJSON",
+			params: model.ExportParams{Entity: model.ExportCasting, Format: "ndjson"},
+			mockBehavior: func(r *mock_repository.MockExport) {
+				r.EXPECT().ExportCasting(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ model.ExportParams, emit func(model.CastingRecord) error) error {
+						if err := emit(model.CastingRecord{FilmID: 1, Film: "Matrix", ActorID: 7, Actor: "Keanu Reeves", Character: "Neo", BillingOrder: 1}); err != nil {
+							return err
+						}
+						return emit(model.CastingRecord{FilmID: 1, Film: "Matrix", ActorID: 8, Actor: "Hugo Weaving"})
+					})
+			},
+			expected: `{"film_id":1,"film":"Matrix","actor_id":7,"actor":"Keanu Reeves","character":"Neo","billing_order":1}` + "\n" +
+				`{"film_id":1,"film":"Matrix","actor_id":8,"actor":"Hugo Weaving","character":null,"billing_order":null}` + "\n",
+		},
+		{
+			name:   "Storage Error",
+			params: model.ExportParams{Entity: model.ExportActors, Format: "json"},
+			mockBehavior: func(r *mock_repository.MockExport) {
+				r.EXPECT().ExportActors(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockExport(c)
+			test.mockBehavior(repo)
+
+			service := NewExportService(repo)
+
+			var out strings.Builder
+			err := service.Export(context.Background(), test.params, &out)
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expected, out.String())
+		})
+	}
+}
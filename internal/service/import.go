@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	"film-library/internal/repository"
+	"film-library/internal/utils/records"
+	"fmt"
+	"io"
+)
+
+type ImportService struct {
+	repo repository.Import
+}
+
+func NewImportService(repo repository.Import) *ImportService {
+	return &ImportService{repo: repo}
+}
+
+// Import читает строки из r по одной, проверяет каждую и передаёт корректные в хранилище.
+// Некорректные строки не прерывают импорт, а попадают в отчёт
+func (s *ImportService) Import(ctx context.Context, req model.ImportRequest, r io.Reader) (model.ImportReport, error) {
+	if err := req.Validate(); err != nil {
+		return model.ImportReport{}, err
+	}
+
+	reader, err := records.NewReader(req.Format, r)
+	if err != nil {
+		return model.ImportReport{}, domain.Wrap(err, domain.ErrBadRequest, "invalid_import_file", "не удалось прочитать заголовок файла")
+	}
+	if err := model.ValidateImportColumns(req.Kind, reader.Columns()); err != nil {
+		return model.ImportReport{}, err
+	}
+
+	report := model.ImportReport{Kind: req.Kind, DryRun: req.DryRun, Errors: []model.ImportRowError{}}
+
+	var result model.ImportResult
+	switch req.Kind {
+	case model.ImportFilms:
+		result, err = s.repo.ImportFilms(ctx, importRows(reader, &report, model.ParseImportFilm), req.DryRun)
+	case model.ImportActors:
+		result, err = s.repo.ImportActors(ctx, importRows(reader, &report, func(row map[string]string, _ int) (model.Actor, error) {
+			return model.ParseImportActor(row)
+		}), req.DryRun)
+	case model.ImportCast:
+		result, err = s.repo.ImportCast(ctx, importRows(reader, &report, model.ParseImportCast), req.DryRun)
+	}
+	if err != nil {
+		return model.ImportReport{}, fmt.Errorf("ошибка импорта: %w", err)
+	}
+
+	for _, rowErr := range result.Errors {
+		report.AddError(rowErr.Line, rowErr.Errors)
+	}
+	report.Created = result.Created
+	report.Updated = result.Updated
+	report.Unchanged = report.Rows - report.Failed - report.Created - report.Updated
+
+	return report, nil
+}
+
+// importRows превращает читатель в источник строк для хранилища: разбирает
+// и проверяет каждую запись, отклонённые записывает в отчёт
+func importRows[T any](reader records.Reader, report *model.ImportReport, parse func(row map[string]string, line int) (T, error)) func(yield func(T) bool) error {
+	return func(yield func(T) bool) error {
+		for {
+			row, err := reader.Next()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			var rowErr *records.RowError
+			if errors.As(err, &rowErr) {
+				report.Rows++
+				report.AddError(rowErr.Line, domain.FieldErrors{{
+					Rule: "format", Message: "строку не удалось разобрать", Key: "validation.row",
+				}})
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("ошибка чтения файла: %w", err)
+			}
+
+			report.Rows++
+			value, err := parse(row, reader.Line())
+			var fieldErrs domain.FieldErrors
+			if errors.As(err, &fieldErrs) {
+				report.AddError(reader.Line(), fieldErrs)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			if !yield(value) {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	mock_repository "film-library/internal/repository/mocks"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportService_Import(t *testing.T) {
+	type mockBehavior func(r *mock_repository.MockImport)
+
+	tests := []struct {
+		name           string
+		req            model.ImportRequest
+		input          string
+		mockBehavior   mockBehavior
+		expected       model.ImportReport
+		expectedRows   []string
+		expectedFields []string
+		expectedKind   error
+		expectedCode   string
+	}{
+		{
+			name:  "Invalid Rows Go To Report",
+			req:   model.ImportRequest{Kind: model.ImportActors, Format: "csv", DryRun: true},
+			input: "Name,gender,date_of_birth\nKeanu Reeves,male,1964-09-02\nNobody,robot,1990-01-01\nCarrie-Anne Moss,female,1967-08-21\nBroken,male,02.09.1964\n",
+			mockBehavior: func(r *mock_repository.MockImport) {
+				r.EXPECT().ImportActors(gomock.Any(), gomock.Any(), true).DoAndReturn(
+					func(_ context.Context, rows func(yield func(model.Actor) bool) error, _ bool) (model.ImportResult, error) {
+						var names []string
+						err := rows(func(a model.Actor) bool {
+							names = append(names, a.Name)
+							return true
+						})
+						require.Equal(t, []string{"Keanu Reeves", "Carrie-Anne Moss"}, names)
+						return model.ImportResult{Created: 1}, err
+					})
+			},
+			expected:       model.ImportReport{Kind: model.ImportActors, DryRun: true, Rows: 4, Created: 1, Unchanged: 1, Failed: 2},
+			expectedFields: []string{"gender", "date_of_birth"},
+		},
+		{
+			name:  "Rejected By Storage",
+			req:   model.ImportRequest{Kind: model.ImportCast, Format: "ndjson"},
+			input: `{"film": "Matrix", "actor": "Keanu Reeves", "billing_order": 1}` + "\n" + `{"film": "Matrix 5", "actor": "Keanu Reeves"}` + "\n",
+			mockBehavior: func(r *mock_repository.MockImport) {
+				r.EXPECT().ImportCast(gomock.Any(), gomock.Any(), false).DoAndReturn(
+					func(_ context.Context, rows func(yield func(model.CastImportRow) bool) error, _ bool) (model.ImportResult, error) {
+						var lines []int
+						err := rows(func(c model.CastImportRow) bool {
+							lines = append(lines, c.Line)
+							return true
+						})
+						require.Equal(t, []int{1, 2}, lines)
+						return model.ImportResult{Created: 1, Errors: []model.ImportRowError{{Line: 2, Errors: domain.FieldErrors{
+							{Field: "film", Rule: "exists", Key: "validation.unknown_film"},
+						}}}}, err
+					})
+			},
+			expected:       model.ImportReport{Kind: model.ImportCast, Rows: 2, Created: 1, Failed: 1},
+			expectedFields: []string{"film"},
+		},
+		{
+			name: "Films From Export",
+			req:  model.ImportRequest{Kind: model.ImportFilms, Format: "csv"},
+			input: "id,name,description,release_date,rating,user_score,votes,genres,countries,tags\n" +
+				"1,Matrix,,1999-03-31,8.7,8.25,4,Action|science fiction,us,Cyberpunk\n" +
+				"2,Heat,,1995-12-15,8.3,0,0,,USA,\n",
+			mockBehavior: func(r *mock_repository.MockImport) {
+				r.EXPECT().ImportFilms(gomock.Any(), gomock.Any(), false).DoAndReturn(
+					func(_ context.Context, rows func(yield func(model.FilmImportRow) bool) error, _ bool) (model.ImportResult, error) {
+						var films []model.FilmImportRow
+						err := rows(func(f model.FilmImportRow) bool {
+							films = append(films, f)
+							return true
+						})
+						require.Len(t, films, 1)
+						require.Equal(t, 2, films[0].Line)
+						require.Equal(t, model.FilmLabels{
+							Genres: []string{"Action", "science fiction"}, Countries: []string{"US"}, Tags: []string{"cyberpunk"},
+						}, films[0].FilmLabels)
+						require.Zero(t, films[0].Id)
+						require.Zero(t, films[0].Votes)
+						return model.ImportResult{Created: 1}, err
+					})
+			},
+			expected:       model.ImportReport{Kind: model.ImportFilms, Rows: 2, Created: 1, Failed: 1},
+			expectedFields: []string{"countries[0]"},
+		},
+		{
+			name:         "Unknown Column",
+			req:          model.ImportRequest{Kind: model.ImportFilms, Format: "csv"},
+			input:        "title,rating\nMatrix,8.7\n",
+			mockBehavior: func(r *mock_repository.MockImport) {},
+			expectedKind: domain.ErrBadRequest,
+			expectedCode: "unknown_import_column",
+		},
+		{
+			name:         "Invalid Kind",
+			req:          model.ImportRequest{Kind: "users", Format: "csv"},
+			input:        "name\n",
+			mockBehavior: func(r *mock_repository.MockImport) {},
+			expectedKind: domain.ErrBadRequest,
+			expectedCode: "invalid_import_kind",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockImport(c)
+			test.mockBehavior(repo)
+
+			service := NewImportService(repo)
+
+			report, err := service.Import(context.Background(), test.req, strings.NewReader(test.input))
+			if test.expectedKind != nil {
+				require.ErrorIs(t, err, test.expectedKind)
+				var domainErr *domain.Error
+				require.True(t, errors.As(err, &domainErr))
+				require.Equal(t, test.expectedCode, domainErr.Code)
+				return
+			}
+
+			require.NoError(t, err)
+
+			var fields []string
+			for _, rowErr := range report.Errors {
+				for _, fe := range rowErr.Errors {
+					fields = append(fields, fe.Field)
+				}
+			}
+			require.Equal(t, test.expectedFields, fields)
+
+			report.Errors = nil
+			require.Equal(t, test.expected, report)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"film-library/internal/domain"
 	"film-library/internal/model"
 	"film-library/internal/repository"
 	"fmt"
@@ -17,63 +18,159 @@ func NewMovieService(repo repository.Movie) *MovieService {
 	return &MovieService{repo: repo}
 }
 
-func (s *MovieService) AddMovie(ctx context.Context, film model.Film) error {
-	exists, err := s.repo.MovieExistsByName(ctx, film.Name)
-	if err != nil {
-		return fmt.Errorf("Ошибка проверки фильма: %w", err)
+// AddMovie создаёт фильм и возвращает его с присвоенными id и версией
+func (s *MovieService) AddMovie(ctx context.Context, film model.Film) (model.Film, error) {
+	// Повтор названия ловит уникальный индекс: отдельная проверка заранее не защищает от гонки
+	if err := s.repo.CreateFilm(ctx, &film); err != nil {
+		return model.Film{}, filmError("ошибка создания фильма", err)
 	}
-	if !exists {
-		return errors.New("Фильм не найден")
+
+	return film, nil
+}
+
+// UpdateMovie перезаписывает фильм. film.Version - ожидаемая версия (0 - без проверки),
+// в ответе - новая
+func (s *MovieService) UpdateMovie(ctx context.Context, film model.Film) (model.Film, error) {
+	if err := s.repo.UpdateFilm(ctx, &film); err != nil {
+		return model.Film{}, filmError("ошибка изменения фильма", err)
 	}
-	return s.repo.CreateFilm(ctx, &film)
+
+	return film, nil
 }
 
-func (s *MovieService) UpdateMovie(ctx context.Context, film model.Film) error {
-	exists, err := s.repo.MovieExistsById(ctx, film.Id)
+// filmPatchDocument - фильм, к которому применяется патч. Метки в нём всегда есть,
+// хотя бы пустыми, чтобы JSON Patch мог дописывать в них значения ("/genres/-")
+type filmPatchDocument struct {
+	model.Film
+	Genres    []string `json:"genres"`
+	Countries []string `json:"countries"`
+	Tags      []string `json:"tags"`
+}
+
+// PatchMovie применяет патч к текущему фильму, проверяет результат целиком
+// и сохраняет только изменившиеся колонки и метки. Состав через PATCH не меняется.
+// version - ожидаемая версия (0 - без проверки)
+func (s *MovieService) PatchMovie(ctx context.Context, id, version int, patch model.Patch) (model.Film, error) {
+	current, err := s.repo.GetFilmByID(ctx, id)
 	if err != nil {
-		return fmt.Errorf("Ошибка проверки фильма: %w", err)
+		return model.Film{}, filmError("ошибка получения фильма", err)
 	}
-	if !exists {
-		return errors.New("Фильм не найден")
+	if version != 0 && current.Version != version {
+		return model.Film{}, domain.VersionMismatch()
 	}
 
-	return s.repo.UpdateFilm(ctx, &film)
-}
+	doc := filmPatchDocument{
+		Film:      current,
+		Genres:    nonNil(current.Genres),
+		Countries: nonNil(current.Countries),
+		Tags:      nonNil(current.Tags),
+	}
+	doc.ListActors = nil
+	if err := patch.Apply(&doc); err != nil {
+		return model.Film{}, err
+	}
+
+	film := doc.Film
+	if film.Id != id {
+		return model.Film{}, model.ReadOnlyField("id")
+	}
+	if film.ListActors != nil {
+		return model.Film{}, model.ReadOnlyField("list_actors")
+	}
+	if film.UserScore != current.UserScore || film.Votes != current.Votes {
+		return model.Film{}, model.ReadOnlyField("user_score")
+	}
 
-func (s *MovieService) DeleteMovie(ctx context.Context, id int) error {
-	exists, err := s.repo.MovieExistsById(ctx, id)
+	// Удалённый патчем список меток - то же, что пустой
+	film.FilmLabels = model.FilmLabels{
+		Genres:    nonNil(doc.Genres),
+		Countries: nonNil(doc.Countries),
+		Tags:      nonNil(doc.Tags),
+	}
+	if err := film.Validate(); err != nil {
+		return model.Film{}, err
+	}
+
+	film.ListActors = current.ListActors
+	film.Version = current.Version
+	changes := model.ChangedColumns(current.Columns(), film.Columns())
+	labels := model.ChangedLabels(current.FilmLabels, film.FilmLabels)
+	if len(changes) == 0 && labels.IsZero() {
+		film.FilmLabels = current.FilmLabels
+		return film, nil
+	}
+
+	// Версия, с которой читали, защищает от записи между чтением и обновлением
+	film.Version, err = s.repo.PatchFilm(ctx, id, current.Version, changes, &labels)
 	if err != nil {
-		return fmt.Errorf("Ошибка проверки фильма: %w", err)
+		return model.Film{}, filmError("ошибка изменения фильма", err)
+	}
+
+	// Жанры - в написании справочника, неизменённые метки - как были
+	film.FilmLabels = current.FilmLabels
+	if labels.Genres != nil {
+		film.Genres = labels.Genres
 	}
-	if !exists {
-		return errors.New("Фильм не найден")
+	if labels.Countries != nil {
+		film.Countries = labels.Countries
+	}
+	if labels.Tags != nil {
+		film.Tags = labels.Tags
 	}
 
-	return s.repo.DeleteFilm(ctx, id)
+	return film, nil
 }
 
-func (s *MovieService) GetFilms(ctx context.Context, sortBy string) ([]model.Film, error) {
-	data, err := s.repo.GetAllFilms(ctx, sortBy)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get actors: %w", err)
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
 	}
+	return values
+}
 
-	if len(data) == 0 {
-		return []model.Film{}, nil
+func (s *MovieService) DeleteMovie(ctx context.Context, id, version int) error {
+	if err := s.repo.DeleteFilm(ctx, id, version); err != nil {
+		return filmError("ошибка удаления фильма", err)
 	}
 
-	return data, nil
+	return nil
 }
 
-func (s *MovieService) SearchFilm(ctx context.Context, actor, film string) (model.Film, error) {
-	films, err := s.repo.SearchFilm(ctx, actor, film)
+func (s *MovieService) GetFilmByID(ctx context.Context, id int) (model.Film, error) {
+	film, err := s.repo.GetFilmByID(ctx, id)
 	if err != nil {
-		return model.Film{}, fmt.Errorf("ошибка поиска: %w", err)
+		return model.Film{}, filmError("ошибка получения фильма", err)
 	}
 
-	if len(films.ListActors) == 0 {
-		return model.Film{}, nil
+	return film, nil
+}
+
+func (s *MovieService) GetFilms(ctx context.Context, params model.FilmListParams) (model.FilmPage, error) {
+	page, err := s.repo.GetAllFilms(ctx, params)
+	if err != nil {
+		return model.FilmPage{}, fmt.Errorf("failed to get films: %w", err)
 	}
 
-	return films, nil
+	return page, nil
+}
+
+func (s *MovieService) SearchFilms(ctx context.Context, params model.FilmSearchParams) (model.FilmSearchPage, error) {
+	page, err := s.repo.SearchFilms(ctx, params)
+	if err != nil {
+		return model.FilmSearchPage{}, fmt.Errorf("ошибка поиска: %w", err)
+	}
+
+	return page, nil
+}
+
+// filmError уточняет код и сообщение ошибок хранилища для фильма
+func filmError(msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return domain.Wrap(err, domain.ErrNotFound, "film_not_found", "фильм не найден")
+	case errors.Is(err, domain.ErrConflict):
+		return domain.Wrap(err, domain.ErrConflict, "film_exists", "фильм с таким названием уже существует")
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
 }
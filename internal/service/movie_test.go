@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	mock_repository "film-library/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieService_PatchMovie(t *testing.T) {
+	released := time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC)
+	current := model.Film{
+		Id:          1,
+		Name:        "Matrix",
+		Description: "text",
+		Releasedate: released,
+		Rating:      8.5,
+		ListActors:  []model.CastMember{{Actor: model.Actor{Id: 7, Name: "Keanu"}}},
+		Version:     4,
+	}
+
+	type mockBehavior func(r *mock_repository.MockMovie)
+
+	tests := []struct {
+		name         string
+		version      int
+		patch        model.Patch
+		mockBehavior mockBehavior
+		expected     model.Film
+		expectedKind error
+		expectedCode string
+	}{
+		{
+			name:  "Merge Patch Writes Changed Columns",
+			patch: model.Patch{ContentType: "application/merge-patch+json", Body: []byte(`{"rating": 9, "description": null}`)},
+			mockBehavior: func(r *mock_repository.MockMovie) {
+				r.EXPECT().PatchFilm(gomock.Any(), 1, 4, map[string]any{"rating": float32(9), "description": ""}, &model.FilmLabels{}).Return(5, nil)
+			},
+			expected: model.Film{Id: 1, Name: "Matrix", Releasedate: released, Rating: 9, ListActors: current.ListActors, Version: 5},
+		},
+		{
+			name:    "Expected Version Matches",
+			version: 4,
+			patch:   model.Patch{ContentType: "application/merge-patch+json", Body: []byte(`{"name": "The Matrix"}`)},
+			mockBehavior: func(r *mock_repository.MockMovie) {
+				r.EXPECT().PatchFilm(gomock.Any(), 1, 4, map[string]any{"name": "The Matrix"}, &model.FilmLabels{}).Return(5, nil)
+			},
+			expected: model.Film{Id: 1, Name: "The Matrix", Description: "text", Releasedate: released, Rating: 8.5, ListActors: current.ListActors, Version: 5},
+		},
+		{
+			name:  "Genre Appended To Empty List",
+			patch: model.Patch{ContentType: "application/json-patch+json", Body: []byte(`[{"op": "add", "path": "/genres/-", "value": "science fiction"}]`)},
+			mockBehavior: func(r *mock_repository.MockMovie) {
+				r.EXPECT().PatchFilm(gomock.Any(), 1, 4, map[string]any{}, &model.FilmLabels{Genres: []string{"science fiction"}}).
+					DoAndReturn(func(_ context.Context, _, _ int, _ map[string]any, labels *model.FilmLabels) (int, error) {
+						labels.Genres[0] = "Science Fiction"
+						return 5, nil
+					})
+			},
+			expected: model.Film{
+				Id: 1, Name: "Matrix", Description: "text", Releasedate: released, Rating: 8.5, ListActors: current.ListActors,
+				FilmLabels: model.FilmLabels{Genres: []string{"Science Fiction"}}, Version: 5,
+			},
+		},
+		{
+			name:  "Tags Normalized",
+			patch: model.Patch{ContentType: "application/merge-patch+json", Body: []byte(`{"tags": ["  Cult  Classic", "cult classic", ""]}`)},
+			mockBehavior: func(r *mock_repository.MockMovie) {
+				r.EXPECT().PatchFilm(gomock.Any(), 1, 4, map[string]any{}, &model.FilmLabels{Tags: []string{"cult classic"}}).Return(5, nil)
+			},
+			expected: model.Film{
+				Id: 1, Name: "Matrix", Description: "text", Releasedate: released, Rating: 8.5, ListActors: current.ListActors,
+				FilmLabels: model.FilmLabels{Tags: []string{"cult classic"}}, Version: 5,
+			},
+		},
+		{
+			name:         "Invalid Country Code",
+			patch:        model.Patch{ContentType: "application/merge-patch+json", Body: []byte(`{"countries": ["USA"]}`)},
+			expectedKind: domain.ErrValidation,
+		},
+		{
+			name:         "Stale Version",
+			version:      3,
+			patch:        model.Patch{ContentType: "application/merge-patch+json", Body: []byte(`{"rating": 9}`)},
+			expectedKind: domain.ErrPrecondition,
+			expectedCode: "version_mismatch",
+		},
+		{
+			name:     "Nothing Changed",
+			patch:    model.Patch{ContentType: "application/json-patch+json", Body: []byte(`[{"op": "replace", "path": "/name", "value": "Matrix"}]`)},
+			expected: current,
+		},
+		{
+			name:         "Invalid Result",
+			patch:        model.Patch{ContentType: "application/json-patch+json", Body: []byte(`[{"op": "replace", "path": "/rating", "value": 11}]`)},
+			expectedKind: domain.ErrValidation,
+		},
+		{
+			name:         "Cast Is Read Only",
+			patch:        model.Patch{ContentType: "application/merge-patch+json", Body: []byte(`{"list_actors": []}`)},
+			expectedKind: domain.ErrValidation,
+			expectedCode: "read_only_field",
+		},
+		{
+			name:         "Unknown Field",
+			patch:        model.Patch{ContentType: "application/merge-patch+json", Body: []byte(`{"title": "Matrix"}`)},
+			expectedKind: domain.ErrBadRequest,
+			expectedCode: "invalid_patch",
+		},
+		{
+			name:         "Test Failed",
+			patch:        model.Patch{ContentType: "application/json-patch+json", Body: []byte(`[{"op": "test", "path": "/rating", "value": 7}]`)},
+			expectedKind: domain.ErrConflict,
+			expectedCode: "patch_conflict",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockMovie(c)
+			repo.EXPECT().GetFilmByID(gomock.Any(), 1).Return(current, nil)
+			if tc.mockBehavior != nil {
+				tc.mockBehavior(repo)
+			}
+
+			film, err := NewMovieService(repo).PatchMovie(context.Background(), 1, tc.version, tc.patch)
+			if tc.expectedKind != nil {
+				require.ErrorIs(t, err, tc.expectedKind)
+
+				if tc.expectedCode != "" {
+					var de *domain.Error
+					require.True(t, errors.As(err, &de))
+					require.Equal(t, tc.expectedCode, de.Code)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, film)
+		})
+	}
+}
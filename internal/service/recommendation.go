@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"film-library/internal/model"
+	"film-library/internal/repository"
+	"fmt"
+)
+
+type RecommendationService struct {
+	repo repository.Recommendation
+}
+
+func NewRecommendationService(repo repository.Recommendation) *RecommendationService {
+	return &RecommendationService{repo: repo}
+}
+
+func (s *RecommendationService) GetSimilarFilms(ctx context.Context, params model.SimilarParams) (model.SimilarPage, error) {
+	if err := params.Validate(); err != nil {
+		return model.SimilarPage{}, err
+	}
+
+	page, err := s.repo.GetSimilarFilms(ctx, params)
+	if err != nil {
+		return model.SimilarPage{}, filmError("ошибка получения похожих фильмов", err)
+	}
+
+	return page, nil
+}
+
+func (s *RecommendationService) GetRecommendations(ctx context.Context, params model.RecommendationParams) (model.RecommendationPage, error) {
+	if err := params.Validate(); err != nil {
+		return model.RecommendationPage{}, err
+	}
+
+	page, err := s.repo.GetRecommendations(ctx, params)
+	if err != nil {
+		return model.RecommendationPage{}, fmt.Errorf("ошибка получения рекомендаций: %w", err)
+	}
+
+	return page, nil
+}
+
+func (s *RecommendationService) RefreshRecommendations(ctx context.Context) error {
+	if err := s.repo.RefreshRecommendations(ctx); err != nil {
+		return fmt.Errorf("ошибка пересчёта рекомендаций: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	"film-library/internal/repository"
+	"fmt"
+)
+
+type ReviewService struct {
+	repo repository.Review
+}
+
+func NewReviewService(repo repository.Review) *ReviewService {
+	return &ReviewService{repo: repo}
+}
+
+func (s *ReviewService) GetRating(ctx context.Context, userID, filmID int) (model.FilmScore, error) {
+	score, err := s.repo.GetRating(ctx, userID, filmID)
+	if err != nil {
+		return model.FilmScore{}, filmError("ошибка получения оценки фильма", err)
+	}
+
+	return score, nil
+}
+
+func (s *ReviewService) SetRating(ctx context.Context, userID, filmID int, rating model.RatingRequest) (model.FilmScore, error) {
+	if err := rating.Validate(); err != nil {
+		return model.FilmScore{}, err
+	}
+
+	score, err := s.repo.SetRating(ctx, userID, filmID, rating.Score)
+	if err != nil {
+		return model.FilmScore{}, filmError("ошибка сохранения оценки фильма", err)
+	}
+
+	return score, nil
+}
+
+func (s *ReviewService) DeleteRating(ctx context.Context, userID, filmID int) (model.FilmScore, error) {
+	score, err := s.repo.DeleteRating(ctx, userID, filmID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return model.FilmScore{}, domain.Wrap(err, domain.ErrNotFound, "rating_not_found", "фильм или оценка не найдены")
+	}
+	if err != nil {
+		return model.FilmScore{}, fmt.Errorf("ошибка удаления оценки фильма: %w", err)
+	}
+
+	return score, nil
+}
+
+// GetFilmReviews - одобренные рецензии фильма
+func (s *ReviewService) GetFilmReviews(ctx context.Context, params model.ReviewListParams) (model.ReviewPage, error) {
+	params.Status = model.ReviewApproved
+	if err := params.Validate(); err != nil {
+		return model.ReviewPage{}, err
+	}
+
+	page, err := s.repo.GetReviews(ctx, params)
+	if err != nil {
+		return model.ReviewPage{}, filmError("ошибка получения рецензий", err)
+	}
+
+	return page, nil
+}
+
+// GetReviews - рецензии в любом статусе, для модерации
+func (s *ReviewService) GetReviews(ctx context.Context, params model.ReviewListParams) (model.ReviewPage, error) {
+	if err := params.Validate(); err != nil {
+		return model.ReviewPage{}, err
+	}
+
+	page, err := s.repo.GetReviews(ctx, params)
+	if err != nil {
+		return model.ReviewPage{}, filmError("ошибка получения рецензий", err)
+	}
+
+	return page, nil
+}
+
+func (s *ReviewService) GetUserReview(ctx context.Context, userID, filmID int) (model.Review, error) {
+	review, err := s.repo.GetUserReview(ctx, userID, filmID)
+	if err != nil {
+		return model.Review{}, reviewError("ошибка получения рецензии", err)
+	}
+
+	return review, nil
+}
+
+func (s *ReviewService) SaveReview(ctx context.Context, userID, filmID int, review model.ReviewRequest) (model.Review, error) {
+	if err := review.Validate(); err != nil {
+		return model.Review{}, err
+	}
+
+	saved, err := s.repo.SaveReview(ctx, userID, filmID, review.Body)
+	if err != nil {
+		return model.Review{}, filmError("ошибка сохранения рецензии", err)
+	}
+
+	return saved, nil
+}
+
+func (s *ReviewService) DeleteReview(ctx context.Context, userID, filmID int) error {
+	if err := s.repo.DeleteReview(ctx, userID, filmID); err != nil {
+		return reviewError("ошибка удаления рецензии", err)
+	}
+
+	return nil
+}
+
+// ModerateReview переводит рецензию в approved или hidden
+func (s *ReviewService) ModerateReview(ctx context.Context, id, moderatorID int, status string) (model.Review, error) {
+	if status != model.ReviewApproved && status != model.ReviewHidden {
+		return model.Review{}, domain.BadRequest("invalid_review_status", "статус рецензии должен быть pending, approved или hidden")
+	}
+
+	review, err := s.repo.ModerateReview(ctx, id, moderatorID, status)
+	if err != nil {
+		return model.Review{}, reviewError("ошибка модерации рецензии", err)
+	}
+
+	return review, nil
+}
+
+func reviewError(msg string, err error) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Wrap(err, domain.ErrNotFound, "review_not_found", "рецензия не найдена")
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
@@ -2,34 +2,147 @@ package service
 
 import (
 	"context"
+	"film-library/internal/config"
 	"film-library/internal/model"
 	"film-library/internal/repository"
+	"io"
+	"time"
 )
 
 //go:generate mockgen -source=service.go -destination=mocks/mock.go
 
 type Authorization interface {
-	CreateUser(ctx context.Context, user model.User) (string, error)
-	VerifyUser(ctx context.Context, username, password string) (string, *model.User, error)
+	CreateUser(ctx context.Context, user model.User) (model.TokenPair, error)
+	VerifyUser(ctx context.Context, username, password string) (model.TokenPair, *model.User, error)
+	RefreshTokens(ctx context.Context, refreshToken string) (model.TokenPair, error)
+	Logout(ctx context.Context, userID int, refreshToken, accessJTI string, accessExpiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	PurgeExpiredTokens(ctx context.Context) (model.TokenPurgeResult, error)
 	VerifyToken(tokenString string) (*model.TokenClaims, error)
 }
 
 type Actor interface {
 	AddActor(ctx context.Context, actor model.Actor) error
-	UpdateActor(ctx context.Context, actor model.Actor) error
-	DeleteActor(ctx context.Context, id int) error
+	UpdateActor(ctx context.Context, actor model.Actor) (model.Actor, error)
+	PatchActor(ctx context.Context, id, version int, patch model.Patch) (model.Actor, error)
+	DeleteActor(ctx context.Context, id, version int) error
+	GetActorByID(ctx context.Context, id int) (model.ActorWithFilms, error)
 }
 
 type Movie interface {
-	AddMovie(ctx context.Context, film model.Film) error
-	UpdateMovie(ctx context.Context, film model.Film) error
-	DeleteMovie(ctx context.Context, id int) error
-	GetFilms(ctx context.Context, sortBy string) ([]model.Film, error)
-	SearchFilm(ctx context.Context, actor, film string) (model.Film, error)
+	AddMovie(ctx context.Context, film model.Film) (model.Film, error)
+	UpdateMovie(ctx context.Context, film model.Film) (model.Film, error)
+	PatchMovie(ctx context.Context, id, version int, patch model.Patch) (model.Film, error)
+	DeleteMovie(ctx context.Context, id, version int) error
+	GetFilmByID(ctx context.Context, id int) (model.Film, error)
+	GetFilms(ctx context.Context, params model.FilmListParams) (model.FilmPage, error)
+	SearchFilms(ctx context.Context, params model.FilmSearchParams) (model.FilmSearchPage, error)
 }
 
 type ActorMovie interface {
-	GetAllActorWithFilms(ctx context.Context) (map[int]model.ActorWithFilms, error)
+	GetActors(ctx context.Context, params model.ActorListParams) (model.ActorPage, error)
+	GetActorFilms(ctx context.Context, actorID int) ([]model.Film, error)
+}
+
+type Casting interface {
+	GetCast(ctx context.Context, filmID int) ([]model.CastMember, error)
+	ReplaceCast(ctx context.Context, filmID int, entries []model.CastingEntry) error
+	AddCastMember(ctx context.Context, filmID int, entry model.CastingEntry) error
+	RemoveCastMember(ctx context.Context, filmID, actorID int) error
+}
+
+type Credit interface {
+	GetCredits(ctx context.Context, filmID int) ([]model.Credit, error)
+	ReplaceCredits(ctx context.Context, filmID int, credits []model.Credit) error
+	AddCredit(ctx context.Context, filmID int, credit model.Credit) error
+	RemoveCredit(ctx context.Context, filmID, personID int, role string) error
+	GetFilmography(ctx context.Context, personID int) (model.Filmography, error)
+}
+
+type Access interface {
+	HasPermission(ctx context.Context, role int, required model.Permission) (bool, error)
+	GetRoles(ctx context.Context) ([]model.Role, error)
+	AssignRole(ctx context.Context, userID int, role string) (model.UserResponse, error)
+}
+
+type Trash interface {
+	GetTrash(ctx context.Context) (model.Trash, error)
+	RestoreFilm(ctx context.Context, id int) (model.Film, error)
+	RestoreActor(ctx context.Context, id int) (model.ActorWithFilms, error)
+	PurgeTrash(ctx context.Context, retention time.Duration) (model.PurgeResult, error)
+}
+
+type Audit interface {
+	GetAuditLog(ctx context.Context, filter model.AuditFilter) (model.AuditPage, error)
+}
+
+type Import interface {
+	Import(ctx context.Context, req model.ImportRequest, r io.Reader) (model.ImportReport, error)
+}
+
+type Export interface {
+	Export(ctx context.Context, params model.ExportParams, w io.Writer) error
+}
+
+type Stats interface {
+	GetActorStats(ctx context.Context, params model.ActorStatsParams) (model.ActorStatsPage, error)
+	GetActorStatsByID(ctx context.Context, id int) (model.ActorStats, error)
+	GetYearStats(ctx context.Context, years model.YearRange) ([]model.YearStats, error)
+	GetRatingHistogram(ctx context.Context, years model.YearRange) ([]model.RatingBucket, error)
+	GetCostars(ctx context.Context, params model.CostarParams) (model.CostarPage, error)
+	RefreshStats(ctx context.Context) error
+}
+
+type Taxonomy interface {
+	GetGenres(ctx context.Context) ([]model.Genre, error)
+	GetGenre(ctx context.Context, id int) (model.Genre, error)
+	CreateGenre(ctx context.Context, genre model.Genre) (model.Genre, error)
+	UpdateGenre(ctx context.Context, genre model.Genre) (model.Genre, error)
+	DeleteGenre(ctx context.Context, id int) error
+	GetCountries(ctx context.Context) ([]model.Country, error)
+	GetCountry(ctx context.Context, code string) (model.Country, error)
+	CreateCountry(ctx context.Context, country model.Country) (model.Country, error)
+	UpdateCountry(ctx context.Context, country model.Country) (model.Country, error)
+	DeleteCountry(ctx context.Context, code string) error
+	GetTags(ctx context.Context, params model.TagListParams) (model.TagPage, error)
+	GetTag(ctx context.Context, id int) (model.Tag, error)
+	CreateTag(ctx context.Context, tag model.Tag) (model.Tag, error)
+	UpdateTag(ctx context.Context, tag model.Tag) (model.Tag, error)
+	DeleteTag(ctx context.Context, id int) error
+	GetFilmFacets(ctx context.Context, params model.FilmListParams) (model.FilmFacets, error)
+}
+
+type Review interface {
+	GetRating(ctx context.Context, userID, filmID int) (model.FilmScore, error)
+	SetRating(ctx context.Context, userID, filmID int, rating model.RatingRequest) (model.FilmScore, error)
+	DeleteRating(ctx context.Context, userID, filmID int) (model.FilmScore, error)
+	GetFilmReviews(ctx context.Context, params model.ReviewListParams) (model.ReviewPage, error)
+	GetReviews(ctx context.Context, params model.ReviewListParams) (model.ReviewPage, error)
+	GetUserReview(ctx context.Context, userID, filmID int) (model.Review, error)
+	SaveReview(ctx context.Context, userID, filmID int, review model.ReviewRequest) (model.Review, error)
+	DeleteReview(ctx context.Context, userID, filmID int) error
+	ModerateReview(ctx context.Context, id, moderatorID int, status string) (model.Review, error)
+}
+
+type Watchlist interface {
+	GetWatchlists(ctx context.Context, viewerID, ownerID int) ([]model.Watchlist, error)
+	GetWatchlist(ctx context.Context, id, viewerID int) (model.WatchlistWithFilms, error)
+	CreateWatchlist(ctx context.Context, list model.Watchlist) (model.Watchlist, error)
+	UpdateWatchlist(ctx context.Context, list model.Watchlist) (model.Watchlist, error)
+	DeleteWatchlist(ctx context.Context, id, userID int) error
+	AddWatchlistFilm(ctx context.Context, userID, id, filmID int, entry model.WatchlistEntry) error
+	RemoveWatchlistFilm(ctx context.Context, userID, id, filmID int) error
+	ReplaceWatchlistFilms(ctx context.Context, userID, id int, order model.WatchlistOrder) error
+	GetWatched(ctx context.Context, params model.WatchedListParams) (model.WatchedPage, error)
+	SetWatched(ctx context.Context, userID, filmID int, req model.WatchedRequest) (model.WatchedMark, error)
+	DeleteWatched(ctx context.Context, userID, filmID int) error
+	GetFeed(ctx context.Context, params model.FeedParams) (model.FeedPage, error)
+}
+
+type Recommendation interface {
+	GetSimilarFilms(ctx context.Context, params model.SimilarParams) (model.SimilarPage, error)
+	GetRecommendations(ctx context.Context, params model.RecommendationParams) (model.RecommendationPage, error)
+	RefreshRecommendations(ctx context.Context) error
 }
 
 type Service struct {
@@ -37,13 +150,37 @@ type Service struct {
 	Actor
 	Movie
 	ActorMovie
+	Casting
+	Credit
+	Access
+	Trash
+	Audit
+	Import
+	Export
+	Stats
+	Taxonomy
+	Review
+	Watchlist
+	Recommendation
 }
 
-func NewService(repos *repository.Repository, secret string) *Service {
+func NewService(repos *repository.Repository, secret string, authCfg config.Auth) *Service {
 	return &Service{
-		Authorization: NewAuthService(repos.Authorization, secret),
-		Actor:         NewActorService(repos.Actor),
-		Movie:         NewMovieService(repos.Movie),
-		ActorMovie:    NewActorMovieService(repos.ActorMovie),
+		Authorization:  NewAuthService(repos.Authorization, secret, authCfg),
+		Actor:          NewActorService(repos.Actor),
+		Movie:          NewMovieService(repos.Movie),
+		ActorMovie:     NewActorMovieService(repos.ActorMovie),
+		Casting:        NewCastingService(repos.Casting),
+		Credit:         NewCreditService(repos.Credit),
+		Access:         NewAccessService(repos.Access),
+		Trash:          NewTrashService(repos.Trash),
+		Audit:          NewAuditService(repos.Audit),
+		Import:         NewImportService(repos.Import),
+		Export:         NewExportService(repos.Export),
+		Stats:          NewStatsService(repos.Stats),
+		Taxonomy:       NewTaxonomyService(repos.Taxonomy),
+		Review:         NewReviewService(repos.Review),
+		Watchlist:      NewWatchlistService(repos.Watchlist),
+		Recommendation: NewRecommendationService(repos.Recommendation),
 	}
 }
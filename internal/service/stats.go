@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"film-library/internal/model"
+	"film-library/internal/repository"
+	"fmt"
+)
+
+type StatsService struct {
+	repo repository.Stats
+}
+
+func NewStatsService(repo repository.Stats) *StatsService {
+	return &StatsService{repo: repo}
+}
+
+func (s *StatsService) GetActorStats(ctx context.Context, params model.ActorStatsParams) (model.ActorStatsPage, error) {
+	page, err := s.repo.GetActorStats(ctx, params)
+	if err != nil {
+		return model.ActorStatsPage{}, fmt.Errorf("ошибка получения статистики актёров: %w", err)
+	}
+
+	return page, nil
+}
+
+func (s *StatsService) GetActorStatsByID(ctx context.Context, id int) (model.ActorStats, error) {
+	stats, err := s.repo.GetActorStatsByID(ctx, id)
+	if err != nil {
+		return model.ActorStats{}, actorError("ошибка получения статистики актёра", err)
+	}
+
+	return stats, nil
+}
+
+func (s *StatsService) GetYearStats(ctx context.Context, years model.YearRange) ([]model.YearStats, error) {
+	stats, err := s.repo.GetYearStats(ctx, years)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения статистики по годам: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (s *StatsService) GetRatingHistogram(ctx context.Context, years model.YearRange) ([]model.RatingBucket, error) {
+	buckets, err := s.repo.GetRatingHistogram(ctx, years)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения распределения рейтингов: %w", err)
+	}
+
+	return buckets, nil
+}
+
+func (s *StatsService) GetCostars(ctx context.Context, params model.CostarParams) (model.CostarPage, error) {
+	page, err := s.repo.GetCostars(ctx, params)
+	if err != nil {
+		return model.CostarPage{}, fmt.Errorf("ошибка получения пар актёров: %w", err)
+	}
+
+	return page, nil
+}
+
+func (s *StatsService) RefreshStats(ctx context.Context) error {
+	if err := s.repo.RefreshStats(ctx); err != nil {
+		return fmt.Errorf("ошибка обновления статистики: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	"film-library/internal/repository"
+	"fmt"
+)
+
+type TaxonomyService struct {
+	repo repository.Taxonomy
+}
+
+func NewTaxonomyService(repo repository.Taxonomy) *TaxonomyService {
+	return &TaxonomyService{repo: repo}
+}
+
+func (s *TaxonomyService) GetGenres(ctx context.Context) ([]model.Genre, error) {
+	genres, err := s.repo.GetGenres(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения жанров: %w", err)
+	}
+
+	return genres, nil
+}
+
+func (s *TaxonomyService) GetGenre(ctx context.Context, id int) (model.Genre, error) {
+	genre, err := s.repo.GetGenre(ctx, id)
+	if err != nil {
+		return model.Genre{}, genreError("ошибка получения жанра", err)
+	}
+
+	return genre, nil
+}
+
+func (s *TaxonomyService) CreateGenre(ctx context.Context, genre model.Genre) (model.Genre, error) {
+	if err := s.repo.CreateGenre(ctx, &genre); err != nil {
+		return model.Genre{}, genreError("ошибка создания жанра", err)
+	}
+
+	return genre, nil
+}
+
+func (s *TaxonomyService) UpdateGenre(ctx context.Context, genre model.Genre) (model.Genre, error) {
+	if err := s.repo.UpdateGenre(ctx, &genre); err != nil {
+		return model.Genre{}, genreError("ошибка изменения жанра", err)
+	}
+
+	return genre, nil
+}
+
+func (s *TaxonomyService) DeleteGenre(ctx context.Context, id int) error {
+	err := s.repo.DeleteGenre(ctx, id)
+	// Ссылки из фильмов не дают удалить запись справочника
+	if errors.Is(err, domain.ErrValidation) {
+		return domain.Wrap(err, domain.ErrConflict, "genre_in_use", "жанр назначен фильмам")
+	}
+	if err != nil {
+		return genreError("ошибка удаления жанра", err)
+	}
+
+	return nil
+}
+
+func (s *TaxonomyService) GetCountries(ctx context.Context) ([]model.Country, error) {
+	countries, err := s.repo.GetCountries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения стран: %w", err)
+	}
+
+	return countries, nil
+}
+
+func (s *TaxonomyService) GetCountry(ctx context.Context, code string) (model.Country, error) {
+	country, err := s.repo.GetCountry(ctx, code)
+	if err != nil {
+		return model.Country{}, countryError("ошибка получения страны", err)
+	}
+
+	return country, nil
+}
+
+func (s *TaxonomyService) CreateCountry(ctx context.Context, country model.Country) (model.Country, error) {
+	if err := s.repo.CreateCountry(ctx, &country); err != nil {
+		return model.Country{}, countryError("ошибка создания страны", err)
+	}
+
+	return country, nil
+}
+
+func (s *TaxonomyService) UpdateCountry(ctx context.Context, country model.Country) (model.Country, error) {
+	if err := s.repo.UpdateCountry(ctx, &country); err != nil {
+		return model.Country{}, countryError("ошибка изменения страны", err)
+	}
+
+	return country, nil
+}
+
+func (s *TaxonomyService) DeleteCountry(ctx context.Context, code string) error {
+	err := s.repo.DeleteCountry(ctx, code)
+	// Ссылки из фильмов не дают удалить запись справочника
+	if errors.Is(err, domain.ErrValidation) {
+		return domain.Wrap(err, domain.ErrConflict, "country_in_use", "страна указана у фильмов")
+	}
+	if err != nil {
+		return countryError("ошибка удаления страны", err)
+	}
+
+	return nil
+}
+
+func (s *TaxonomyService) GetTags(ctx context.Context, params model.TagListParams) (model.TagPage, error) {
+	page, err := s.repo.GetTags(ctx, params)
+	if err != nil {
+		return model.TagPage{}, fmt.Errorf("ошибка получения тегов: %w", err)
+	}
+
+	return page, nil
+}
+
+func (s *TaxonomyService) GetTag(ctx context.Context, id int) (model.Tag, error) {
+	tag, err := s.repo.GetTag(ctx, id)
+	if err != nil {
+		return model.Tag{}, tagError("ошибка получения тега", err)
+	}
+
+	return tag, nil
+}
+
+func (s *TaxonomyService) CreateTag(ctx context.Context, tag model.Tag) (model.Tag, error) {
+	if err := s.repo.CreateTag(ctx, &tag); err != nil {
+		return model.Tag{}, tagError("ошибка создания тега", err)
+	}
+
+	return tag, nil
+}
+
+func (s *TaxonomyService) UpdateTag(ctx context.Context, tag model.Tag) (model.Tag, error) {
+	if err := s.repo.UpdateTag(ctx, &tag); err != nil {
+		return model.Tag{}, tagError("ошибка изменения тега", err)
+	}
+
+	return tag, nil
+}
+
+func (s *TaxonomyService) DeleteTag(ctx context.Context, id int) error {
+	if err := s.repo.DeleteTag(ctx, id); err != nil {
+		return tagError("ошибка удаления тега", err)
+	}
+
+	return nil
+}
+
+func (s *TaxonomyService) GetFilmFacets(ctx context.Context, params model.FilmListParams) (model.FilmFacets, error) {
+	facets, err := s.repo.GetFilmFacets(ctx, params)
+	if err != nil {
+		return model.FilmFacets{}, fmt.Errorf("ошибка подсчёта фасетов: %w", err)
+	}
+
+	return facets, nil
+}
+
+// genreError уточняет код и сообщение ошибок хранилища для жанра
+func genreError(msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return domain.Wrap(err, domain.ErrNotFound, "genre_not_found", "жанр не найден")
+	case errors.Is(err, domain.ErrConflict):
+		return domain.Wrap(err, domain.ErrConflict, "genre_exists", "жанр с таким названием уже существует")
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
+
+// countryError уточняет код и сообщение ошибок хранилища для страны
+func countryError(msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return domain.Wrap(err, domain.ErrNotFound, "country_not_found", "страна не найдена")
+	case errors.Is(err, domain.ErrConflict):
+		return domain.Wrap(err, domain.ErrConflict, "country_exists", "страна с таким кодом или названием уже существует")
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
+
+// tagError уточняет код и сообщение ошибок хранилища для тега
+func tagError(msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return domain.Wrap(err, domain.ErrNotFound, "tag_not_found", "тег не найден")
+	case errors.Is(err, domain.ErrConflict):
+		return domain.Wrap(err, domain.ErrConflict, "tag_exists", "тег с таким названием уже существует")
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"film-library/internal/model"
+	"film-library/internal/repository"
+	"fmt"
+	"time"
+)
+
+type TrashService struct {
+	repo repository.Trash
+}
+
+func NewTrashService(repo repository.Trash) *TrashService {
+	return &TrashService{repo: repo}
+}
+
+func (s *TrashService) GetTrash(ctx context.Context) (model.Trash, error) {
+	trash, err := s.repo.GetTrash(ctx)
+	if err != nil {
+		return model.Trash{}, fmt.Errorf("ошибка получения корзины: %w", err)
+	}
+
+	return trash, nil
+}
+
+// RestoreFilm возвращает фильм из корзины. Фильма нет в корзине - ErrNotFound,
+// название занято другим фильмом - ErrConflict
+func (s *TrashService) RestoreFilm(ctx context.Context, id int) (model.Film, error) {
+	film, err := s.repo.RestoreFilm(ctx, id)
+	if err != nil {
+		return model.Film{}, filmError("ошибка восстановления фильма", err)
+	}
+
+	return film, nil
+}
+
+func (s *TrashService) RestoreActor(ctx context.Context, id int) (model.ActorWithFilms, error) {
+	actor, err := s.repo.RestoreActor(ctx, id)
+	if err != nil {
+		return model.ActorWithFilms{}, actorError("ошибка восстановления актёра", err)
+	}
+
+	return actor, nil
+}
+
+// PurgeTrash окончательно удаляет записи, пролежавшие в корзине дольше retention
+func (s *TrashService) PurgeTrash(ctx context.Context, retention time.Duration) (model.PurgeResult, error) {
+	result, err := s.repo.PurgeTrash(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return model.PurgeResult{}, fmt.Errorf("ошибка очистки корзины: %w", err)
+	}
+
+	return result, nil
+}
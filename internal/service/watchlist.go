@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/model"
+	"film-library/internal/repository"
+	"fmt"
+	"time"
+)
+
+type WatchlistService struct {
+	repo repository.Watchlist
+}
+
+func NewWatchlistService(repo repository.Watchlist) *WatchlistService {
+	return &WatchlistService{repo: repo}
+}
+
+// GetWatchlists - списки пользователя ownerID: все свои или только публичные чужие
+func (s *WatchlistService) GetWatchlists(ctx context.Context, viewerID, ownerID int) ([]model.Watchlist, error) {
+	lists, err := s.repo.GetWatchlists(ctx, ownerID, viewerID != ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения списков: %w", err)
+	}
+
+	return lists, nil
+}
+
+func (s *WatchlistService) GetWatchlist(ctx context.Context, id, viewerID int) (model.WatchlistWithFilms, error) {
+	list, err := s.repo.GetWatchlist(ctx, id, viewerID)
+	if err != nil {
+		return model.WatchlistWithFilms{}, watchlistError("ошибка получения списка", err)
+	}
+
+	return list, nil
+}
+
+func (s *WatchlistService) CreateWatchlist(ctx context.Context, list model.Watchlist) (model.Watchlist, error) {
+	if err := list.Validate(); err != nil {
+		return model.Watchlist{}, err
+	}
+
+	if err := s.repo.CreateWatchlist(ctx, &list); err != nil {
+		return model.Watchlist{}, watchlistError("ошибка создания списка", err)
+	}
+
+	return list, nil
+}
+
+func (s *WatchlistService) UpdateWatchlist(ctx context.Context, list model.Watchlist) (model.Watchlist, error) {
+	if err := list.Validate(); err != nil {
+		return model.Watchlist{}, err
+	}
+
+	if err := s.repo.UpdateWatchlist(ctx, &list); err != nil {
+		return model.Watchlist{}, watchlistError("ошибка изменения списка", err)
+	}
+
+	return list, nil
+}
+
+func (s *WatchlistService) DeleteWatchlist(ctx context.Context, id, userID int) error {
+	if err := s.repo.DeleteWatchlist(ctx, id, userID); err != nil {
+		return watchlistError("ошибка удаления списка", err)
+	}
+
+	return nil
+}
+
+func (s *WatchlistService) AddWatchlistFilm(ctx context.Context, userID, id, filmID int, entry model.WatchlistEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.repo.AddWatchlistFilm(ctx, userID, id, filmID, entry.Position); err != nil {
+		return watchlistError("ошибка добавления фильма в список", err)
+	}
+
+	return nil
+}
+
+func (s *WatchlistService) RemoveWatchlistFilm(ctx context.Context, userID, id, filmID int) error {
+	err := s.repo.RemoveWatchlistFilm(ctx, userID, id, filmID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Wrap(err, domain.ErrNotFound, "watchlist_film_not_found", "список или фильм в нём не найден")
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка удаления фильма из списка: %w", err)
+	}
+
+	return nil
+}
+
+func (s *WatchlistService) ReplaceWatchlistFilms(ctx context.Context, userID, id int, order model.WatchlistOrder) error {
+	if err := order.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.repo.ReplaceWatchlistFilms(ctx, userID, id, order.FilmIDs); err != nil {
+		return watchlistError("ошибка изменения порядка фильмов в списке", err)
+	}
+
+	return nil
+}
+
+func (s *WatchlistService) GetWatched(ctx context.Context, params model.WatchedListParams) (model.WatchedPage, error) {
+	if err := params.Validate(); err != nil {
+		return model.WatchedPage{}, err
+	}
+
+	page, err := s.repo.GetWatched(ctx, params)
+	if err != nil {
+		return model.WatchedPage{}, fmt.Errorf("ошибка получения просмотренных фильмов: %w", err)
+	}
+
+	return page, nil
+}
+
+// SetWatched отмечает фильм просмотренным в указанный день, по умолчанию сегодня
+func (s *WatchlistService) SetWatched(ctx context.Context, userID, filmID int, req model.WatchedRequest) (model.WatchedMark, error) {
+	watchedOn, err := req.Date(time.Now())
+	if err != nil {
+		return model.WatchedMark{}, err
+	}
+
+	if err := s.repo.SetWatched(ctx, userID, filmID, watchedOn); err != nil {
+		return model.WatchedMark{}, filmError("ошибка отметки о просмотре", err)
+	}
+
+	return model.WatchedMark{FilmID: filmID, WatchedOn: watchedOn}, nil
+}
+
+func (s *WatchlistService) DeleteWatched(ctx context.Context, userID, filmID int) error {
+	err := s.repo.DeleteWatched(ctx, userID, filmID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Wrap(err, domain.ErrNotFound, "watched_not_found", "фильм не отмечен просмотренным")
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка удаления отметки о просмотре: %w", err)
+	}
+
+	return nil
+}
+
+// GetFeed - лента пользователя; без начала периода - фильмы за последние FeedPeriod дней
+func (s *WatchlistService) GetFeed(ctx context.Context, params model.FeedParams) (model.FeedPage, error) {
+	if err := params.Validate(); err != nil {
+		return model.FeedPage{}, err
+	}
+
+	if params.ReleasedFrom.IsZero() {
+		params.ReleasedFrom = time.Now().AddDate(0, 0, -model.FeedPeriod)
+	}
+
+	page, err := s.repo.GetFeed(ctx, params)
+	if err != nil {
+		return model.FeedPage{}, fmt.Errorf("ошибка получения ленты: %w", err)
+	}
+
+	return page, nil
+}
+
+// watchlistError уточняет код для отсутствующего списка и повтора имени.
+// Ссылки на несуществующие фильмы хранилище уже отдаёт как ошибку валидации
+func watchlistError(msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return domain.Wrap(err, domain.ErrNotFound, "watchlist_not_found", "список не найден")
+	case errors.Is(err, domain.ErrConflict):
+		return domain.Wrap(err, domain.ErrConflict, "watchlist_exists", "список с таким именем уже есть")
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
@@ -1,12 +1,50 @@
 package authmid
 
-import "net/http"
-
-func IsAdmin(r *http.Request) bool {
-	roleVal := r.Context().Value("role")
-	if roleVal == nil {
-		return false
-	}
-	role, ok := roleVal.(int)
-	return ok && role == 1
+import (
+	"context"
+	"time"
+)
+
+// ctxKey - собственный тип ключей, чтобы не пересекаться с чужими значениями в контексте
+type ctxKey int
+
+const (
+	userIDKey ctxKey = iota
+	roleKey
+	tokenKey
+)
+
+// tokenInfo - идентификатор и срок действия access-токена, нужны для его отзыва
+type tokenInfo struct {
+	jti       string
+	expiresAt time.Time
+}
+
+// WithUser кладёт в контекст данные пользователя из токена
+func WithUser(ctx context.Context, userID, role int) context.Context {
+	ctx = context.WithValue(ctx, userIDKey, userID)
+	return context.WithValue(ctx, roleKey, role)
+}
+
+// UserID возвращает идентификатор аутентифицированного пользователя
+func UserID(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(userIDKey).(int)
+	return id, ok
+}
+
+// Role возвращает роль аутентифицированного пользователя
+func Role(ctx context.Context) (int, bool) {
+	role, ok := ctx.Value(roleKey).(int)
+	return role, ok
+}
+
+// WithToken кладёт в контекст идентификатор (jti) и срок действия access-токена
+func WithToken(ctx context.Context, jti string, expiresAt time.Time) context.Context {
+	return context.WithValue(ctx, tokenKey, tokenInfo{jti: jti, expiresAt: expiresAt})
+}
+
+// Token возвращает идентификатор и срок действия access-токена
+func Token(ctx context.Context) (string, time.Time, bool) {
+	info, ok := ctx.Value(tokenKey).(tokenInfo)
+	return info.jti, info.expiresAt, ok
 }
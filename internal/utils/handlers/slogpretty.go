@@ -48,7 +48,11 @@ func (h *PrettyHandler) Handle(_ context.Context, r slog.Record) error {
 		level = color.RedString(level)
 	}
 
-	fields := make(map[string]interface{}, r.NumAttrs())
+	fields := make(map[string]interface{}, r.NumAttrs()+len(h.attrs))
+
+	for _, a := range h.attrs {
+		fields[a.Key] = a.Value.Any()
+	}
 
 	r.Attrs(func(a slog.Attr) bool {
 		fields[a.Key] = a.Value.Any()
@@ -56,10 +60,6 @@ func (h *PrettyHandler) Handle(_ context.Context, r slog.Record) error {
 		return true
 	})
 
-	for _, a := range h.attrs {
-		fields[a.Key] = a.Value.Any()
-	}
-
 	var b []byte
 	var err error
 
@@ -83,11 +83,17 @@ func (h *PrettyHandler) Handle(_ context.Context, r slog.Record) error {
 	return nil
 }
 
+// WithAttrs добавляет атрибуты к уже накопленным, а не заменяет их:
+// иначе log.With(...).With(...) терял бы первые поля
 func (h *PrettyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	merged := make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	merged = append(merged, h.attrs...)
+	merged = append(merged, attrs...)
+
 	return &PrettyHandler{
-		Handler: h.Handler,
+		Handler: h.Handler.WithAttrs(attrs),
 		l:       h.l,
-		attrs:   attrs,
+		attrs:   merged,
 	}
 }
 
@@ -96,5 +102,6 @@ func (h *PrettyHandler) WithGroup(name string) slog.Handler {
 	return &PrettyHandler{
 		Handler: h.Handler.WithGroup(name),
 		l:       h.l,
+		attrs:   h.attrs,
 	}
 }
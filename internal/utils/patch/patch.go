@@ -0,0 +1,341 @@
+// Package patch применяет к JSON-документу JSON Merge Patch (RFC 7396)
+// и JSON Patch (RFC 6902). Документ и патч передаются как сырой JSON.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Типы содержимого PATCH-запроса
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+var (
+	// ErrInvalid - патч не разбирается: неверный JSON, операция или путь
+	ErrInvalid = errors.New("invalid patch")
+	// ErrConflict - патч корректен, но не подходит к документу: нет пути или не прошла операция test
+	ErrConflict = errors.New("patch conflicts with document")
+)
+
+// Apply применяет патч типа contentType к документу doc
+func Apply(contentType string, doc, patch []byte) ([]byte, error) {
+	switch contentType {
+	case MergePatchType:
+		return Merge(doc, patch)
+	case JSONPatchType:
+		return ApplyOps(doc, patch)
+	default:
+		return nil, fmt.Errorf("%w: unsupported type %q", ErrInvalid, contentType)
+	}
+}
+
+// Merge применяет JSON Merge Patch: объекты сливаются рекурсивно,
+// null удаляет ключ, остальные значения заменяются целиком
+func Merge(doc, patch []byte) ([]byte, error) {
+	var target any
+	if len(bytes.TrimSpace(doc)) > 0 {
+		if err := json.Unmarshal(doc, &target); err != nil {
+			return nil, fmt.Errorf("patch: decode document: %w", err)
+		}
+	}
+
+	var p any
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	return json.Marshal(mergeValue(target, p))
+}
+
+func mergeValue(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergeValue(t[k], v)
+	}
+
+	return t
+}
+
+// Operation - одна операция JSON Patch
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"` // nil - поле не передано, "null" - передан null
+}
+
+// ApplyOps применяет JSON Patch: операции выполняются по порядку,
+// при первой ошибке документ не меняется
+func ApplyOps(doc, patch []byte) ([]byte, error) {
+	var ops []Operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	var target any
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, fmt.Errorf("patch: decode document: %w", err)
+	}
+
+	for i, op := range ops {
+		var err error
+		if target, err = applyOp(target, op); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	return json.Marshal(target)
+}
+
+func applyOp(doc any, op Operation) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("%w: missing value", ErrInvalid)
+		}
+		var value any
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+
+		switch op.Op {
+		case "add":
+			return add(doc, path, value)
+		case "replace":
+			return replace(doc, path, value)
+		default:
+			current, err := get(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, fmt.Errorf("%w: test failed", ErrConflict)
+			}
+			return doc, nil
+		}
+
+	case "remove":
+		return remove(doc, path)
+
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+
+		if op.Op == "copy" {
+			// Копия не должна делить вложенные объекты с исходным значением
+			raw, _ := json.Marshal(value)
+			json.Unmarshal(raw, &value)
+			return add(doc, path, value)
+		}
+
+		if isPrefix(from, path) && len(from) < len(path) {
+			return nil, fmt.Errorf("%w: cannot move a value into its own child", ErrInvalid)
+		}
+		if doc, err = remove(doc, from); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+
+	default:
+		return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalid, op.Op)
+	}
+}
+
+// parsePointer разбирает JSON Pointer (RFC 6901) на токены; "" - весь документ
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		return nil, fmt.Errorf("%w: path %q must start with /", ErrInvalid, p)
+	}
+
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+	}
+	return tokens, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func get(doc any, path []string) (any, error) {
+	for _, token := range path {
+		var err error
+		if doc, err = child(doc, token); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+// child возвращает элемент объекта или массива по токену пути
+func child(node any, token string) (any, error) {
+	switch n := node.(type) {
+	case map[string]any:
+		v, ok := n[token]
+		if !ok {
+			return nil, fmt.Errorf("%w: key %q not found", ErrConflict, token)
+		}
+		return v, nil
+	case []any:
+		i, err := index(token, len(n)-1)
+		if err != nil {
+			return nil, err
+		}
+		return n[i], nil
+	default:
+		return nil, fmt.Errorf("%w: cannot descend into %q", ErrConflict, token)
+	}
+}
+
+// index разбирает индекс массива; допустимы значения от 0 до max
+func index(token string, max int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalid, token)
+	}
+	if i > max {
+		return 0, fmt.Errorf("%w: array index %d out of range", ErrConflict, i)
+	}
+	return i, nil
+}
+
+// update находит родителя последнего токена пути и заменяет его результатом fn
+func update(node any, path []string, fn func(parent any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(node, path[0])
+	}
+
+	c, err := child(node, path[0])
+	if err != nil {
+		return nil, err
+	}
+	c, err = update(c, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n := node.(type) {
+	case map[string]any:
+		n[path[0]] = c
+	case []any:
+		i, _ := strconv.Atoi(path[0])
+		n[i] = c
+	}
+	return node, nil
+}
+
+func add(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return update(doc, path, func(parent any, token string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			p[token] = value
+			return p, nil
+		case []any:
+			if token == "-" {
+				return append(p, value), nil
+			}
+			i, err := index(token, len(p))
+			if err != nil {
+				return nil, err
+			}
+			p = append(p, nil)
+			copy(p[i+1:], p[i:])
+			p[i] = value
+			return p, nil
+		default:
+			return nil, fmt.Errorf("%w: cannot add %q to a scalar", ErrConflict, token)
+		}
+	})
+}
+
+func remove(doc any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("%w: cannot remove the whole document", ErrInvalid)
+	}
+
+	return update(doc, path, func(parent any, token string) (any, error) {
+		if _, err := child(parent, token); err != nil {
+			return nil, err
+		}
+
+		switch p := parent.(type) {
+		case map[string]any:
+			delete(p, token)
+			return p, nil
+		default:
+			s := p.([]any)
+			i, _ := strconv.Atoi(token)
+			return append(s[:i], s[i+1:]...), nil
+		}
+	})
+}
+
+func replace(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return update(doc, path, func(parent any, token string) (any, error) {
+		if _, err := child(parent, token); err != nil {
+			return nil, err
+		}
+
+		switch p := parent.(type) {
+		case map[string]any:
+			p[token] = value
+			return p, nil
+		default:
+			s := p.([]any)
+			i, _ := strconv.Atoi(token)
+			s[i] = value
+			return s, nil
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		patch    string
+		expected string
+	}{
+		{
+			name:     "Replace And Add",
+			doc:      `{"name": "Matrix", "rating": 8}`,
+			patch:    `{"rating": 9.5, "description": "text"}`,
+			expected: `{"name": "Matrix", "rating": 9.5, "description": "text"}`,
+		},
+		{
+			name:     "Null Removes Key",
+			doc:      `{"name": "Matrix", "description": "text"}`,
+			patch:    `{"description": null}`,
+			expected: `{"name": "Matrix"}`,
+		},
+		{
+			name:     "Nested Objects",
+			doc:      `{"a": {"b": 1, "c": 2}}`,
+			patch:    `{"a": {"c": null, "d": 3}}`,
+			expected: `{"a": {"b": 1, "d": 3}}`,
+		},
+		{
+			name:     "Arrays Replaced Whole",
+			doc:      `{"tags": ["a", "b"]}`,
+			patch:    `{"tags": ["c"]}`,
+			expected: `{"tags": ["c"]}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := Merge([]byte(tc.doc), []byte(tc.patch))
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expected, string(out))
+		})
+	}
+
+	_, err := Merge([]byte(`{}`), []byte(`{`))
+	require.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestApplyOps(t *testing.T) {
+	tests := []struct {
+		name        string
+		doc         string
+		patch       string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "Replace",
+			doc:      `{"name": "Matrix", "rating": 8}`,
+			patch:    `[{"op": "replace", "path": "/rating", "value": 9}]`,
+			expected: `{"name": "Matrix", "rating": 9}`,
+		},
+		{
+			name:     "Add And Remove",
+			doc:      `{"name": "Matrix", "description": "text"}`,
+			patch:    `[{"op": "remove", "path": "/description"}, {"op": "add", "path": "/rating", "value": 7}]`,
+			expected: `{"name": "Matrix", "rating": 7}`,
+		},
+		{
+			name:     "Array Insert And Append",
+			doc:      `{"tags": ["a", "c"]}`,
+			patch:    `[{"op": "add", "path": "/tags/1", "value": "b"}, {"op": "add", "path": "/tags/-", "value": "d"}]`,
+			expected: `{"tags": ["a", "b", "c", "d"]}`,
+		},
+		{
+			name:     "Move And Copy",
+			doc:      `{"a": {"x": 1}, "b": {}}`,
+			patch:    `[{"op": "copy", "from": "/a/x", "path": "/b/y"}, {"op": "move", "from": "/a", "path": "/c"}]`,
+			expected: `{"b": {"y": 1}, "c": {"x": 1}}`,
+		},
+		{
+			name:     "Escaped Pointer",
+			doc:      `{"a/b": 1, "m~n": 2}`,
+			patch:    `[{"op": "replace", "path": "/a~1b", "value": 3}, {"op": "remove", "path": "/m~0n"}]`,
+			expected: `{"a/b": 3}`,
+		},
+		{
+			name:     "Test Passed",
+			doc:      `{"rating": 8}`,
+			patch:    `[{"op": "test", "path": "/rating", "value": 8}, {"op": "replace", "path": "/rating", "value": 9}]`,
+			expected: `{"rating": 9}`,
+		},
+		{
+			name:        "Test Failed",
+			doc:         `{"rating": 8}`,
+			patch:       `[{"op": "test", "path": "/rating", "value": 7}]`,
+			expectedErr: ErrConflict,
+		},
+		{
+			name:        "Replace Missing Key",
+			doc:         `{"name": "Matrix"}`,
+			patch:       `[{"op": "replace", "path": "/rating", "value": 9}]`,
+			expectedErr: ErrConflict,
+		},
+		{
+			name:        "Missing Value",
+			doc:         `{"name": "Matrix"}`,
+			patch:       `[{"op": "add", "path": "/rating"}]`,
+			expectedErr: ErrInvalid,
+		},
+		{
+			name:        "Unknown Operation",
+			doc:         `{"name": "Matrix"}`,
+			patch:       `[{"op": "rename", "path": "/name"}]`,
+			expectedErr: ErrInvalid,
+		},
+		{
+			name:        "Bad Pointer",
+			doc:         `{"name": "Matrix"}`,
+			patch:       `[{"op": "remove", "path": "name"}]`,
+			expectedErr: ErrInvalid,
+		},
+		{
+			name:        "Not An Array",
+			doc:         `{"name": "Matrix"}`,
+			patch:       `{"op": "remove", "path": "/name"}`,
+			expectedErr: ErrInvalid,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := ApplyOps([]byte(tc.doc), []byte(tc.patch))
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expected, string(out))
+		})
+	}
+}
@@ -0,0 +1,220 @@
+// Package records читает и пишет табличные данные построчно: CSV с заголовком
+// или JSON Lines (один объект на строку). Каждая запись - поле -> значение
+package records
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatJSON   = "json" // только для записи: массив объектов
+)
+
+// maxLineSize - предел длины одной строки JSON Lines
+const maxLineSize = 1 << 20
+
+// ListSeparator разделяет элементы списка в одной ячейке CSV;
+// массив строк JSON Lines читается в тот же вид
+const ListSeparator = "|"
+
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// Reader отдаёт записи по одной, не читая вход целиком
+type Reader interface {
+	// Next возвращает следующую запись; в конце данных - io.EOF.
+	// *RowError означает, что испорчена только эта запись и чтение можно продолжать
+	Next() (map[string]string, error)
+	// Line - номер строки входных данных, с которой начинается последняя запись
+	Line() int
+	// Columns - колонки из заголовка CSV; у JSON Lines заголовка нет - nil
+	Columns() []string
+}
+
+// RowError - запись не удалось разобрать
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// NewReader создаёт читатель формата format. Для CSV сразу читает заголовок
+func NewReader(format string, r io.Reader) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+		return &ndjsonReader{scanner: scanner}, nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// FormatOf определяет формат по Content-Type или расширению файла; "" - не удалось
+func FormatOf(contentTypeOrPath string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentTypeOrPath); err == nil {
+		switch mediaType {
+		case "text/csv":
+			return FormatCSV
+		case "application/x-ndjson", "application/jsonl", "application/json-lines":
+			return FormatNDJSON
+		}
+	}
+
+	switch strings.ToLower(filepath.Ext(contentTypeOrPath)) {
+	case ".csv":
+		return FormatCSV
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON
+	}
+	return ""
+}
+
+type csvReader struct {
+	r      *csv.Reader
+	header []string
+	line   int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("csv: missing header")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("csv header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+	}
+	cr.FieldsPerRecord = len(header)
+
+	return &csvReader{r: cr, header: header}, nil
+}
+
+func (c *csvReader) Columns() []string {
+	return c.header
+}
+
+func (c *csvReader) Next() (map[string]string, error) {
+	record, err := c.r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		c.line = parseErr.StartLine
+		return nil, &RowError{Line: parseErr.StartLine, Err: parseErr.Err}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.line, _ = c.r.FieldPos(0)
+
+	row := make(map[string]string, len(c.header))
+	for i, name := range c.header {
+		row[name] = strings.TrimSpace(record[i])
+	}
+	return row, nil
+}
+
+func (c *csvReader) Line() int {
+	return c.line
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (n *ndjsonReader) Next() (map[string]string, error) {
+	for n.scanner.Scan() {
+		n.line++
+		raw := bytes.TrimSpace(n.scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+
+		var object map[string]any
+		if err := dec.Decode(&object); err != nil {
+			return nil, &RowError{Line: n.line, Err: err}
+		}
+
+		row := make(map[string]string, len(object))
+		for name, value := range object {
+			s, err := scalar(value)
+			if err != nil {
+				return nil, &RowError{Line: n.line, Err: fmt.Errorf("field %q: %w", name, err)}
+			}
+			row[strings.ToLower(name)] = s
+		}
+		return row, nil
+	}
+
+	if err := n.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+func (n *ndjsonReader) Line() int {
+	return n.line
+}
+
+func (n *ndjsonReader) Columns() []string {
+	return nil
+}
+
+// scalar приводит значение JSON к строке. Массив строк склеивается через
+// ListSeparator, вложенные объекты и прочие массивы не поддерживаются
+func scalar(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return strings.TrimSpace(v), nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return fmt.Sprint(v), nil
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return "", errors.New("only arrays of strings are supported")
+			}
+			items[i] = strings.TrimSpace(s)
+		}
+		return strings.Join(items, ListSeparator), nil
+	default:
+		return "", errors.New("nested values are not supported")
+	}
+}
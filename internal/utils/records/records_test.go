@@ -0,0 +1,78 @@
+package records
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readAll(t *testing.T, r Reader) ([]map[string]string, []int) {
+	t.Helper()
+
+	var rows []map[string]string
+	var broken []int
+	for {
+		row, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return rows, broken
+		}
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			broken = append(broken, rowErr.Line)
+			continue
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	input := "\ufeffName, Rating\nMatrix, 8.7\n\"Multi\nline\",9\nbroken\nHeat,8.3\n"
+
+	r, err := NewReader(FormatCSV, strings.NewReader(input))
+	require.NoError(t, err)
+	require.Equal(t, []string{"name", "rating"}, r.Columns())
+
+	rows, broken := readAll(t, r)
+	require.Equal(t, []map[string]string{
+		{"name": "Matrix", "rating": "8.7"},
+		{"name": "Multi\nline", "rating": "9"},
+		{"name": "Heat", "rating": "8.3"},
+	}, rows)
+	require.Equal(t, []int{5}, broken)
+}
+
+func TestNDJSONReader(t *testing.T) {
+	input := `{"name": "Matrix", "rating": 8.7, "description": null}
+
+{"name": "Heat"
+{"name": "Alien", "tags": [1]}
+{"Name": "Heat", "rating": 8}
+{"name": "Aliens", "tags": ["scifi", " action "], "genres": []}
+{"name": "Ronin", "crew": {"director": "Frankenheimer"}}
+`
+
+	r, err := NewReader(FormatNDJSON, strings.NewReader(input))
+	require.NoError(t, err)
+	require.Nil(t, r.Columns())
+
+	rows, broken := readAll(t, r)
+	require.Equal(t, []map[string]string{
+		{"name": "Matrix", "rating": "8.7", "description": ""},
+		{"name": "Heat", "rating": "8"},
+		{"name": "Aliens", "tags": "scifi|action", "genres": ""},
+	}, rows)
+	require.Equal(t, []int{3, 4, 7}, broken)
+	require.Equal(t, 7, r.Line())
+}
+
+func TestFormatOf(t *testing.T) {
+	require.Equal(t, FormatCSV, FormatOf("text/csv; charset=utf-8"))
+	require.Equal(t, FormatNDJSON, FormatOf("application/x-ndjson"))
+	require.Equal(t, FormatNDJSON, FormatOf("films.jsonl"))
+	require.Equal(t, FormatCSV, FormatOf("/tmp/actors.CSV"))
+	require.Equal(t, "", FormatOf("application/json"))
+}
@@ -0,0 +1,177 @@
+package records
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Writer пишет записи по одной через буфер постоянного размера
+type Writer interface {
+	// Write добавляет запись; values идут в порядке колонок
+	Write(values []any) error
+	// Close дописывает хвост формата и сбрасывает буфер; нижний writer не закрывается
+	Close() error
+}
+
+// NewWriter создаёт writer формата format с колонками columns.
+// CSV начинается со строки заголовка, JSON - массив объектов
+func NewWriter(format string, w io.Writer, columns []string) (Writer, error) {
+	buf := bufio.NewWriter(w)
+
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(buf)
+		if err := cw.Write(columns); err != nil {
+			return nil, err
+		}
+		return &csvWriter{buf: buf, w: cw, record: make([]string, len(columns))}, nil
+	case FormatNDJSON:
+		return &jsonWriter{buf: buf, keys: jsonKeys(columns)}, nil
+	case FormatJSON:
+		return &jsonWriter{buf: buf, keys: jsonKeys(columns), array: true}, nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// ContentType - MIME-тип формата для ответа
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatJSON:
+		return "application/json"
+	}
+	return "application/octet-stream"
+}
+
+type csvWriter struct {
+	buf    *bufio.Writer
+	w      *csv.Writer
+	record []string
+}
+
+func (c *csvWriter) Write(values []any) error {
+	for i, v := range values {
+		c.record[i] = formatText(v)
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	if err := c.w.Error(); err != nil {
+		return err
+	}
+	return c.buf.Flush()
+}
+
+type jsonWriter struct {
+	buf   *bufio.Writer
+	keys  [][]byte // `"колонка":` для каждой колонки
+	array bool
+	rows  int
+}
+
+func (j *jsonWriter) Write(values []any) error {
+	switch {
+	case j.array && j.rows == 0:
+		j.buf.WriteString("[\n")
+	case j.array:
+		j.buf.WriteString(",\n")
+	}
+	j.rows++
+
+	j.buf.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			j.buf.WriteByte(',')
+		}
+		j.buf.Write(j.keys[i])
+
+		value, err := json.Marshal(jsonValue(v))
+		if err != nil {
+			return err
+		}
+		j.buf.Write(value)
+	}
+	j.buf.WriteByte('}')
+
+	if !j.array {
+		return j.buf.WriteByte('\n')
+	}
+	return nil
+}
+
+func (j *jsonWriter) Close() error {
+	if j.array {
+		if j.rows == 0 {
+			j.buf.WriteString("[]\n")
+		} else {
+			j.buf.WriteString("\n]\n")
+		}
+	}
+	return j.buf.Flush()
+}
+
+func jsonKeys(columns []string) [][]byte {
+	keys := make([][]byte, len(columns))
+	for i, column := range columns {
+		name, _ := json.Marshal(column)
+		keys[i] = append(name, ':')
+	}
+	return keys
+}
+
+// jsonValue приводит даты к тому же виду, что и в CSV
+func jsonValue(v any) any {
+	if t, ok := v.(time.Time); ok {
+		return formatTime(t)
+	}
+	return v
+}
+
+// formatText - значение ячейки CSV; nil - пустая ячейка, список склеивается через ListSeparator
+func formatText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []string:
+		return strings.Join(v, ListSeparator)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return formatTime(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formatTime - дата без времени пишется как YYYY-MM-DD, как её принимает импорт
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	if t.Equal(t.Truncate(24 * time.Hour)) {
+		return t.Format(time.DateOnly)
+	}
+	return t.Format(time.RFC3339)
+}
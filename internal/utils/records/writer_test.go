@@ -0,0 +1,71 @@
+package records
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	columns := []string{"id", "name", "release_date", "rating", "tags"}
+	rows := [][]any{
+		{1, "Matrix", time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC), float32(8.7), []string{"cyberpunk", "classic"}},
+		{2, "Heat, \"director's cut\"", time.Time{}, float32(0), []string{}},
+	}
+
+	tests := []struct {
+		name     string
+		format   string
+		rows     [][]any
+		expected string
+	}{
+		{
+			name:     "CSV",
+			format:   FormatCSV,
+			rows:     rows,
+			expected: "id,name,release_date,rating,tags\n1,Matrix,1999-03-31,8.7,cyberpunk|classic\n2,\"Heat, \"\"director's cut\"\"\",,0,\n",
+		},
+		{
+			name:     "NDJSON",
+			format:   FormatNDJSON,
+			rows:     rows,
+			expected: `{"id":1,"name":"Matrix","release_date":"1999-03-31","rating":8.7,"tags":["cyberpunk","classic"]}` + "\n" + `{"id":2,"name":"Heat, \"director's cut\"","release_date":"","rating":0,"tags":[]}` + "\n",
+		},
+		{
+			name:     "JSON",
+			format:   FormatJSON,
+			rows:     rows,
+			expected: "[\n" + `{"id":1,"name":"Matrix","release_date":"1999-03-31","rating":8.7,"tags":["cyberpunk","classic"]}` + ",\n" + `{"id":2,"name":"Heat, \"director's cut\"","release_date":"","rating":0,"tags":[]}` + "\n]\n",
+		},
+		{
+			name:     "Empty JSON",
+			format:   FormatJSON,
+			expected: "[]\n",
+		},
+		{
+			name:     "Empty CSV Keeps Header",
+			format:   FormatCSV,
+			expected: "id,name,release_date,rating,tags\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out strings.Builder
+
+			w, err := NewWriter(test.format, &out, columns)
+			require.NoError(t, err)
+			for _, row := range test.rows {
+				require.NoError(t, w.Write(row))
+			}
+			require.NoError(t, w.Close())
+
+			require.Equal(t, test.expected, out.String())
+		})
+	}
+
+	_, err := NewWriter("xml", &strings.Builder{}, columns)
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+}
@@ -0,0 +1,115 @@
+package response
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidIfMatch - в If-Match не ETag записи, выданный сервером
+var ErrInvalidIfMatch = errors.New("invalid If-Match")
+
+// WriteJSONCached отдаёт JSON с ETag, посчитанным по телу ответа.
+// Если клиент прислал совпадающий If-None-Match, отвечает 304 без тела
+func WriteJSONCached(w http.ResponseWriter, r *http.Request, v any) {
+	writeCached(w, r, v, bodyETag)
+}
+
+// WriteJSONVersioned - WriteJSONCached для записи с версией: ETag вида "<версия>-<хеш>".
+// По версии проверяется If-Match при изменении, по хешу - изменения вложенных данных
+// (состав фильма, фильмография), которые версию записи не меняют
+func WriteJSONVersioned(w http.ResponseWriter, r *http.Request, v any, version int) {
+	writeCached(w, r, v, func(body []byte) string { return VersionETag(version, body) })
+}
+
+// WriteJSONVersion отдаёт JSON со статусом status и ETag новой версии записи
+// после изменения, чтобы клиент мог сразу отправить следующий If-Match
+func WriteJSONVersion(w http.ResponseWriter, r *http.Request, status int, v any, version int) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		WriteError(w, r, err, "encode_response_failed")
+		return
+	}
+
+	w.Header().Set("ETag", VersionETag(version, body))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(body, '\n'))
+}
+
+// VersionETag - сильный ETag записи: версия и хеш представления
+func VersionETag(version int, body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + strconv.Itoa(version) + "-" + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// IfMatchVersion достаёт версию записи из заголовка If-Match.
+// "*" возвращает 0: подходит любая версия существующей записи.
+// Слабые ETag и списки из нескольких ETag не принимаются
+func IfMatchVersion(header string) (int, error) {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return 0, nil
+	}
+
+	tag, ok := strings.CutPrefix(header, `"`)
+	if !ok || !strings.HasSuffix(tag, `"`) || strings.Contains(header, ",") {
+		return 0, ErrInvalidIfMatch
+	}
+
+	versionPart, _, _ := strings.Cut(strings.TrimSuffix(tag, `"`), "-")
+	version, err := strconv.Atoi(versionPart)
+	if err != nil || version < 1 {
+		return 0, ErrInvalidIfMatch
+	}
+
+	return version, nil
+}
+
+func writeCached(w http.ResponseWriter, r *http.Request, v any, etagOf func(body []byte) string) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		WriteError(w, r, err, "encode_response_failed")
+		return
+	}
+
+	etag := etagOf(body)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+
+	if MatchETag(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(append(body, '\n'))
+}
+
+func bodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// MatchETag проверяет, есть ли etag в списке из заголовка If-None-Match.
+// Сравнение слабое: префикс W/ не учитывается
+func MatchETag(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,18 +1,132 @@
 package response
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"film-library/internal/domain"
+	"film-library/internal/i18n"
+	"film-library/internal/model"
+	"film-library/internal/utils/sl"
 	"net/http"
+	"strings"
 )
 
-type ErrorResponse struct {
-	Message string `json:"message"`
+// WriteJSONError отдаёт ошибку с кодом code. Сообщение берётся из каталога i18n
+// по тому же коду на языке запроса
+func WriteJSONError(w http.ResponseWriter, r *http.Request, code string, status int) {
+	writeError(w, status, code, i18n.T(r.Context(), code))
 }
 
-func WriteJSONError(w http.ResponseWriter, msg string, status int) {
+// WriteError - единая точка отображения ошибок сервисов в HTTP.
+// Доменные ошибки превращаются в 400/404/409/422/401/403 со своим кодом и сообщением,
+// всё остальное логируется и отдаётся как 500 с сообщением из каталога по ключу fallback
+func WriteError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	var fieldErrs domain.FieldErrors
+	if errors.As(err, &fieldErrs) {
+		WriteProblem(w, r, fieldErrs)
+		return
+	}
+
+	status := errorStatus(err)
+	if status == http.StatusInternalServerError {
+		msg, _ := i18n.Lookup(i18n.EN, fallback)
+		sl.FromContext(r.Context()).Error(msg, sl.Err(err))
+		writeError(w, status, statusCode(status), i18n.T(r.Context(), fallback))
+		return
+	}
+
+	code := statusCode(status)
+	msg := i18n.T(r.Context(), code)
+	var de *domain.Error
+	if errors.As(err, &de) {
+		if de.Code != "" {
+			code = de.Code
+		}
+		// Для кода без перевода отдаём исходное сообщение доменной ошибки
+		if translated, ok := i18n.Lookup(i18n.Locale(r.Context()), code, de.Args...); ok {
+			msg = translated
+		} else {
+			msg = de.Message
+		}
+	}
+
+	writeError(w, status, code, msg)
+}
+
+// ProblemTypeValidation - тип проблемы для ошибок валидации (RFC 7807, поле type)
+const ProblemTypeValidation = "/problems/validation-failed"
+
+// WriteProblem отдаёт 422 application/problem+json со списком неверных полей
+func WriteProblem(w http.ResponseWriter, r *http.Request, errs domain.FieldErrors) {
+	ctx := r.Context()
+	localized := LocalizeFieldErrors(ctx, errs)
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(model.Problem{
+		Type:     ProblemTypeValidation,
+		Title:    i18n.T(ctx, "validation.title"),
+		Status:   http.StatusUnprocessableEntity,
+		Detail:   i18n.T(ctx, "validation.detail"),
+		Instance: r.URL.Path,
+		Code:     "validation_failed",
+		Errors:   localized,
+	})
+}
+
+// LocalizeFieldErrors переводит сообщения об ошибках полей на язык запроса
+func LocalizeFieldErrors(ctx context.Context, errs domain.FieldErrors) domain.FieldErrors {
+	localized := make(domain.FieldErrors, len(errs))
+	for i, fe := range errs {
+		if fe.Key != "" {
+			if msg, ok := i18n.Lookup(i18n.Locale(ctx), fe.Key, fe.Args...); ok {
+				fe.Message = msg
+			}
+		}
+		localized[i] = fe
+	}
+	return localized
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrBadRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrPrecondition):
+		return http.StatusPreconditionFailed
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// statusCode - код по умолчанию для статуса: "Not Found" -> "not_found"
+func statusCode(status int) string {
+	switch status {
+	case http.StatusUnprocessableEntity:
+		return "validation_failed"
+	case http.StatusInternalServerError:
+		return "internal_error"
+	}
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+func writeError(w http.ResponseWriter, status int, code, msg string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(ErrorResponse{
+	json.NewEncoder(w).Encode(model.ErrorResponse{
+		Status:  status,
 		Message: msg,
+		Code:    code,
 	})
 }
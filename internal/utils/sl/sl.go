@@ -1,10 +1,29 @@
 package sl
 
-import "log/slog"
+import (
+	"context"
+	"log/slog"
+)
 
-func Err(err error) *slog.Attr {
-	return &slog.Attr{
+type ctxKey struct{}
+
+func Err(err error) slog.Attr {
+	return slog.Attr{
 		Key:   "error",
 		Value: slog.StringValue(err.Error()),
 	}
 }
+
+// WithLogger кладёт в контекст логгер текущего запроса
+func WithLogger(ctx context.Context, log *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, log)
+}
+
+// FromContext возвращает логгер запроса (с request_id и т.п.),
+// а вне запроса - логгер по умолчанию
+func FromContext(ctx context.Context) *slog.Logger {
+	if log, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return log
+	}
+	return slog.Default()
+}
@@ -0,0 +1,251 @@
+// Package validate проверяет DTO запросов по тегам `validate:"..."`.
+// В отличие от ручных проверок собирает все нарушения разом, а не только первое.
+//
+// Правила:
+//
+//	required      - значение не пустое
+//	omitempty     - пустое значение остальные правила не проверяют
+//	min=N, max=N  - длина строки в символах, размер среза или значение числа
+//	oneof=a b     - одно из перечисленных значений
+//	past          - дата не в будущем
+//	min_age=N     - с даты прошло не меньше N лет
+//
+// Вложенные структуры и срезы структур проверяются рекурсивно, встроенные
+// структуры - как часть внешней. Путь поля строится по json-тегам: "list_actors[0].name".
+package validate
+
+import (
+	"film-library/internal/domain"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Struct проверяет структуру (или срез структур) и возвращает domain.FieldErrors
+// со всеми нарушениями либо nil
+func Struct(v any) error {
+	var errs domain.FieldErrors
+	walk(reflect.ValueOf(v), "", &errs)
+	return errs.Err()
+}
+
+type rule struct {
+	name   string
+	params []string
+}
+
+type field struct {
+	index    int
+	name     string
+	embedded bool
+	rules    []rule
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	cache    sync.Map // reflect.Type -> []field
+)
+
+func walk(v reflect.Value, path string, errs *domain.FieldErrors) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return
+		}
+		for _, f := range fields(v.Type()) {
+			fv := v.Field(f.index)
+			if f.embedded {
+				walk(fv, path, errs)
+				continue
+			}
+
+			fpath := join(path, f.name)
+			if !check(fv, fpath, f.rules, errs) {
+				continue
+			}
+			walk(fv, fpath, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walk(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+// check применяет правила к полю. false - поле пустое,
+// внутрь него спускаться не нужно
+func check(v reflect.Value, path string, rules []rule, errs *domain.FieldErrors) bool {
+	for _, r := range rules {
+		switch r.name {
+		case "omitempty":
+			if v.IsZero() {
+				return false
+			}
+		case "required":
+			if v.IsZero() {
+				*errs = append(*errs, domain.FieldError{
+					Field: path, Rule: r.name, Message: "обязательное поле", Key: "validation.required",
+				})
+				return false
+			}
+		default:
+			if fe, ok := apply(deref(v), r); !ok {
+				fe.Field, fe.Rule, fe.Params = path, r.name, r.params
+				*errs = append(*errs, fe)
+			}
+		}
+	}
+	return true
+}
+
+// apply проверяет одно правило. Сообщение заполняется на русском,
+// на язык клиента его переводят по Key при отправке ответа
+func apply(v reflect.Value, r rule) (domain.FieldError, bool) {
+	if !v.IsValid() {
+		return domain.FieldError{}, true
+	}
+
+	switch r.name {
+	case "min", "max":
+		limit, err := strconv.ParseFloat(r.params[0], 64)
+		if err != nil {
+			panic(fmt.Sprintf("validate: bad %s param %q", r.name, r.params[0]))
+		}
+		size, unit := measure(v)
+		if r.name == "min" && size < limit {
+			return failed("validation.min."+unit, "не меньше %s"+units[unit], r.params[0]), false
+		}
+		if r.name == "max" && size > limit {
+			return failed("validation.max."+unit, "не больше %s"+units[unit], r.params[0]), false
+		}
+	case "oneof":
+		s := fmt.Sprint(v.Interface())
+		for _, p := range r.params {
+			if s == p {
+				return domain.FieldError{}, true
+			}
+		}
+		return failed("validation.oneof", "допустимые значения: %s", strings.Join(r.params, ", ")), false
+	case "past":
+		if t, ok := v.Interface().(time.Time); ok && t.After(time.Now()) {
+			return failed("validation.past", "дата не может быть в будущем"), false
+		}
+	case "min_age":
+		years, err := strconv.Atoi(r.params[0])
+		if err != nil {
+			panic(fmt.Sprintf("validate: bad min_age param %q", r.params[0]))
+		}
+		if t, ok := v.Interface().(time.Time); ok && !t.IsZero() && t.AddDate(years, 0, 0).After(time.Now()) {
+			return failed("validation.min_age", "с даты должно пройти не меньше %s лет", r.params[0]), false
+		}
+	default:
+		panic("validate: unknown rule " + r.name)
+	}
+	return domain.FieldError{}, true
+}
+
+func failed(key, format string, args ...any) domain.FieldError {
+	return domain.FieldError{Message: fmt.Sprintf(format, args...), Key: key, Args: args}
+}
+
+// units - окончания сообщений min и max для разных типов значений
+var units = map[string]string{
+	"string": " символов",
+	"items":  " элементов",
+	"number": "",
+}
+
+// measure - то, с чем сравнивают min и max, и вид значения для ключа сообщения
+func measure(v reflect.Value) (float64, string) {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), "string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), "items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), "number"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), "number"
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), "number"
+	}
+	panic("validate: min/max on " + v.Kind().String())
+}
+
+func deref(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// fields разбирает теги типа один раз и кэширует результат
+func fields(t reflect.Type) []field {
+	if cached, ok := cache.Load(t); ok {
+		return cached.([]field)
+	}
+
+	var list []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if sf.Anonymous && name == "" {
+			list = append(list, field{index: i, embedded: true})
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		list = append(list, field{index: i, name: name, rules: parse(sf.Tag.Get("validate"))})
+	}
+
+	cache.Store(t, list)
+	return list
+}
+
+// parse: "required,min=3,oneof=male female" -> [{required} {min [3]} {oneof [male female]}]
+func parse(tag string) []rule {
+	if tag == "" {
+		return nil
+	}
+
+	var rules []rule
+	for _, part := range strings.Split(tag, ",") {
+		name, param, ok := strings.Cut(part, "=")
+		r := rule{name: name}
+		if ok {
+			r.params = strings.Fields(param)
+		}
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
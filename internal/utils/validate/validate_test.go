@@ -0,0 +1,85 @@
+package validate
+
+import (
+	"errors"
+	"film-library/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type Person struct {
+	Name      string    `json:"name" validate:"required,max=5"`
+	Gender    string    `json:"gender" validate:"omitempty,oneof=male female"`
+	BirthDate time.Time `json:"birth_date" validate:"past,min_age=5"`
+}
+
+type role struct {
+	Person
+	Character string `json:"character" validate:"max=3"`
+}
+
+type movie struct {
+	Title  string  `json:"title" validate:"required"`
+	Rating float32 `json:"rating" validate:"min=0,max=10"`
+	Cast   []role  `json:"cast"`
+	Lead   *Person `json:"lead"`
+	Secret string  `json:"-" validate:"required"`
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected domain.FieldErrors
+	}{
+		{
+			name:  "Ok",
+			input: movie{Title: "Matrix", Rating: 8.7, Cast: []role{{Person: Person{Name: "Neo", Gender: "male"}}}},
+		},
+		{
+			name: "All Errors At Once",
+			input: &movie{
+				Rating: 11,
+				Cast: []role{
+					{Person: Person{Name: "Trinity"}, Character: "hero"},
+					{Person: Person{Name: "Smith", Gender: "robot", BirthDate: time.Now().AddDate(1, 0, 0)}},
+				},
+				Lead: &Person{},
+			},
+			expected: domain.FieldErrors{
+				{Field: "title", Rule: "required", Message: "обязательное поле", Key: "validation.required"},
+				{Field: "rating", Rule: "max", Params: []string{"10"}, Message: "не больше 10", Key: "validation.max.number", Args: []any{"10"}},
+				{Field: "cast[0].name", Rule: "max", Params: []string{"5"}, Message: "не больше 5 символов", Key: "validation.max.string", Args: []any{"5"}},
+				{Field: "cast[0].character", Rule: "max", Params: []string{"3"}, Message: "не больше 3 символов", Key: "validation.max.string", Args: []any{"3"}},
+				{Field: "cast[1].gender", Rule: "oneof", Params: []string{"male", "female"}, Message: "допустимые значения: male, female", Key: "validation.oneof", Args: []any{"male, female"}},
+				{Field: "cast[1].birth_date", Rule: "past", Message: "дата не может быть в будущем", Key: "validation.past"},
+				{Field: "cast[1].birth_date", Rule: "min_age", Params: []string{"5"}, Message: "с даты должно пройти не меньше 5 лет", Key: "validation.min_age", Args: []any{"5"}},
+				{Field: "lead.name", Rule: "required", Message: "обязательное поле", Key: "validation.required"},
+			},
+		},
+		{
+			name:  "Slice Of Structs",
+			input: []Person{{Name: "Neo"}, {Name: ""}},
+			expected: domain.FieldErrors{
+				{Field: "[1].name", Rule: "required", Message: "обязательное поле", Key: "validation.required"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Struct(tc.input)
+			if tc.expected == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var errs domain.FieldErrors
+			require.True(t, errors.As(err, &errs))
+			require.True(t, errors.Is(err, domain.ErrValidation))
+			require.Equal(t, tc.expected, errs)
+		})
+	}
+}
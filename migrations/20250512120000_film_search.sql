@@ -0,0 +1,41 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Поисковые векторы фильмов: название важнее описания.
+-- Каталог двуязычный, поэтому держим отдельные векторы для английской и русской морфологии
+ALTER TABLE films ADD COLUMN IF NOT EXISTS search_en tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', name), 'A') ||
+        setweight(to_tsvector('english', description), 'B')
+    ) STORED;
+
+ALTER TABLE films ADD COLUMN IF NOT EXISTS search_ru tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', name), 'A') ||
+        setweight(to_tsvector('russian', description), 'B')
+    ) STORED;
+
+-- Имена не склоняем и не стеммим
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', name)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_film_search_en ON films USING GIN (search_en);
+CREATE INDEX IF NOT EXISTS idx_film_search_ru ON films USING GIN (search_ru);
+CREATE INDEX IF NOT EXISTS idx_actor_search ON actors USING GIN (search_vector);
+
+-- Триграммы для поиска с опечатками
+CREATE INDEX IF NOT EXISTS idx_film_name_trgm ON films USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_actor_name_trgm ON actors USING GIN (name gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_actor_name_trgm;
+DROP INDEX IF EXISTS idx_film_name_trgm;
+DROP INDEX IF EXISTS idx_actor_search;
+DROP INDEX IF EXISTS idx_film_search_ru;
+DROP INDEX IF EXISTS idx_film_search_en;
+
+ALTER TABLE actors DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE films DROP COLUMN IF EXISTS search_ru;
+ALTER TABLE films DROP COLUMN IF EXISTS search_en;
+
+DROP EXTENSION IF EXISTS pg_trgm;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Роль актёра в фильме и порядок в титрах. NULL - не указано
+ALTER TABLE actor_film ADD COLUMN IF NOT EXISTS character_name VARCHAR(255);
+ALTER TABLE actor_film ADD COLUMN IF NOT EXISTS billing_order INT CHECK (billing_order > 0);
+
+CREATE INDEX IF NOT EXISTS idx_actor_film_actor ON actor_film (actor_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_actor_film_actor;
+
+ALTER TABLE actor_film DROP COLUMN IF EXISTS billing_order;
+ALTER TABLE actor_film DROP COLUMN IF EXISTS character_name;
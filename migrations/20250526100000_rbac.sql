@@ -0,0 +1,40 @@
+-- +goose Up
+-- Редактор управляет каталогом, но не пользователями
+INSERT INTO roles (name) VALUES ('editor')
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id SERIAL PRIMARY KEY,
+    resource VARCHAR(50) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    UNIQUE (resource, action)
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id INT REFERENCES roles(id) ON DELETE CASCADE,
+    permission_id INT REFERENCES permissions(id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+INSERT INTO permissions (resource, action)
+SELECT r.resource, a.action
+FROM (VALUES ('films'), ('actors'), ('users')) AS r(resource)
+CROSS JOIN (VALUES ('read'), ('create'), ('update'), ('delete')) AS a(action)
+ON CONFLICT (resource, action) DO NOTHING;
+
+-- Матрица прав: пользователь читает каталог, редактор его меняет,
+-- администратор вдобавок управляет пользователями
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT ro.id, p.id
+FROM roles ro
+JOIN permissions p ON
+    (ro.name = 'user' AND p.resource IN ('films', 'actors') AND p.action = 'read')
+    OR (ro.name = 'editor' AND p.resource IN ('films', 'actors'))
+    OR ro.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+
+DELETE FROM roles WHERE name = 'editor';
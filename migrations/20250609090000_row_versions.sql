@@ -0,0 +1,9 @@
+-- +goose Up
+-- Версия строки для оптимистической блокировки: растёт при каждом изменении,
+-- клиент передаёт её в If-Match, и UPDATE/DELETE проверяют её в WHERE
+ALTER TABLE films ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE actors DROP COLUMN IF EXISTS version;
+ALTER TABLE films DROP COLUMN IF EXISTS version;
@@ -0,0 +1,44 @@
+-- +goose Up
+-- Мягкое удаление: строка остаётся в таблице с отметкой deleted_at вместе со связями
+-- actor_film, пока её не восстановят или не сотрёт задача очистки корзины
+ALTER TABLE films ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- Имя должно быть уникальным только среди неудалённых записей
+ALTER TABLE films DROP CONSTRAINT IF EXISTS films_name_key;
+ALTER TABLE actors DROP CONSTRAINT IF EXISTS actors_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_films_name_alive ON films (name) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_actors_name_alive ON actors (name) WHERE deleted_at IS NULL;
+
+-- Корзина и её очистка выбирают только удалённые строки
+CREATE INDEX IF NOT EXISTS idx_films_deleted_at ON films (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_actors_deleted_at ON actors (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Корзина доступна только администратору
+INSERT INTO permissions (resource, action)
+VALUES ('trash', 'read'), ('trash', 'update')
+ON CONFLICT (resource, action) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT ro.id, p.id
+FROM roles ro
+JOIN permissions p ON p.resource = 'trash'
+WHERE ro.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM permissions WHERE resource = 'trash';
+
+DROP INDEX IF EXISTS idx_actors_deleted_at;
+DROP INDEX IF EXISTS idx_films_deleted_at;
+DROP INDEX IF EXISTS uq_actors_name_alive;
+DROP INDEX IF EXISTS uq_films_name_alive;
+
+-- Удалённые записи не пережили бы возврат уникальности имён
+DELETE FROM films WHERE deleted_at IS NOT NULL;
+DELETE FROM actors WHERE deleted_at IS NOT NULL;
+ALTER TABLE actors ADD CONSTRAINT actors_name_key UNIQUE (name);
+ALTER TABLE films ADD CONSTRAINT films_name_key UNIQUE (name);
+
+ALTER TABLE actors DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE films DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,33 @@
+-- +goose Up
+-- Журнал изменений каталога: пишется в той же транзакции, что и само изменение
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT REFERENCES users(id) ON DELETE SET NULL, -- NULL - фоновая задача
+    action TEXT NOT NULL,                                -- create, update, delete, restore, purge
+    entity TEXT NOT NULL,                                -- film, actor
+    entity_id INT NOT NULL,
+    before JSONB,                                        -- изменившиеся поля до изменения
+    after JSONB,                                         -- и после него
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log (entity, entity_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_user ON audit_log (user_id, created_at DESC);
+
+-- Журнал читает только администратор
+INSERT INTO permissions (resource, action)
+VALUES ('audit', 'read')
+ON CONFLICT (resource, action) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT ro.id, p.id
+FROM roles ro
+JOIN permissions p ON p.resource = 'audit'
+WHERE ro.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM permissions WHERE resource = 'audit';
+
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,14 @@
+-- +goose Up
+INSERT INTO permissions (resource, action)
+VALUES ('import', 'create')
+ON CONFLICT (resource, action) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT ro.id, p.id
+FROM roles ro
+JOIN permissions p ON p.resource = 'import'
+WHERE ro.name IN ('editor', 'admin')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM permissions WHERE resource = 'import';
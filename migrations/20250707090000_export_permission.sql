@@ -0,0 +1,16 @@
+-- +goose Up
+INSERT INTO permissions (resource, action)
+VALUES ('export', 'read')
+ON CONFLICT (resource, action) DO NOTHING;
+
+-- Выгружается то же, что и так доступно на чтение, поэтому право есть у всех ролей;
+-- отдельный ресурс позволяет отключить тяжёлые выгрузки, не трогая чтение каталога
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT ro.id, p.id
+FROM roles ro
+JOIN permissions p ON p.resource = 'export'
+WHERE ro.name IN ('user', 'editor', 'admin')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM permissions WHERE resource = 'export';
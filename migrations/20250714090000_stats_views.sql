@@ -0,0 +1,106 @@
+-- +goose Up
+-- Статистика каталога считается заранее в материализованных представлениях.
+-- Учитываются только живые фильмы и актёры; уникальные индексы нужны для
+-- REFRESH MATERIALIZED VIEW CONCURRENTLY, который не блокирует чтение
+
+-- Агрегаты по актёру; актёры без фильмов тоже есть, с нулём фильмов
+CREATE MATERIALIZED VIEW actor_stats AS
+SELECT a.id AS actor_id,
+       count(f.id) AS film_count,
+       round(avg(f.rating), 2) AS avg_rating,
+       min(f.rating) AS min_rating,
+       max(f.rating) AS max_rating,
+       min(f.release_date) AS first_release,
+       max(f.release_date) AS last_release
+FROM actors a
+LEFT JOIN actor_film af ON af.actor_id = a.id
+LEFT JOIN films f ON f.id = af.film_id AND f.deleted_at IS NULL
+WHERE a.deleted_at IS NULL
+GROUP BY a.id;
+
+CREATE UNIQUE INDEX uq_actor_stats ON actor_stats (actor_id);
+
+-- Выход фильмов по годам
+CREATE MATERIALIZED VIEW film_year_stats AS
+SELECT extract(year FROM release_date)::int AS year,
+       count(*) AS film_count,
+       round(avg(rating), 2) AS avg_rating,
+       min(rating) AS min_rating,
+       max(rating) AS max_rating
+FROM films
+WHERE deleted_at IS NULL
+GROUP BY 1;
+
+CREATE UNIQUE INDEX uq_film_year_stats ON film_year_stats (year);
+
+-- Распределение рейтингов по корзинам шириной 1 (рейтинг 10 - в последней) с разбивкой по годам
+CREATE MATERIALIZED VIEW film_rating_buckets AS
+SELECT extract(year FROM release_date)::int AS year,
+       least(floor(rating), 9)::int AS bucket,
+       count(*) AS film_count
+FROM films
+WHERE deleted_at IS NULL
+GROUP BY 1, 2;
+
+CREATE UNIQUE INDEX uq_film_rating_buckets ON film_rating_buckets (year, bucket);
+
+-- Пары актёров, снимавшихся вместе; actor_id < costar_id, чтобы пара была одна
+CREATE MATERIALIZED VIEW costar_pairs AS
+SELECT af1.actor_id, af2.actor_id AS costar_id, count(*) AS film_count
+FROM actor_film af1
+JOIN actor_film af2 ON af2.film_id = af1.film_id AND af2.actor_id > af1.actor_id
+JOIN films f ON f.id = af1.film_id AND f.deleted_at IS NULL
+JOIN actors a1 ON a1.id = af1.actor_id AND a1.deleted_at IS NULL
+JOIN actors a2 ON a2.id = af2.actor_id AND a2.deleted_at IS NULL
+GROUP BY af1.actor_id, af2.actor_id;
+
+CREATE UNIQUE INDEX uq_costar_pairs ON costar_pairs (actor_id, costar_id);
+CREATE INDEX idx_costar_pairs_costar ON costar_pairs (costar_id);
+CREATE INDEX idx_costar_pairs_film_count ON costar_pairs (film_count DESC);
+
+-- Уведомление об изменении каталога. NOTIFY доставляется только после COMMIT,
+-- а одинаковые уведомления одной транзакции схлопываются в одно
+-- +goose StatementBegin
+CREATE FUNCTION notify_catalog_changed() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('catalog_changed', TG_TABLE_NAME);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER films_catalog_changed
+AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON films
+FOR EACH STATEMENT EXECUTE FUNCTION notify_catalog_changed();
+
+CREATE TRIGGER actors_catalog_changed
+AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON actors
+FOR EACH STATEMENT EXECUTE FUNCTION notify_catalog_changed();
+
+CREATE TRIGGER actor_film_catalog_changed
+AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON actor_film
+FOR EACH STATEMENT EXECUTE FUNCTION notify_catalog_changed();
+
+INSERT INTO permissions (resource, action)
+VALUES ('stats', 'read')
+ON CONFLICT (resource, action) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT ro.id, p.id
+FROM roles ro
+JOIN permissions p ON p.resource = 'stats'
+WHERE ro.name IN ('user', 'editor', 'admin')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM permissions WHERE resource = 'stats';
+
+DROP TRIGGER IF EXISTS actor_film_catalog_changed ON actor_film;
+DROP TRIGGER IF EXISTS actors_catalog_changed ON actors;
+DROP TRIGGER IF EXISTS films_catalog_changed ON films;
+DROP FUNCTION IF EXISTS notify_catalog_changed();
+
+DROP MATERIALIZED VIEW IF EXISTS costar_pairs;
+DROP MATERIALIZED VIEW IF EXISTS film_rating_buckets;
+DROP MATERIALIZED VIEW IF EXISTS film_year_stats;
+DROP MATERIALIZED VIEW IF EXISTS actor_stats;
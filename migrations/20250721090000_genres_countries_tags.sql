@@ -0,0 +1,63 @@
+-- +goose Up
+-- Жанры и страны - справочники, которые ведёт редактор; теги - свободные метки,
+-- создаются при назначении фильму. Имена жанров и тегов уникальны без учёта регистра
+CREATE TABLE IF NOT EXISTS genres (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_genres_name ON genres (lower(name));
+
+-- Страны по ISO 3166-1 alpha-2
+CREATE TABLE IF NOT EXISTS countries (
+    code CHAR(2) PRIMARY KEY CHECK (code ~ '^[A-Z]{2}$'),
+    name VARCHAR(100) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE CHECK (name = lower(name))
+);
+CREATE INDEX IF NOT EXISTS idx_tags_name_prefix ON tags (name text_pattern_ops);
+
+-- Жанр или страну, назначенные фильмам, удалить нельзя; тег снимается со всех фильмов
+CREATE TABLE IF NOT EXISTS film_genres (
+    film_id INT NOT NULL REFERENCES films(id) ON DELETE CASCADE,
+    genre_id INT NOT NULL REFERENCES genres(id) ON DELETE RESTRICT,
+    PRIMARY KEY (film_id, genre_id)
+);
+CREATE INDEX IF NOT EXISTS idx_film_genres_genre ON film_genres (genre_id);
+
+CREATE TABLE IF NOT EXISTS film_countries (
+    film_id INT NOT NULL REFERENCES films(id) ON DELETE CASCADE,
+    country_code CHAR(2) NOT NULL REFERENCES countries(code) ON DELETE RESTRICT,
+    PRIMARY KEY (film_id, country_code)
+);
+CREATE INDEX IF NOT EXISTS idx_film_countries_country ON film_countries (country_code);
+
+CREATE TABLE IF NOT EXISTS film_tags (
+    film_id INT NOT NULL REFERENCES films(id) ON DELETE CASCADE,
+    tag_id INT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (film_id, tag_id)
+);
+CREATE INDEX IF NOT EXISTS idx_film_tags_tag ON film_tags (tag_id);
+
+INSERT INTO genres (name)
+VALUES ('Action'), ('Adventure'), ('Animation'), ('Comedy'), ('Crime'), ('Documentary'),
+       ('Drama'), ('Fantasy'), ('Horror'), ('Musical'), ('Mystery'), ('Romance'),
+       ('Science Fiction'), ('Thriller'), ('War'), ('Western')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO countries (code, name)
+VALUES ('AU', 'Australia'), ('CA', 'Canada'), ('CN', 'China'), ('DE', 'Germany'),
+       ('ES', 'Spain'), ('FR', 'France'), ('GB', 'United Kingdom'), ('IN', 'India'),
+       ('IT', 'Italy'), ('JP', 'Japan'), ('KR', 'South Korea'), ('NZ', 'New Zealand'),
+       ('RU', 'Russia'), ('SE', 'Sweden'), ('US', 'United States')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS film_tags;
+DROP TABLE IF EXISTS film_countries;
+DROP TABLE IF EXISTS film_genres;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS countries;
+DROP TABLE IF EXISTS genres;
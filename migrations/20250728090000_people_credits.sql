@@ -0,0 +1,107 @@
+-- +goose Up
+-- Актёры становятся участниками (people), а связи actor_film - титрами фильма
+-- (film_credits), где актёр - лишь одна из ролей. Идентификаторы сохраняются,
+-- поэтому старые ссылки на актёров продолжают работать
+
+-- Представления статистики читают связи без учёта роли, пересоздаём их ниже
+DROP MATERIALIZED VIEW IF EXISTS costar_pairs;
+DROP MATERIALIZED VIEW IF EXISTS actor_stats;
+
+ALTER TABLE actors RENAME TO people;
+ALTER TRIGGER actors_catalog_changed ON people RENAME TO people_catalog_changed;
+
+ALTER TABLE actor_film RENAME TO film_credits;
+ALTER TRIGGER actor_film_catalog_changed ON film_credits RENAME TO film_credits_catalog_changed;
+ALTER TABLE film_credits RENAME COLUMN actor_id TO person_id;
+ALTER TABLE film_credits RENAME COLUMN billing_order TO position;
+ALTER INDEX IF EXISTS idx_actor_film_actor RENAME TO idx_film_credits_person;
+
+-- Все существующие связи - актёрские. Один человек может быть в фильме
+-- в нескольких ролях, например режиссёром и актёром
+ALTER TABLE film_credits ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'actor'
+    CHECK (role IN ('actor', 'director', 'writer', 'producer', 'composer', 'cinematographer', 'editor'));
+ALTER TABLE film_credits ALTER COLUMN role DROP DEFAULT;
+ALTER TABLE film_credits ADD CONSTRAINT film_credits_character_check CHECK (role = 'actor' OR character_name IS NULL);
+
+ALTER TABLE film_credits DROP CONSTRAINT actor_film_pkey;
+ALTER TABLE film_credits ADD PRIMARY KEY (film_id, person_id, role);
+
+CREATE MATERIALIZED VIEW actor_stats AS
+SELECT a.id AS actor_id,
+       count(f.id) AS film_count,
+       round(avg(f.rating), 2) AS avg_rating,
+       min(f.rating) AS min_rating,
+       max(f.rating) AS max_rating,
+       min(f.release_date) AS first_release,
+       max(f.release_date) AS last_release
+FROM people a
+LEFT JOIN film_credits af ON af.person_id = a.id AND af.role = 'actor'
+LEFT JOIN films f ON f.id = af.film_id AND f.deleted_at IS NULL
+WHERE a.deleted_at IS NULL
+GROUP BY a.id;
+
+CREATE UNIQUE INDEX uq_actor_stats ON actor_stats (actor_id);
+
+CREATE MATERIALIZED VIEW costar_pairs AS
+SELECT af1.person_id AS actor_id, af2.person_id AS costar_id, count(*) AS film_count
+FROM film_credits af1
+JOIN film_credits af2 ON af2.film_id = af1.film_id AND af2.role = 'actor' AND af2.person_id > af1.person_id
+JOIN films f ON f.id = af1.film_id AND f.deleted_at IS NULL
+JOIN people a1 ON a1.id = af1.person_id AND a1.deleted_at IS NULL
+JOIN people a2 ON a2.id = af2.person_id AND a2.deleted_at IS NULL
+WHERE af1.role = 'actor'
+GROUP BY af1.person_id, af2.person_id;
+
+CREATE UNIQUE INDEX uq_costar_pairs ON costar_pairs (actor_id, costar_id);
+CREATE INDEX idx_costar_pairs_costar ON costar_pairs (costar_id);
+CREATE INDEX idx_costar_pairs_film_count ON costar_pairs (film_count DESC);
+
+-- +goose Down
+DROP MATERIALIZED VIEW IF EXISTS costar_pairs;
+DROP MATERIALIZED VIEW IF EXISTS actor_stats;
+
+-- Съёмочная группа в старой схеме не помещается
+DELETE FROM film_credits WHERE role <> 'actor';
+
+ALTER TABLE film_credits DROP CONSTRAINT film_credits_pkey;
+ALTER TABLE film_credits ADD CONSTRAINT actor_film_pkey PRIMARY KEY (film_id, person_id);
+ALTER TABLE film_credits DROP CONSTRAINT IF EXISTS film_credits_character_check;
+ALTER TABLE film_credits DROP COLUMN role;
+
+ALTER INDEX IF EXISTS idx_film_credits_person RENAME TO idx_actor_film_actor;
+ALTER TABLE film_credits RENAME COLUMN position TO billing_order;
+ALTER TABLE film_credits RENAME COLUMN person_id TO actor_id;
+ALTER TRIGGER film_credits_catalog_changed ON film_credits RENAME TO actor_film_catalog_changed;
+ALTER TABLE film_credits RENAME TO actor_film;
+
+ALTER TRIGGER people_catalog_changed ON people RENAME TO actors_catalog_changed;
+ALTER TABLE people RENAME TO actors;
+
+CREATE MATERIALIZED VIEW actor_stats AS
+SELECT a.id AS actor_id,
+       count(f.id) AS film_count,
+       round(avg(f.rating), 2) AS avg_rating,
+       min(f.rating) AS min_rating,
+       max(f.rating) AS max_rating,
+       min(f.release_date) AS first_release,
+       max(f.release_date) AS last_release
+FROM actors a
+LEFT JOIN actor_film af ON af.actor_id = a.id
+LEFT JOIN films f ON f.id = af.film_id AND f.deleted_at IS NULL
+WHERE a.deleted_at IS NULL
+GROUP BY a.id;
+
+CREATE UNIQUE INDEX uq_actor_stats ON actor_stats (actor_id);
+
+CREATE MATERIALIZED VIEW costar_pairs AS
+SELECT af1.actor_id, af2.actor_id AS costar_id, count(*) AS film_count
+FROM actor_film af1
+JOIN actor_film af2 ON af2.film_id = af1.film_id AND af2.actor_id > af1.actor_id
+JOIN films f ON f.id = af1.film_id AND f.deleted_at IS NULL
+JOIN actors a1 ON a1.id = af1.actor_id AND a1.deleted_at IS NULL
+JOIN actors a2 ON a2.id = af2.actor_id AND a2.deleted_at IS NULL
+GROUP BY af1.actor_id, af2.actor_id;
+
+CREATE UNIQUE INDEX uq_costar_pairs ON costar_pairs (actor_id, costar_id);
+CREATE INDEX idx_costar_pairs_costar ON costar_pairs (costar_id);
+CREATE INDEX idx_costar_pairs_film_count ON costar_pairs (film_count DESC);
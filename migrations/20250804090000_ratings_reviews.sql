@@ -0,0 +1,58 @@
+-- +goose Up
+-- Оценки и рецензии пользователей. films.rating по-прежнему ставит редактор,
+-- а средняя оценка пользователей и число голосов хранятся рядом и пересчитываются
+-- в той же транзакции, что и изменение оценки
+CREATE TABLE IF NOT EXISTS user_ratings (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    film_id INT NOT NULL REFERENCES films(id) ON DELETE CASCADE,
+    score SMALLINT NOT NULL CHECK (score BETWEEN 1 AND 10),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, film_id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_ratings_film ON user_ratings (film_id);
+
+-- Одна рецензия пользователя на фильм. Новая и изменённая рецензия ждёт модерации,
+-- в списке рецензий фильма видны только одобренные
+CREATE TABLE IF NOT EXISTS reviews (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    film_id INT NOT NULL REFERENCES films(id) ON DELETE CASCADE,
+    body TEXT NOT NULL CHECK (length(body) BETWEEN 1 AND 5000),
+    status VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'hidden')),
+    moderated_by INT REFERENCES users(id) ON DELETE SET NULL,
+    moderated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (user_id, film_id)
+);
+CREATE INDEX IF NOT EXISTS idx_reviews_film_status ON reviews (film_id, status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_reviews_status ON reviews (status, created_at);
+
+-- Фильм без голосов: user_score = 0, votes = 0
+ALTER TABLE films ADD COLUMN IF NOT EXISTS user_score NUMERIC(4,2) NOT NULL DEFAULT 0;
+ALTER TABLE films ADD COLUMN IF NOT EXISTS votes INT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_films_user_score ON films (user_score, id) WHERE deleted_at IS NULL;
+
+INSERT INTO permissions (resource, action)
+VALUES ('reviews', 'read'), ('reviews', 'create'), ('reviews', 'update'), ('reviews', 'delete')
+ON CONFLICT (resource, action) DO NOTHING;
+
+-- Свои оценки и рецензии ставят и удаляют все; update - модерация, только у администратора
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT ro.id, p.id
+FROM roles ro
+JOIN permissions p ON p.resource = 'reviews'
+WHERE ro.name IN ('user', 'editor', 'admin') AND p.action <> 'update'
+   OR ro.name = 'admin' AND p.action = 'update'
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM permissions WHERE resource = 'reviews';
+
+DROP INDEX IF EXISTS idx_films_user_score;
+ALTER TABLE films DROP COLUMN IF EXISTS votes;
+ALTER TABLE films DROP COLUMN IF EXISTS user_score;
+
+DROP TABLE IF EXISTS reviews;
+DROP TABLE IF EXISTS user_ratings;
@@ -0,0 +1,52 @@
+-- +goose Up
+-- Личные списки фильмов: у пользователя может быть несколько именованных списков,
+-- имена уникальны без учёта регистра. Публичный список виден всем
+CREATE TABLE IF NOT EXISTS watchlists (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(1000) NOT NULL DEFAULT '',
+    is_public BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_watchlists_user_name ON watchlists (user_id, lower(name));
+
+-- position - место фильма в списке начиная с 1; уникальность не требуется,
+-- чтобы переставлять фильмы одним UPDATE
+CREATE TABLE IF NOT EXISTS watchlist_films (
+    watchlist_id INT NOT NULL REFERENCES watchlists(id) ON DELETE CASCADE,
+    film_id INT NOT NULL REFERENCES films(id) ON DELETE CASCADE,
+    position INT NOT NULL CHECK (position > 0),
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (watchlist_id, film_id)
+);
+CREATE INDEX IF NOT EXISTS idx_watchlist_films_film ON watchlist_films (film_id);
+
+-- Отметка о просмотре: одна на пользователя и фильм, повторный просмотр меняет дату
+CREATE TABLE IF NOT EXISTS watched_films (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    film_id INT NOT NULL REFERENCES films(id) ON DELETE CASCADE,
+    watched_on DATE NOT NULL,
+    PRIMARY KEY (user_id, film_id)
+);
+CREATE INDEX IF NOT EXISTS idx_watched_films_user_date ON watched_films (user_id, watched_on DESC);
+
+INSERT INTO permissions (resource, action)
+VALUES ('watchlists', 'read'), ('watchlists', 'create'), ('watchlists', 'update'), ('watchlists', 'delete')
+ON CONFLICT (resource, action) DO NOTHING;
+
+-- Каждый ведёт только свои списки и просмотры, поэтому права есть у всех ролей
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT ro.id, p.id
+FROM roles ro
+JOIN permissions p ON p.resource = 'watchlists'
+WHERE ro.name IN ('user', 'editor', 'admin')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM permissions WHERE resource = 'watchlists';
+
+DROP TABLE IF EXISTS watched_films;
+DROP TABLE IF EXISTS watchlist_films;
+DROP TABLE IF EXISTS watchlists;
@@ -0,0 +1,27 @@
+-- +goose Up
+-- Похожие фильмы: заполняется фоновой задачей по общим актёрам,
+-- для каждого фильма хранятся только лучшие совпадения
+CREATE TABLE IF NOT EXISTS film_similar (
+    film_id INT NOT NULL REFERENCES films(id) ON DELETE CASCADE,
+    similar_id INT NOT NULL REFERENCES films(id) ON DELETE CASCADE,
+    score REAL NOT NULL,
+    shared_actors INT NOT NULL CHECK (shared_actors > 0),
+    PRIMARY KEY (film_id, similar_id)
+);
+CREATE INDEX IF NOT EXISTS idx_film_similar_score ON film_similar (film_id, score DESC, similar_id);
+
+-- Персональные рекомендации: тоже заполняются фоновой задачей.
+-- reason - откуда взялась рекомендация: cast (актёры из понравившихся фильмов),
+-- ratings (высокие оценки пользователей с похожими вкусами) или обе причины
+CREATE TABLE IF NOT EXISTS user_recommendations (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    film_id INT NOT NULL REFERENCES films(id) ON DELETE CASCADE,
+    score REAL NOT NULL,
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('cast', 'ratings', 'cast_and_ratings')),
+    PRIMARY KEY (user_id, film_id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_recommendations_score ON user_recommendations (user_id, score DESC, film_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_recommendations;
+DROP TABLE IF EXISTS film_similar;
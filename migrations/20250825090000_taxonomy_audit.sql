@@ -0,0 +1,18 @@
+-- +goose Up
+-- Изменения жанров, стран и тегов тоже пишутся в журнал. Страна определяется
+-- кодом, а не числовым id, поэтому у записи есть либо entity_id, либо entity_code
+ALTER TABLE audit_log ALTER COLUMN entity_id DROP NOT NULL;
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS entity_code TEXT;
+ALTER TABLE audit_log DROP CONSTRAINT IF EXISTS audit_log_entity_key;
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_entity_key CHECK ((entity_id IS NULL) <> (entity_code IS NULL));
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity_code ON audit_log (entity, entity_code, created_at DESC)
+    WHERE entity_code IS NOT NULL;
+
+-- +goose Down
+DELETE FROM audit_log WHERE entity_id IS NULL;
+
+DROP INDEX IF EXISTS idx_audit_log_entity_code;
+ALTER TABLE audit_log DROP CONSTRAINT IF EXISTS audit_log_entity_key;
+ALTER TABLE audit_log DROP COLUMN IF EXISTS entity_code;
+ALTER TABLE audit_log ALTER COLUMN entity_id SET NOT NULL;
@@ -0,0 +1,42 @@
+-- +goose Up
+-- В статистику актёров попадали все участники, в том числе режиссёры и сценаристы
+-- без актёрских ролей. Оставляем тех, кто снимался как актёр, и тех, кто ещё
+-- не указан ни в одних титрах (только что добавлен как актёр)
+DROP MATERIALIZED VIEW IF EXISTS actor_stats;
+
+CREATE MATERIALIZED VIEW actor_stats AS
+SELECT a.id AS actor_id,
+       count(f.id) AS film_count,
+       round(avg(f.rating), 2) AS avg_rating,
+       min(f.rating) AS min_rating,
+       max(f.rating) AS max_rating,
+       min(f.release_date) AS first_release,
+       max(f.release_date) AS last_release
+FROM people a
+LEFT JOIN film_credits af ON af.person_id = a.id AND af.role = 'actor'
+LEFT JOIN films f ON f.id = af.film_id AND f.deleted_at IS NULL
+WHERE a.deleted_at IS NULL
+  AND (EXISTS (SELECT 1 FROM film_credits ac WHERE ac.person_id = a.id AND ac.role = 'actor')
+       OR NOT EXISTS (SELECT 1 FROM film_credits ac WHERE ac.person_id = a.id))
+GROUP BY a.id;
+
+CREATE UNIQUE INDEX uq_actor_stats ON actor_stats (actor_id);
+
+-- +goose Down
+DROP MATERIALIZED VIEW IF EXISTS actor_stats;
+
+CREATE MATERIALIZED VIEW actor_stats AS
+SELECT a.id AS actor_id,
+       count(f.id) AS film_count,
+       round(avg(f.rating), 2) AS avg_rating,
+       min(f.rating) AS min_rating,
+       max(f.rating) AS max_rating,
+       min(f.release_date) AS first_release,
+       max(f.release_date) AS last_release
+FROM people a
+LEFT JOIN film_credits af ON af.person_id = a.id AND af.role = 'actor'
+LEFT JOIN films f ON f.id = af.film_id AND f.deleted_at IS NULL
+WHERE a.deleted_at IS NULL
+GROUP BY a.id;
+
+CREATE UNIQUE INDEX uq_actor_stats ON actor_stats (actor_id);
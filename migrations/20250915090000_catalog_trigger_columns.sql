@@ -0,0 +1,16 @@
+-- +goose Up
+-- Каждая оценка пересчитывает films.user_score и votes, и триггер на любой UPDATE
+-- фильмов пересобирал всю статистику после каждого голоса. Представления оценки
+-- пользователей не читают, поэтому уведомляем только об изменении полей каталога
+DROP TRIGGER IF EXISTS films_catalog_changed ON films;
+
+CREATE TRIGGER films_catalog_changed
+AFTER INSERT OR UPDATE OF name, description, release_date, rating, deleted_at OR DELETE OR TRUNCATE ON films
+FOR EACH STATEMENT EXECUTE FUNCTION notify_catalog_changed();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS films_catalog_changed ON films;
+
+CREATE TRIGGER films_catalog_changed
+AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON films
+FOR EACH STATEMENT EXECUTE FUNCTION notify_catalog_changed();